@@ -66,6 +66,8 @@ func main() {
 	sentries()
 	// Continuous profiling
 	profiling()
+	// Clean expired async searches
+	go core.ZINC_ASYNC_SEARCH_LIST.Clean()
 
 	// HTTP init
 	app := gin.New()
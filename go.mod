@@ -15,6 +15,10 @@ require (
 	github.com/gin-gonic/gin v1.9.1
 	github.com/go-ego/gse v0.80.2
 	github.com/goccy/go-json v0.10.2
+	github.com/ikawaha/kagome-dict v1.0.4
+	github.com/ikawaha/kagome-dict-ko v0.2.1
+	github.com/ikawaha/kagome-dict/ipa v1.0.4
+	github.com/ikawaha/kagome/v2 v2.8.0
 	github.com/joho/godotenv v1.4.0
 	github.com/prometheus/client_golang v1.15.0
 	github.com/pyroscope-io/client v0.6.0
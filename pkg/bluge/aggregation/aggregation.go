@@ -24,8 +24,15 @@ const (
 	NumericValuesSource
 	BooleanValueSource
 	BooleanValuesSource
+	LongValueSource
+	LongValuesSource
 )
 
+// ValueSource is the source of aggregation, it should implement the interface of the value type
+type ValueSource interface {
+	Fields() []string
+}
+
 type SearchAggregation interface {
 	AddAggregation(name string, aggregation search.Aggregation)
 }
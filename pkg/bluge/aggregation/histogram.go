@@ -25,7 +25,7 @@ import (
 )
 
 type HistogramAggregation struct {
-	src         search.FieldSource
+	src         search.NumericValuesSource
 	size        int
 	interval    float64
 	offset      float64
@@ -49,7 +49,7 @@ type HistogramBound struct {
 // NewHistogramAggregation returns a termsAggregation
 // field use to set the field use to terms aggregation
 func NewHistogramAggregation(
-	field search.FieldSource,
+	field search.NumericValuesSource,
 	interval,
 	offset float64,
 	extendedBounds,
@@ -0,0 +1,73 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package aggregation
+
+import (
+	"math"
+
+	"github.com/blugelabs/bluge/search"
+)
+
+// Int64ValuesSource is the source of exact int64 values
+type Int64ValuesSource interface {
+	Fields() []string
+	Int64s(match *search.DocumentMatch) []int64
+}
+
+// LongFieldSource reads the values of long field, they are indexed as the date time terms of nanoseconds.
+// The numbers are the int64 values divided by the scaling factor if it is set.
+type LongFieldSource struct {
+	search.FieldSource
+	scalingFactor float64
+}
+
+// LongField returns the source of long field, scalingFactor is used by scaled_float, 0 means not scaled
+func LongField(field string, scalingFactor float64) *LongFieldSource {
+	return &LongFieldSource{
+		FieldSource:   search.Field(field),
+		scalingFactor: scalingFactor,
+	}
+}
+
+func (f *LongFieldSource) Int64s(match *search.DocumentMatch) []int64 {
+	dates := f.FieldSource.Dates(match)
+	rv := make([]int64, 0, len(dates))
+	for _, t := range dates {
+		rv = append(rv, t.UnixNano())
+	}
+	return rv
+}
+
+func (f *LongFieldSource) Number(match *search.DocumentMatch) float64 {
+	numbers := f.Numbers(match)
+	if len(numbers) == 0 {
+		return math.NaN()
+	}
+	return numbers[0]
+}
+
+func (f *LongFieldSource) Numbers(match *search.DocumentMatch) []float64 {
+	values := f.Int64s(match)
+	rv := make([]float64, 0, len(values))
+	for _, v := range values {
+		if f.scalingFactor > 0 {
+			rv = append(rv, float64(v)/f.scalingFactor)
+		} else {
+			rv = append(rv, float64(v))
+		}
+	}
+	return rv
+}
@@ -24,7 +24,7 @@ import (
 )
 
 type TermsAggregation struct {
-	src     search.FieldSource
+	src     ValueSource
 	srcType int
 	size    int
 
@@ -38,7 +38,8 @@ type TermsAggregation struct {
 // NewTermsAggregation returns a termsAggregation
 // field use to set the field use to terms aggregation
 // valueType use to set the value type, can be diy.TextValueSource / diy.TextValuesSource / diy.NumericValueSource / diy.NumericValuesSource
-func NewTermsAggregation(field search.FieldSource, valueType int, size int) *TermsAggregation {
+// diy.LongValueSource / diy.LongValuesSource need the field of LongFieldSource
+func NewTermsAggregation(field ValueSource, valueType int, size int) *TermsAggregation {
 	rv := &TermsAggregation{
 		src:     field,
 		srcType: valueType,
@@ -106,6 +107,10 @@ func (a *TermsCalculator) Consume(d *search.DocumentMatch) {
 		a.consumeNumericValueSource(d)
 	case NumericValuesSource:
 		a.consumeNumericValuesSource(d)
+	case LongValueSource:
+		a.consumeLongValueSource(d)
+	case LongValuesSource:
+		a.consumeLongValuesSource(d)
 	case BooleanValueSource:
 		a.consumeBooleanValueSource(d)
 	case BooleanValuesSource:
@@ -181,6 +186,42 @@ func (a *TermsCalculator) consumeNumericValuesSource(d *search.DocumentMatch) {
 	}
 }
 
+func (a *TermsCalculator) consumeLongValueSource(d *search.DocumentMatch) {
+	a.total++
+	src := a.src.(Int64ValuesSource)
+	values := src.Int64s(d)
+	if len(values) == 0 {
+		return
+	}
+	termStr := strconv.FormatInt(values[0], 10)
+	bucket, ok := a.bucketsMap[termStr]
+	if ok {
+		bucket.Consume(d)
+	} else {
+		newBucket := search.NewBucket(termStr, a.aggregations)
+		newBucket.Consume(d)
+		a.bucketsMap[termStr] = newBucket
+		a.bucketsList = append(a.bucketsList, newBucket)
+	}
+}
+
+func (a *TermsCalculator) consumeLongValuesSource(d *search.DocumentMatch) {
+	a.total++
+	src := a.src.(Int64ValuesSource)
+	for _, term := range src.Int64s(d) {
+		termStr := strconv.FormatInt(term, 10)
+		bucket, ok := a.bucketsMap[termStr]
+		if ok {
+			bucket.Consume(d)
+		} else {
+			newBucket := search.NewBucket(termStr, a.aggregations)
+			newBucket.Consume(d)
+			a.bucketsMap[termStr] = newBucket
+			a.bucketsList = append(a.bucketsList, newBucket)
+		}
+	}
+}
+
 func (a *TermsCalculator) consumeBooleanValueSource(d *search.DocumentMatch) {
 	a.total++
 	src := a.src.(search.NumericValueSource)
@@ -0,0 +1,68 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package bn
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blugelabs/bluge/analysis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzer(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "stemmer",
+			text: "ছেলেরা বইগুলো পড়েছিলেন",
+			want: "[ছেল বই পর]",
+		},
+		{
+			name: "stemmer with ya nukta",
+			text: "মেয়েদেরকে খাইয়াছিলেন",
+			want: "[মে খা]",
+		},
+		{
+			name: "normalization",
+			text: "ক্ষমা শব্দ",
+			want: "[খম সব্দ]",
+		},
+		{
+			name: "stop words and digits",
+			text: "আমি এই গান ভালোবাসি ১২৩",
+			want: "[আম গান ভালোবাস 123]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Analyzer().Analyze([]byte(tt.text))
+			assert.Equal(t, tt.want, collectToken(got))
+		})
+	}
+}
+
+func collectToken(tokens analysis.TokenStream) string {
+	str := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		str = append(str, string(token.Term))
+	}
+	return "[" + strings.Join(str, " ") + "]"
+}
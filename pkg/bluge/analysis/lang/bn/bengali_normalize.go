@@ -0,0 +1,127 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package bn
+
+import (
+	"bytes"
+
+	"github.com/blugelabs/bluge/analysis"
+)
+
+// BengaliNormalizeFilter normalizes the Bengali orthography, it follows the Lucene BengaliNormalizer,
+// which is based on the paper "A Double Metaphone Encoding for Bangla and its Application in Spelling Checker".
+type BengaliNormalizeFilter struct {
+}
+
+func NormalizeFilter() *BengaliNormalizeFilter {
+	return &BengaliNormalizeFilter{}
+}
+
+func (s *BengaliNormalizeFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		runes := bytes.Runes(token.Term)
+		token.Term = analysis.BuildTermFromRunes(normalize(runes))
+	}
+	return input
+}
+
+func normalize(s []rune) []rune {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		// delete chandrabindu
+		case 'ঁ':
+			s = analysis.DeleteRune(s, i)
+			i--
+		// dirgho i kar -> rossho i kar
+		case 'ী':
+			s[i] = 'ি'
+		// dirgho u kar -> rossho u kar
+		case 'ূ':
+			s[i] = 'ু'
+		// khio (ka + hasant + ssa)
+		case 'ক':
+			if i+2 < len(s) && s[i+1] == '্' && s[i+2] == 'ষ' {
+				if i == 0 {
+					s[i] = 'খ'
+					s = analysis.DeleteRune(s, i+2)
+					s = analysis.DeleteRune(s, i+1)
+				} else {
+					s[i+1] = 'খ'
+					s = analysis.DeleteRune(s, i+2)
+				}
+			}
+		// nga -> anusvara
+		case 'ঙ':
+			s[i] = 'ং'
+		// ja phala
+		case 'য':
+			if i == 2 && s[i-1] == '্' {
+				s[i-1] = 'ে'
+				if i+1 < len(s) && s[i+1] == 'া' {
+					s = analysis.DeleteRune(s, i+1)
+				}
+				s = analysis.DeleteRune(s, i)
+				i--
+			} else if i >= 1 && s[i-1] == '্' {
+				s = analysis.DeleteRune(s, i)
+				s = analysis.DeleteRune(s, i-1)
+				i -= 2
+			}
+		// ba phala
+		case 'ব':
+			if i == 0 || s[i-1] != '্' {
+				break
+			}
+			if i == 2 {
+				s = analysis.DeleteRune(s, i)
+				s = analysis.DeleteRune(s, i-1)
+				i -= 2
+			} else if i >= 5 && s[i-3] == '্' {
+				s = analysis.DeleteRune(s, i)
+				s = analysis.DeleteRune(s, i-1)
+				i -= 2
+			} else if i >= 2 {
+				s[i-1] = s[i-2]
+				s = analysis.DeleteRune(s, i)
+				i--
+			}
+		// visarga
+		case 'ঃ':
+			if i == len(s)-1 {
+				if len(s) <= 3 {
+					s[i] = 'হ'
+				} else {
+					s = analysis.DeleteRune(s, i)
+				}
+			} else {
+				s[i] = s[i+1]
+			}
+		// all the sh -> sa
+		case 'শ', 'ষ':
+			s[i] = 'স'
+		// nna -> na
+		case 'ণ':
+			s[i] = 'ন'
+		// rra, rha -> ra
+		case 'ড়', 'ঢ়':
+			s[i] = 'র'
+		// khanda ta -> ta
+		case 'ৎ':
+			s[i] = 'ত'
+		}
+	}
+	return s
+}
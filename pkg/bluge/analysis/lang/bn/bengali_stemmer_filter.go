@@ -0,0 +1,69 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package bn
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	"github.com/blugelabs/bluge/analysis"
+)
+
+// suffixes the inflectional suffixes removed by the stemmer, grouped by length from the longest,
+// the ya with nukta is the composed form as the indic normalization outputs,
+// they are from the Lucene BengaliStemmer, which is based on the paper
+// "Natural Language Processing in Bengali: A Lightweight Stemmer for Bengali".
+var suffixes = [][]string{
+	{"িয়াছিলাম", "িতেছিলাম", "িতেছিলেন", "ইতেছিলেন", "িয়াছিলেন", "ইয়াছিলেন"},
+	{"িতেছিলি", "িতেছিলে", "িয়াছিলা", "িয়াছিলে", "িতেছিলা", "িয়াছিলি", "য়েদেরকে"},
+	{"িতেছিস", "িতেছেন", "িয়াছিস", "িয়াছেন", "েছিলাম", "েছিলেন", "েদেরকে"},
+	{"িতেছি", "িতেছা", "িতেছে", "ছিলাম", "ছিলেন", "িয়াছি", "িয়াছা", "িয়াছে", "েছিলে", "েছিলা", "য়েদের", "দেরকে"},
+	{"িলাম", "িলেন", "িতাম", "িতেন", "িবেন", "ছিলি", "ছিলে", "ছিলা", "তেছে", "িতেছ", "খানা", "খানি", "গুলো", "গুলি", "য়েরা", "েদের"},
+	{"লাম", "িলি", "ইলি", "িলে", "ইলে", "লেন", "িলা", "ইলা", "তাম", "িতি", "ইতি", "িতে", "ইতে", "তেন", "িতা", "িবা", "ইবা", "িবি", "ইবি", "বেন", "িবে", "ইবে", "ছেন", "য়োন", "য়ের", "েরা", "দের"},
+	{"িস", "েন", "লি", "লে", "লা", "তি", "তে", "তা", "বি", "বে", "বা", "ছি", "ছা", "ছে", "ুন", "ুক", "টা", "টি", "নি", "ের", "রা", "কে"},
+	{"ি", "ী", "া", "ো", "ে", "ব", "ত"},
+}
+
+type BengaliStemmerFilter struct {
+}
+
+func StemmerFilter() *BengaliStemmerFilter {
+	return &BengaliStemmerFilter{}
+}
+
+func (s *BengaliStemmerFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		// if not protected keyword, stem it
+		if !token.KeyWord {
+			token.Term = stem(token.Term)
+		}
+	}
+	return input
+}
+
+// stem removes the longest suffix, the stem keeps at least two characters
+func stem(input []byte) []byte {
+	inputLen := utf8.RuneCount(input)
+	for _, group := range suffixes {
+		for _, suffix := range group {
+			n := utf8.RuneCountInString(suffix)
+			if inputLen > n+1 && bytes.HasSuffix(input, []byte(suffix)) {
+				return analysis.TruncateRunes(input, n)
+			}
+		}
+	}
+	return input
+}
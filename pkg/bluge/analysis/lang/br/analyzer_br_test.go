@@ -0,0 +1,79 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package br
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blugelabs/bluge/analysis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzer(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "stemmer",
+			text: "boataria boatos quilométricas quilometrico",
+			want: "[boat boat quilometr quilometr]",
+		},
+		{
+			name: "stop words",
+			text: "As crianças brincavam alegremente nas ruas da cidade",
+			want: "[crianc brinc alegr ruas cidad]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Analyzer().Analyze([]byte(tt.text))
+			assert.Equal(t, tt.want, collectToken(got))
+		})
+	}
+}
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		term string
+		want string
+	}{
+		{term: "felicidades", want: "felic"},
+		{term: "nacionalização", want: "nacionaliz"},
+		{term: "amigos", want: "amig"},
+		{term: "gostaria", want: "gost"},
+		{term: "bom", want: "bom"},
+		{term: "r2d2", want: "r2d2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.term, func(t *testing.T) {
+			got := StemmerFilter().Filter(analysis.TokenStream{&analysis.Token{Term: []byte(tt.term)}})
+			assert.Equal(t, tt.want, string(got[0].Term))
+		})
+	}
+}
+
+func collectToken(tokens analysis.TokenStream) string {
+	str := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		str = append(str, string(token.Term))
+	}
+	return "[" + strings.Join(str, " ") + "]"
+}
@@ -0,0 +1,329 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package br
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/blugelabs/bluge/analysis"
+)
+
+// BrazilianStemmerFilter stems the Brazilian Portuguese words, it follows the Lucene BrazilianStemmer,
+// which is a variant of the snowball Portuguese stemmer that works on the terms without accents.
+type BrazilianStemmerFilter struct {
+}
+
+func StemmerFilter() *BrazilianStemmerFilter {
+	return &BrazilianStemmerFilter{}
+}
+
+func (s *BrazilianStemmerFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		// if not protected keyword, stem it
+		if !token.KeyWord {
+			if stemmed, ok := stem(string(token.Term)); ok {
+				token.Term = []byte(stemmed)
+			}
+		}
+	}
+	return input
+}
+
+// region the region of the term a suffix must be in to be removed
+type region int
+
+const (
+	regionR1 region = iota
+	regionR2
+	regionRV
+)
+
+// suffixRule replaces the suffix with replacement when the suffix is in the region,
+// and it's preceded by precededBy if that is set.
+type suffixRule struct {
+	suffix      string
+	replacement string
+	region      region
+	precededBy  string
+}
+
+// standardSuffixes the noun and adjective suffixes, in the order they are tried
+var standardSuffixes = []suffixRule{
+	{suffix: "uciones", replacement: "u", region: regionR2},
+	{suffix: "imentos", region: regionR2},
+	{suffix: "amentos", region: regionR2},
+	{suffix: "adores", region: regionR2},
+	{suffix: "adoras", region: regionR2},
+	{suffix: "logias", replacement: "log", region: regionR2},
+	{suffix: "encias", replacement: "ente", region: regionR2},
+	{suffix: "amente", region: regionR1},
+	{suffix: "idades", region: regionR2},
+	{suffix: "acoes", region: regionR2},
+	{suffix: "imento", region: regionR2},
+	{suffix: "amento", region: regionR2},
+	{suffix: "adora", region: regionR2},
+	{suffix: "ismos", region: regionR2},
+	{suffix: "istas", region: regionR2},
+	{suffix: "logia", replacement: "log", region: regionR2},
+	{suffix: "ucion", replacement: "u", region: regionR2},
+	{suffix: "encia", replacement: "ente", region: regionR2},
+	{suffix: "mente", region: regionR2},
+	{suffix: "idade", region: regionR2},
+	{suffix: "acao", region: regionR2},
+	{suffix: "ezas", region: regionR2},
+	{suffix: "icos", region: regionR2},
+	{suffix: "icas", region: regionR2},
+	{suffix: "ismo", region: regionR2},
+	{suffix: "avel", region: regionR2},
+	{suffix: "ivel", region: regionR2},
+	{suffix: "ista", region: regionR2},
+	{suffix: "osos", region: regionR2},
+	{suffix: "osas", region: regionR2},
+	{suffix: "ador", region: regionR2},
+	{suffix: "ivas", region: regionR2},
+	{suffix: "ivos", region: regionR2},
+	{suffix: "iras", replacement: "ir", region: regionRV, precededBy: "e"},
+	{suffix: "eza", region: regionR2},
+	{suffix: "ico", region: regionR2},
+	{suffix: "ica", region: regionR2},
+	{suffix: "oso", region: regionR2},
+	{suffix: "osa", region: regionR2},
+	{suffix: "iva", region: regionR2},
+	{suffix: "ivo", region: regionR2},
+	{suffix: "ira", replacement: "ir", region: regionRV, precededBy: "e"},
+}
+
+// verbSuffixes the verb suffixes removed in RV, grouped by length from the longest
+var verbSuffixes = [][]string{
+	{"issemos", "essemos", "assemos", "ariamos", "eriamos", "iriamos"},
+	{"iremos", "eremos", "aremos", "avamos", "iramos", "eramos", "aramos", "asseis", "esseis", "isseis", "arieis", "erieis", "irieis"},
+	{"irmos", "iamos", "armos", "ermos", "areis", "ereis", "ireis", "asses", "esses", "isses", "astes", "estes", "istes", "ardes", "erdes", "irdes", "ariam", "eriam", "iriam", "arias", "erias", "irias", "assem", "essem", "issem", "aveis"},
+	{"aria", "eria", "iria", "asse", "esse", "isse", "aste", "este", "iste", "arei", "erei", "irei", "aram", "eram", "iram", "avam", "arem", "erem", "irem", "ando", "endo", "indo", "arao", "erao", "irao", "adas", "idas", "aras", "eras", "iras", "avas", "ares", "eres", "ires", "ados", "idos", "amos", "emos", "imos", "ieis"},
+	{"ada", "ida", "ara", "era", "ira", "ava", "iam", "ado", "ido", "ias", "ais", "eis"},
+	{"ia", "ei", "am", "em", "ar", "er", "ir", "as", "es", "is", "eu", "iu", "ou"},
+}
+
+// residualSuffixes the suffixes removed in RV when neither standard nor verb suffix is removed
+var residualSuffixes = []string{"os", "a", "i", "o"}
+
+// stem returns the stem of the term, it returns false if the term is not indexable
+func stem(term string) (string, bool) {
+	ct := createCT(term)
+	if !isIndexable(ct) {
+		return "", false
+	}
+	if !isStemmable(ct) {
+		return ct, true
+	}
+
+	r1 := getR1(ct)
+	r2 := getR1(r1)
+	rv := getRV(ct)
+
+	var altered bool
+	ct, altered = step1(ct, r1, r2, rv)
+	if !altered {
+		ct, altered = step2(ct, rv)
+	}
+	// the regions are recalculated after the suffixes removed
+	rv = getRV(ct)
+	if altered {
+		ct = step3(ct, rv)
+	} else {
+		ct = step4(ct, rv)
+	}
+	return step5(ct, getRV(ct)), true
+}
+
+// step1 removes the standard suffix
+func step1(ct, r1, r2, rv string) (string, bool) {
+	for _, rule := range standardSuffixes {
+		if !strings.HasSuffix(ct, rule.suffix) {
+			continue
+		}
+		var r string
+		switch rule.region {
+		case regionR1:
+			r = r1
+		case regionR2:
+			r = r2
+		case regionRV:
+			r = rv
+		}
+		if !strings.HasSuffix(r, rule.suffix) {
+			continue
+		}
+		stem := strings.TrimSuffix(ct, rule.suffix)
+		if rule.precededBy != "" && !strings.HasSuffix(stem, rule.precededBy) {
+			continue
+		}
+		return stem + rule.replacement, true
+	}
+	return ct, false
+}
+
+// step2 removes the verb suffix
+func step2(ct, rv string) (string, bool) {
+	for _, group := range verbSuffixes {
+		for _, suffix := range group {
+			if strings.HasSuffix(rv, suffix) {
+				return strings.TrimSuffix(ct, suffix), true
+			}
+		}
+	}
+	return ct, false
+}
+
+// step3 deletes suffix i if in RV and preceded by c
+func step3(ct, rv string) string {
+	if strings.HasSuffix(rv, "i") && strings.HasSuffix(ct, "ci") {
+		return strings.TrimSuffix(ct, "i")
+	}
+	return ct
+}
+
+// step4 deletes the residual suffix
+func step4(ct, rv string) string {
+	for _, suffix := range residualSuffixes {
+		if strings.HasSuffix(rv, suffix) {
+			return strings.TrimSuffix(ct, suffix)
+		}
+	}
+	return ct
+}
+
+// step5 deletes the suffix e in RV, and the u of gu or the i of ci if they are in RV
+func step5(ct, rv string) string {
+	if !strings.HasSuffix(rv, "e") {
+		return ct
+	}
+	ct = strings.TrimSuffix(ct, "e")
+	rv = strings.TrimSuffix(rv, "e")
+	if (strings.HasSuffix(ct, "gu") && strings.HasSuffix(rv, "u")) ||
+		(strings.HasSuffix(ct, "ci") && strings.HasSuffix(rv, "i")) {
+		ct = ct[:len(ct)-1]
+	}
+	return ct
+}
+
+// createCT lowercases the term, removes the accents and the leading and trailing punctuation
+func createCT(term string) string {
+	ct := changeTerm(term)
+	if len(ct) < 2 {
+		return ct
+	}
+	if strings.ContainsRune("\"'-,;.?!", rune(ct[0])) {
+		ct = ct[1:]
+	}
+	if len(ct) < 2 {
+		return ct
+	}
+	if strings.ContainsRune("\"'-,;.?!", rune(ct[len(ct)-1])) {
+		ct = ct[:len(ct)-1]
+	}
+	return ct
+}
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a",
+	"é", "e", "ê", "e",
+	"í", "i",
+	"ó", "o", "ô", "o", "õ", "o",
+	"ú", "u", "ü", "u",
+	"ç", "c",
+	"ñ", "n",
+)
+
+func changeTerm(term string) string {
+	return accentReplacer.Replace(strings.ToLower(term))
+}
+
+func isIndexable(term string) bool {
+	n := len([]rune(term))
+	return n > 2 && n < 30
+}
+
+// isStemmable discards the terms that contain non-letter characters
+func isStemmable(term string) bool {
+	for _, r := range term {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isVowel(c byte) bool {
+	return c == 'a' || c == 'e' || c == 'i' || c == 'o' || c == 'u'
+}
+
+// getR1 returns the region after the first non-vowel following a vowel, or empty if there is no such non-vowel
+func getR1(value string) string {
+	i := len(value) - 1
+	j := 0
+	for ; j < i; j++ {
+		if isVowel(value[j]) {
+			break
+		}
+	}
+	if j >= i {
+		return ""
+	}
+	for ; j < i; j++ {
+		if !isVowel(value[j]) {
+			break
+		}
+	}
+	if j >= i {
+		return ""
+	}
+	return value[j+1:]
+}
+
+// getRV returns the region RV:
+// if the second letter is a consonant, RV is the region after the next following vowel,
+// or if the first two letters are vowels, RV is the region after the next consonant,
+// and otherwise RV is the region after the third letter.
+func getRV(value string) string {
+	i := len(value) - 1
+	if i > 0 && !isVowel(value[1]) {
+		j := 2
+		for ; j < i; j++ {
+			if isVowel(value[j]) {
+				break
+			}
+		}
+		if j < i {
+			return value[j+1:]
+		}
+	}
+	if i > 1 && isVowel(value[0]) && isVowel(value[1]) {
+		j := 2
+		for ; j < i; j++ {
+			if !isVowel(value[j]) {
+				break
+			}
+		}
+		if j < i {
+			return value[j+1:]
+		}
+	}
+	if i > 2 {
+		return value[3:]
+	}
+	return ""
+}
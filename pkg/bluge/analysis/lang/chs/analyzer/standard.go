@@ -17,15 +17,15 @@ package analyzer
 
 import (
 	"github.com/blugelabs/bluge/analysis"
-	"github.com/go-ego/gse"
 
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/chs/dict"
 	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/chs/token"
 	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/chs/tokenizer"
 )
 
-func NewStandardAnalyzer(seg *gse.Segmenter) *analysis.Analyzer {
+func NewStandardAnalyzer(seg *dict.Segmenter, stop *dict.Stop) *analysis.Analyzer {
 	return &analysis.Analyzer{
 		Tokenizer:    tokenizer.NewStandardTokenizer(seg),
-		TokenFilters: []analysis.TokenFilter{token.NewStopTokenFilter(seg, nil)},
+		TokenFilters: []analysis.TokenFilter{token.NewStopTokenFilter(stop)},
 	}
 }
@@ -0,0 +1,88 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package dict
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-ego/gse"
+
+	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+)
+
+// Reloadable is a dictionary which can be reloaded from disk at runtime
+type Reloadable interface {
+	Reload() error
+}
+
+// loadBaseDict loads the embed dictionary into the segmenter according to the gse plugin config
+func loadBaseDict(seg *gse.Segmenter) {
+	if !config.Global.Plugin.GSE.Enable {
+		// load empty dict
+		_ = seg.LoadDictStr(`zinc`)
+		return
+	}
+	if strings.ToUpper(config.Global.Plugin.GSE.DictEmbed) == "BIG" {
+		_ = seg.LoadDictEmbed("zh_s")
+	} else {
+		_ = seg.LoadDictStr(_dictCHS)
+	}
+}
+
+// loadBaseStop loads the embed stop words into the segmenter according to the gse plugin config
+func loadBaseStop(seg *gse.Segmenter) {
+	if !config.Global.Plugin.GSE.EnableStop {
+		return
+	}
+	if config.Global.Plugin.GSE.Enable && strings.ToUpper(config.Global.Plugin.GSE.DictEmbed) == "BIG" {
+		_ = seg.LoadStopEmbed()
+	} else {
+		_ = seg.LoadStopStr(_dictStop)
+	}
+}
+
+// dictPath returns the file path of the dictionary, the name should be a file in the gse dictionary path
+func dictPath(option, name string) (string, error) {
+	if name == "" || filepath.IsAbs(name) || strings.Contains(name, "..") {
+		return "", errors.New(errors.ErrorTypeIllegalArgumentException, "[gse] "+option+" should be a file name in the dictionary path")
+	}
+	return filepath.Join(config.Global.Plugin.GSE.DictPath, filepath.Clean(name)), nil
+}
+
+// parseRule parses the user dictionary rule in format: word frequency [part-of-speech]
+func parseRule(rule string) (string, float64, string, error) {
+	fields := strings.Fields(rule)
+	if len(fields) < 2 || len(fields) > 3 {
+		return "", 0, "", errors.New(errors.ErrorTypeIllegalArgumentException, "[gse] user_dictionary_rules ["+rule+"] should be in format: word frequency [part-of-speech]")
+	}
+	freq, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || freq <= 0 {
+		return "", 0, "", errors.New(errors.ErrorTypeIllegalArgumentException, "[gse] user_dictionary_rules ["+rule+"] frequency should be a positive number")
+	}
+	pos := ""
+	if len(fields) == 3 {
+		pos = fields[2]
+	}
+	return fields[0], freq, pos, nil
+}
+
+// cacheKey returns the key of the dictionaries in the registry
+func cacheKey(files, words []string) string {
+	return strings.Join(files, "\x00") + "\x01" + strings.Join(words, "\x00")
+}
@@ -13,7 +13,7 @@
 * limitations under the License.
  */
 
-package chs
+package dict
 
 var _dictCHS = `的 3188252 uj
 了 883634 ul
@@ -13,7 +13,7 @@
 * limitations under the License.
  */
 
-package chs
+package dict
 
 var _dictStop = `,
 .
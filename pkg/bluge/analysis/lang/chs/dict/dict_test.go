@@ -0,0 +1,207 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package dict
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/config"
+)
+
+func TestLoadDict(t *testing.T) {
+	type args struct {
+		enable     bool
+		enableStop bool
+		embed      string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantUser bool
+		wantStop bool
+	}{
+		{
+			name: "enable=false,embed=small",
+			args: args{
+				enable:     false,
+				enableStop: false,
+				embed:      "SMALL",
+			},
+		},
+		{
+			name: "enable=true,embed=small",
+			args: args{
+				enable:     true,
+				enableStop: true,
+				embed:      "SMALL",
+			},
+			wantUser: true,
+			wantStop: true,
+		},
+		{
+			name: "enable=true,embed=big",
+			args: args{
+				enable:     true,
+				enableStop: true,
+				embed:      "BIG",
+			},
+			wantUser: true,
+			wantStop: true,
+		},
+	}
+
+	prepareDict(t)
+	defer cleanDict(t)
+	defer func() {
+		config.Global.Plugin.GSE.Enable = true
+		config.Global.Plugin.GSE.EnableStop = true
+		config.Global.Plugin.GSE.DictEmbed = "small"
+	}()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.Global.Plugin.GSE.Enable = tt.args.enable
+			config.Global.Plugin.GSE.EnableStop = tt.args.enableStop
+			config.Global.Plugin.GSE.DictEmbed = tt.args.embed
+
+			seg := &Segmenter{userDicts: []string{"user.txt", "missing.txt"}, defaults: true}
+			assert.NoError(t, seg.Reload())
+			_, _, ok := seg.Get().Find("你若安好便是晴天")
+			assert.Equal(t, tt.wantUser, ok)
+
+			stop := &Stop{stopDicts: []string{"stop.txt", "missing.txt"}, defaults: true}
+			assert.NoError(t, stop.Reload())
+			assert.Equal(t, tt.wantStop, stop.IsStop("你好"))
+			assert.Equal(t, tt.args.enableStop, stop.IsStop("，"))
+		})
+	}
+}
+
+func TestNewSegmenter(t *testing.T) {
+	prepareDict(t)
+	defer cleanDict(t)
+
+	t.Run("default", func(t *testing.T) {
+		seg, err := NewSegmenter(nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultSegmenter(), seg)
+	})
+
+	t.Run("user dictionary and rules", func(t *testing.T) {
+		seg, err := NewSegmenter([]string{"user.txt"}, []string{"灭霸响指 100 n"})
+		assert.NoError(t, err)
+		_, _, ok := seg.Get().Find("你若安好便是晴天")
+		assert.True(t, ok)
+		_, _, ok = seg.Get().Find("灭霸响指")
+		assert.True(t, ok)
+
+		// the same dictionaries share the segmenter
+		seg2, err := NewSegmenter([]string{"user.txt"}, []string{"灭霸响指 100 n"})
+		assert.NoError(t, err)
+		assert.True(t, seg == seg2)
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		err := writeFile("./data/products.txt", "无限手套 100 n\n")
+		assert.NoError(t, err)
+		seg, err := NewSegmenter([]string{"products.txt"}, nil)
+		assert.NoError(t, err)
+		_, _, ok := seg.Get().Find("复仇者联盟")
+		assert.False(t, ok)
+
+		err = writeFile("./data/products.txt", "无限手套 100 n\n复仇者联盟 100 n\n")
+		assert.NoError(t, err)
+		assert.NoError(t, seg.Reload())
+		_, _, ok = seg.Get().Find("复仇者联盟")
+		assert.True(t, ok)
+
+		// keep the loaded dictionary if reload failed
+		assert.NoError(t, os.Remove("./data/products.txt"))
+		assert.Error(t, seg.Reload())
+		_, _, ok = seg.Get().Find("复仇者联盟")
+		assert.True(t, ok)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewSegmenter([]string{"../user.txt"}, nil)
+		assert.Error(t, err)
+		_, err = NewSegmenter([]string{"/etc/passwd"}, nil)
+		assert.Error(t, err)
+		_, err = NewSegmenter([]string{"not_exists.txt"}, nil)
+		assert.Error(t, err)
+		_, err = NewSegmenter(nil, []string{"灭霸"})
+		assert.Error(t, err)
+		_, err = NewSegmenter(nil, []string{"灭霸 abc n"})
+		assert.Error(t, err)
+	})
+}
+
+func TestNewStop(t *testing.T) {
+	prepareDict(t)
+	defer cleanDict(t)
+
+	t.Run("default", func(t *testing.T) {
+		stop, err := NewStop(nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultStop(), stop)
+	})
+
+	t.Run("stop dictionary and words", func(t *testing.T) {
+		stop, err := NewStop([]string{"stop.txt"}, []string{"哈哈"})
+		assert.NoError(t, err)
+		assert.True(t, stop.IsStop("你好"))
+		assert.True(t, stop.IsStop("哈哈"))
+		assert.False(t, stop.IsStop("晴天"))
+
+		err = writeFile("./data/stop.txt", "你好\n晴天\n")
+		assert.NoError(t, err)
+		assert.NoError(t, stop.Reload())
+		assert.True(t, stop.IsStop("晴天"))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewStop([]string{"../stop.txt"}, nil)
+		assert.Error(t, err)
+		_, err = NewStop([]string{"not_exists.txt"}, nil)
+		assert.Error(t, err)
+	})
+}
+
+func prepareDict(t *testing.T) {
+	_ = os.Mkdir("data", 0755)
+	config.Global.Plugin.GSE.DictPath = "./data"
+	err := writeFile("./data/user.txt", "你若安好便是晴天 100 n\n")
+	assert.NoError(t, err)
+	err = writeFile("./data/stop.txt", "你好\n")
+	assert.NoError(t, err)
+}
+
+func cleanDict(t *testing.T) {
+	assert.NoError(t, os.RemoveAll("data"))
+}
+
+func writeFile(path string, content string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write([]byte(content))
+	return err
+}
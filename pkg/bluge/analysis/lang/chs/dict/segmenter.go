@@ -0,0 +1,133 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package dict
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-ego/gse"
+	"github.com/rs/zerolog/log"
+
+	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+// Segmenter holds a gse segmenter loaded with the base dictionary and the user dictionaries,
+// the segmenter is replaced as a whole when reloading, so the tokenizers using it never see a half loaded dictionary.
+type Segmenter struct {
+	userDicts []string
+	userRules []string
+	defaults  bool // the default segmenter skips the missing user dictionary
+	lock      sync.Mutex
+	seg       atomic.Value
+}
+
+var (
+	defaultSegmenter     *Segmenter
+	defaultSegmenterOnce sync.Once
+	segmenterLock        sync.Mutex
+	segmenters           = make(map[string]*Segmenter)
+)
+
+// DefaultSegmenter returns the segmenter with the user dictionary ${ZINC_PLUGIN_GSE_DICT_PATH}/user.txt
+func DefaultSegmenter() *Segmenter {
+	defaultSegmenterOnce.Do(func() {
+		defaultSegmenter = &Segmenter{userDicts: []string{"user.txt"}, defaults: true}
+		_ = defaultSegmenter.Reload()
+	})
+	return defaultSegmenter
+}
+
+// NewSegmenter returns the segmenter loaded with the user dictionary files and rules,
+// segmenters with the same dictionaries are shared.
+func NewSegmenter(userDicts, userRules []string) (*Segmenter, error) {
+	if len(userDicts) == 0 && len(userRules) == 0 {
+		return DefaultSegmenter(), nil
+	}
+
+	key := cacheKey(userDicts, userRules)
+	segmenterLock.Lock()
+	defer segmenterLock.Unlock()
+	if s, ok := segmenters[key]; ok {
+		return s, nil
+	}
+	s := &Segmenter{userDicts: userDicts, userRules: userRules}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	segmenters[key] = s
+	return s, nil
+}
+
+// Get returns the current gse segmenter
+func (s *Segmenter) Get() *gse.Segmenter {
+	return s.seg.Load().(*gse.Segmenter)
+}
+
+// Reload loads the dictionaries again, it keeps the current segmenter if loading failed
+func (s *Segmenter) Reload() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	seg, err := s.load()
+	if err != nil {
+		return err
+	}
+	s.seg.Store(seg)
+	return nil
+}
+
+func (s *Segmenter) load() (*gse.Segmenter, error) {
+	seg := new(gse.Segmenter)
+	loadBaseDict(seg)
+	seg.Load = true
+	seg.SkipLog = true
+	if s.defaults && !config.Global.Plugin.GSE.Enable {
+		return seg, nil
+	}
+
+	for _, name := range s.userDicts {
+		file, err := dictPath("user_dictionary", name)
+		if err != nil {
+			return nil, err
+		}
+		if s.defaults {
+			if ok, _ := zutils.IsExist(file); !ok {
+				continue
+			}
+		}
+		log.Info().Msgf("Loading  Gse user dict... %s", file)
+		if err = seg.Read(file); err != nil {
+			if s.defaults {
+				log.Error().Err(err).Msgf("Loading  Gse user dict... %s", file)
+				continue
+			}
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[gse] user_dictionary "+err.Error())
+		}
+	}
+	for _, rule := range s.userRules {
+		text, freq, pos, err := parseRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		_ = seg.AddToken(text, freq, pos)
+	}
+	seg.CalcToken()
+
+	return seg, nil
+}
@@ -0,0 +1,147 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package dict
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-ego/gse"
+	"github.com/rs/zerolog/log"
+
+	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+// Stop holds the stop words loaded from the base stop dictionary, the stop dictionary files and the inline stop words.
+type Stop struct {
+	stopDicts []string
+	stopWords []string
+	defaults  bool // the default stop words skip the missing stop dictionary
+	lock      sync.Mutex
+	words     atomic.Value
+}
+
+var (
+	defaultStop     *Stop
+	defaultStopOnce sync.Once
+	stopLock        sync.Mutex
+	stops           = make(map[string]*Stop)
+)
+
+// DefaultStop returns the stop words with the stop dictionary ${ZINC_PLUGIN_GSE_DICT_PATH}/stop.txt
+func DefaultStop() *Stop {
+	defaultStopOnce.Do(func() {
+		defaultStop = &Stop{stopDicts: []string{"stop.txt"}, defaults: true}
+		_ = defaultStop.Reload()
+	})
+	return defaultStop
+}
+
+// NewStop returns the stop words loaded with the stop dictionary files and the stop words,
+// stop words with the same dictionaries are shared.
+func NewStop(stopDicts, stopWords []string) (*Stop, error) {
+	if len(stopDicts) == 0 && len(stopWords) == 0 {
+		return DefaultStop(), nil
+	}
+
+	key := cacheKey(stopDicts, stopWords)
+	stopLock.Lock()
+	defer stopLock.Unlock()
+	if s, ok := stops[key]; ok {
+		return s, nil
+	}
+	s := &Stop{stopDicts: stopDicts, stopWords: stopWords}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	stops[key] = s
+	return s, nil
+}
+
+// IsStop checks the word is a stop word
+func (s *Stop) IsStop(word string) bool {
+	_, ok := s.words.Load().(map[string]bool)[word]
+	return ok
+}
+
+// Reload loads the stop dictionaries again, it keeps the current stop words if loading failed
+func (s *Stop) Reload() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	words, err := s.load()
+	if err != nil {
+		return err
+	}
+	s.words.Store(words)
+	return nil
+}
+
+func (s *Stop) load() (map[string]bool, error) {
+	seg := new(gse.Segmenter)
+	seg.StopWordMap = make(map[string]bool)
+	loadBaseStop(seg)
+	words := seg.StopWordMap
+	if s.defaults && !config.Global.Plugin.GSE.Enable {
+		return words, nil
+	}
+
+	for _, name := range s.stopDicts {
+		file, err := dictPath("stop_dictionary", name)
+		if err != nil {
+			return nil, err
+		}
+		if s.defaults {
+			if ok, _ := zutils.IsExist(file); !ok {
+				continue
+			}
+		}
+		log.Info().Msgf("Loading  Gse user stop... %s", file)
+		if err = readStop(file, words); err != nil {
+			if s.defaults {
+				log.Error().Err(err).Msgf("Loading  Gse user stop... %s", file)
+				continue
+			}
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[gse] stop_dictionary "+err.Error())
+		}
+	}
+	for _, word := range s.stopWords {
+		words[word] = true
+	}
+
+	return words, nil
+}
+
+func readStop(file string, words map[string]bool) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" {
+			words[word] = true
+		}
+	}
+	return scanner.Err()
+}
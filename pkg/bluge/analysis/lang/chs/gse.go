@@ -16,87 +16,29 @@
 package chs
 
 import (
-	"strings"
-
 	"github.com/blugelabs/bluge/analysis"
-	"github.com/go-ego/gse"
-	"github.com/rs/zerolog/log"
 
 	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/chs/analyzer"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/chs/dict"
 	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/chs/token"
 	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/chs/tokenizer"
-	"github.com/zincsearch/zincsearch/pkg/config"
-	"github.com/zincsearch/zincsearch/pkg/zutils"
 )
 
 func NewGseStandardAnalyzer() *analysis.Analyzer {
-	return analyzer.NewStandardAnalyzer(seg)
+	return analyzer.NewStandardAnalyzer(dict.DefaultSegmenter(), dict.DefaultStop())
 }
 
 func NewGseSearchAnalyzer() *analysis.Analyzer {
-	return analyzer.NewSearchAnalyzer(seg)
+	return analyzer.NewSearchAnalyzer(dict.DefaultSegmenter(), dict.DefaultStop())
 }
 
 func NewGseStandardTokenizer() analysis.Tokenizer {
-	return tokenizer.NewStandardTokenizer(seg)
+	return tokenizer.NewStandardTokenizer(dict.DefaultSegmenter())
 }
 func NewGseSearchTokenizer() analysis.Tokenizer {
-	return tokenizer.NewSearchTokenizer(seg)
+	return tokenizer.NewSearchTokenizer(dict.DefaultSegmenter())
 }
 
 func NewGseStopTokenFilter() analysis.TokenFilter {
-	return token.NewStopTokenFilter(seg, nil)
-}
-
-var seg *gse.Segmenter
-
-func init() {
-	seg = new(gse.Segmenter)
-	enable := config.Global.Plugin.GSE.Enable         // true / false
-	enableStop := config.Global.Plugin.GSE.EnableStop // true / false
-	embed := config.Global.Plugin.GSE.DictEmbed       // small / big
-	embed = strings.ToUpper(embed)
-	loadDict(enable, enableStop, embed)
-}
-
-func loadDict(enable, enableStop bool, embed string) {
-	if enable {
-		// load default dict
-		if embed == "BIG" {
-			_ = seg.LoadDictEmbed("zh_s")
-			if enableStop {
-				_ = seg.LoadStopEmbed()
-			}
-		} else {
-			_ = seg.LoadDictStr(_dictCHS)
-			if enableStop {
-				_ = seg.LoadStopStr(_dictStop)
-			}
-		}
-	} else {
-		// load empty dict
-		_ = seg.LoadDictStr(`zinc`)
-		if enableStop {
-			_ = seg.LoadStopStr(_dictStop)
-		}
-	}
-
-	seg.Load = true
-	seg.SkipLog = true
-	if !enable {
-		return
-	}
-
-	// load user dict
-	dataPath := config.Global.Plugin.GSE.DictPath
-	userDict := dataPath + "/user.txt"
-	log.Info().Msgf("Loading  Gse user dict... %s", userDict)
-	if ok, _ := zutils.IsExist(userDict); ok {
-		_ = seg.LoadDict(userDict)
-	}
-	stopDict := dataPath + "/stop.txt"
-	log.Info().Msgf("Loading  Gse user stop... %s", stopDict)
-	if ok, _ := zutils.IsExist(stopDict); ok {
-		_ = seg.LoadStop(stopDict)
-	}
+	return token.NewStopTokenFilter(dict.DefaultStop())
 }
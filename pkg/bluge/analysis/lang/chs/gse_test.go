@@ -26,60 +26,10 @@ import (
 	"github.com/zincsearch/zincsearch/pkg/config"
 )
 
-func TestLoadDict(t *testing.T) {
-	type args struct {
-		enable     bool
-		enableStop bool
-		embed      string
-	}
-	tests := []struct {
-		name string
-		args args
-	}{
-		{
-			name: "enable=false,embed=small",
-			args: args{
-				enable:     false,
-				enableStop: false,
-				embed:      "SMALL",
-			},
-		},
-		{
-			name: "enable=true,embed=small",
-			args: args{
-				enable:     true,
-				enableStop: true,
-				embed:      "SMALL",
-			},
-		},
-		{
-			name: "enable=true,embed=big",
-			args: args{
-				enable:     true,
-				enableStop: true,
-				embed:      "BIG",
-			},
-		},
-	}
-
-	t.Run("prepare dict", func(t *testing.T) {
-		_ = os.Mkdir("data", 0755)
-		config.Global.Plugin.GSE.DictPath = "./data"
-		err := writeFile("./data/user.txt", "你若安好便是晴天 100 n\n")
-		assert.NoError(t, err)
-		err = writeFile("./data/stop.txt", "你好\n")
-		assert.NoError(t, err)
-	})
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			loadDict(tt.args.enable, tt.args.enableStop, tt.args.embed)
-		})
-	}
-
-	t.Run("clean dict", func(t *testing.T) {
-		os.RemoveAll("data")
-	})
+func TestMain(m *testing.M) {
+	config.Global.Plugin.GSE.Enable = true
+	config.Global.Plugin.GSE.DictEmbed = "big"
+	os.Exit(m.Run())
 }
 
 func TestNewGseStandardAnalyzer(t *testing.T) {
@@ -188,16 +138,6 @@ func TestNewGseStopTokenFilter(t *testing.T) {
 	}
 }
 
-func writeFile(path string, content string) error {
-	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-	_, err = f.Write([]byte(content))
-	return err
-}
-
 func collectToken(tokens analysis.TokenStream) string {
 	str := make([]string, 0, len(tokens))
 	for _, token := range tokens {
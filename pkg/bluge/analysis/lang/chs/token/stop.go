@@ -17,26 +17,22 @@ package token
 
 import (
 	"github.com/blugelabs/bluge/analysis"
-	"github.com/go-ego/gse"
+
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/chs/dict"
 )
 
 type StopTokenFilter struct {
-	seg *gse.Segmenter
+	stop *dict.Stop
 }
 
-func NewStopTokenFilter(seg *gse.Segmenter, stopwords []string) *StopTokenFilter {
-	if len(stopwords) > 0 {
-		for _, word := range stopwords {
-			seg.AddStop(word)
-		}
-	}
-	return &StopTokenFilter{seg}
+func NewStopTokenFilter(stop *dict.Stop) *StopTokenFilter {
+	return &StopTokenFilter{stop}
 }
 
 func (f *StopTokenFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
 	var j, skipped int
 	for _, token := range input {
-		if !f.seg.IsStop(string(token.Term)) {
+		if !f.stop.IsStop(string(token.Term)) {
 			token.PositionIncr += skipped
 			skipped = 0
 			input[j] = token
@@ -48,3 +44,7 @@ func (f *StopTokenFilter) Filter(input analysis.TokenStream) analysis.TokenStrea
 
 	return input[:j]
 }
+
+func (f *StopTokenFilter) Dictionary() dict.Reloadable {
+	return f.stop
+}
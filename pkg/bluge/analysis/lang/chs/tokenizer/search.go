@@ -17,24 +17,25 @@ package tokenizer
 
 import (
 	"github.com/blugelabs/bluge/analysis"
-	"github.com/go-ego/gse"
 
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/chs/dict"
 	"github.com/zincsearch/zincsearch/pkg/config"
 )
 
 type SearchTokenizer struct {
-	seg *gse.Segmenter
+	seg *dict.Segmenter
 }
 
-func NewSearchTokenizer(seg *gse.Segmenter) *SearchTokenizer {
+func NewSearchTokenizer(seg *dict.Segmenter) *SearchTokenizer {
 	return &SearchTokenizer{seg}
 }
 
 func (t *SearchTokenizer) Tokenize(input []byte) analysis.TokenStream {
 	result := make(analysis.TokenStream, 0, len(input))
 	text := string(input)
-	search := t.seg.CutSearch(text, config.Global.Plugin.GSE.EnableHMM)
-	tokens := t.seg.Analyze(search, text)
+	seg := t.seg.Get()
+	search := seg.CutSearch(text, config.Global.Plugin.GSE.EnableHMM)
+	tokens := seg.Analyze(search, text)
 	var start, positionIncr int
 	for _, token := range tokens {
 		positionIncr = 1
@@ -65,3 +66,7 @@ func (t *SearchTokenizer) Tokenize(input []byte) analysis.TokenStream {
 	}
 	return result
 }
+
+func (t *SearchTokenizer) Dictionary() dict.Reloadable {
+	return t.seg
+}
@@ -17,20 +17,21 @@ package tokenizer
 
 import (
 	"github.com/blugelabs/bluge/analysis"
-	"github.com/go-ego/gse"
+
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/chs/dict"
 )
 
 type StandardTokenizer struct {
-	seg *gse.Segmenter
+	seg *dict.Segmenter
 }
 
-func NewStandardTokenizer(seg *gse.Segmenter) *StandardTokenizer {
+func NewStandardTokenizer(seg *dict.Segmenter) *StandardTokenizer {
 	return &StandardTokenizer{seg}
 }
 
 func (t *StandardTokenizer) Tokenize(input []byte) analysis.TokenStream {
 	result := make(analysis.TokenStream, 0, len(input))
-	segments := t.seg.Segment(input)
+	segments := t.seg.Get().Segment(input)
 	for _, seg := range segments {
 		typ := analysis.Ideographic
 		alphaNumeric := true
@@ -53,3 +54,7 @@ func (t *StandardTokenizer) Tokenize(input []byte) analysis.TokenStream {
 	}
 	return result
 }
+
+func (t *StandardTokenizer) Dictionary() dict.Reloadable {
+	return t.seg
+}
@@ -0,0 +1,106 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package et
+
+import (
+	"bytes"
+
+	"github.com/blugelabs/bluge/analysis"
+)
+
+// EstonianStemmerFilter is a light stemmer for Estonian, it removes the case ending,
+// the plural marker and the stem vowel, so the inflected forms of a noun share the same stem.
+type EstonianStemmerFilter struct {
+}
+
+func StemmerFilter() *EstonianStemmerFilter {
+	return &EstonianStemmerFilter{}
+}
+
+func (s *EstonianStemmerFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		// if not protected keyword, stem it
+		if !token.KeyWord {
+			runes := bytes.Runes(token.Term)
+			token.Term = analysis.BuildTermFromRunes(stem(runes))
+		}
+	}
+	return input
+}
+
+// minStemLength the minimum length of the stem after a suffix removed
+const minStemLength = 3
+
+// caseEndings the endings of the cases attached to the genitive stem, longer first:
+// illative, elative, allative, ablative, translative, terminative, essive, abessive, comitative, inessive, adessive
+var caseEndings = [][]rune{
+	[]rune("sse"), []rune("st"), []rune("le"), []rune("lt"), []rune("ks"),
+	[]rune("ni"), []rune("na"), []rune("ta"), []rune("ga"), []rune("s"), []rune("l"),
+}
+
+// pluralMarkers the plural markers: genitive plural -de/-te and nominative plural -d
+var pluralMarkers = [][]rune{
+	[]rune("de"), []rune("te"), []rune("d"),
+}
+
+func stem(s []rune) []rune {
+	s = removeEnding(s, caseEndings)
+	s = removeEnding(s, pluralMarkers)
+	// the stem vowel
+	if len(s) > minStemLength && isStemVowel(s[len(s)-1]) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// removeEnding removes the first matched ending which follows a vowel, the endings are attached to the vowel stems
+func removeEnding(s []rune, endings [][]rune) []rune {
+	for _, ending := range endings {
+		n := len(s) - len(ending)
+		if n >= minStemLength && isVowel(s[n-1]) && endsWith(s, ending) {
+			return s[:n]
+		}
+	}
+	return s
+}
+
+func isStemVowel(r rune) bool {
+	switch r {
+	case 'a', 'e', 'i', 'u':
+		return true
+	}
+	return false
+}
+
+func isVowel(r rune) bool {
+	switch r {
+	case 'a', 'e', 'i', 'o', 'u', 'õ', 'ä', 'ö', 'ü':
+		return true
+	}
+	return false
+}
+
+func endsWith(s, suffix []rune) bool {
+	if len(suffix) > len(s) {
+		return false
+	}
+	for i := range suffix {
+		if s[len(s)-len(suffix)+i] != suffix[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ja
+
+import (
+	"github.com/blugelabs/bluge/analysis"
+	"github.com/blugelabs/bluge/analysis/lang/cjk"
+	"github.com/blugelabs/bluge/analysis/token"
+	"github.com/rs/zerolog/log"
+
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/morph"
+)
+
+// Analyzer the kuromoji analyzer, it uses the search mode and the default user dictionary
+func Analyzer() *analysis.Analyzer {
+	return morph.Wrap(&analysis.Analyzer{
+		Tokenizer: DefaultTokenizer(),
+		TokenFilters: []analysis.TokenFilter{
+			BaseFormFilter(),
+			PartOfSpeechFilter(nil),
+			cjk.NewWidthFilter(),
+			StopWordsFilter(),
+			StemmerFilter(DefaultStemmerMinimumLength),
+			token.NewLowerCaseFilter(),
+		},
+	})
+}
+
+// DefaultTokenizer returns the tokenizer in search mode with the default user dictionary
+func DefaultTokenizer() *Tokenizer {
+	t, err := NewTokenizer(ModeSearch, true, DefaultUserDict())
+	if err != nil {
+		// only fails if the dictionary is invalid
+		log.Error().Err(err).Msg("create kuromoji tokenizer failed")
+		t, _ = NewTokenizer(ModeSearch, true, nil)
+	}
+	return t
+}
@@ -0,0 +1,104 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ja
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ikawaha/kagome-dict/dict"
+	"github.com/ikawaha/kagome-dict/ipa"
+	"github.com/rs/zerolog/log"
+
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/morph"
+	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+)
+
+// DefaultUserDictFile the user dictionary in the dictionary path used by the build-in analyzer and tokenizer
+const DefaultUserDictFile = "user.txt"
+
+var defaultUserDict struct {
+	once sync.Once
+	dict *dict.UserDict
+}
+
+// Dict returns the embedded IPA dictionary, it's loaded when first used
+func Dict() *dict.Dict {
+	return ipa.Dict()
+}
+
+// DefaultUserDict returns the user dictionary ${ZINC_PLUGIN_KUROMOJI_DICT_PATH}/user.txt, it returns nil if not exists
+func DefaultUserDict() *dict.UserDict {
+	defaultUserDict.once.Do(func() {
+		file := filepath.Join(config.Global.Plugin.Kuromoji.DictPath, DefaultUserDictFile)
+		if _, err := os.Stat(file); err != nil {
+			return
+		}
+		log.Info().Msgf("Loading  Kuromoji user dict... %s", file)
+		var err error
+		if defaultUserDict.dict, err = LoadUserDict(DefaultUserDictFile); err != nil {
+			log.Error().Err(err).Str("file", file).Msg("load kuromoji user dict failed")
+		}
+	})
+	return defaultUserDict.dict
+}
+
+// LoadUserDict loads the user dictionary file, the name is relative to the dictionary path
+func LoadUserDict(name string) (*dict.UserDict, error) {
+	file, err := userDictPath(config.Global.Plugin.Kuromoji.DictPath, name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[kuromoji] user_dictionary "+err.Error())
+	}
+	defer f.Close()
+	records, err := dict.NewUserDicRecords(f)
+	if err != nil {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[kuromoji] user_dictionary "+err.Error())
+	}
+	return morph.NewUserDict(records)
+}
+
+// ParseUserDictRules parses the rules in format: surface,segmentation,readings,part-of-speech
+func ParseUserDictRules(rules []string) (*dict.UserDict, error) {
+	records, err := dict.NewUserDicRecords(strings.NewReader(strings.Join(rules, "\n")))
+	if err != nil {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[kuromoji] user_dictionary_rules "+err.Error())
+	}
+	for _, r := range records {
+		if strings.Join(r.Tokens, "") != r.Text {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[kuromoji] user_dictionary_rules the segmentation of ["+r.Text+"] doesn't match the surface")
+		}
+	}
+	udict, err := morph.NewUserDict(records)
+	if err != nil {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[kuromoji] user_dictionary_rules "+err.Error())
+	}
+	return udict, nil
+}
+
+// userDictPath returns the file in the dictionary path, the name can't go out of the path
+func userDictPath(root, name string) (string, error) {
+	if name == "" || filepath.IsAbs(name) || strings.Contains(name, "..") {
+		return "", errors.New(errors.ErrorTypeIllegalArgumentException, "[kuromoji] user_dictionary should be a file name in the dictionary path")
+	}
+	return filepath.Join(root, filepath.Clean(name)), nil
+}
@@ -0,0 +1,121 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ja
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/blugelabs/bluge/analysis"
+	"github.com/blugelabs/bluge/analysis/token"
+
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/morph"
+)
+
+// DefaultStopTags the part-of-speech tags removed by default, same as the stoptags.txt of kuromoji
+var DefaultStopTags = []string{
+	"接続詞",
+	"助詞",
+	"助詞-格助詞",
+	"助詞-格助詞-一般",
+	"助詞-格助詞-引用",
+	"助詞-格助詞-連語",
+	"助詞-接続助詞",
+	"助詞-係助詞",
+	"助詞-副助詞",
+	"助詞-間投助詞",
+	"助詞-並立助詞",
+	"助詞-終助詞",
+	"助詞-副助詞／並立助詞／終助詞",
+	"助詞-連体化",
+	"助詞-副詞化",
+	"助詞-特殊",
+	"助動詞",
+	"記号",
+	"記号-一般",
+	"記号-読点",
+	"記号-句点",
+	"記号-空白",
+	"記号-括弧開",
+	"記号-括弧閉",
+	"その他-間投",
+	"フィラー",
+	"非言語音",
+}
+
+// DefaultStemmerMinimumLength the minimum length of katakana term to remove the prolonged sound mark
+const DefaultStemmerMinimumLength = 4
+
+// PartOfSpeechFilter removes the tokens by part-of-speech tags, it uses the default tags if tags is empty
+func PartOfSpeechFilter(tags []string) analysis.TokenFilter {
+	if len(tags) == 0 {
+		tags = DefaultStopTags
+	}
+	return morph.NewStopTagsFilter(tags, nil)
+}
+
+// BaseFormFilter replaces the inflected term with the base form, e.g. 飲み to 飲む
+func BaseFormFilter() analysis.TokenFilter {
+	return morph.NewBaseFormFilter()
+}
+
+// ReadingFormFilter replaces the term with the katakana reading or romaji
+func ReadingFormFilter(useRomaji bool) analysis.TokenFilter {
+	if useRomaji {
+		return morph.NewReadingFormFilter(ToRomaji)
+	}
+	return morph.NewReadingFormFilter(nil)
+}
+
+// StopWordsFilter removes the Japanese stop words
+func StopWordsFilter() analysis.TokenFilter {
+	return token.NewStopTokensFilter(StopWords())
+}
+
+// KatakanaStemmer removes the trailing prolonged sound mark of katakana terms, e.g. コピー to コピ
+type KatakanaStemmer struct {
+	minimumLength int
+}
+
+func StemmerFilter(minimumLength int) analysis.TokenFilter {
+	if minimumLength <= 0 {
+		minimumLength = DefaultStemmerMinimumLength
+	}
+	return &KatakanaStemmer{minimumLength: minimumLength}
+}
+
+func (f *KatakanaStemmer) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		if token.KeyWord || utf8.RuneCount(token.Term) < f.minimumLength {
+			continue
+		}
+		last, size := utf8.DecodeLastRune(token.Term)
+		if last != 'ー' || !isKatakana(token.Term[:len(token.Term)-size]) {
+			continue
+		}
+		token.Term = token.Term[:len(token.Term)-size]
+	}
+	return input
+}
+
+func isKatakana(term []byte) bool {
+	for _, r := range string(term) {
+		if !unicode.Is(unicode.Katakana, r) && r != 'ー' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,155 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ja
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blugelabs/bluge/analysis"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/morph"
+)
+
+func TestAnalyzer(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "default",
+			text: "関西国際空港で飲みました。コピーを取る",
+			want: "[関西 国際 空港 飲む コピー 取る]",
+		},
+		{
+			name: "stemmer",
+			text: "サーバーとプリンター",
+			want: "[サーバ プリンタ]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Analyzer().Analyze([]byte(tt.text))
+			assert.Equal(t, tt.want, collectToken(got))
+		})
+	}
+}
+
+func TestNewTokenizer(t *testing.T) {
+	tests := []struct {
+		name               string
+		mode               string
+		discardPunctuation bool
+		rules              []string
+		text               string
+		want               string
+		wantErr            bool
+	}{
+		{
+			name:               "normal",
+			mode:               "normal",
+			discardPunctuation: true,
+			text:               "関西国際空港。",
+			want:               "[関西国際空港]",
+		},
+		{
+			name:               "search",
+			mode:               "search",
+			discardPunctuation: true,
+			text:               "関西国際空港。",
+			want:               "[関西 国際 空港]",
+		},
+		{
+			name:               "keep punctuation",
+			mode:               "search",
+			discardPunctuation: false,
+			text:               "関西国際空港。",
+			want:               "[関西 国際 空港 。]",
+		},
+		{
+			name:               "user dictionary",
+			mode:               "search",
+			discardPunctuation: true,
+			rules:              []string{"東京スカイツリー,東京 スカイツリー,トウキョウ スカイツリー,カスタム名詞"},
+			text:               "東京スカイツリーへ",
+			want:               "[東京 スカイツリー へ]",
+		},
+		{
+			name:    "invalid mode",
+			mode:    "unknown",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userDict, err := ParseUserDictRules(tt.rules)
+			assert.NoError(t, err)
+			tokenizer, err := NewTokenizer(tt.mode, tt.discardPunctuation, userDict)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, collectToken(tokenizer.Tokenize([]byte(tt.text))))
+		})
+	}
+}
+
+func TestParseUserDictRules(t *testing.T) {
+	_, err := ParseUserDictRules([]string{"東京スカイツリー,東京 ツリー,トウキョウ ツリー,カスタム名詞"})
+	assert.Error(t, err)
+}
+
+func TestReadingFormFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		useRomaji bool
+		text      string
+		want      string
+	}{
+		{
+			name: "katakana",
+			text: "東京の喫茶店",
+			want: "[トウキョウ ノ キッサテン]",
+		},
+		{
+			name:      "romaji",
+			useRomaji: true,
+			text:      "東京の喫茶店",
+			want:      "[toukyou no kissaten]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, morphemes := DefaultTokenizer().TokenizeMorphemes([]byte(tt.text))
+			got := morph.FilterTokens(ReadingFormFilter(tt.useRomaji), tokens, morphemes)
+			assert.Equal(t, tt.want, collectToken(got))
+		})
+	}
+}
+
+func collectToken(tokens analysis.TokenStream) string {
+	str := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		str = append(str, string(token.Term))
+	}
+	return "[" + strings.Join(str, " ") + "]"
+}
@@ -0,0 +1,95 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ja
+
+import (
+	"strings"
+)
+
+// romajiDigraphs the katakana combined with a small ya, yu, yo or vowel
+var romajiDigraphs = map[string]string{
+	"キャ": "kya", "キュ": "kyu", "キョ": "kyo", "ギャ": "gya", "ギュ": "gyu", "ギョ": "gyo",
+	"シャ": "sha", "シュ": "shu", "ショ": "sho", "シェ": "she", "ジャ": "ja", "ジュ": "ju", "ジョ": "jo", "ジェ": "je",
+	"チャ": "cha", "チュ": "chu", "チョ": "cho", "チェ": "che", "ヂャ": "ja", "ヂュ": "ju", "ヂョ": "jo",
+	"ニャ": "nya", "ニュ": "nyu", "ニョ": "nyo", "ヒャ": "hya", "ヒュ": "hyu", "ヒョ": "hyo",
+	"ビャ": "bya", "ビュ": "byu", "ビョ": "byo", "ピャ": "pya", "ピュ": "pyu", "ピョ": "pyo",
+	"ミャ": "mya", "ミュ": "myu", "ミョ": "myo", "リャ": "rya", "リュ": "ryu", "リョ": "ryo",
+	"ティ": "ti", "ディ": "di", "トゥ": "tu", "ドゥ": "du", "ツァ": "tsa", "ツェ": "tse", "ツォ": "tso",
+	"ファ": "fa", "フィ": "fi", "フェ": "fe", "フォ": "fo", "ウィ": "wi", "ウェ": "we", "ウォ": "wo",
+	"ヴァ": "va", "ヴィ": "vi", "ヴェ": "ve", "ヴォ": "vo",
+}
+
+// romajiMonographs the single katakana
+var romajiMonographs = map[rune]string{
+	'ア': "a", 'イ': "i", 'ウ': "u", 'エ': "e", 'オ': "o",
+	'カ': "ka", 'キ': "ki", 'ク': "ku", 'ケ': "ke", 'コ': "ko",
+	'ガ': "ga", 'ギ': "gi", 'グ': "gu", 'ゲ': "ge", 'ゴ': "go",
+	'サ': "sa", 'シ': "shi", 'ス': "su", 'セ': "se", 'ソ': "so",
+	'ザ': "za", 'ジ': "ji", 'ズ': "zu", 'ゼ': "ze", 'ゾ': "zo",
+	'タ': "ta", 'チ': "chi", 'ツ': "tsu", 'テ': "te", 'ト': "to",
+	'ダ': "da", 'ヂ': "ji", 'ヅ': "zu", 'デ': "de", 'ド': "do",
+	'ナ': "na", 'ニ': "ni", 'ヌ': "nu", 'ネ': "ne", 'ノ': "no",
+	'ハ': "ha", 'ヒ': "hi", 'フ': "fu", 'ヘ': "he", 'ホ': "ho",
+	'バ': "ba", 'ビ': "bi", 'ブ': "bu", 'ベ': "be", 'ボ': "bo",
+	'パ': "pa", 'ピ': "pi", 'プ': "pu", 'ペ': "pe", 'ポ': "po",
+	'マ': "ma", 'ミ': "mi", 'ム': "mu", 'メ': "me", 'モ': "mo",
+	'ヤ': "ya", 'ユ': "yu", 'ヨ': "yo",
+	'ラ': "ra", 'リ': "ri", 'ル': "ru", 'レ': "re", 'ロ': "ro",
+	'ワ': "wa", 'ヰ': "i", 'ヱ': "e", 'ヲ': "o", 'ン': "n", 'ヴ': "vu",
+	'ァ': "a", 'ィ': "i", 'ゥ': "u", 'ェ': "e", 'ォ': "o", 'ャ': "ya", 'ュ': "yu", 'ョ': "yo", 'ヮ': "wa",
+}
+
+// ToRomaji converts the katakana reading to the Hepburn romanization,
+// the small tsu doubles the next consonant and the prolonged sound mark is dropped.
+func ToRomaji(katakana string) string {
+	runes := []rune(katakana)
+	var b strings.Builder
+	double := false
+	for i := 0; i < len(runes); i++ {
+		var syllable string
+		if i+1 < len(runes) {
+			if s, ok := romajiDigraphs[string(runes[i:i+2])]; ok {
+				syllable = s
+				i++
+			}
+		}
+		if syllable == "" {
+			switch runes[i] {
+			case 'ッ':
+				double = true
+				continue
+			case 'ー':
+				continue
+			}
+			s, ok := romajiMonographs[runes[i]]
+			if !ok {
+				// keeps the character can't be converted
+				s = string(runes[i])
+			}
+			syllable = s
+		}
+		if double {
+			if strings.HasPrefix(syllable, "ch") {
+				b.WriteByte('t')
+			} else if c := syllable[0]; c != 'a' && c != 'i' && c != 'u' && c != 'e' && c != 'o' && c < 0x80 {
+				b.WriteByte(c)
+			}
+			double = false
+		}
+		b.WriteString(syllable)
+	}
+	return b.String()
+}
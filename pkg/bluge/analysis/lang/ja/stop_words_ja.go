@@ -0,0 +1,139 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ja
+
+import (
+	"github.com/blugelabs/bluge/analysis"
+)
+
+// this content was obtained from:
+// lucene/analysis/kuromoji/src/resources/org/apache/lucene/analysis/ja/stopwords.txt
+
+var StopWordsBytes = []byte(`# This file defines a stopword set for Japanese.
+あの
+いう
+いる
+う
+うち
+え
+お
+および
+おり
+か
+かつて
+から
+が
+き
+ここ
+こと
+この
+これ
+これら
+さ
+さらに
+し
+しかし
+する
+ず
+せ
+せる
+そして
+その
+その他
+その後
+それ
+それぞれ
+た
+ただし
+たち
+ため
+たり
+だ
+だっ
+つ
+て
+で
+でき
+できる
+です
+では
+でも
+と
+という
+といった
+とき
+ところ
+として
+とともに
+とも
+と共に
+な
+ない
+なお
+なかっ
+ながら
+なく
+なっ
+など
+なら
+なり
+なる
+に
+において
+における
+について
+にて
+によって
+により
+による
+に対して
+に対する
+に関する
+の
+ので
+のみ
+は
+ば
+へ
+ほか
+ほとんど
+ほど
+ます
+また
+または
+まで
+も
+もの
+ものの
+や
+よう
+より
+ら
+られ
+られる
+れ
+れる
+を
+ん
+及び
+特に
+`)
+
+func StopWords() analysis.TokenMap {
+	rv := analysis.NewTokenMap()
+	rv.LoadBytes(StopWordsBytes)
+	return rv
+}
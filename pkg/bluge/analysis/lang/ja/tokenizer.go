@@ -0,0 +1,134 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ja
+
+import (
+	"strings"
+
+	"github.com/blugelabs/bluge/analysis"
+	"github.com/ikawaha/kagome-dict/dict"
+	kagome "github.com/ikawaha/kagome/v2/tokenizer"
+
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/morph"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+)
+
+const (
+	ModeNormal   = "normal"   // regular segmentation
+	ModeSearch   = "search"   // splits the long compound nouns also, the default mode
+	ModeExtended = "extended" // splits the unknown words into unigrams also
+)
+
+// Tokenizer the kuromoji style Japanese tokenizer, it returns the part-of-speech, base form and reading of tokens as morphemes
+type Tokenizer struct {
+	tokenizer          *kagome.Tokenizer
+	mode               kagome.TokenizeMode
+	discardPunctuation bool
+}
+
+// NewTokenizer creates the tokenizer with the IPA dictionary, userDict can be nil
+func NewTokenizer(mode string, discardPunctuation bool, userDict *dict.UserDict) (*Tokenizer, error) {
+	t := &Tokenizer{discardPunctuation: discardPunctuation}
+	switch strings.ToLower(mode) {
+	case ModeNormal:
+		t.mode = kagome.Normal
+	case ModeSearch, "":
+		t.mode = kagome.Search
+	case ModeExtended:
+		t.mode = kagome.Extended
+	default:
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[kuromoji] unknown mode ["+mode+"]")
+	}
+
+	opts := []kagome.Option{kagome.OmitBosEos()}
+	if userDict != nil {
+		opts = append(opts, kagome.UserDict(userDict))
+	}
+	var err error
+	if t.tokenizer, err = kagome.New(Dict(), opts...); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Tokenizer) Tokenize(input []byte) analysis.TokenStream {
+	result, _ := t.TokenizeMorphemes(input)
+	return result
+}
+
+// TokenizeMorphemes returns the tokens and their morphemes for the morph.Chain
+func (t *Tokenizer) TokenizeMorphemes(input []byte) (analysis.TokenStream, morph.Morphemes) {
+	tokens := t.tokenizer.Analyze(string(input), t.mode)
+	result := make(analysis.TokenStream, 0, len(tokens))
+	morphemes := make(morph.Morphemes, len(tokens))
+	for _, token := range tokens {
+		if token.Class == kagome.DUMMY || token.Surface == "" {
+			continue
+		}
+		if t.discardPunctuation && morph.IsPunctuation(token.Surface) {
+			continue
+		}
+
+		if token.Class == kagome.USER {
+			result = appendUserToken(result, morphemes, token)
+			continue
+		}
+
+		m := morph.Morpheme{
+			POS:      morph.JoinPOS(token.POS()),
+			BaseForm: morph.Feature(token.BaseForm()),
+			Reading:  morph.Feature(token.Reading()),
+		}
+		if m.BaseForm == token.Surface {
+			m.BaseForm = ""
+		}
+		result = append(result, &analysis.Token{
+			Term:         []byte(token.Surface),
+			Start:        token.Position,
+			End:          token.Position + len(token.Surface),
+			PositionIncr: 1,
+			Type:         analysis.Ideographic,
+		})
+		morphemes[result[len(result)-1]] = m
+	}
+	return result, morphemes
+}
+
+// appendUserToken appends the segments of the user dictionary word
+func appendUserToken(result analysis.TokenStream, morphemes morph.Morphemes, token kagome.Token) analysis.TokenStream {
+	pos, _ := token.FeatureAt(0)
+	segments, _ := token.FeatureAt(1)
+	readings, _ := token.FeatureAt(2)
+	words := strings.Split(segments, "/")
+	yomi := strings.Split(readings, "/")
+	start := token.Position
+	for i, word := range words {
+		m := morph.Morpheme{POS: pos}
+		if i < len(yomi) {
+			m.Reading = yomi[i]
+		}
+		result = append(result, &analysis.Token{
+			Term:         []byte(word),
+			Start:        start,
+			End:          start + len(word),
+			PositionIncr: 1,
+			Type:         analysis.Ideographic,
+		})
+		morphemes[result[len(result)-1]] = m
+		start += len(word)
+	}
+	return result
+}
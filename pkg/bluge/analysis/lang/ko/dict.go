@@ -0,0 +1,116 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ko
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	kodict "github.com/ikawaha/kagome-dict-ko"
+	"github.com/ikawaha/kagome-dict/dict"
+	"github.com/rs/zerolog/log"
+
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/morph"
+	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+)
+
+// DefaultUserDictFile the user dictionary in the dictionary path used by the build-in analyzer and tokenizer
+const DefaultUserDictFile = "user.txt"
+
+// userDictPOS the part-of-speech of the words in user dictionary, they are general nouns
+const userDictPOS = "NNG"
+
+var defaultUserDict struct {
+	once sync.Once
+	dict *dict.UserDict
+}
+
+// Dict returns the embedded mecab-ko-dic dictionary, it's loaded when first used
+func Dict() *dict.Dict {
+	return kodict.Dict()
+}
+
+// DefaultUserDict returns the user dictionary ${ZINC_PLUGIN_NORI_DICT_PATH}/user.txt, it returns nil if not exists
+func DefaultUserDict() *dict.UserDict {
+	defaultUserDict.once.Do(func() {
+		file := filepath.Join(config.Global.Plugin.Nori.DictPath, DefaultUserDictFile)
+		if _, err := os.Stat(file); err != nil {
+			return
+		}
+		log.Info().Msgf("Loading  Nori user dict... %s", file)
+		var err error
+		if defaultUserDict.dict, err = LoadUserDict(DefaultUserDictFile); err != nil {
+			log.Error().Err(err).Str("file", file).Msg("load nori user dict failed")
+		}
+	})
+	return defaultUserDict.dict
+}
+
+// LoadUserDict loads the user dictionary file, the name is relative to the dictionary path
+func LoadUserDict(name string) (*dict.UserDict, error) {
+	if name == "" || filepath.IsAbs(name) || strings.Contains(name, "..") {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[nori] user_dictionary should be a file name in the dictionary path")
+	}
+	f, err := os.Open(filepath.Join(config.Global.Plugin.Nori.DictPath, filepath.Clean(name)))
+	if err != nil {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[nori] user_dictionary "+err.Error())
+	}
+	defer f.Close()
+
+	var rules []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		rules = append(rules, scanner.Text())
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[nori] user_dictionary "+err.Error())
+	}
+	return ParseUserDictRules(rules)
+}
+
+// ParseUserDictRules parses the rules in format: word [segment...], e.g. 세종시 세종 시
+func ParseUserDictRules(rules []string) (*dict.UserDict, error) {
+	records := make(dict.UserDictRecords, 0, len(rules))
+	seen := make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		rule = strings.TrimSpace(rule)
+		if rule == "" || strings.HasPrefix(rule, "#") {
+			continue
+		}
+		fields := strings.Fields(rule)
+		word, segments := fields[0], fields[1:]
+		if len(segments) == 0 {
+			segments = []string{word}
+		}
+		if strings.Join(segments, "") != word {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[nori] user_dictionary_rules the segments of ["+word+"] doesn't match the word")
+		}
+		if _, ok := seen[word]; ok {
+			continue
+		}
+		seen[word] = struct{}{}
+		records = append(records, dict.UserDicRecord{Text: word, Tokens: segments, Yomi: segments, Pos: userDictPOS})
+	}
+	udict, err := morph.NewUserDict(records)
+	if err != nil {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[nori] user_dictionary_rules "+err.Error())
+	}
+	return udict, nil
+}
@@ -0,0 +1,58 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ko
+
+import (
+	"strings"
+
+	"github.com/blugelabs/bluge/analysis"
+
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/morph"
+)
+
+// DefaultStopTags the part-of-speech tags removed by default, same as nori,
+// E is all the verbal endings and J is all the postpositions.
+var DefaultStopTags = []string{
+	"E", "IC", "J", "MAG", "MAJ", "MM", "SP", "SSC", "SSO", "SC", "SE", "XPN", "XSA", "XSN", "XSV", "UNA", "NA", "VSV",
+}
+
+// PartOfSpeechFilter removes the tokens by part-of-speech tags, it uses the default tags if tags is empty
+func PartOfSpeechFilter(tags []string) analysis.TokenFilter {
+	if len(tags) == 0 {
+		tags = DefaultStopTags
+	}
+	return morph.NewStopTagsFilter(tags, tagOf)
+}
+
+// ReadingFormFilter replaces the hanja term with the hangul reading
+func ReadingFormFilter() analysis.TokenFilter {
+	return morph.NewReadingFormFilter(nil)
+}
+
+// tagOf returns the nori tag of the mecab-ko-dic part-of-speech,
+// the tag of multiple morphemes like VV+EP uses the first one.
+func tagOf(pos string) string {
+	if i := strings.Index(pos, "+"); i > 0 {
+		pos = pos[:i]
+	}
+	switch {
+	case strings.HasPrefix(pos, "E"):
+		return "E"
+	case strings.HasPrefix(pos, "J"):
+		return "J"
+	}
+	return pos
+}
@@ -0,0 +1,122 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ko
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blugelabs/bluge/analysis"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/morph"
+)
+
+func TestAnalyzer(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "default",
+			text: "가거도항 공원에 갔다.",
+			want: "[가거도 항 공원 가]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Analyzer().Analyze([]byte(tt.text))
+			assert.Equal(t, tt.want, collectToken(got))
+		})
+	}
+}
+
+func TestNewTokenizer(t *testing.T) {
+	tests := []struct {
+		name       string
+		decompound string
+		rules      []string
+		text       string
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "none",
+			decompound: DecompoundNone,
+			text:       "가거도항에 갔다",
+			want:       "[가거도항 에 갔 다]",
+		},
+		{
+			name:       "discard",
+			decompound: DecompoundDiscard,
+			text:       "가거도항에 갔다",
+			want:       "[가거도 항 에 가 았 다]",
+		},
+		{
+			name:       "mixed",
+			decompound: DecompoundMixed,
+			text:       "가거도항에 갔다",
+			want:       "[가거도항 가거도 항 에 갔 가 았 다]",
+		},
+		{
+			name:       "user dictionary",
+			decompound: DecompoundDiscard,
+			rules:      []string{"세종시 세종 시"},
+			text:       "한국 세종시",
+			want:       "[한국 세종 시]",
+		},
+		{
+			name:       "invalid decompound",
+			decompound: "unknown",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userDict, err := ParseUserDictRules(tt.rules)
+			assert.NoError(t, err)
+			tokenizer, err := NewTokenizer(tt.decompound, true, userDict)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, collectToken(tokenizer.Tokenize([]byte(tt.text))))
+		})
+	}
+}
+
+func TestParseUserDictRules(t *testing.T) {
+	_, err := ParseUserDictRules([]string{"세종시 세종 도"})
+	assert.Error(t, err)
+}
+
+func TestReadingFormFilter(t *testing.T) {
+	tokens, morphemes := DefaultTokenizer().TokenizeMorphemes([]byte("漢字"))
+	got := morph.FilterTokens(ReadingFormFilter(), tokens, morphemes)
+	assert.Equal(t, "[한자]", collectToken(got))
+}
+
+func collectToken(tokens analysis.TokenStream) string {
+	str := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		str = append(str, string(token.Term))
+	}
+	return "[" + strings.Join(str, " ") + "]"
+}
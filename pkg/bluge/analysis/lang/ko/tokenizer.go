@@ -0,0 +1,183 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package ko
+
+import (
+	"strings"
+
+	"github.com/blugelabs/bluge/analysis"
+	kodict "github.com/ikawaha/kagome-dict-ko"
+	"github.com/ikawaha/kagome-dict/dict"
+	kagome "github.com/ikawaha/kagome/v2/tokenizer"
+
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/morph"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+)
+
+const (
+	DecompoundNone    = "none"    // keeps the compound and inflected words
+	DecompoundDiscard = "discard" // splits them and discards the original word, the default mode
+	DecompoundMixed   = "mixed"   // splits them and keeps the original word
+)
+
+// Tokenizer the nori style Korean tokenizer, it returns the part-of-speech and reading of tokens as morphemes
+type Tokenizer struct {
+	tokenizer          *kagome.Tokenizer
+	decompound         string
+	discardPunctuation bool
+}
+
+// NewTokenizer creates the tokenizer with the mecab-ko-dic dictionary, userDict can be nil
+func NewTokenizer(decompound string, discardPunctuation bool, userDict *dict.UserDict) (*Tokenizer, error) {
+	t := &Tokenizer{decompound: strings.ToLower(decompound), discardPunctuation: discardPunctuation}
+	switch t.decompound {
+	case DecompoundNone, DecompoundMixed:
+	case DecompoundDiscard, "":
+		t.decompound = DecompoundDiscard
+	default:
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[nori] unknown decompound_mode ["+decompound+"]")
+	}
+
+	opts := []kagome.Option{kagome.OmitBosEos()}
+	if userDict != nil {
+		opts = append(opts, kagome.UserDict(userDict))
+	}
+	var err error
+	if t.tokenizer, err = kagome.New(Dict(), opts...); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// morpheme a part of compound or inflected word
+type morpheme struct {
+	surface string
+	pos     string
+}
+
+func (t *Tokenizer) Tokenize(input []byte) analysis.TokenStream {
+	result, _ := t.TokenizeMorphemes(input)
+	return result
+}
+
+// TokenizeMorphemes returns the tokens and their morphemes for the morph.Chain
+func (t *Tokenizer) TokenizeMorphemes(input []byte) (analysis.TokenStream, morph.Morphemes) {
+	tokens := t.tokenizer.Analyze(string(input), kagome.Normal)
+	result := make(analysis.TokenStream, 0, len(tokens))
+	morphemes := make(morph.Morphemes, len(tokens))
+	for _, token := range tokens {
+		if token.Class == kagome.DUMMY || strings.TrimSpace(token.Surface) == "" {
+			continue
+		}
+		if t.discardPunctuation && morph.IsPunctuation(token.Surface) {
+			continue
+		}
+
+		var m morph.Morpheme
+		var parts []morpheme
+		switch token.Class {
+		case kagome.USER:
+			m.POS = userDictPOS
+			segments, _ := token.FeatureAt(1)
+			if words := strings.Split(segments, "/"); len(words) > 1 {
+				for _, word := range words {
+					parts = append(parts, morpheme{surface: word, pos: userDictPOS})
+				}
+			}
+		default:
+			m.POS = morph.JoinPOS(token.POS())
+			if reading := morph.Feature(token.FeatureAt(kodict.Reading)); reading != token.Surface {
+				m.Reading = reading
+			}
+			switch morph.Feature(token.FeatureAt(kodict.Type)) {
+			case "Compound", "Inflect", "Preanalysis":
+				parts = parseExpression(morph.Feature(token.FeatureAt(kodict.Expression)))
+			}
+		}
+
+		start, end := token.Position, token.Position+len(token.Surface)
+		if len(parts) < 2 || t.decompound == DecompoundNone {
+			result = append(result, &analysis.Token{
+				Term:         []byte(token.Surface),
+				Start:        start,
+				End:          end,
+				PositionIncr: 1,
+				Type:         analysis.Ideographic,
+			})
+			morphemes[result[len(result)-1]] = m
+			continue
+		}
+
+		positionIncr := 1
+		if t.decompound == DecompoundMixed {
+			result = append(result, &analysis.Token{
+				Term:         []byte(token.Surface),
+				Start:        start,
+				End:          end,
+				PositionIncr: 1,
+				Type:         analysis.Ideographic,
+			})
+			morphemes[result[len(result)-1]] = m
+			positionIncr = 0
+		}
+		result = appendParts(result, morphemes, parts, start, end, positionIncr)
+	}
+	return result, morphemes
+}
+
+// appendParts appends the parts of word, the offsets of parts are the offsets of word if the parts don't match the surface
+func appendParts(result analysis.TokenStream, morphemes morph.Morphemes, parts []morpheme, start, end, positionIncr int) analysis.TokenStream {
+	var length int
+	for _, p := range parts {
+		length += len(p.surface)
+	}
+	matched := length == end-start
+	offset := start
+	for _, p := range parts {
+		token := &analysis.Token{
+			Term:         []byte(p.surface),
+			Start:        start,
+			End:          end,
+			PositionIncr: positionIncr,
+			Type:         analysis.Ideographic,
+		}
+		morphemes[token] = morph.Morpheme{POS: p.pos}
+		if matched {
+			token.Start, token.End = offset, offset+len(p.surface)
+			offset += len(p.surface)
+		}
+		result = append(result, token)
+		positionIncr = 1
+	}
+	return result
+}
+
+// parseExpression parses the expression of compound or inflected word, e.g. 가/VV/*+았/EP/*
+func parseExpression(expression string) []morpheme {
+	if expression == "" {
+		return nil
+	}
+	items := strings.Split(expression, "+")
+	parts := make([]morpheme, 0, len(items))
+	for _, item := range items {
+		fields := strings.Split(item, "/")
+		if len(fields) < 2 || fields[0] == "" {
+			return nil
+		}
+		parts = append(parts, morpheme{surface: fields[0], pos: fields[1]})
+	}
+	return parts
+}
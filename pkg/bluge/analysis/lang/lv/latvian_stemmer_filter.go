@@ -0,0 +1,153 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package lv
+
+import (
+	"bytes"
+
+	"github.com/blugelabs/bluge/analysis"
+)
+
+// LatvianStemmerFilter stems the Latvian words, it follows the Lucene LatvianStemmer, which is a light version
+// of the algorithm in Kārlis Kreslins' thesis "A stemming algorithm for Latvian",
+// it only removes the noun and adjective endings and unpalatalizes the stem.
+type LatvianStemmerFilter struct {
+}
+
+func StemmerFilter() *LatvianStemmerFilter {
+	return &LatvianStemmerFilter{}
+}
+
+func (s *LatvianStemmerFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		// if not protected keyword, stem it
+		if !token.KeyWord {
+			runes := bytes.Runes(token.Term)
+			token.Term = analysis.BuildTermFromRunes(stem(runes))
+		}
+	}
+	return input
+}
+
+// affix an ending, vc is the number of vowels the word must have more than,
+// palatalizes means the ending may change the last consonant of the stem.
+type affix struct {
+	affix       []rune
+	vc          int
+	palatalizes bool
+}
+
+var affixes = []affix{
+	{[]rune("ajiem"), 3, false}, {[]rune("ajai"), 3, false},
+	{[]rune("ajam"), 2, false}, {[]rune("ajām"), 2, false},
+	{[]rune("ajos"), 2, false}, {[]rune("ajās"), 2, false},
+	{[]rune("iem"), 2, true}, {[]rune("ajā"), 2, false},
+	{[]rune("ais"), 2, false}, {[]rune("ai"), 2, false},
+	{[]rune("ei"), 2, false}, {[]rune("ām"), 1, false},
+	{[]rune("am"), 1, false}, {[]rune("ēm"), 1, false},
+	{[]rune("īm"), 1, false}, {[]rune("im"), 1, false},
+	{[]rune("um"), 1, false}, {[]rune("us"), 1, true},
+	{[]rune("as"), 1, false}, {[]rune("ās"), 1, false},
+	{[]rune("es"), 1, false}, {[]rune("os"), 1, true},
+	{[]rune("ij"), 1, false}, {[]rune("īs"), 1, false},
+	{[]rune("ēs"), 1, false}, {[]rune("is"), 1, false},
+	{[]rune("ie"), 1, false}, {[]rune("u"), 1, true},
+	{[]rune("a"), 1, true}, {[]rune("i"), 1, true},
+	{[]rune("e"), 1, false}, {[]rune("ā"), 1, false},
+	{[]rune("ē"), 1, false}, {[]rune("ī"), 1, false},
+	{[]rune("ū"), 1, false}, {[]rune("o"), 1, false},
+	{[]rune("s"), 0, false}, {[]rune("š"), 0, false},
+}
+
+func stem(s []rune) []rune {
+	numVowels := numVowels(s)
+	for _, a := range affixes {
+		if numVowels > a.vc && len(s) >= len(a.affix)+3 && endsWith(s, a.affix) {
+			removed := s[len(s)-len(a.affix)]
+			s = s[:len(s)-len(a.affix)]
+			if a.palatalizes {
+				return unpalatalize(s, removed)
+			}
+			return s
+		}
+	}
+	return s
+}
+
+// unpalatalize reverts the consonant changes of the stem, removed is the first letter of the removed ending
+func unpalatalize(s []rune, removed rune) []rune {
+	// if the ending is -u, it's genitive plural, and these two can only apply then.
+	if removed == 'u' {
+		// kš -> kst
+		if endsWith(s, []rune("kš")) {
+			s[len(s)-1] = 's'
+			return append(s, 't')
+		}
+		// ņņ -> nn
+		if endsWith(s, []rune("ņņ")) {
+			s[len(s)-2], s[len(s)-1] = 'n', 'n'
+			return s
+		}
+	}
+
+	switch {
+	case endsWith(s, []rune("pj")), endsWith(s, []rune("bj")), endsWith(s, []rune("mj")), endsWith(s, []rune("vj")):
+		// labial consonant
+		return s[:len(s)-1]
+	case endsWith(s, []rune("šņ")):
+		s[len(s)-2], s[len(s)-1] = 's', 'n'
+	case endsWith(s, []rune("žņ")):
+		s[len(s)-2], s[len(s)-1] = 'z', 'n'
+	case endsWith(s, []rune("šļ")):
+		s[len(s)-2], s[len(s)-1] = 's', 'l'
+	case endsWith(s, []rune("žļ")):
+		s[len(s)-2], s[len(s)-1] = 'z', 'l'
+	case endsWith(s, []rune("ļņ")):
+		s[len(s)-2], s[len(s)-1] = 'l', 'n'
+	case endsWith(s, []rune("ļļ")):
+		s[len(s)-2], s[len(s)-1] = 'l', 'l'
+	case s[len(s)-1] == 'č':
+		s[len(s)-1] = 'c'
+	case s[len(s)-1] == 'ļ':
+		s[len(s)-1] = 'l'
+	case s[len(s)-1] == 'ņ':
+		s[len(s)-1] = 'n'
+	}
+	return s
+}
+
+func numVowels(s []rune) int {
+	n := 0
+	for _, r := range s {
+		switch r {
+		case 'a', 'e', 'i', 'o', 'u', 'ā', 'ī', 'ē', 'ū':
+			n++
+		}
+	}
+	return n
+}
+
+func endsWith(s, suffix []rune) bool {
+	if len(suffix) > len(s) {
+		return false
+	}
+	for i := range suffix {
+		if s[len(s)-len(suffix)+i] != suffix[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,233 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+// Package morph keeps the morphological features of the tokens produced by the dictionary based tokenizers,
+// the token filters like part-of-speech, base form and reading form use them after tokenization.
+// The features of one input are kept in Morphemes, it is passed from the tokenizer to the filters by Chain.
+package morph
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/blugelabs/bluge/analysis"
+	"github.com/ikawaha/kagome-dict/dict"
+)
+
+// Morpheme the features of a token from the dictionary
+type Morpheme struct {
+	POS      string // part-of-speech tag, the hierarchy is joined by -
+	BaseForm string // empty if the token has no inflection
+	Reading  string // empty if unknown
+}
+
+// Morphemes the morphemes of the tokens produced from one input, it lives as long as the token stream
+type Morphemes map[*analysis.Token]Morpheme
+
+// Tokenizer the dictionary based tokenizer, it returns the morphemes of tokens besides the tokens
+type Tokenizer interface {
+	analysis.Tokenizer
+	TokenizeMorphemes(input []byte) (analysis.TokenStream, Morphemes)
+}
+
+// Filter the token filter reads the morphemes of tokens, Filter without morphemes keeps the tokens
+type Filter interface {
+	analysis.TokenFilter
+	FilterMorphemes(input analysis.TokenStream, morphemes Morphemes) analysis.TokenStream
+}
+
+// Chain runs the tokenizer and the token filters as one tokenizer, so the filters read the morphemes of
+// the same input. The morphemes are dropped with the token stream after the input analyzed.
+type Chain struct {
+	Tokenizer Tokenizer
+	Filters   []analysis.TokenFilter
+}
+
+func (c *Chain) Tokenize(input []byte) analysis.TokenStream {
+	tokens, morphemes := c.Tokenizer.TokenizeMorphemes(input)
+	for _, filter := range c.Filters {
+		tokens = FilterTokens(filter, tokens, morphemes)
+	}
+	return tokens
+}
+
+// FilterTokens runs the filter with the morphemes if the filter reads them
+func FilterTokens(filter analysis.TokenFilter, input analysis.TokenStream, morphemes Morphemes) analysis.TokenStream {
+	if f, ok := filter.(Filter); ok {
+		return f.FilterMorphemes(input, morphemes)
+	}
+	return filter.Filter(input)
+}
+
+// Wrap returns the analyzer whose token filters run in a Chain with the tokenizer if the tokenizer is morphological,
+// otherwise it returns the analyzer itself. The filters appended after wrapped are moved into the chain too.
+func Wrap(ana *analysis.Analyzer) *analysis.Analyzer {
+	if ana == nil || len(ana.TokenFilters) == 0 {
+		return ana
+	}
+	tokenizer, filters := Unwrap(ana)
+	t, ok := tokenizer.(Tokenizer)
+	if !ok {
+		return ana
+	}
+	return &analysis.Analyzer{
+		CharFilters: ana.CharFilters,
+		Tokenizer:   &Chain{Tokenizer: t, Filters: filters},
+	}
+}
+
+// Unwrap returns the tokenizer and the token filters of the analyzer, the filters in Chain are included
+func Unwrap(ana *analysis.Analyzer) (analysis.Tokenizer, []analysis.TokenFilter) {
+	c, ok := ana.Tokenizer.(*Chain)
+	if !ok {
+		return ana.Tokenizer, ana.TokenFilters
+	}
+	filters := make([]analysis.TokenFilter, 0, len(c.Filters)+len(ana.TokenFilters))
+	filters = append(filters, c.Filters...)
+	filters = append(filters, ana.TokenFilters...)
+	return c.Tokenizer, filters
+}
+
+// JoinPOS joins the part-of-speech hierarchy, the empty level * is skipped
+func JoinPOS(pos []string) string {
+	parts := make([]string, 0, len(pos))
+	for _, p := range pos {
+		if p == "" || p == "*" {
+			break
+		}
+		parts = append(parts, p)
+	}
+	return strings.Join(parts, "-")
+}
+
+// Feature returns the feature, the empty value * is returned as empty string
+func Feature(v string, ok bool) string {
+	if !ok || v == "*" {
+		return ""
+	}
+	return v
+}
+
+// IsPunctuation returns true if the text only contains punctuations, symbols and spaces
+func IsPunctuation(text string) bool {
+	for _, r := range text {
+		if !unicode.IsPunct(r) && !unicode.IsSymbol(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewUserDict builds the user dictionary from the records, it returns nil if no record
+func NewUserDict(records dict.UserDictRecords) (*dict.UserDict, error) {
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return records.NewUserDict()
+}
+
+// StopTagsFilter removes the tokens whose part-of-speech is in the stop tags
+type StopTagsFilter struct {
+	tags map[string]struct{}
+	tag  func(pos string) string
+}
+
+// NewStopTagsFilter creates the part-of-speech filter, tag normalizes the part-of-speech of token before matching
+func NewStopTagsFilter(tags []string, tag func(pos string) string) *StopTagsFilter {
+	f := &StopTagsFilter{tags: make(map[string]struct{}, len(tags)), tag: tag}
+	for _, t := range tags {
+		f.tags[t] = struct{}{}
+	}
+	return f
+}
+
+func (f *StopTagsFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	return input
+}
+
+func (f *StopTagsFilter) FilterMorphemes(input analysis.TokenStream, morphemes Morphemes) analysis.TokenStream {
+	var j, skipped int
+	for _, token := range input {
+		if m, ok := morphemes[token]; ok {
+			pos := m.POS
+			if f.tag != nil {
+				pos = f.tag(pos)
+			}
+			if _, stop := f.tags[pos]; stop {
+				skipped += token.PositionIncr
+				continue
+			}
+		}
+		token.PositionIncr += skipped
+		skipped = 0
+		input[j] = token
+		j++
+	}
+	return input[:j]
+}
+
+// BaseFormFilter replaces the term with the base form
+type BaseFormFilter struct{}
+
+func NewBaseFormFilter() *BaseFormFilter {
+	return &BaseFormFilter{}
+}
+
+func (f *BaseFormFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	return input
+}
+
+func (f *BaseFormFilter) FilterMorphemes(input analysis.TokenStream, morphemes Morphemes) analysis.TokenStream {
+	for _, token := range input {
+		if token.KeyWord {
+			continue
+		}
+		if m, ok := morphemes[token]; ok && m.BaseForm != "" {
+			token.Term = []byte(m.BaseForm)
+		}
+	}
+	return input
+}
+
+// ReadingFormFilter replaces the term with the reading, convert transforms the reading if not nil
+type ReadingFormFilter struct {
+	convert func(reading string) string
+}
+
+func NewReadingFormFilter(convert func(reading string) string) *ReadingFormFilter {
+	return &ReadingFormFilter{convert: convert}
+}
+
+func (f *ReadingFormFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	return input
+}
+
+func (f *ReadingFormFilter) FilterMorphemes(input analysis.TokenStream, morphemes Morphemes) analysis.TokenStream {
+	for _, token := range input {
+		if token.KeyWord {
+			continue
+		}
+		m, ok := morphemes[token]
+		if !ok || m.Reading == "" {
+			continue
+		}
+		reading := m.Reading
+		if f.convert != nil {
+			reading = f.convert(reading)
+		}
+		token.Term = []byte(reading)
+	}
+	return input
+}
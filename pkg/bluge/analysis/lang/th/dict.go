@@ -0,0 +1,98 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package th
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/zincsearch/zincsearch/pkg/config"
+)
+
+// DefaultUserDictFile the user dictionary in the dictionary path, the words are added to the embedded dictionary
+const DefaultUserDictFile = "user.txt"
+
+//go:embed dict/words_th.txt
+var embeddedWords []byte
+
+var defaultDict struct {
+	once sync.Once
+	dict *Dictionary
+}
+
+// Dictionary the Thai words used by the tokenizer to find the word boundaries
+type Dictionary struct {
+	words  map[string]struct{}
+	maxLen int // max length of the words in runes
+}
+
+// NewDictionary creates the dictionary with the words, one word a line, the line starts with # is ignored
+func NewDictionary(r io.Reader) (*Dictionary, error) {
+	d := &Dictionary{words: make(map[string]struct{})}
+	if err := d.load(r); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Dict returns the embedded dictionary with the words in ${ZINC_PLUGIN_THAI_DICT_PATH}/user.txt
+func Dict() *Dictionary {
+	defaultDict.once.Do(func() {
+		defaultDict.dict, _ = NewDictionary(bytes.NewReader(embeddedWords))
+
+		file := filepath.Join(config.Global.Plugin.Thai.DictPath, DefaultUserDictFile)
+		f, err := os.Open(file)
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		log.Info().Msgf("Loading  Thai user dict... %s", file)
+		if err = defaultDict.dict.load(f); err != nil {
+			log.Error().Err(err).Str("file", file).Msg("load thai user dict failed")
+		}
+	})
+	return defaultDict.dict
+}
+
+// Contains reports whether the word is in the dictionary
+func (d *Dictionary) Contains(word string) bool {
+	_, ok := d.words[word]
+	return ok
+}
+
+func (d *Dictionary) load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		d.words[word] = struct{}{}
+		if n := utf8.RuneCountInString(word); n > d.maxLen {
+			d.maxLen = n
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,211 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package th
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/blugelabs/bluge/analysis"
+	"github.com/blugelabs/bluge/analysis/tokenizer"
+)
+
+// Tokenizer splits the Thai text into words by the dictionary, Thai is written without spaces between words.
+// It uses the maximal matching: the segmentation with the least unknown characters and then the least words is chosen,
+// the unknown characters are split by the Thai character clusters and kept together as one token.
+// The text in other scripts is tokenized by the unicode tokenizer.
+type Tokenizer struct {
+	dict    *Dictionary
+	unicode *tokenizer.UnicodeTokenizer
+}
+
+func NewTokenizer(dict *Dictionary) *Tokenizer {
+	return &Tokenizer{
+		dict:    dict,
+		unicode: tokenizer.NewUnicodeTokenizer(),
+	}
+}
+
+// DefaultTokenizer returns the tokenizer with the default dictionary
+func DefaultTokenizer() *Tokenizer {
+	return NewTokenizer(Dict())
+}
+
+func (t *Tokenizer) Tokenize(input []byte) analysis.TokenStream {
+	rv := make(analysis.TokenStream, 0, len(input)/6)
+	start := 0
+	for start < len(input) {
+		r, _ := utf8.DecodeRune(input[start:])
+		isThai := isThaiLetter(r)
+		end := start
+		for end < len(input) {
+			r, size := utf8.DecodeRune(input[end:])
+			if isThaiLetter(r) != isThai {
+				break
+			}
+			end += size
+		}
+		if isThai {
+			rv = t.appendThai(rv, input[start:end], start)
+		} else {
+			for _, token := range t.unicode.Tokenize(input[start:end]) {
+				if !hasLetterOrDigit(token.Term) {
+					continue
+				}
+				token.Start += start
+				token.End += start
+				token.PositionIncr = 1
+				rv = append(rv, token)
+			}
+		}
+		start = end
+	}
+	return rv
+}
+
+// appendThai appends the words of the Thai text, offset is the position of the text in the input
+func (t *Tokenizer) appendThai(rv analysis.TokenStream, text []byte, offset int) analysis.TokenStream {
+	runes := make([]rune, 0, len(text)/3)
+	offsets := make([]int, 0, len(text)/3+1)
+	for i, r := range string(text) {
+		runes = append(runes, r)
+		offsets = append(offsets, offset+i)
+	}
+	offsets = append(offsets, offset+len(text))
+
+	for _, word := range t.segment(runes) {
+		rv = append(rv, &analysis.Token{
+			Term:         []byte(string(runes[word.start:word.end])),
+			Start:        offsets[word.start],
+			End:          offsets[word.end],
+			PositionIncr: 1,
+			Type:         analysis.AlphaNumeric,
+		})
+	}
+	return rv
+}
+
+// span the word in runes
+type span struct {
+	start, end int
+}
+
+// path the best segmentation of the text ending at a position
+type path struct {
+	reachable bool
+	unknown   int // the number of unknown clusters
+	words     int
+	prev      int
+	known     bool // whether the last word is in the dictionary
+}
+
+func (p path) better(unknown, words int) bool {
+	return !p.reachable || unknown < p.unknown || (unknown == p.unknown && words < p.words)
+}
+
+// segment returns the words of the Thai text
+func (t *Tokenizer) segment(runes []rune) []span {
+	n := len(runes)
+	paths := make([]path, n+1)
+	paths[0].reachable = true
+	for i := 0; i < n; i++ {
+		p := paths[i]
+		if !p.reachable {
+			continue
+		}
+		for l := 1; l <= t.dict.maxLen && i+l <= n; l++ {
+			j := i + l
+			if !isBoundary(runes, j) || !t.dict.Contains(string(runes[i:j])) {
+				continue
+			}
+			if paths[j].better(p.unknown, p.words+1) {
+				paths[j] = path{reachable: true, unknown: p.unknown, words: p.words + 1, prev: i, known: true}
+			}
+		}
+		j := nextCluster(runes, i)
+		if paths[j].better(p.unknown+1, p.words+1) {
+			paths[j] = path{reachable: true, unknown: p.unknown + 1, words: p.words + 1, prev: i}
+		}
+	}
+
+	// backtrack, the adjacent unknown clusters are merged to one word
+	words := make([]span, 0, paths[n].words)
+	for j := n; j > 0; {
+		i := paths[j].prev
+		if !paths[j].known {
+			for i > 0 && !paths[i].known {
+				i = paths[i].prev
+			}
+		}
+		words = append(words, span{start: i, end: j})
+		j = i
+	}
+	for i, j := 0, len(words)-1; i < j; i, j = i+1, j-1 {
+		words[i], words[j] = words[j], words[i]
+	}
+	return words
+}
+
+// nextCluster returns the end of the Thai character cluster starting at i,
+// a cluster is a consonant with the leading vowel, the following vowels and the tone marks.
+func nextCluster(runes []rune, i int) int {
+	j := i
+	if isLeadingVowel(runes[j]) {
+		j++
+	}
+	if j < len(runes) {
+		j++
+	}
+	for j < len(runes) && !isBoundary(runes, j) {
+		j++
+	}
+	return j
+}
+
+// isBoundary reports whether a word can start at j, a word can't start with a combining mark or a following vowel,
+// and can't end with a leading vowel
+func isBoundary(runes []rune, j int) bool {
+	if j == 0 || j == len(runes) {
+		return true
+	}
+	if isLeadingVowel(runes[j-1]) {
+		return false
+	}
+	r := runes[j]
+	return !unicode.Is(unicode.Mn, r) && !isFollowingVowel(r)
+}
+
+func isLeadingVowel(r rune) bool {
+	return r >= 'เ' && r <= 'ไ'
+}
+
+func isFollowingVowel(r rune) bool {
+	return r == 'ะ' || r == 'า' || r == 'ำ' || r == 'ๅ'
+}
+
+// isThaiLetter reports whether the rune is a Thai letter or mark, the repetition mark and the digits are excluded
+func isThaiLetter(r rune) bool {
+	return unicode.Is(unicode.Thai, r) && r != 'ๆ' && r != 'ฯ' && (unicode.IsLetter(r) || unicode.IsMark(r))
+}
+
+func hasLetterOrDigit(term []byte) bool {
+	for _, r := range string(term) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package th
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blugelabs/bluge/analysis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenizer(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "dictionary words",
+			text: "ฉันชอบกินข้าวผัดกุ้งที่ร้านอาหารใกล้บ้าน",
+			want: "[ฉัน ชอบ กิน ข้าวผัด กุ้ง ที่ ร้านอาหาร ใกล้ บ้าน]",
+		},
+		{
+			name: "mixed scripts",
+			text: "iPhone 15 ราคาเท่าไหร่ครับ",
+			want: "[iPhone 15 ราคา เท่าไหร่ ครับ]",
+		},
+		{
+			name: "unknown words",
+			text: "ไปกรุงเทพฯกับเพื่อนฑฒณ",
+			want: "[ไป กรุงเทพ กับ เพื่อน ฑฒณ]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DefaultTokenizer().Tokenize([]byte(tt.text))
+			assert.Equal(t, tt.want, collectToken(got))
+		})
+	}
+}
+
+func TestTokenizerOffsets(t *testing.T) {
+	text := "abc ภาษาไทย"
+	got := DefaultTokenizer().Tokenize([]byte(text))
+	assert.Len(t, got, 2)
+	for _, token := range got {
+		assert.Equal(t, string(token.Term), text[token.Start:token.End])
+	}
+}
+
+func TestNewDictionary(t *testing.T) {
+	dict, err := NewDictionary(strings.NewReader("# comment\nสวัสดี\nครับ\n"))
+	assert.NoError(t, err)
+	assert.True(t, dict.Contains("สวัสดี"))
+	assert.False(t, dict.Contains("# comment"))
+
+	got := NewTokenizer(dict).Tokenize([]byte("สวัสดีครับ"))
+	assert.Equal(t, "[สวัสดี ครับ]", collectToken(got))
+}
+
+func TestAnalyzer(t *testing.T) {
+	got := Analyzer().Analyze([]byte("ประเทศไทยมีประชากรประมาณ ๖๐ ล้านคน"))
+	assert.Equal(t, "[ประเทศไทย ประชากร ประมาณ 60 ล้าน คน]", collectToken(got))
+}
+
+func collectToken(tokens analysis.TokenStream) string {
+	str := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		str = append(str, string(token.Term))
+	}
+	return "[" + strings.Join(str, " ") + "]"
+}
@@ -0,0 +1,74 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package digit
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/blugelabs/bluge/analysis"
+)
+
+// DecimalDigitFilter converts all the unicode decimal digits to 0-9
+type DecimalDigitFilter struct{}
+
+func NewDecimalDigitFilter() *DecimalDigitFilter {
+	return &DecimalDigitFilter{}
+}
+
+func (t *DecimalDigitFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		token.Term = decimalDigit(token.Term)
+	}
+
+	return input
+}
+
+func decimalDigit(term []byte) []byte {
+	var rv []byte
+	for i := 0; i < len(term); {
+		r, size := utf8.DecodeRune(term[i:])
+		if r >= utf8.RuneSelf && unicode.IsDigit(r) {
+			if rv == nil {
+				rv = make([]byte, 0, len(term))
+				rv = append(rv, term[:i]...)
+			}
+			rv = append(rv, byte('0'+digitValue(r)))
+		} else if rv != nil {
+			rv = append(rv, term[i:i+size]...)
+		}
+		i += size
+	}
+	if rv == nil {
+		return term
+	}
+	return rv
+}
+
+// digitValue returns the value of a unicode decimal digit, the digits of a script are contiguous from zero
+func digitValue(r rune) rune {
+	for _, rng := range unicode.Nd.R16 {
+		if r >= rune(rng.Lo) && r <= rune(rng.Hi) {
+			return (r - rune(rng.Lo)) % 10
+		}
+	}
+	for _, rng := range unicode.Nd.R32 {
+		if r >= rune(rng.Lo) && r <= rune(rng.Hi) {
+			return (r - rune(rng.Lo)) % 10
+		}
+	}
+	return 0
+}
@@ -0,0 +1,44 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package digit
+
+import (
+	"testing"
+
+	"github.com/blugelabs/bluge/analysis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecimalDigitFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		term string
+		want string
+	}{
+		{name: "ascii", term: "abc123", want: "abc123"},
+		{name: "thai", term: "๒๕๖๖", want: "2566"},
+		{name: "bengali", term: "১২৩", want: "123"},
+		{name: "arabic-indic", term: "٠١٢٣٤٥٦٧٨٩", want: "0123456789"},
+		{name: "fullwidth", term: "ｘ１０", want: "ｘ10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewDecimalDigitFilter().Filter(analysis.TokenStream{&analysis.Token{Term: []byte(tt.term)}})
+			assert.Equal(t, tt.want, string(got[0].Term))
+		})
+	}
+}
@@ -120,7 +120,7 @@ func loadLanguageStopWords(rv *analysis.TokenMap, language string) bool {
 		dict = id.StopWords()
 	case "_it_", "_italian_":
 		dict = it.StopWords()
-	case "_lv", "_latvian_":
+	case "_lv_", "_latvian_":
 		dict = lv.StopWords()
 	case "_nl_", "_dutch_":
 		dict = nl.StopWords()
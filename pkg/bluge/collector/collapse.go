@@ -0,0 +1,239 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package collector
+
+import (
+	"context"
+	"sort"
+
+	"github.com/blugelabs/bluge/search"
+)
+
+// Collapse keeps the top document of each value of the field
+type Collapse struct {
+	Field     string
+	InnerHits []*InnerHitsRequest
+}
+
+// InnerHitsRequest expands the top documents of each collapsed group
+type InnerHitsRequest struct {
+	Name string
+	From int
+	Size int
+	Sort search.SortOrder
+}
+
+// InnerHits are the top documents of a collapsed group
+type InnerHits struct {
+	Name    string
+	Total   int
+	Hits    []*search.DocumentMatch // sorted top from + size documents
+	request *InnerHitsRequest
+}
+
+// Page returns the documents of the inner hits after from
+func (h *InnerHits) Page() []*search.DocumentMatch {
+	return page(h.Hits, h.request.From, h.request.Size)
+}
+
+// add adds a copy of the document, the copy has the sort value of the inner hits
+func (h *InnerHits) add(doc *search.DocumentMatch) {
+	h.Total++
+	n := h.request.From + h.request.Size
+	if n <= 0 {
+		return
+	}
+
+	c := *doc
+	c.SortValue = nil
+	c.FieldTermLocations = nil
+	c.Locations = nil
+	h.request.Sort.Compute(&c)
+	h.insert(&c, n)
+}
+
+func (h *InnerHits) insert(doc *search.DocumentMatch, n int) {
+	i := sort.Search(len(h.Hits), func(i int) bool { return h.request.Sort.Compare(doc, h.Hits[i]) < 0 })
+	if i >= n {
+		return
+	}
+	h.Hits = append(h.Hits, nil)
+	copy(h.Hits[i+1:], h.Hits[i:])
+	h.Hits[i] = doc
+	if len(h.Hits) > n {
+		h.Hits = h.Hits[:n]
+	}
+}
+
+// MergeInnerHits merges the inner hits of the same group collected by different readers
+func MergeInnerHits(a, b []*InnerHits) []*InnerHits {
+	if a == nil {
+		return b
+	}
+	for i := range a {
+		if i >= len(b) {
+			break
+		}
+		a[i].Total += b[i].Total
+		n := a[i].request.From + a[i].request.Size
+		for _, doc := range b[i].Hits {
+			a[i].insert(doc, n)
+		}
+	}
+	return a
+}
+
+// GroupValue returns the value of the collapse field of the document, nil if the document doesn't have the field
+func GroupValue(match *search.DocumentMatch, field string) []byte {
+	return search.Field(field).Value(match)
+}
+
+type group struct {
+	best      *search.DocumentMatch
+	innerHits []*InnerHits
+}
+
+// collapseCollector collects the top document of each group, and then the top n groups
+type collapseCollector struct {
+	size     int
+	skip     int
+	sort     search.SortOrder
+	collapse *Collapse
+}
+
+func newCollapseCollector(size, skip int, order search.SortOrder, collapse *Collapse) *collapseCollector {
+	return &collapseCollector{size: size, skip: skip, sort: order, collapse: collapse}
+}
+
+func (c *collapseCollector) Collect(ctx context.Context, aggs search.Aggregations, searcher search.Collectible) (search.DocumentMatchIterator, error) {
+	defer func() {
+		_ = searcher.Close()
+	}()
+
+	sortSize := len(c.sort)
+	fields := append(c.sort.Fields(), c.collapse.Field)
+	for _, inner := range c.collapse.InnerHits {
+		fields = append(fields, inner.Sort.Fields()...)
+		if len(inner.Sort) > sortSize {
+			sortSize = len(inner.Sort)
+		}
+	}
+	fields = append(fields, aggs.Fields()...)
+	fields = uniqueFields(fields)
+
+	searchContext := search.NewSearchContext(c.BackingSize()+searcher.DocumentMatchPoolSize(), sortSize)
+	bucket := search.NewBucket("", aggs)
+	groups := make(map[string]*group)
+	var missing *group
+
+	var hitNumber int
+	next, err := searcher.Next(searchContext)
+	for err == nil && next != nil {
+		if hitNumber%1024 == 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			default:
+			}
+		}
+		hitNumber++
+		next.HitNumber = hitNumber
+
+		if err = next.LoadDocumentValues(searchContext, fields); err != nil {
+			return nil, err
+		}
+		c.sort.Compute(next)
+		bucket.Consume(next)
+
+		var g *group
+		if value := GroupValue(next, c.collapse.Field); value != nil {
+			if g = groups[string(value)]; g == nil {
+				g = c.newGroup()
+				groups[string(value)] = g
+			}
+		} else {
+			if missing == nil {
+				missing = c.newGroup()
+			}
+			g = missing
+		}
+		for _, inner := range g.innerHits {
+			inner.add(next)
+		}
+		switch {
+		case g.best == nil:
+			g.best = next
+		case c.sort.Compare(next, g.best) < 0:
+			searchContext.DocumentMatchPool.Put(g.best)
+			g.best = next
+		default:
+			searchContext.DocumentMatchPool.Put(next)
+		}
+
+		next, err = searcher.Next(searchContext)
+	}
+	if err != nil {
+		return nil, err
+	}
+	bucket.Finish()
+
+	docs := make([]*search.DocumentMatch, 0, len(groups)+1)
+	innerHits := make(map[*search.DocumentMatch][]*InnerHits, len(groups)+1)
+	for _, g := range groups {
+		docs = append(docs, g.best)
+		innerHits[g.best] = g.innerHits
+	}
+	if missing != nil {
+		docs = append(docs, missing.best)
+		innerHits[missing.best] = missing.innerHits
+	}
+	sort.Slice(docs, func(i, j int) bool { return c.sort.Compare(docs[i], docs[j]) < 0 })
+	docs = page(docs, c.skip, c.size)
+	for _, doc := range docs {
+		doc.Complete(nil)
+	}
+
+	return &DocumentIterator{docs: docs, bucket: bucket, innerHits: innerHits}, nil
+}
+
+func (c *collapseCollector) newGroup() *group {
+	g := &group{innerHits: make([]*InnerHits, 0, len(c.collapse.InnerHits))}
+	for _, inner := range c.collapse.InnerHits {
+		g.innerHits = append(g.innerHits, &InnerHits{Name: inner.Name, request: inner})
+	}
+	return g
+}
+
+func (c *collapseCollector) Size() int {
+	return 0
+}
+
+func (c *collapseCollector) BackingSize() int {
+	return c.size + c.skip + 1
+}
+
+func uniqueFields(fields []string) []string {
+	seen := make(map[string]struct{}, len(fields))
+	rv := fields[:0]
+	for _, field := range fields {
+		if _, ok := seen[field]; ok {
+			continue
+		}
+		seen[field] = struct{}{}
+		rv = append(rv, field)
+	}
+	return rv
+}
@@ -0,0 +1,159 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/search"
+	blugecollector "github.com/blugelabs/bluge/search/collector"
+)
+
+// Rescorer re-scores the top window_size documents by the rescore query
+type Rescorer struct {
+	Query              bluge.Query
+	WindowSize         int
+	QueryWeight        float64
+	RescoreQueryWeight float64
+	ScoreMode          string // total(default), multiply, avg, max, min
+}
+
+func (r *Rescorer) combine(primary, secondary float64) float64 {
+	switch r.ScoreMode {
+	case "multiply":
+		return primary * secondary
+	case "avg":
+		return (primary + secondary) / 2
+	case "max":
+		return math.Max(primary, secondary)
+	case "min":
+		return math.Min(primary, secondary)
+	default:
+		return primary + secondary
+	}
+}
+
+// rescore re-scores the top documents and sorts them by the new score,
+// the documents out of the window keep their scores and positions.
+func (r *Rescorer) rescore(i search.Reader, options search.SearcherOptions, order search.SortOrder, docs []*search.DocumentMatch) error {
+	window := docs
+	if r.WindowSize < len(window) {
+		window = window[:r.WindowSize]
+	}
+	if len(window) == 0 {
+		return nil
+	}
+
+	s, err := r.Query.Searcher(i, options)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	ctx := search.NewSearchContext(s.DocumentMatchPoolSize(), 0)
+
+	byNumber := make([]*search.DocumentMatch, len(window))
+	copy(byNumber, window)
+	sort.Slice(byNumber, func(i, j int) bool { return byNumber[i].Number < byNumber[j].Number })
+
+	var current *search.DocumentMatch
+	done := false
+	for _, doc := range byNumber {
+		if !done && (current == nil || current.Number < doc.Number) {
+			if current, err = s.Advance(ctx, doc.Number); err != nil {
+				return err
+			}
+			done = current == nil
+		}
+		primary := doc.Score * r.QueryWeight
+		if current == nil || current.Number != doc.Number {
+			doc.Score = primary
+			continue
+		}
+		doc.Score = r.combine(primary, current.Score*r.RescoreQueryWeight)
+		if options.Explain {
+			doc.Explanation = search.NewExplanation(doc.Score,
+				fmt.Sprintf("rescore, score mode [%s] of:", r.ScoreMode),
+				search.NewExplanation(primary, fmt.Sprintf("primary weight %v of:", r.QueryWeight), doc.Explanation),
+				search.NewExplanation(current.Score*r.RescoreQueryWeight, fmt.Sprintf("secondary weight %v of:", r.RescoreQueryWeight), current.Explanation),
+			)
+		}
+	}
+
+	sort.SliceStable(window, func(i, j int) bool { return window[i].Score > window[j].Score })
+	for _, doc := range window {
+		doc.SortValue = doc.SortValue[:0]
+		order.Compute(doc)
+	}
+	return nil
+}
+
+// rescoreCollector collects the top documents of max(from + size, window_size) and rescores them
+type rescoreCollector struct {
+	search    *TopNSearch
+	collector *blugecollector.TopNCollector
+}
+
+func newRescoreCollector(s *TopNSearch) *rescoreCollector {
+	n := s.Size() + s.From()
+	for _, r := range s.rescorers {
+		if r.WindowSize > n {
+			n = r.WindowSize
+		}
+	}
+	return &rescoreCollector{
+		search:    s,
+		collector: blugecollector.NewTopNCollector(n, 0, s.SortOrder()),
+	}
+}
+
+func (c *rescoreCollector) Collect(ctx context.Context, aggs search.Aggregations, searcher search.Collectible) (search.DocumentMatchIterator, error) {
+	dmi, err := c.collector.Collect(ctx, aggs, searcher)
+	if err != nil {
+		return nil, err
+	}
+	var docs []*search.DocumentMatch
+	next, err := dmi.Next()
+	for err == nil && next != nil {
+		docs = append(docs, next)
+		next, err = dmi.Next()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range c.search.rescorers {
+		if err := r.rescore(c.search.reader, c.search.options, c.search.SortOrder(), docs); err != nil {
+			return nil, err
+		}
+	}
+
+	return &DocumentIterator{
+		docs:   page(docs, c.search.From(), c.search.Size()),
+		bucket: dmi.Aggregations(),
+	}, nil
+}
+
+func (c *rescoreCollector) Size() int {
+	return c.collector.Size()
+}
+
+func (c *rescoreCollector) BackingSize() int {
+	return c.collector.BackingSize()
+}
@@ -0,0 +1,118 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+// Package collector implements the collectors of bluge for rescore and field collapsing.
+package collector
+
+import (
+	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/search"
+)
+
+// TopNSearch is the top n search which supports rescore and collapse,
+// it keeps the reader of the search to execute the rescore queries on the top documents.
+type TopNSearch struct {
+	*bluge.TopNSearch
+	rescorers []*Rescorer
+	collapse  *Collapse
+	reader    search.Reader
+	options   search.SearcherOptions
+}
+
+func NewTopNSearch(req *bluge.TopNSearch) *TopNSearch {
+	return &TopNSearch{TopNSearch: req}
+}
+
+// Rescore sets the rescorers which are executed in order
+func (s *TopNSearch) Rescore(rescorers ...*Rescorer) *TopNSearch {
+	s.rescorers = rescorers
+	return s
+}
+
+// Collapse sets the field collapsing of the search
+func (s *TopNSearch) Collapse(collapse *Collapse) *TopNSearch {
+	s.collapse = collapse
+	return s
+}
+
+func (s *TopNSearch) Searcher(i search.Reader, config bluge.Config) (search.Searcher, error) {
+	s.reader = i
+	s.options = search.SearcherOptions{
+		SimilarityForField: func(field string) search.Similarity {
+			if pfs, ok := config.PerFieldSimilarity[field]; ok {
+				return pfs
+			}
+			return config.DefaultSimilarity
+		},
+		DefaultSearchField: config.DefaultSearchField,
+		DefaultAnalyzer:    config.DefaultSearchAnalyzer,
+		Explain:            s.Options().ExplainScores,
+		Score:              s.Options().Score,
+	}
+	return s.TopNSearch.Searcher(i, config)
+}
+
+func (s *TopNSearch) Collector() search.Collector {
+	switch {
+	case s.collapse != nil:
+		return newCollapseCollector(s.Size(), s.From(), s.SortOrder(), s.collapse)
+	case len(s.rescorers) > 0:
+		return newRescoreCollector(s)
+	default:
+		return s.TopNSearch.Collector()
+	}
+}
+
+// InnerHitsIterator is implemented by the iterators of the collapsed search
+type InnerHitsIterator interface {
+	InnerHits(match *search.DocumentMatch) []*InnerHits
+}
+
+// DocumentIterator iterates the documents collected by the collectors of this package
+type DocumentIterator struct {
+	docs      []*search.DocumentMatch
+	bucket    *search.Bucket
+	innerHits map[*search.DocumentMatch][]*InnerHits
+	index     int
+}
+
+func (i *DocumentIterator) Next() (*search.DocumentMatch, error) {
+	if i.index >= len(i.docs) {
+		return nil, nil
+	}
+	doc := i.docs[i.index]
+	i.index++
+	return doc, nil
+}
+
+func (i *DocumentIterator) Aggregations() *search.Bucket {
+	return i.bucket
+}
+
+func (i *DocumentIterator) InnerHits(match *search.DocumentMatch) []*InnerHits {
+	return i.innerHits[match]
+}
+
+// page returns the documents of the page
+func page(docs []*search.DocumentMatch, from, size int) []*search.DocumentMatch {
+	if from >= len(docs) {
+		return nil
+	}
+	docs = docs[from:]
+	if size < len(docs) {
+		docs = docs[:size]
+	}
+	return docs
+}
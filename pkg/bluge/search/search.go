@@ -26,17 +26,34 @@ import (
 	"github.com/blugelabs/bluge/search/aggregations"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/zincsearch/zincsearch/pkg/bluge/collector"
 	"github.com/zincsearch/zincsearch/pkg/config"
 	"github.com/zincsearch/zincsearch/pkg/meta"
 	"github.com/zincsearch/zincsearch/pkg/uquery"
 )
 
+// Progress is called after the result of a reader is merged, dmi iterates the documents and aggregations
+// merged from the completed readers. The calls are serial and dmi is only valid during the call.
+type Progress func(dmi search.DocumentMatchIterator, completed int)
+
 func MultiSearch(
 	ctx context.Context,
 	query *meta.ZincQuery,
 	mappings *meta.Mappings,
 	analyzers map[string]*analysis.Analyzer,
 	readers ...*bluge.Reader,
+) (search.DocumentMatchIterator, error) {
+	return MultiSearchWithProgress(ctx, query, mappings, analyzers, nil, readers...)
+}
+
+// MultiSearchWithProgress searches like MultiSearch and reports the partial results by progress
+func MultiSearchWithProgress(
+	ctx context.Context,
+	query *meta.ZincQuery,
+	mappings *meta.Mappings,
+	analyzers map[string]*analysis.Analyzer,
+	progress Progress,
+	readers ...*bluge.Reader,
 ) (search.DocumentMatchIterator, error) {
 	if len(readers) == 0 {
 		return &DocumentList{
@@ -60,58 +77,76 @@ func MultiSearch(
 
 	eg := &errgroup.Group{}
 	eg.SetLimit(config.Global.Shard.GoroutineNum)
-	docs := make(chan *search.DocumentMatch, len(readers)*10)
-	aggs := make(chan *search.Bucket, len(readers))
+	results := make(chan *readerResult, len(readers))
 
 	docList := &DocumentList{
 		bucket: search.NewBucket("", bucketAggs),
 		from:   int64(query.From),
 		size:   int64(query.Size),
 	}
+	if query.Collapse != nil {
+		docList.collapseField = query.Collapse.Field
+	}
 	heap.Init(docList)
 	// handle skip and limit
 	maxSize := int64(query.Size)
 	query.Size += query.From
 	query.From = 0
 
-	egDoc := &errgroup.Group{}
-	egDoc.Go(func() error {
-		for doc := range docs {
-			heap.Push(docList, &Document{doc})
-		}
-		return nil
-	})
-	egDoc.Go(func() error {
-		for agg := range aggs {
-			docList.bucket.Merge(agg)
-		}
-		return nil
-	})
-
-	for _, r := range readers {
-		r := r
+	// parse the requests before searching, the query is read by progress while merging
+	reqs := make([]bluge.SearchRequest, len(readers))
+	for i := range readers {
 		req, err := uquery.ParseQueryDSL(query, mappings, analyzers)
 		if err != nil {
 			return nil, err
 		}
 		if docList.sort == nil {
-			if req, ok := req.(*bluge.TopNSearch); ok {
+			if req, ok := req.(interface{ SortOrder() search.SortOrder }); ok {
 				docList.sort = req.SortOrder().Copy()
 			}
 		}
+		reqs[i] = req
+	}
+
+	egDoc := &errgroup.Group{}
+	egDoc.Go(func() error {
+		completed := 0
+		for result := range results {
+			for _, doc := range result.docs {
+				heap.Push(docList, doc)
+			}
+			docList.bucket.Merge(result.bucket)
+			completed++
+			if progress != nil {
+				docList.bucket.Aggregation("duration").Finish()
+				progress(docList.snapshot(maxSize), completed)
+			}
+		}
+		return nil
+	})
+
+	for i, r := range readers {
+		r, req := r, reqs[i]
 		eg.Go(func() error {
 			var n int64
 			dmi, err := r.Search(ctx, req)
 			if err != nil {
 				return err
 			}
+			innerHits, _ := dmi.(collector.InnerHitsIterator)
+			result := new(readerResult)
 			next, err := dmi.Next()
 			for err == nil && next != nil {
 				n++
-				docs <- next
+				doc := &Document{doc: next}
+				if innerHits != nil {
+					doc.innerHits = innerHits.InnerHits(next)
+				}
+				result.docs = append(result.docs, doc)
 				next, err = dmi.Next()
 			}
-			aggs <- dmi.Aggregations()
+			result.bucket = dmi.Aggregations()
+			results <- result
 
 			if n > atomic.LoadInt64(&docList.size) {
 				atomic.StoreInt64(&docList.size, n)
@@ -120,14 +155,13 @@ func MultiSearch(
 			return err
 		})
 	}
-	if err := eg.Wait(); err != nil {
+	err := eg.Wait()
+	close(results)
+	_ = egDoc.Wait()
+	if err != nil {
 		return nil, err
 	}
 
-	close(docs)
-	close(aggs)
-	_ = egDoc.Wait()
-
 	docList.Done()
 	docList.bucket.Aggregation("duration").Finish()
 
@@ -138,21 +172,33 @@ func MultiSearch(
 	return docList, nil
 }
 
+// readerResult is the documents and aggregations searched from a reader
+type readerResult struct {
+	docs   []*Document
+	bucket *search.Bucket
+}
+
 type Document struct {
-	doc *search.DocumentMatch
+	doc       *search.DocumentMatch
+	innerHits []*collector.InnerHits
 }
 
 type DocumentList struct {
-	from   int64
-	size   int64
-	len    int64
-	next   int64
-	docs   []*Document
-	bucket *search.Bucket
-	sort   search.SortOrder
+	from          int64
+	size          int64
+	len           int64
+	next          int64
+	docs          []*Document
+	bucket        *search.Bucket
+	sort          search.SortOrder
+	collapseField string
+	innerHits     map[*search.DocumentMatch][]*collector.InnerHits
 }
 
 func (d *DocumentList) Done() {
+	if d.collapseField != "" {
+		d.collapse()
+	}
 	// do skip
 	alldocLen := int64(d.Len())
 	for i := int64(0); i < d.from && i < alldocLen; i++ {
@@ -162,6 +208,25 @@ func (d *DocumentList) Done() {
 	d.len = int64(len(d.docs))
 }
 
+// snapshot copies the documents merged so far into a finished list of size,
+// the aggregations are shared with d.
+func (d *DocumentList) snapshot(size int64) *DocumentList {
+	docs := make([]*Document, len(d.docs))
+	for i, doc := range d.docs {
+		docs[i] = &Document{doc: doc.doc, innerHits: doc.innerHits}
+	}
+	s := &DocumentList{
+		from:          d.from,
+		size:          size,
+		docs:          docs,
+		bucket:        d.bucket,
+		sort:          d.sort,
+		collapseField: d.collapseField,
+	}
+	s.Done()
+	return s
+}
+
 func (d *DocumentList) Next() (*search.DocumentMatch, error) {
 	if d.next >= d.size || d.next >= d.len {
 		return nil, nil
@@ -175,6 +240,42 @@ func (d *DocumentList) Aggregations() *search.Bucket {
 	return d.bucket
 }
 
+func (d *DocumentList) InnerHits(match *search.DocumentMatch) []*collector.InnerHits {
+	return d.innerHits[match]
+}
+
+// collapse keeps the top document of each group collected by the readers,
+// the inner hits of the same group are merged into the top document.
+func (d *DocumentList) collapse() {
+	d.innerHits = make(map[*search.DocumentMatch][]*collector.InnerHits)
+	groups := make(map[string]*Document)
+	var missing *Document
+	docs := make([]*Document, 0, d.Len())
+	for d.Len() > 0 {
+		doc := heap.Pop(d).(*Document)
+		value := collector.GroupValue(doc.doc, d.collapseField)
+		top := missing
+		if value != nil {
+			top = groups[string(value)]
+		}
+		if top != nil {
+			top.innerHits = collector.MergeInnerHits(top.innerHits, doc.innerHits)
+			continue
+		}
+		if value != nil {
+			groups[string(value)] = doc
+		} else {
+			missing = doc
+		}
+		docs = append(docs, doc)
+	}
+	for _, doc := range docs {
+		d.innerHits[doc.doc] = doc.innerHits
+	}
+	d.docs = docs
+	heap.Init(d)
+}
+
 func (d *DocumentList) Push(doc interface{}) {
 	d.docs = append(d.docs, doc.(*Document))
 }
@@ -57,8 +57,10 @@ type config struct {
 	WalSyncInterval           time.Duration `env:"ZINC_WAL_SYNC_INTERVAL,default=1s"`      // sync wal to disk, 1s, 10ms
 	WalRedoLogNoSync          bool          `env:"ZINC_WAL_REDOLOG_NO_SYNC,default=false"` // control sync after every write
 	ZincSwaggerEnable         bool          `env:"ZINC_SWAGGER_ENABLE,default=true"`
+	SnapshotRepoPath          []string      `env:"ZINC_SNAPSHOT_REPO_PATH,default=./snapshots"` // allowed locations of fs snapshot repository
 	Cluster                   cluster
 	Shard                     shard
+	AsyncSearch               asyncSearch
 	Etcd                      etcd
 	Plugin                    plugin
 }
@@ -76,6 +78,11 @@ type shard struct {
 	MaxSize uint64 `env:"ZINC_SHARD_MAX_SIZE,default=1073741824"`
 }
 
+type asyncSearch struct {
+	// SpillToMetadata moves the completed async search results from memory to the metadata store.
+	SpillToMetadata bool `env:"ZINC_ASYNC_SEARCH_SPILL_TO_METADATA,default=false"`
+}
+
 type etcd struct {
 	Endpoints []string `env:"ZINC_ETCD_ENDPOINTS"`
 	Prefix    string   `env:"ZINC_ETCD_PREFIX,default=/zinc"`
@@ -84,8 +91,11 @@ type etcd struct {
 }
 
 type plugin struct {
-	ES  elasticsearch
-	GSE gse
+	ES       elasticsearch
+	GSE      gse
+	Kuromoji kuromoji
+	Nori     nori
+	Thai     thai
 }
 
 type elasticsearch struct {
@@ -100,6 +110,18 @@ type gse struct {
 	DictPath   string `env:"ZINC_PLUGIN_GSE_DICT_PATH,default=./plugins/gse/dict"`
 }
 
+type kuromoji struct {
+	DictPath string `env:"ZINC_PLUGIN_KUROMOJI_DICT_PATH,default=./plugins/kuromoji/dict"`
+}
+
+type nori struct {
+	DictPath string `env:"ZINC_PLUGIN_NORI_DICT_PATH,default=./plugins/nori/dict"`
+}
+
+type thai struct {
+	DictPath string `env:"ZINC_PLUGIN_THAI_DICT_PATH,default=./plugins/thai/dict"`
+}
+
 var Global = new(config)
 
 func init() {
@@ -0,0 +1,310 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/ider"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/metadata"
+)
+
+// asyncSearchCleanInterval is the interval to remove the expired async searches
+const asyncSearchCleanInterval = time.Minute
+
+var ZINC_ASYNC_SEARCH_LIST = &AsyncSearchList{searches: make(map[string]*AsyncSearch)}
+
+type AsyncSearchList struct {
+	searches map[string]*AsyncSearch
+	lock     sync.RWMutex
+}
+
+// AsyncSearch is a search running in background, the response is updated with the partial results
+// when a reader is completed, the completed search may be spilled to the metadata store.
+type AsyncSearch struct {
+	ref       meta.AsyncSearchResponse
+	err       error
+	done      chan struct{}
+	cancel    context.CancelFunc
+	submitted bool
+	deleted   bool
+	lock      sync.RWMutex
+}
+
+// SubmitAsyncSearch starts the search in background and waits for the completion until wait,
+// the search is kept until keepAlive expired. The search completed in wait is only kept if keepOnCompletion.
+func SubmitAsyncSearch(indexNames []string, query *meta.ZincQuery, wait, keepAlive time.Duration, keepOnCompletion bool) (*meta.AsyncSearchResponse, error) {
+	start := time.Now()
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &AsyncSearch{done: make(chan struct{}), cancel: cancel}
+	s.ref = meta.AsyncSearchResponse{
+		ID:                     ider.Generate(),
+		IsPartial:              true,
+		IsRunning:              true,
+		StartTimeInMillis:      start.UnixMilli(),
+		ExpirationTimeInMillis: start.Add(keepAlive).UnixMilli(),
+		Response:               &meta.SearchResponse{Hits: meta.Hits{Hits: []meta.Hit{}}},
+	}
+	ZINC_ASYNC_SEARCH_LIST.Add(s)
+
+	go func() {
+		resp, err := MultiSearchWithProgress(ctx, indexNames, query, s.update)
+		s.finish(resp, err)
+	}()
+	s.Wait(wait)
+
+	s.lock.Lock()
+	s.submitted = true
+	completed := !s.ref.IsRunning
+	err := s.err
+	s.lock.Unlock()
+	if completed && (err != nil || !keepOnCompletion) {
+		ZINC_ASYNC_SEARCH_LIST.Remove(s.GetID())
+		if err != nil {
+			return nil, err
+		}
+		ret := s.GetResult()
+		ret.ID = ""
+		return &ret, nil
+	}
+	if completed {
+		s.spill()
+	}
+	ret := s.GetResult()
+	return &ret, nil
+}
+
+// GetAsyncSearch returns the async search by id, it waits for the completion until wait,
+// the expiration time is extended by keepAlive if it is greater than zero.
+func GetAsyncSearch(id string, wait, keepAlive time.Duration) (*meta.AsyncSearchResponse, error) {
+	if s, ok := ZINC_ASYNC_SEARCH_LIST.Get(id); ok {
+		s.Wait(wait)
+		if keepAlive > 0 {
+			s.KeepAlive(keepAlive)
+		}
+		ret := s.GetResult()
+		return &ret, nil
+	}
+
+	ret, err := metadata.AsyncSearch.Get(id)
+	if err != nil {
+		if err == errors.ErrKeyNotFound {
+			return nil, asyncSearchNotFound(id)
+		}
+		return nil, err
+	}
+	if ret.ExpirationTimeInMillis < time.Now().UnixMilli() {
+		_ = metadata.AsyncSearch.Delete(id)
+		return nil, asyncSearchNotFound(id)
+	}
+	if keepAlive > 0 {
+		ret.ExpirationTimeInMillis = time.Now().Add(keepAlive).UnixMilli()
+		if err = metadata.AsyncSearch.Set(id, *ret); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// DeleteAsyncSearch cancels the running async search and deletes the response
+func DeleteAsyncSearch(id string) error {
+	s, ok := ZINC_ASYNC_SEARCH_LIST.Get(id)
+	if ok {
+		s.Delete()
+		ZINC_ASYNC_SEARCH_LIST.Remove(id)
+	}
+	_, err := metadata.AsyncSearch.Get(id)
+	if err != nil {
+		if err == errors.ErrKeyNotFound {
+			if ok {
+				return nil
+			}
+			return asyncSearchNotFound(id)
+		}
+		return err
+	}
+	return metadata.AsyncSearch.Delete(id)
+}
+
+func asyncSearchNotFound(id string) error {
+	return errors.New(errors.ErrorTypeResourceNotFound, "async search ["+id+"] is not found")
+}
+
+func (s *AsyncSearch) GetID() string {
+	return s.ref.ID
+}
+
+// GetResult returns a copy of the async search response
+func (s *AsyncSearch) GetResult() meta.AsyncSearchResponse {
+	s.lock.RLock()
+	ret := s.ref
+	s.lock.RUnlock()
+	return ret
+}
+
+// Wait blocks until the search completed or timeout
+func (s *AsyncSearch) Wait(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-s.done:
+	case <-timer.C:
+	}
+}
+
+// KeepAlive extends the expiration time to keepAlive from now
+func (s *AsyncSearch) KeepAlive(keepAlive time.Duration) {
+	s.lock.Lock()
+	s.ref.ExpirationTimeInMillis = time.Now().Add(keepAlive).UnixMilli()
+	s.lock.Unlock()
+}
+
+// IsExpired checks the expiration time is passed
+func (s *AsyncSearch) IsExpired() bool {
+	s.lock.RLock()
+	expired := s.ref.ExpirationTimeInMillis < time.Now().UnixMilli()
+	s.lock.RUnlock()
+	return expired
+}
+
+// Delete cancels the search and prevents the response from being spilled
+func (s *AsyncSearch) Delete() {
+	s.lock.Lock()
+	s.deleted = true
+	s.lock.Unlock()
+	s.cancel()
+}
+
+// update replaces the response with the partial response
+func (s *AsyncSearch) update(resp *meta.SearchResponse) {
+	s.lock.Lock()
+	if s.ref.IsRunning {
+		s.ref.Response = resp
+	}
+	s.lock.Unlock()
+}
+
+// finish marks the search completed with the response or error, the partial response is kept if error
+func (s *AsyncSearch) finish(resp *meta.SearchResponse, err error) {
+	s.lock.Lock()
+	s.ref.IsRunning = false
+	s.ref.CompletionTimeInMillis = time.Now().UnixMilli()
+	if err != nil {
+		var e *errors.Error
+		if !errors.As(err, &e) {
+			e = errors.New(errors.ErrorTypeRuntimeException, err.Error())
+		}
+		s.err = e
+		s.ref.Error = e
+	} else {
+		s.ref.IsPartial = resp.TimedOut
+		s.ref.Response = resp
+	}
+	submitted := s.submitted
+	s.lock.Unlock()
+	close(s.done)
+	s.cancel()
+
+	// the search completed in wait is spilled by submit if it should be kept
+	if submitted {
+		s.spill()
+	}
+}
+
+// spill moves the completed search to the metadata store if it is enabled
+func (s *AsyncSearch) spill() {
+	if !config.Global.AsyncSearch.SpillToMetadata {
+		return
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if s.deleted {
+		return
+	}
+	if err := metadata.AsyncSearch.Set(s.ref.ID, s.ref); err != nil {
+		log.Error().Err(err).Str("id", s.ref.ID).Msg("spill async search failed")
+		return
+	}
+	ZINC_ASYNC_SEARCH_LIST.Remove(s.ref.ID)
+}
+
+func (l *AsyncSearchList) Add(s *AsyncSearch) {
+	l.lock.Lock()
+	l.searches[s.GetID()] = s
+	l.lock.Unlock()
+}
+
+// Get returns the async search by id, the expired search isn't returned
+func (l *AsyncSearchList) Get(id string) (*AsyncSearch, bool) {
+	l.lock.RLock()
+	s, ok := l.searches[id]
+	l.lock.RUnlock()
+	if ok && s.IsExpired() {
+		return nil, false
+	}
+	return s, ok
+}
+
+func (l *AsyncSearchList) Remove(id string) {
+	l.lock.Lock()
+	delete(l.searches, id)
+	l.lock.Unlock()
+}
+
+func (l *AsyncSearchList) List() []*AsyncSearch {
+	l.lock.RLock()
+	searches := make([]*AsyncSearch, 0, len(l.searches))
+	for _, s := range l.searches {
+		searches = append(searches, s)
+	}
+	l.lock.RUnlock()
+	return searches
+}
+
+// Clean deletes the expired async searches in memory and the metadata store periodically
+func (l *AsyncSearchList) Clean() {
+	tick := time.NewTicker(asyncSearchCleanInterval)
+	for range tick.C {
+		for _, s := range l.List() {
+			if s.IsExpired() {
+				s.Delete()
+				l.Remove(s.GetID())
+			}
+		}
+
+		searches, err := metadata.AsyncSearch.List(0, 0)
+		if err != nil {
+			log.Error().Err(err).Msg("list spilled async searches failed")
+			continue
+		}
+		now := time.Now().UnixMilli()
+		for _, ref := range searches {
+			if ref.ExpirationTimeInMillis < now {
+				_ = metadata.AsyncSearch.Delete(ref.ID)
+			}
+		}
+	}
+}
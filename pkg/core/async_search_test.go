@@ -0,0 +1,116 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/metadata"
+)
+
+func TestAsyncSearch(t *testing.T) {
+	indexName := "TestAsyncSearch.index_1"
+	query := func() *meta.ZincQuery {
+		return &meta.ZincQuery{
+			Query: &meta.Query{MatchAll: &meta.MatchAllQuery{}},
+			Size:  10,
+		}
+	}
+
+	t.Run("prepare", func(t *testing.T) {
+		index, err := NewIndex(indexName, "disk", 2)
+		assert.NoError(t, err)
+		assert.NotNil(t, index)
+		err = StoreIndex(index)
+		assert.NoError(t, err)
+		for i := 0; i < 10; i++ {
+			err = index.CreateDocument(strconv.Itoa(i), map[string]interface{}{"name": "doc " + strconv.Itoa(i)}, false)
+			assert.NoError(t, err)
+		}
+		// wait for WAL write to index
+		assert.Eventually(t, func() bool {
+			resp, err := MultiSearch([]string{indexName}, query())
+			return err == nil && resp.Hits.Total.Value == 10
+		}, 10*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("progress", func(t *testing.T) {
+		partials := make([]*meta.SearchResponse, 0)
+		resp, err := MultiSearchWithProgress(context.Background(), []string{indexName}, query(), func(resp *meta.SearchResponse) {
+			partials = append(partials, resp)
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 10, resp.Hits.Total.Value)
+		assert.NotEmpty(t, partials)
+		for i, partial := range partials {
+			assert.Equal(t, int64(i+1), partial.Shards.Successful)
+			assert.LessOrEqual(t, partial.Hits.Total.Value, resp.Hits.Total.Value)
+		}
+		assert.Equal(t, resp.Hits.Total.Value, partials[len(partials)-1].Hits.Total.Value)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := MultiSearchWithProgress(ctx, []string{indexName}, query(), nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("spill to metadata", func(t *testing.T) {
+		config.Global.AsyncSearch.SpillToMetadata = true
+		defer func() {
+			config.Global.AsyncSearch.SpillToMetadata = false
+		}()
+
+		resp, err := SubmitAsyncSearch([]string{indexName}, query(), 10*time.Second, time.Minute, true)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, resp.ID)
+		assert.False(t, resp.IsRunning)
+		_, ok := ZINC_ASYNC_SEARCH_LIST.Get(resp.ID)
+		assert.False(t, ok)
+
+		got, err := GetAsyncSearch(resp.ID, 0, time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, 10, got.Response.Hits.Total.Value)
+		assert.Greater(t, got.ExpirationTimeInMillis, resp.ExpirationTimeInMillis)
+
+		assert.NoError(t, DeleteAsyncSearch(resp.ID))
+		_, err = metadata.AsyncSearch.Get(resp.ID)
+		assert.Error(t, err)
+		_, err = GetAsyncSearch(resp.ID, 0, 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		resp, err := SubmitAsyncSearch([]string{indexName}, query(), 10*time.Second, time.Millisecond, true)
+		assert.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+		_, err = GetAsyncSearch(resp.ID, 0, 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		err := DeleteIndex(indexName)
+		assert.NoError(t, err)
+	})
+}
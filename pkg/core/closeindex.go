@@ -0,0 +1,117 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	zincanalysis "github.com/zincsearch/zincsearch/pkg/uquery/analysis"
+)
+
+// CloseIndex closes the index and persists the closed state,
+// a closed index keeps its data and metadata but can not be read or written until opened
+func CloseIndex(name string) error {
+	index, exists := GetIndex(name)
+	if !exists {
+		return errors.New(errors.ErrorTypeIndexNotFoundException, "index ["+name+"] does not exists")
+	}
+
+	index.lock.Lock()
+	if index.ref.Closed {
+		index.lock.Unlock()
+		return nil
+	}
+	index.ref.Closed = true
+	index.analyzers = nil
+	index.lock.Unlock()
+
+	// write the documents in WAL into index, then the stats in metadata are correct
+	index.flushWAL()
+
+	if err := index.UpdateMetadata(); err != nil {
+		return err
+	}
+	return index.Close()
+}
+
+// OpenIndex opens the closed index, the shards will open automatically by trigger
+func OpenIndex(name string) error {
+	index, exists := GetIndex(name)
+	if !exists {
+		return errors.New(errors.ErrorTypeIndexNotFoundException, "index ["+name+"] does not exists")
+	}
+	if !index.IsClosed() {
+		return nil
+	}
+
+	settings := index.GetSettings()
+	var analysis *meta.IndexAnalysis
+	if settings != nil {
+		analysis = settings.Analysis
+	}
+	analyzers, err := zincanalysis.RequestAnalyzer(analysis)
+	if err != nil {
+		return errors.New(errors.ErrorTypeRuntimeException, "parse stored analysis error").Cause(err)
+	}
+
+	index.lock.Lock()
+	index.ref.Closed = false
+	index.analyzers = analyzers
+	index.lock.Unlock()
+
+	return storeIndex(index)
+}
+
+// IsClosed returns true if the index is closed
+func (index *Index) IsClosed() bool {
+	index.lock.RLock()
+	closed := index.ref.Closed
+	index.lock.RUnlock()
+	return closed
+}
+
+// CheckOpen returns an error if the index is closed
+func (index *Index) CheckOpen() error {
+	if index.IsClosed() {
+		return errors.New(errors.ErrorTypeIndexClosedException, "index ["+index.GetName()+"] is closed")
+	}
+	return nil
+}
+
+// CheckWrite returns an error if the index is closed or blocked for writing
+func (index *Index) CheckWrite() error {
+	if err := index.CheckOpen(); err != nil {
+		return err
+	}
+	index.lock.RLock()
+	blocked := index.ref.Settings.IsWriteBlocked()
+	index.lock.RUnlock()
+	if blocked {
+		return errors.New(errors.ErrorTypeClusterBlockException, "index ["+index.GetName()+"] blocked by: [FORBIDDEN/index write (api)]")
+	}
+	return nil
+}
+
+// CheckMetadataWrite returns an error if the index is read only, it blocks mappings and settings changes
+func (index *Index) CheckMetadataWrite() error {
+	index.lock.RLock()
+	readOnly := index.ref.Settings.IsReadOnly()
+	index.lock.RUnlock()
+	if readOnly {
+		return errors.New(errors.ErrorTypeClusterBlockException, "index ["+index.GetName()+"] blocked by: [FORBIDDEN/index read-only (api)]")
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/metadata"
+)
+
+func TestCloseIndex(t *testing.T) {
+	indexName := "TestCloseIndex.index_1"
+
+	t.Run("prepare", func(t *testing.T) {
+		index, err := NewIndex(indexName, "disk", 2)
+		assert.NoError(t, err)
+		assert.NoError(t, StoreIndex(index))
+		assert.NoError(t, index.CreateDocument("1", map[string]interface{}{"name": "Prabhat"}, false))
+	})
+
+	t.Run("close", func(t *testing.T) {
+		assert.NoError(t, CloseIndex(indexName))
+		index, ok := GetIndex(indexName)
+		assert.True(t, ok)
+		assert.True(t, index.IsClosed())
+		assert.Equal(t, uint64(1), index.GetStats().DocNum)
+
+		// closed again is ok
+		assert.NoError(t, CloseIndex(indexName))
+
+		// persisted in metadata
+		stored, err := metadata.Index.Get(indexName)
+		assert.NoError(t, err)
+		assert.True(t, stored.Closed)
+
+		// can not read or write
+		var e *errors.Error
+		err = index.CreateDocument("2", map[string]interface{}{"name": "Hengfei"}, false)
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, errors.ErrorTypeIndexClosedException, e.Type)
+		_, err = index.GetDocument("1")
+		assert.Error(t, err)
+		_, err = index.Search(&meta.ZincQuery{Size: 10})
+		assert.Error(t, err)
+
+		// skipped by wildcard search, but explicit name returns error
+		resp, err := MultiSearch([]string{"TestCloseIndex.*"}, &meta.ZincQuery{Size: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, resp.Hits.Total.Value)
+		_, err = MultiSearch([]string{indexName}, &meta.ZincQuery{Size: 10})
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, errors.ErrorTypeIndexClosedException, e.Type)
+	})
+
+	t.Run("load closed index from metadata", func(t *testing.T) {
+		stored, err := metadata.Index.Get(indexName)
+		assert.NoError(t, err)
+		index, err := newIndexFromMetadata(stored)
+		assert.NoError(t, err)
+		assert.True(t, index.IsClosed())
+		assert.Nil(t, index.GetAnalyzers())
+	})
+
+	t.Run("open", func(t *testing.T) {
+		assert.NoError(t, OpenIndex(indexName))
+		index, _ := GetIndex(indexName)
+		assert.False(t, index.IsClosed())
+		hit, err := index.GetDocument("1")
+		assert.NoError(t, err)
+		assert.Equal(t, "1", hit.ID)
+		assert.NoError(t, index.CreateDocument("2", map[string]interface{}{"name": "Hengfei"}, false))
+	})
+
+	t.Run("not exists", func(t *testing.T) {
+		assert.Error(t, CloseIndex("TestCloseIndex.not_exists"))
+		assert.Error(t, OpenIndex("TestCloseIndex.not_exists"))
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		assert.NoError(t, DeleteIndex(indexName))
+	})
+}
+
+func TestIndex_CheckWrite(t *testing.T) {
+	indexName := "TestIndex_CheckWrite.index_1"
+	yes, no := true, false
+
+	index, err := NewIndex(indexName, "disk", 1)
+	assert.NoError(t, err)
+	assert.NoError(t, StoreIndex(index))
+	assert.NoError(t, index.CreateDocument("1", map[string]interface{}{"name": "Prabhat"}, false))
+
+	var e *errors.Error
+	assert.NoError(t, index.SetSettings(&meta.IndexSettings{Blocks: &meta.IndexBlocks{Write: &yes}}))
+	err = index.CreateDocument("2", map[string]interface{}{"name": "Hengfei"}, false)
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, errors.ErrorTypeClusterBlockException, e.Type)
+	assert.Error(t, index.UpdateDocument("1", map[string]interface{}{"name": "Hengfei"}, false))
+	assert.Error(t, index.DeleteDocument("1"))
+	assert.NoError(t, index.CheckMetadataWrite())
+
+	assert.NoError(t, index.SetSettings(&meta.IndexSettings{Blocks: &meta.IndexBlocks{Write: &no, ReadOnly: &yes}}))
+	assert.Error(t, index.CheckWrite())
+	assert.Error(t, index.CheckMetadataWrite())
+
+	assert.NoError(t, index.SetSettings(&meta.IndexSettings{Blocks: &meta.IndexBlocks{ReadOnly: &no}}))
+	assert.NoError(t, index.CheckWrite())
+	assert.NoError(t, index.CreateDocument("2", map[string]interface{}{"name": "Hengfei"}, false))
+
+	assert.NoError(t, DeleteIndex(indexName))
+}
@@ -16,6 +16,7 @@
 package core
 
 import (
+	"sort"
 	"sync"
 	"sync/atomic"
 
@@ -35,6 +36,8 @@ type Index struct {
 	shards       map[string]*IndexShard
 	shardNum     int64
 	shardHashing *rendezvous.Rendezvous
+	counters     indexCounters
+	aliases      []string // the aliases of template, added when the index is stored
 	lock         sync.RWMutex
 }
 
@@ -90,6 +93,26 @@ func (index *Index) GetStats() meta.IndexStat {
 	return s
 }
 
+// GetShards return a copy of first layer shards metadata sorted by shard ID, include second layer shards
+func (index *Index) GetShards() []meta.IndexShard {
+	index.lock.RLock()
+	shards := make([]meta.IndexShard, 0, len(index.ref.Shards))
+	for _, shard := range index.ref.Shards {
+		s := *shard
+		s.Shards = make([]*meta.IndexSecondShard, 0, len(shard.Shards))
+		for _, secondShard := range shard.Shards {
+			ss := *secondShard
+			s.Shards = append(s.Shards, &ss)
+		}
+		shards = append(shards, s)
+	}
+	index.lock.RUnlock()
+	sort.Slice(shards, func(i, j int) bool {
+		return shards[i].ID < shards[j].ID
+	})
+	return shards
+}
+
 func (index *Index) UpdateWALSize(size uint64) {
 	index.lock.Lock()
 	index.ref.Stats.WALSize = size
@@ -113,17 +136,29 @@ func (index *Index) UseTemplate() error {
 		return nil
 	}
 
-	if template.Template.Settings != nil {
+	// merge the component templates
+	resolved, err := ResolveTemplate(index.GetName(), template)
+	if err != nil {
+		return err
+	}
+
+	if resolved.Settings != nil {
 		// update settings
-		_ = index.SetSettings(template.Template.Settings)
+		_ = index.SetSettings(resolved.Settings)
 		// update analyzers
-		analyzers, _ := zincanalysis.RequestAnalyzer(template.Template.Settings.Analysis)
+		analyzers, _ := zincanalysis.RequestAnalyzer(resolved.Settings.Analysis)
 		_ = index.SetAnalyzers(analyzers)
 	}
 
-	if template.Template.Mappings != nil {
-		_ = index.SetMappings(template.Template.Mappings)
+	if resolved.Mappings != nil {
+		_ = index.SetMappings(resolved.Mappings)
+	}
+
+	index.lock.Lock()
+	for alias := range resolved.Aliases {
+		index.aliases = append(index.aliases, alias)
 	}
+	index.lock.Unlock()
 
 	return nil
 }
@@ -155,6 +190,14 @@ func (index *Index) SetSettings(settings *meta.IndexSettings) error {
 				index.ref.Settings.Analysis.Analyzer[k] = v
 			}
 		}
+		if settings.Analysis.Normalizer != nil {
+			if index.ref.Settings.Analysis.Normalizer == nil {
+				index.ref.Settings.Analysis.Normalizer = make(map[string]*meta.Analyzer)
+			}
+			for k, v := range settings.Analysis.Normalizer {
+				index.ref.Settings.Analysis.Normalizer[k] = v
+			}
+		}
 		if settings.Analysis.CharFilter != nil {
 			if index.ref.Settings.Analysis.CharFilter == nil {
 				index.ref.Settings.Analysis.CharFilter = make(map[string]interface{})
@@ -180,6 +223,17 @@ func (index *Index) SetSettings(settings *meta.IndexSettings) error {
 			}
 		}
 	}
+	if settings.Blocks != nil {
+		if index.ref.Settings.Blocks == nil {
+			index.ref.Settings.Blocks = new(meta.IndexBlocks)
+		}
+		if settings.Blocks.ReadOnly != nil {
+			index.ref.Settings.Blocks.ReadOnly = settings.Blocks.ReadOnly
+		}
+		if settings.Blocks.Write != nil {
+			index.ref.Settings.Blocks.Write = settings.Blocks.Write
+		}
+	}
 	index.lock.Unlock()
 
 	return nil
@@ -202,9 +256,9 @@ func (index *Index) SetMappings(mappings *meta.Mappings) error {
 		mappings = meta.NewMappings()
 	}
 
-	// custom analyzer just for text field
+	// custom analyzer just for text and completion field
 	for field, prop := range mappings.ListProperty() {
-		if prop.Type != "text" {
+		if prop.Type != "text" && prop.Type != "completion" {
 			prop.Analyzer = ""
 			prop.SearchAnalyzer = ""
 			mappings.SetProperty(field, prop)
@@ -231,6 +285,9 @@ func (index *Index) SetMappings(mappings *meta.Mappings) error {
 	for field, prop := range mappings.ListProperty() {
 		index.ref.Mappings.SetProperty(field, prop)
 	}
+	if index.ref.Mappings != mappings {
+		index.ref.Mappings.MergeDynamic(mappings.GetDynamic())
+	}
 	index.lock.Unlock()
 
 	return nil
@@ -248,18 +305,26 @@ func (index *Index) GetWALSize() uint64 {
 	return size
 }
 
-// GetReaders return all shard readers
+// GetReaders return all shard readers, the readers should be closed by CloseReaders
 func (index *Index) GetReaders(timeMin, timeMax int64) ([]*bluge.Reader, error) {
+	if err := index.CheckOpen(); err != nil {
+		return nil, err
+	}
 	readers := make([]*bluge.Reader, 0)
 	for _, shard := range index.shards {
 		rs, err := shard.GetReaders(timeMin, timeMax)
 		if err != nil {
+			for _, r := range readers {
+				_ = r.Close()
+			}
 			return nil, err
 		}
 		if len(rs) > 0 {
 			readers = append(readers, rs...)
 		}
 	}
+	n := atomic.AddInt64(&index.counters.openReaders, int64(len(readers)))
+	SetMetricStatsByIndex(index.GetName(), "open_readers", float64(n))
 	return readers, nil
 }
 
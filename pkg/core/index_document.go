@@ -21,11 +21,43 @@ import (
 
 	"github.com/zincsearch/zincsearch/pkg/errors"
 	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/uquery/query"
 	"github.com/zincsearch/zincsearch/pkg/zutils/json"
 )
 
+func init() {
+	query.DocumentGetter = getDocumentSource
+}
+
+// getDocumentSource returns the source of document, it is used by more_like_this query
+func getDocumentSource(indexName, docID string) (map[string]interface{}, error) {
+	index, ok := ZINC_INDEX_LIST.Get(indexName)
+	if !ok {
+		return nil, errors.New(errors.ErrorTypeIndexNotFoundException, "index "+indexName+" does not exists")
+	}
+	hit, err := index.GetDocument(docID)
+	if err != nil {
+		return nil, err
+	}
+	source, ok := hit.Source.(map[string]interface{})
+	if !ok {
+		return nil, errors.New(errors.ErrorTypeRuntimeException, "document "+docID+" has no source")
+	}
+	return source, nil
+}
+
 // CreateDocument inserts or updates a document in the zinc index
 func (index *Index) CreateDocument(docID string, doc map[string]interface{}, update bool) error {
+	err := index.createDocument(docID, doc, update)
+	index.recordIndexing(meta.ActionTypeInsert, err)
+	return err
+}
+
+func (index *Index) createDocument(docID string, doc map[string]interface{}, update bool) error {
+	if err := index.CheckWrite(); err != nil {
+		return err
+	}
+
 	// metrics
 	IncrMetricStatsByIndex(index.GetName(), "wal_request")
 
@@ -49,6 +81,10 @@ func (index *Index) CreateDocument(docID string, doc map[string]interface{}, upd
 
 // GetDocument get a document in the zinc index
 func (index *Index) GetDocument(docID string) (*meta.Hit, error) {
+	if err := index.CheckOpen(); err != nil {
+		return nil, err
+	}
+
 	// check WAL
 	shard := index.GetShardByDocID(docID)
 	if err := shard.OpenWAL(); err != nil {
@@ -60,6 +96,16 @@ func (index *Index) GetDocument(docID string) (*meta.Hit, error) {
 
 // UpdateDocument updates a document in the zinc index
 func (index *Index) UpdateDocument(docID string, doc map[string]interface{}, insert bool) error {
+	err := index.updateDocument(docID, doc, insert)
+	index.recordIndexing(meta.ActionTypeUpdate, err)
+	return err
+}
+
+func (index *Index) updateDocument(docID string, doc map[string]interface{}, insert bool) error {
+	if err := index.CheckWrite(); err != nil {
+		return err
+	}
+
 	// metrics
 	IncrMetricStatsByIndex(index.GetName(), "wal_request")
 
@@ -89,6 +135,16 @@ func (index *Index) UpdateDocument(docID string, doc map[string]interface{}, ins
 
 // DeleteDocument deletes a document in the zinc index
 func (index *Index) DeleteDocument(docID string) error {
+	err := index.deleteDocument(docID)
+	index.recordIndexing(meta.ActionTypeDelete, err)
+	return err
+}
+
+func (index *Index) deleteDocument(docID string) error {
+	if err := index.CheckWrite(); err != nil {
+		return err
+	}
+
 	// metrics
 	IncrMetricStatsByIndex(index.GetName(), "wal_request")
 
@@ -0,0 +1,123 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/blugelabs/bluge/analysis"
+
+	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/uquery/mappings"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+)
+
+// dynamicMappings returns the mappings of the new field, the first matched dynamic template is used,
+// otherwise it uses the default mapping of the detected type. It returns nil if the type can't be detected.
+func dynamicMappings(analyzers map[string]*analysis.Analyzer, dynamic meta.DynamicMapping, key string, value interface{}) (*meta.Mappings, error) {
+	mappingType, prop := detectDynamicType(dynamic, value)
+	if mappingType == "" {
+		return nil, nil
+	}
+
+	for _, templates := range dynamic.DynamicTemplates {
+		for name, tpl := range templates {
+			if !mappings.MatchDynamicTemplate(tpl, key, mappingType) {
+				continue
+			}
+			m, err := mappings.DynamicTemplate(analyzers, tpl, key, mappingType)
+			if err != nil {
+				return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("dynamic template [%s] can't map field [%s]: %s", name, key, err.Error()))
+			}
+			return m, nil
+		}
+	}
+
+	m := meta.NewMappings()
+	m.SetProperty(key, prop)
+	for field, p := range prop.Fields {
+		m.SetProperty(key+"."+field, p)
+	}
+	return m, nil
+}
+
+// detectDynamicType returns the dynamic type and the default property of the value,
+// the dynamic type is one of string, long, double, boolean and date as match_mapping_type in dynamic templates.
+func detectDynamicType(dynamic meta.DynamicMapping, value interface{}) (string, meta.Property) {
+	switch v := value.(type) {
+	case string:
+		if dynamic.DateDetection == nil || *dynamic.DateDetection {
+			if layout, ok := detectDateFormat(dynamic.DynamicDateFormats, v); ok {
+				prop := meta.NewProperty("date")
+				prop.Format = layout
+				return "date", prop
+			}
+		}
+		if dynamic.NumericDetection != nil && *dynamic.NumericDetection {
+			if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return "long", meta.NewProperty("numeric")
+			}
+			if _, err := strconv.ParseFloat(v, 64); err == nil {
+				return "double", meta.NewProperty("numeric")
+			}
+		}
+		prop := meta.NewProperty("text")
+		if config.Global.EnableTextKeywordMapping {
+			prop.AddField("keyword", meta.NewProperty("keyword"))
+		}
+		return "string", prop
+	case int, int64:
+		return "long", meta.NewProperty("numeric")
+	case json.Number:
+		// only the integer out of float64 precision is kept as json.Number, it needs the exact long field
+		prop := meta.NewProperty("numeric")
+		prop.NumericType = meta.NumericTypeLong
+		return "long", prop
+	case float64:
+		if v == math.Trunc(v) {
+			return "long", meta.NewProperty("numeric")
+		}
+		return "double", meta.NewProperty("numeric")
+	case bool:
+		return "boolean", meta.NewProperty("bool")
+	case []interface{}:
+		// use the first element to detect the type of array
+		for _, vv := range v {
+			if vv != nil {
+				return detectDynamicType(dynamic, vv)
+			}
+		}
+	}
+	return "", meta.Property{}
+}
+
+// detectDateFormat returns the layout of value, it uses the default layouts if formats is empty
+func detectDateFormat(formats []string, value string) (string, bool) {
+	if len(formats) == 0 {
+		return isDateProperty(value)
+	}
+	for _, layout := range formats {
+		if _, err := zutils.ParseTime(value, layout, ""); err == nil {
+			return layout, true
+		}
+	}
+	return "", false
+}
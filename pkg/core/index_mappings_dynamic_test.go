@@ -0,0 +1,158 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/uquery/mappings"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+)
+
+func TestIndex_DynamicMapping(t *testing.T) {
+	newIndexWithMappings := func(t *testing.T, name string, data map[string]interface{}) *Index {
+		index, err := NewIndex(name, "disk", 1)
+		assert.NoError(t, err)
+		m, err := mappings.Request(nil, data)
+		assert.NoError(t, err)
+		assert.NoError(t, index.SetMappings(m))
+		assert.NoError(t, StoreIndex(index))
+		return index
+	}
+
+	t.Run("strict", func(t *testing.T) {
+		index := newIndexWithMappings(t, "TestIndex_DynamicMapping.strict", map[string]interface{}{
+			"dynamic":    "strict",
+			"properties": map[string]interface{}{"name": map[string]interface{}{"type": "keyword"}},
+		})
+		defer DeleteIndex(index.GetName())
+
+		assert.NoError(t, index.CreateDocument("1", map[string]interface{}{"name": "Prabhat"}, false))
+		err := index.CreateDocument("2", map[string]interface{}{"name": "Hengfei", "age": 30}, false)
+		var e *errors.Error
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, errors.ErrorTypeStrictDynamicMapping, e.Type)
+	})
+
+	t.Run("false", func(t *testing.T) {
+		index := newIndexWithMappings(t, "TestIndex_DynamicMapping.false", map[string]interface{}{"dynamic": false})
+		defer DeleteIndex(index.GetName())
+
+		assert.NoError(t, index.CreateDocument("1", map[string]interface{}{"name": "Prabhat"}, false))
+		_, ok := index.GetMappings().GetProperty("name")
+		assert.False(t, ok)
+	})
+
+	t.Run("templates and detection", func(t *testing.T) {
+		index := newIndexWithMappings(t, "TestIndex_DynamicMapping.templates", map[string]interface{}{
+			"date_detection":       false,
+			"numeric_detection":    true,
+			"dynamic_date_formats": []interface{}{"2006/01/02"},
+			"dynamic_templates": []interface{}{
+				map[string]interface{}{"ids": map[string]interface{}{
+					"match":              "*_id",
+					"match_mapping_type": "string",
+					"mapping":            map[string]interface{}{"type": "keyword"},
+				}},
+				map[string]interface{}{"meta": map[string]interface{}{
+					"path_match": "meta.*",
+					"mapping":    map[string]interface{}{"type": "{dynamic_type}", "index": false},
+				}},
+			},
+		})
+		defer DeleteIndex(index.GetName())
+
+		assert.NoError(t, index.CreateDocument("1", map[string]interface{}{
+			"user_id": "u1",
+			"count":   "42",
+			"created": "2022/01/02",
+			"meta":    map[string]interface{}{"score": 1.5},
+		}, false))
+
+		m := index.GetMappings()
+		prop, _ := m.GetProperty("user_id")
+		assert.Equal(t, "keyword", prop.Type)
+		prop, _ = m.GetProperty("count")
+		assert.Equal(t, "numeric", prop.Type)
+		prop, _ = m.GetProperty("created")
+		assert.Equal(t, "text", prop.Type)
+		prop, _ = m.GetProperty("meta.score")
+		assert.Equal(t, "numeric", prop.Type)
+		assert.False(t, prop.Index)
+	})
+
+	t.Run("total fields limit", func(t *testing.T) {
+		index := newIndexWithMappings(t, "TestIndex_DynamicMapping.limit", map[string]interface{}{
+			"total_fields": map[string]interface{}{"limit": 3},
+		})
+		defer DeleteIndex(index.GetName())
+
+		// _id and @timestamp are counted
+		assert.NoError(t, index.CreateDocument("1", map[string]interface{}{"age": 1}, false))
+		err := index.CreateDocument("2", map[string]interface{}{"size": 2}, false)
+		var e *errors.Error
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, errors.ErrorTypeIllegalArgumentException, e.Type)
+	})
+
+	t.Run("template", func(t *testing.T) {
+		m, err := mappings.Request(nil, map[string]interface{}{"dynamic": "strict"})
+		assert.NoError(t, err)
+		tplName := "TestIndex_DynamicMapping.template"
+		assert.NoError(t, NewTemplate(tplName, &meta.IndexTemplate{
+			IndexPatterns: []string{"TestIndex_DynamicMapping.tpl*"},
+			Template:      meta.TemplateTemplate{Mappings: m},
+		}))
+		defer DeleteTemplate(tplName)
+
+		index, err := NewIndex("TestIndex_DynamicMapping.tpl_1", "disk", 1)
+		assert.NoError(t, err)
+		assert.NoError(t, StoreIndex(index))
+		defer DeleteIndex(index.GetName())
+		assert.Equal(t, meta.DynamicStrict, index.GetMappings().GetDynamic().Dynamic)
+		assert.Error(t, index.CreateDocument("1", map[string]interface{}{"name": "Prabhat"}, false))
+	})
+}
+
+func TestDetectDynamicType(t *testing.T) {
+	yes := true
+	tests := []struct {
+		name    string
+		dynamic meta.DynamicMapping
+		value   interface{}
+		want    string
+	}{
+		{name: "string", value: "hello", want: "string"},
+		{name: "date", value: "2022-01-02T03:04:05", want: "date"},
+		{name: "long", value: float64(3), want: "long"},
+		{name: "double", value: 3.5, want: "double"},
+		{name: "big integer", value: json.Number("1234567890123456789"), want: "long"},
+		{name: "boolean", value: true, want: "boolean"},
+		{name: "array", value: []interface{}{nil, "a"}, want: "string"},
+		{name: "numeric string", dynamic: meta.DynamicMapping{NumericDetection: &yes}, value: "1.5", want: "double"},
+		{name: "nil", value: nil, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := detectDynamicType(tt.dynamic, tt.value)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,337 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blugelabs/bluge"
+	"github.com/rs/zerolog/log"
+
+	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/zutils/flatten"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+)
+
+// reindexingIndexes records the indexes in re-indexing for the new fields, only one task can run for an index
+var reindexingIndexes sync.Map
+
+// UpdateMappings validates the changes against the exists mappings and merges them into the index.
+// The exists field can't change its type or index options, the new multi-fields and sub-fields are allowed,
+// they are applied to the exists documents by a background re-index task. The task is nil if no need to re-index.
+func (index *Index) UpdateMappings(mappings *meta.Mappings) (*Task, error) {
+	if err := index.CheckMetadataWrite(); err != nil {
+		return nil, err
+	}
+	if mappings == nil {
+		return nil, nil
+	}
+
+	current := index.GetMappings()
+	parents := make(map[string]struct{})
+	for field := range current.ListProperty() {
+		for i := strings.Index(field, "."); i > 0; i = nextDot(field, i) {
+			parents[field[:i]] = struct{}{}
+		}
+		parents[field] = struct{}{}
+	}
+
+	newFields := make(map[string]struct{})
+	for field, prop := range mappings.ListProperty() {
+		old, ok := current.GetProperty(field)
+		if !ok {
+			// the new field under an exists field or object may have values in the exists documents
+			for i := strings.Index(field, "."); i > 0; i = nextDot(field, i) {
+				if _, ok := parents[field[:i]]; ok {
+					newFields[field] = struct{}{}
+					break
+				}
+			}
+			continue
+		}
+		merged, added, err := mergeProperty(field, old, prop)
+		if err != nil {
+			return nil, err
+		}
+		mappings.SetProperty(field, merged)
+		for _, name := range added {
+			newFields[field+"."+name] = struct{}{}
+		}
+	}
+
+	fields := make([]string, 0, len(newFields))
+	for field := range newFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	if len(fields) > 0 {
+		if _, loaded := reindexingIndexes.LoadOrStore(index.GetName(), struct{}{}); loaded {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "index ["+index.GetName()+"] is re-indexing for the new fields of mappings")
+		}
+	}
+
+	if err := index.SetMappings(mappings); err != nil {
+		reindexingIndexes.Delete(index.GetName())
+		return nil, err
+	}
+	if err := StoreIndex(index); err != nil {
+		reindexingIndexes.Delete(index.GetName())
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	task := NewTask(meta.TaskActionMappingPut, "re-index ["+index.GetName()+"] for new fields ["+strings.Join(fields, ", ")+"]")
+	go func() {
+		defer reindexingIndexes.Delete(index.GetName())
+		resp, err := index.reindexMappings(task, fields)
+		if err != nil {
+			log.Error().Err(err).Str("index", index.GetName()).Msg("re-index for the new fields of mappings failed")
+		}
+		task.Finish(resp, err)
+	}()
+	return task, nil
+}
+
+// nextDot returns the position of next dot after i, it returns -1 if not found
+func nextDot(field string, i int) int {
+	j := strings.Index(field[i+1:], ".")
+	if j < 0 {
+		return -1
+	}
+	return i + 1 + j
+}
+
+// mergeProperty merges the new property into the exists property of field, returns the names of added multi-fields.
+// Only search_analyzer, ignore_above and the new multi-fields can be changed.
+func mergeProperty(field string, old, prop meta.Property) (meta.Property, []string, error) {
+	oldType, newType := propertyType(old), propertyType(prop)
+	if oldType != newType || old.ScalingFactor != prop.ScalingFactor {
+		return old, nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("mapper [%s] cannot be changed from type [%s] to [%s]", field, oldType, newType))
+	}
+
+	conflicts := []struct {
+		name     string
+		old, new interface{}
+	}{
+		{"index", old.Index, prop.Index},
+		{"store", old.Store, prop.Store},
+		{"sortable", old.Sortable, prop.Sortable},
+		{"aggregatable", old.Aggregatable, prop.Aggregatable},
+		{"highlightable", old.Highlightable, prop.Highlightable},
+		{"format", old.Format, prop.Format},
+		{"time_zone", old.TimeZone, prop.TimeZone},
+		{"normalizer", old.Normalizer, prop.Normalizer},
+		{"null_value", fmt.Sprint(old.NullValue), fmt.Sprint(prop.NullValue)},
+		{"copy_to", strings.Join(old.CopyTo, ","), strings.Join(prop.CopyTo, ",")},
+	}
+	if old.Type == "text" {
+		conflicts = append(conflicts, struct {
+			name     string
+			old, new interface{}
+		}{"analyzer", old.Analyzer, prop.Analyzer})
+	}
+	for _, c := range conflicts {
+		if c.old != c.new {
+			return old, nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("mapper [%s] has different [%s] values, from [%v] to [%v]", field, c.name, c.old, c.new))
+		}
+	}
+
+	merged := old.DeepClone()
+	if old.Type == "text" {
+		merged.SearchAnalyzer = prop.SearchAnalyzer
+	}
+	merged.IgnoreAbove = prop.IgnoreAbove
+
+	// the exists multi-fields are kept if they are not in the request
+	var added []string
+	for name, sub := range prop.Fields {
+		oldSub, ok := old.Fields[name]
+		if !ok {
+			merged.AddField(name, sub)
+			added = append(added, name)
+			continue
+		}
+		sub, _, err := mergeProperty(field+"."+name, oldSub, sub)
+		if err != nil {
+			return old, nil, err
+		}
+		merged.AddField(name, sub)
+	}
+	sort.Strings(added)
+	return merged, added, nil
+}
+
+// propertyType returns the declared type of property
+func propertyType(prop meta.Property) string {
+	if prop.NumericType != "" {
+		return prop.NumericType
+	}
+	return prop.Type
+}
+
+// reindexMappings rebuilds the exists documents with the current mappings to index the new fields
+func (index *Index) reindexMappings(task *Task, fields []string) (*meta.MappingsReindexResponse, error) {
+	// open all writers to make sure the documents in WAL are written into index
+	for _, shard := range index.shards {
+		if _, err := shard.GetWriters(); err != nil {
+			return nil, err
+		}
+	}
+
+	readers, err := index.GetReaders(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	var total uint64
+	for _, r := range readers {
+		n, err := r.Count()
+		if err != nil {
+			index.CloseReaders(readers)
+			return nil, err
+		}
+		total += n
+	}
+	index.CloseReaders(readers)
+	task.SetTotal(int64(total))
+
+	resp := &meta.MappingsReindexResponse{Index: index.GetName(), Fields: fields}
+	for _, shard := range index.shards {
+		if err = shard.reindex(task, resp); err != nil {
+			return resp, err
+		}
+	}
+	return resp, nil
+}
+
+// reindex rebuilds the documents of all second layer shards with documents,
+// the WAL consumption of the shard is paused, so the newer writes are applied after re-index.
+func (s *IndexShard) reindex(task *Task, resp *meta.MappingsReindexResponse) error {
+	s.consume.Lock()
+	defer s.consume.Unlock()
+	s.flushWAL()
+
+	for i := int64(0); i < s.GetShardNum(); i++ {
+		w, err := s.GetWriter(i)
+		if err != nil {
+			return err
+		}
+		r, err := w.Reader()
+		if err != nil {
+			return err
+		}
+		err = s.reindexSecondShard(task, resp, w, r)
+		_ = r.Close()
+		if err != nil {
+			return err
+		}
+		s.resetVectors(i)
+	}
+	return nil
+}
+
+// reindexSecondShard rebuilds the documents of reader from the stored _source and updates them by writer
+func (s *IndexShard) reindexSecondShard(task *Task, resp *meta.MappingsReindexResponse, w *bluge.Writer, r *bluge.Reader) error {
+	dmi, err := r.Search(context.Background(), bluge.NewAllMatches(bluge.NewMatchAllQuery()))
+	if err != nil {
+		return err
+	}
+
+	mappings := s.root.GetMappings()
+	batch := bluge.NewBatch()
+	size := 0
+	next, err := dmi.Next()
+	for err == nil && next != nil {
+		var id string
+		var timestamp time.Time
+		source := make(map[string]interface{})
+		err = next.VisitStoredFields(func(field string, value []byte) bool {
+			switch field {
+			case "_id":
+				id = string(value)
+			case meta.TimeFieldName:
+				timestamp, _ = bluge.DecodeDateTime(value)
+			case "_source":
+				_ = json.UnmarshalNumber(value, &source)
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+
+		if bdoc, err := s.rebuildDocument(mappings, id, timestamp, source); err != nil {
+			log.Warn().Err(err).Str("index", s.GetIndexName()).Str("shard", s.GetID()).Str("id", id).Msg("re-index document failed")
+			resp.Failed++
+			task.UpdateStatus(func(status *meta.TaskStatus) { status.Failed++ })
+		} else {
+			batch.Update(bdoc.ID(), bdoc)
+			size++
+			resp.Docs++
+			task.UpdateStatus(func(status *meta.TaskStatus) { status.Updated++ })
+		}
+		if size >= config.Global.BatchSize {
+			if err = w.Batch(batch); err != nil {
+				return err
+			}
+			batch.Reset()
+			size = 0
+		}
+		next, err = dmi.Next()
+	}
+	if err != nil {
+		return err
+	}
+	if size > 0 {
+		return w.Batch(batch)
+	}
+	return nil
+}
+
+// rebuildDocument builds the bluge document from the stored _source like the document in WAL
+func (s *IndexShard) rebuildDocument(mappings *meta.Mappings, id string, timestamp time.Time, source map[string]interface{}) (*bluge.Document, error) {
+	flatDoc, err := flatten.Flatten(source, "")
+	if err != nil {
+		return nil, err
+	}
+	if err = checkCompletionFields(mappings, source, flatDoc); err != nil {
+		return nil, err
+	}
+	if err = checkPercolatorFields(mappings, source, flatDoc); err != nil {
+		return nil, err
+	}
+	analyzers := s.root.GetAnalyzers()
+	for key, value := range flatDoc {
+		if value == nil {
+			continue
+		}
+		if err = checkFieldValue(mappings, analyzers, flatDoc, key, value); err != nil {
+			return nil, err
+		}
+	}
+	flatDoc[meta.TimeFieldName] = timestamp.UnixNano()
+	flatDoc[meta.SourceFieldName] = source
+	return s.BuildBlugeDocumentFromJSON(id, flatDoc)
+}
@@ -0,0 +1,185 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/uquery/mappings"
+)
+
+func TestIndex_UpdateMappings(t *testing.T) {
+	indexName := "TestIndex_UpdateMappings.index_1"
+	var index *Index
+
+	t.Run("prepare", func(t *testing.T) {
+		var err error
+		index, err = NewIndex(indexName, "disk", 1)
+		assert.NoError(t, err)
+		m, err := mappings.Request(nil, map[string]interface{}{
+			"properties": map[string]interface{}{
+				"title": map[string]interface{}{"type": "text"},
+				"count": map[string]interface{}{"type": "integer"},
+			},
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, index.SetMappings(m))
+		assert.NoError(t, StoreIndex(index))
+
+		assert.NoError(t, index.CreateDocument("1", map[string]interface{}{"title": "Hello World", "count": 1}, false))
+		assert.NoError(t, index.CreateDocument("2", map[string]interface{}{"title": "Hello Zinc", "count": 2}, false))
+
+		waitWAL(t, index)
+	})
+
+	t.Run("incompatible changes", func(t *testing.T) {
+		for _, data := range []map[string]interface{}{
+			{"title": map[string]interface{}{"type": "keyword"}},
+			{"count": map[string]interface{}{"type": "long"}},
+			{"title": map[string]interface{}{"type": "text", "analyzer": "keyword"}},
+			{"count": map[string]interface{}{"type": "integer", "index": false}},
+		} {
+			m, err := mappings.Request(nil, map[string]interface{}{"properties": data})
+			assert.NoError(t, err)
+			task, err := index.UpdateMappings(m)
+			assert.Error(t, err)
+			assert.Nil(t, task)
+		}
+		prop, _ := index.GetMappings().GetProperty("count")
+		assert.Equal(t, meta.NumericTypeInteger, prop.NumericType)
+	})
+
+	t.Run("new field without re-index", func(t *testing.T) {
+		m, err := mappings.Request(nil, map[string]interface{}{
+			"properties": map[string]interface{}{
+				"title": map[string]interface{}{"type": "text", "search_analyzer": "standard"},
+				"tag":   map[string]interface{}{"type": "keyword"},
+			},
+		})
+		assert.NoError(t, err)
+		task, err := index.UpdateMappings(m)
+		assert.NoError(t, err)
+		assert.Nil(t, task)
+		_, ok := index.GetMappings().GetProperty("tag")
+		assert.True(t, ok)
+	})
+
+	t.Run("new multi-field with re-index", func(t *testing.T) {
+		m, err := mappings.Request(nil, map[string]interface{}{
+			"properties": map[string]interface{}{
+				"title": map[string]interface{}{
+					"type":   "text",
+					"fields": map[string]interface{}{"raw": map[string]interface{}{"type": "keyword"}},
+				},
+			},
+		})
+		assert.NoError(t, err)
+		task, err := index.UpdateMappings(m)
+		assert.NoError(t, err)
+		if !assert.NotNil(t, task) {
+			return
+		}
+		task.Wait()
+		result := task.GetResult()
+		assert.Equal(t, int64(2), result.Task.Status.Total)
+		assert.Equal(t, int64(2), result.Task.Status.Updated)
+
+		resp, err := index.Search(&meta.ZincQuery{
+			Query: &meta.Query{Term: map[string]*meta.TermQuery{"title.raw": {Value: "Hello Zinc"}}},
+			Size:  10,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, resp.Hits.Total.Value)
+		require.Len(t, resp.Hits.Hits, 1)
+		assert.Equal(t, "2", resp.Hits.Hits[0].ID)
+
+		// the documents are still searchable by the exists fields
+		resp, err = index.Search(&meta.ZincQuery{
+			Query: &meta.Query{Term: map[string]*meta.TermQuery{"title": {Value: "hello"}}},
+			Size:  10,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, resp.Hits.Total.Value)
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		assert.NoError(t, DeleteIndex(indexName))
+	})
+}
+
+func TestIndex_UpdateMappingsWithCompletion(t *testing.T) {
+	indexName := "TestIndex_UpdateMappingsWithCompletion.index_1"
+	var index *Index
+
+	t.Run("prepare", func(t *testing.T) {
+		var err error
+		index, err = NewIndex(indexName, "disk", 1)
+		assert.NoError(t, err)
+		m, err := mappings.Request(nil, map[string]interface{}{
+			"properties": map[string]interface{}{
+				"title":   map[string]interface{}{"type": "text"},
+				"suggest": map[string]interface{}{"type": "completion"},
+			},
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, index.SetMappings(m))
+		assert.NoError(t, StoreIndex(index))
+
+		assert.NoError(t, index.CreateDocument("1", map[string]interface{}{
+			"title":   "nevermind",
+			"suggest": map[string]interface{}{"input": []interface{}{"Nevermind", "Nirvana"}, "weight": 34},
+		}, false))
+		assert.NoError(t, index.CreateDocument("2", map[string]interface{}{"title": "in utero", "suggest": "Nirvana In Utero"}, false))
+
+		waitWAL(t, index)
+	})
+
+	t.Run("re-index keeps completion", func(t *testing.T) {
+		m, err := mappings.Request(nil, map[string]interface{}{
+			"properties": map[string]interface{}{
+				"title": map[string]interface{}{
+					"type":   "text",
+					"fields": map[string]interface{}{"raw": map[string]interface{}{"type": "keyword"}},
+				},
+			},
+		})
+		assert.NoError(t, err)
+		task, err := index.UpdateMappings(m)
+		assert.NoError(t, err)
+		require.NotNil(t, task)
+		task.Wait()
+		assert.Equal(t, int64(2), task.GetResult().Task.Status.Updated)
+
+		resp, err := index.Search(&meta.ZincQuery{Suggest: map[string]interface{}{
+			"song": map[string]interface{}{"prefix": "nir", "completion": map[string]interface{}{"field": "suggest"}},
+		}})
+		assert.NoError(t, err)
+		require.Len(t, resp.Suggest["song"], 1)
+		ids := make([]string, 0)
+		for _, option := range resp.Suggest["song"][0].Options {
+			ids = append(ids, option.ID)
+		}
+		assert.Equal(t, []string{"1", "2"}, ids)
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		assert.NoError(t, DeleteIndex(indexName))
+	})
+}
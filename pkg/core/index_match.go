@@ -0,0 +1,91 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/zincsearch/zincsearch/pkg/errors"
+)
+
+// MatchName checks the name matches the pattern, * in the pattern matches any characters
+func MatchName(pattern, name string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == name
+	}
+	if !strings.HasPrefix(name, parts[0]) {
+		return false
+	}
+	name = name[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(name, part)
+		if i < 0 {
+			return false
+		}
+		name = name[i+len(part):]
+	}
+	return len(name) >= len(last) && strings.HasSuffix(name, last)
+}
+
+// MatchNames checks the name matches any of the comma separated patterns, empty or _all matches all names
+func MatchNames(patterns, name string) bool {
+	if patterns == "" || patterns == "_all" {
+		return true
+	}
+	for _, pattern := range strings.Split(patterns, ",") {
+		if MatchName(strings.TrimSpace(pattern), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchIndexes returns the indexes sorted by name what match the comma separated patterns,
+// empty or _all matches all indexes. The index name without wildcard must exist.
+func MatchIndexes(patterns string) ([]*Index, error) {
+	items := ZINC_INDEX_LIST.List()
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].GetName() < items[j].GetName()
+	})
+	if patterns == "" || patterns == "_all" {
+		return items, nil
+	}
+
+	matched := make(map[string]struct{})
+	for _, pattern := range strings.Split(patterns, ",") {
+		pattern = strings.TrimSpace(pattern)
+		found := false
+		for _, index := range items {
+			if MatchName(pattern, index.GetName()) {
+				matched[index.GetName()] = struct{}{}
+				found = true
+			}
+		}
+		if !found && !strings.Contains(pattern, "*") {
+			return nil, errors.New(errors.ErrorTypeIndexNotFoundException, "index ["+pattern+"] does not exists")
+		}
+	}
+	indexes := make([]*Index, 0, len(matched))
+	for _, index := range items {
+		if _, ok := matched[index.GetName()]; ok {
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes, nil
+}
@@ -0,0 +1,77 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchName(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "abc", name: "abc", want: true},
+		{pattern: "ab", name: "abc", want: false},
+		{pattern: "*", name: "abc", want: true},
+		{pattern: "a*", name: "abc", want: true},
+		{pattern: "*c", name: "abc", want: true},
+		{pattern: "b*", name: "abc", want: false},
+		{pattern: "a*c", name: "abc", want: true},
+		{pattern: "a*c", name: "ac", want: true},
+		{pattern: "a*b*c", name: "aXbYc", want: true},
+		{pattern: "a*b*c", name: "acb", want: false},
+		{pattern: "ab*bc", name: "abc", want: false},
+		{pattern: "log-[0-9]", name: "log-1", want: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MatchName(tt.pattern, tt.name), tt.pattern+" "+tt.name)
+	}
+
+	assert.True(t, MatchNames("", "abc"))
+	assert.True(t, MatchNames("_all", "abc"))
+	assert.True(t, MatchNames("x, a*", "abc"))
+	assert.False(t, MatchNames("x,y*", "abc"))
+}
+
+func TestMatchIndexes(t *testing.T) {
+	indexNames := []string{"TestMatchIndexes.index_2", "TestMatchIndexes.index_1"}
+	for _, indexName := range indexNames {
+		index, err := NewIndex(indexName, "disk", 1)
+		assert.NoError(t, err)
+		assert.NoError(t, StoreIndex(index))
+	}
+	defer func() {
+		for _, indexName := range indexNames {
+			assert.NoError(t, DeleteIndex(indexName))
+		}
+	}()
+
+	indexes, err := MatchIndexes("TestMatchIndexes.*,TestMatchIndexes.index_1")
+	assert.NoError(t, err)
+	assert.Len(t, indexes, 2)
+	assert.Equal(t, "TestMatchIndexes.index_1", indexes[0].GetName())
+
+	indexes, err = MatchIndexes("TestMatchIndexes.none*")
+	assert.NoError(t, err)
+	assert.Len(t, indexes, 0)
+
+	_, err = MatchIndexes("TestMatchIndexes.index_1,TestMatchIndexes.none")
+	assert.Error(t, err)
+}
@@ -0,0 +1,291 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/blugelabs/bluge"
+	"github.com/rs/zerolog/log"
+
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	zincanalysis "github.com/zincsearch/zincsearch/pkg/uquery/analysis"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+)
+
+// resizingIndexes records the source indexes in resizing, an index can only be resized once at the same time
+var resizingIndexes sync.Map
+
+// ResizeIndex creates the target index with a different number of first layer shards,
+// and copies the documents from source, every document is re-hashed by _id into the new shards.
+// The source index is blocked for writes until the task completed.
+func ResizeIndex(typ, sourceName, targetName string, req *meta.IndexResizeRequest) (*Task, error) {
+	if req == nil {
+		req = new(meta.IndexResizeRequest)
+	}
+	source, ok := GetIndex(sourceName)
+	if !ok {
+		return nil, errors.New(errors.ErrorTypeIndexNotFoundException, "index ["+sourceName+"] does not exists")
+	}
+	if err := source.CheckOpen(); err != nil {
+		return nil, err
+	}
+	if err := CheckIndexName(targetName); err != nil {
+		return nil, errors.New(errors.ErrorTypeInvalidArgument, err.Error())
+	}
+	if _, ok := GetIndex(targetName); ok {
+		return nil, errors.New(errors.ErrorTypeInvalidArgument, "index ["+targetName+"] already exists")
+	}
+
+	var shardNum int64
+	if req.Settings != nil {
+		shardNum = req.Settings.NumberOfShards
+	}
+	action, shardNum, err := checkResizeShardNum(typ, source.GetShardNum(), shardNum)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, loaded := resizingIndexes.LoadOrStore(sourceName, struct{}{}); loaded {
+		return nil, errors.New(errors.ErrorTypeInvalidArgument, "index ["+sourceName+"] is resizing")
+	}
+	unblock, err := source.blockWrite()
+	if err != nil {
+		resizingIndexes.Delete(sourceName)
+		return nil, err
+	}
+	target, err := newResizeTarget(source, targetName, shardNum, req.Settings)
+	if err != nil {
+		unblock()
+		resizingIndexes.Delete(sourceName)
+		return nil, err
+	}
+
+	task := NewTask(action, "resize from ["+sourceName+"] to ["+targetName+"]")
+	go func() {
+		defer resizingIndexes.Delete(sourceName)
+		resp, err := resizeIndex(task, source, target, req)
+		unblock()
+		if err != nil {
+			log.Error().Err(err).Str("source", sourceName).Str("target", targetName).Msg("resize index failed")
+			if err := DeleteIndex(targetName); err != nil {
+				log.Error().Err(err).Str("target", targetName).Msg("resize index delete target failed")
+			}
+			task.Finish(nil, err)
+			return
+		}
+		task.Finish(resp, nil)
+	}()
+	return task, nil
+}
+
+// checkResizeShardNum checks the target shard number by resize type, returns the task action and shard number.
+// split needs more shards, shrink needs less shards, clone keeps the same shards.
+func checkResizeShardNum(typ string, sourceNum, targetNum int64) (string, int64, error) {
+	switch typ {
+	case meta.IndexResizeTypeSplit:
+		if targetNum <= sourceNum {
+			return "", 0, errors.New(errors.ErrorTypeIllegalArgumentException, "the number of target shards ["+strconv.FormatInt(targetNum, 10)+"] must be greater than the number of source shards ["+strconv.FormatInt(sourceNum, 10)+"]")
+		}
+		return meta.TaskActionResizeSplit, targetNum, nil
+	case meta.IndexResizeTypeShrink:
+		if targetNum <= 0 || targetNum >= sourceNum {
+			return "", 0, errors.New(errors.ErrorTypeIllegalArgumentException, "the number of target shards ["+strconv.FormatInt(targetNum, 10)+"] must be less than the number of source shards ["+strconv.FormatInt(sourceNum, 10)+"]")
+		}
+		return meta.TaskActionResizeShrink, targetNum, nil
+	case meta.IndexResizeTypeClone:
+		if targetNum != 0 && targetNum != sourceNum {
+			return "", 0, errors.New(errors.ErrorTypeIllegalArgumentException, "the number of target shards ["+strconv.FormatInt(targetNum, 10)+"] must be equal to the number of source shards ["+strconv.FormatInt(sourceNum, 10)+"]")
+		}
+		return meta.TaskActionResizeClone, sourceNum, nil
+	default:
+		return "", 0, errors.New(errors.ErrorTypeIllegalArgumentException, "unknown resize type ["+typ+"]")
+	}
+}
+
+// blockWrite sets index.blocks.write for the index, the returned function restores the previous value
+func (index *Index) blockWrite() (func(), error) {
+	index.lock.RLock()
+	var prev *bool
+	if index.ref.Settings != nil && index.ref.Settings.Blocks != nil {
+		prev = index.ref.Settings.Blocks.Write
+	}
+	index.lock.RUnlock()
+
+	restore := func() {
+		index.lock.Lock()
+		index.ref.Settings.Blocks.Write = prev
+		index.lock.Unlock()
+	}
+	blocked := true
+	_ = index.SetSettings(&meta.IndexSettings{Blocks: &meta.IndexBlocks{Write: &blocked}})
+	if err := storeIndex(index); err != nil {
+		restore()
+		return nil, err
+	}
+	return func() {
+		restore()
+		if err := storeIndex(index); err != nil {
+			log.Error().Err(err).Str("index", index.GetName()).Msg("restore index.blocks.write failed")
+		}
+	}, nil
+}
+
+// newResizeTarget creates the target index with settings, analyzers and mappings of source
+func newResizeTarget(source *Index, name string, shardNum int64, reqSettings *meta.IndexSettings) (*Index, error) {
+	index, err := newIndex(name, source.GetStorageType(), shardNum, false)
+	if err != nil {
+		return nil, errors.New(errors.ErrorTypeInvalidArgument, err.Error())
+	}
+
+	settings := new(meta.IndexSettings)
+	data, err := json.Marshal(source.GetSettings())
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, settings); err != nil {
+		return nil, err
+	}
+	settings.NumberOfShards = shardNum
+	settings.Blocks = nil
+	if reqSettings != nil && reqSettings.NumberOfReplicas > 0 {
+		settings.NumberOfReplicas = reqSettings.NumberOfReplicas
+	}
+	analyzers, err := zincanalysis.RequestAnalyzer(settings.Analysis)
+	if err != nil {
+		return nil, errors.New(errors.ErrorTypeRuntimeException, "parse stored analysis error").Cause(err)
+	}
+	_ = index.SetSettings(settings)
+	_ = index.SetAnalyzers(analyzers)
+	if mappings := source.GetMappings(); mappings != nil {
+		_ = index.SetMappings(mappings.DeepClone())
+	}
+
+	if err = StoreIndex(index); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// resizeIndex copies all documents from source to target and updates aliases
+func resizeIndex(task *Task, source, target *Index, req *meta.IndexResizeRequest) (*meta.IndexResizeResponse, error) {
+	// open all writers to make sure the documents in WAL are written into index
+	for _, shard := range source.shards {
+		if _, err := shard.GetWriters(); err != nil {
+			return nil, err
+		}
+	}
+	source.flushWAL()
+
+	readers, err := source.GetReaders(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer source.CloseReaders(readers)
+
+	var total uint64
+	for _, r := range readers {
+		n, err := r.Count()
+		if err != nil {
+			return nil, err
+		}
+		total += n
+	}
+	task.SetTotal(int64(total))
+
+	var docs int64
+	for _, r := range readers {
+		n, err := copyDocuments(task, r, target)
+		docs += n
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// write the documents in WAL of target into index, then the stats are correct
+	target.flushWAL()
+	if err = target.UpdateMetadata(); err != nil {
+		return nil, err
+	}
+
+	resp := &meta.IndexResizeResponse{
+		Acknowledged:       true,
+		ShardsAcknowledged: true,
+		Index:              target.GetName(),
+		Docs:               docs,
+	}
+	for alias := range req.Aliases {
+		if err = ZINC_INDEX_ALIAS_LIST.AddIndexesToAlias(alias, []string{target.GetName()}); err != nil {
+			return nil, err
+		}
+		resp.Aliases = append(resp.Aliases, alias)
+	}
+	if req.SwapAliases {
+		for _, alias := range ZINC_INDEX_ALIAS_LIST.GetAliasesForIndex(source.GetName()) {
+			if err = ZINC_INDEX_ALIAS_LIST.AddIndexesToAlias(alias, []string{target.GetName()}); err != nil {
+				return nil, err
+			}
+			if err = ZINC_INDEX_ALIAS_LIST.RemoveIndexesFromAlias(alias, []string{source.GetName()}); err != nil {
+				return nil, err
+			}
+			resp.Aliases = append(resp.Aliases, alias)
+		}
+	}
+	return resp, nil
+}
+
+// copyDocuments writes all documents of the reader into target, returns the number of documents
+func copyDocuments(task *Task, r *bluge.Reader, target *Index) (int64, error) {
+	dmi, err := r.Search(context.Background(), bluge.NewAllMatches(bluge.NewMatchAllQuery()))
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	next, err := dmi.Next()
+	for err == nil && next != nil {
+		var id string
+		var timestamp time.Time
+		doc := make(map[string]interface{})
+		err = next.VisitStoredFields(func(field string, value []byte) bool {
+			switch field {
+			case "_id":
+				id = string(value)
+			case meta.TimeFieldName:
+				timestamp, _ = bluge.DecodeDateTime(value)
+			case "_source":
+				_ = json.UnmarshalNumber(value, &doc)
+			}
+			return true
+		})
+		if err != nil {
+			return n, err
+		}
+		doc[meta.TimeFieldName] = timestamp.UnixNano()
+		if err = target.CreateDocument(id, doc, false); err != nil {
+			task.UpdateStatus(func(status *meta.TaskStatus) { status.Failed++ })
+			return n, errors.New(errors.ErrorTypeRuntimeException, "copy document ["+id+"] failed").Cause(err)
+		}
+		n++
+		task.UpdateStatus(func(status *meta.TaskStatus) { status.Created++ })
+		next, err = dmi.Next()
+	}
+	return n, err
+}
@@ -0,0 +1,124 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/meta"
+)
+
+func TestResizeIndex(t *testing.T) {
+	sourceName := "TestResizeIndex.index_1"
+	splitName := "TestResizeIndex.index_2"
+	shrinkName := "TestResizeIndex.index_3"
+	alias := "TestResizeIndex.alias"
+
+	t.Run("prepare", func(t *testing.T) {
+		index, err := NewIndex(sourceName, "disk", 2)
+		assert.NoError(t, err)
+		assert.NoError(t, StoreIndex(index))
+		for i := 0; i < 20; i++ {
+			id := strconv.Itoa(i)
+			assert.NoError(t, index.CreateDocument(id, map[string]interface{}{"name": "name " + id, "num": i}, false))
+		}
+		assert.NoError(t, ZINC_INDEX_ALIAS_LIST.AddIndexesToAlias(alias, []string{sourceName}))
+	})
+
+	t.Run("check shard number", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			typ      string
+			shardNum int64
+		}{
+			{name: "split to less", typ: meta.IndexResizeTypeSplit, shardNum: 1},
+			{name: "shrink to more", typ: meta.IndexResizeTypeShrink, shardNum: 3},
+			{name: "shrink to zero", typ: meta.IndexResizeTypeShrink, shardNum: 0},
+			{name: "clone to different", typ: meta.IndexResizeTypeClone, shardNum: 3},
+			{name: "unknown type", typ: "unknown", shardNum: 3},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req := &meta.IndexResizeRequest{Settings: &meta.IndexSettings{NumberOfShards: tt.shardNum}}
+				_, err := ResizeIndex(tt.typ, sourceName, "TestResizeIndex.invalid", req)
+				assert.Error(t, err)
+			})
+		}
+		_, err := ResizeIndex(meta.IndexResizeTypeClone, "TestResizeIndex.not_exists", "TestResizeIndex.invalid", nil)
+		assert.Error(t, err)
+		_, err = ResizeIndex(meta.IndexResizeTypeClone, sourceName, sourceName, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("split", func(t *testing.T) {
+		req := &meta.IndexResizeRequest{
+			Settings:    &meta.IndexSettings{NumberOfShards: 5},
+			SwapAliases: true,
+		}
+		task, err := ResizeIndex(meta.IndexResizeTypeSplit, sourceName, splitName, req)
+		assert.NoError(t, err)
+		source, _ := GetIndex(sourceName)
+		task.Wait()
+
+		result := task.GetResult()
+		assert.True(t, result.Completed)
+		assert.Nil(t, result.Error)
+		assert.Equal(t, meta.TaskActionResizeSplit, result.Task.Action)
+		assert.Equal(t, int64(20), result.Task.Status.Total)
+		assert.Equal(t, int64(20), result.Task.Status.Created)
+
+		// source is writable again
+		assert.NoError(t, source.CheckWrite())
+
+		target, ok := GetIndex(splitName)
+		assert.True(t, ok)
+		assert.Equal(t, int64(5), target.GetShardNum())
+		assert.Equal(t, int64(5), target.GetSettings().NumberOfShards)
+		assert.Equal(t, uint64(20), target.GetStats().DocNum)
+		for i := 0; i < 20; i++ {
+			hit, err := target.GetDocument(strconv.Itoa(i))
+			assert.NoError(t, err)
+			assert.Equal(t, "name "+strconv.Itoa(i), hit.Source.(map[string]interface{})["name"])
+		}
+
+		// alias moved to target
+		indexes, _ := ZINC_INDEX_ALIAS_LIST.GetIndexesForAlias(alias)
+		assert.Equal(t, []string{splitName}, indexes)
+	})
+
+	t.Run("shrink", func(t *testing.T) {
+		req := &meta.IndexResizeRequest{Settings: &meta.IndexSettings{NumberOfShards: 1}}
+		task, err := ResizeIndex(meta.IndexResizeTypeShrink, splitName, shrinkName, req)
+		assert.NoError(t, err)
+		task.Wait()
+		assert.Nil(t, task.GetResult().Error)
+
+		target, ok := GetIndex(shrinkName)
+		assert.True(t, ok)
+		assert.Equal(t, int64(1), target.GetShardNum())
+		assert.Equal(t, uint64(20), target.GetStats().DocNum)
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		assert.NoError(t, ZINC_INDEX_ALIAS_LIST.RemoveIndexesFromAlias(alias, []string{splitName}))
+		assert.NoError(t, DeleteIndex(sourceName))
+		assert.NoError(t, DeleteIndex(splitName))
+		assert.NoError(t, DeleteIndex(shrinkName))
+	})
+}
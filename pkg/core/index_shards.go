@@ -45,6 +45,7 @@ const (
 // The shards num can not be modify, because if change the num
 // hash algorithm will distribute the same docID to another shard,
 // then we will can not found the old document, maybe cause duplicate documents.
+// To change the num use ResizeIndex, it copies documents into a new index and re-hashes every docID.
 // First layer shard just used for distribute not really store documents.
 type IndexShard struct {
 	open   uint64
@@ -55,6 +56,8 @@ type IndexShard struct {
 	wal    *wal.Log
 	lock   sync.RWMutex
 	close  chan struct{}
+	// consume held while consuming WAL, or while WAL consumption is paused
+	consume sync.Mutex
 }
 
 // IndexSecondShard second layer shard by auto increate shards for index.
@@ -66,10 +69,11 @@ type IndexShard struct {
 // we will fozen old shards, just write new documents to new shards and do merge in new shards
 // this will improve shard performance.
 type IndexSecondShard struct {
-	root   *Index
-	ref    *meta.IndexSecondShard
-	writer *bluge.Writer
-	lock   sync.RWMutex
+	root    *Index
+	ref     *meta.IndexSecondShard
+	writer  *bluge.Writer
+	vectors shardVectors
+	lock    sync.RWMutex
 }
 
 // GetShardByDocID return the shard by hash docID
@@ -281,6 +285,7 @@ func (s *IndexShard) Close() error {
 			return err
 		}
 		secondShard.writer = nil
+		secondShard.vectors.reset()
 	}
 
 	if err := s.wal.Close(); err != nil {
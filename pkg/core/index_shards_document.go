@@ -18,13 +18,20 @@ package core
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/analysis"
+	"github.com/blugelabs/bluge/numeric"
 
 	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/errors"
 	"github.com/zincsearch/zincsearch/pkg/meta"
 	zincanalysis "github.com/zincsearch/zincsearch/pkg/uquery/analysis"
+	"github.com/zincsearch/zincsearch/pkg/uquery/knn"
+	"github.com/zincsearch/zincsearch/pkg/uquery/suggest"
 	"github.com/zincsearch/zincsearch/pkg/zutils"
 	"github.com/zincsearch/zincsearch/pkg/zutils/flatten"
 	"github.com/zincsearch/zincsearch/pkg/zutils/json"
@@ -43,36 +50,17 @@ func (s *IndexShard) BuildBlugeDocumentFromJSON(docID string, doc map[string]int
 
 	// Create a new bluge document
 	bdoc := bluge.NewDocument(docID)
-	// Iterate through each field and add it to the bluge document
-	for key, value := range doc {
-		if value == nil || key == meta.TimeFieldName || key == meta.SourceFieldName {
-			continue
-		}
-
-		prop, ok := mappings.GetProperty(key)
-		if !ok || !prop.Index {
-			continue // not index, skip
-		}
-
-		switch v := value.(type) {
-		case []interface{}:
-			for _, v := range v {
-				if err := s.buildField(mappings, bdoc, key, v); err != nil {
-					return nil, err
-				}
-			}
-		default:
-			if err := s.buildField(mappings, bdoc, key, v); err != nil {
-				return nil, err
-			}
-		}
+	allExcluding, err := buildDocumentFields(mappings, s.root.GetAnalyzers(), bdoc, doc)
+	if err != nil {
+		return nil, err
 	}
 
 	// set timestamp
 	timestamp := time.Now()
 	if value, ok := doc[meta.TimeFieldName]; ok {
 		delete(doc, meta.TimeFieldName)
-		timestamp = time.Unix(0, int64(value.(float64)))
+		ns, _ := zutils.ToInt64(value)
+		timestamp = time.Unix(0, ns)
 	}
 	bdoc.AddField(bluge.NewDateTimeField(meta.TimeFieldName, timestamp).StoreValue().Sortable().Aggregatable())
 
@@ -87,7 +75,7 @@ func (s *IndexShard) BuildBlugeDocumentFromJSON(docID string, doc map[string]int
 	bdoc.AddField(bluge.NewStoredOnlyField("_source", sourceByteVal))
 
 	bdoc.AddField(bluge.NewStoredOnlyField("_index", []byte(s.GetIndexName())))
-	bdoc.AddField(bluge.NewCompositeFieldExcluding("_all", []string{"_id", "_index", "_source", meta.TimeFieldName}))
+	bdoc.AddField(bluge.NewCompositeFieldExcluding("_all", allExcluding))
 
 	// Add time for index
 	bdoc.SetTimestamp(timestamp.UnixNano())
@@ -97,9 +85,70 @@ func (s *IndexShard) BuildBlugeDocumentFromJSON(docID string, doc map[string]int
 	return bdoc, nil
 }
 
-func (s *IndexShard) buildField(mappings *meta.Mappings, bdoc *bluge.Document, key string, value interface{}) error {
+// buildDocumentFields adds the indexed fields of the flattened document to bdoc, it returns the fields excluded from _all
+func buildDocumentFields(mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer, bdoc *bluge.Document, doc map[string]interface{}) ([]string, error) {
+	allExcluding := []string{"_id", "_index", "_source", meta.TimeFieldName}
+	// Iterate through each field and add it to the bluge document
+	for key, value := range doc {
+		if key == meta.TimeFieldName || key == meta.SourceFieldName {
+			continue
+		}
+
+		prop, ok := mappings.GetProperty(key)
+		if !ok || !prop.Index {
+			continue // not index, skip
+		}
+		if prop.Type == "completion" {
+			// the encoded inputs are not searchable by _all
+			allExcluding = append(allExcluding, key)
+		}
+		if prop.Type == "percolator" {
+			// the extracted terms are only used to select the candidates of percolate query
+			allExcluding = append(allExcluding, key)
+			if err := buildPercolatorField(mappings, analyzers, bdoc, key, value); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if prop.Type == "dense_vector" {
+			vector, err := knn.Vector(key, prop, value)
+			if err != nil {
+				return nil, err
+			}
+			bdoc.AddField(bluge.NewStoredOnlyField(key, knn.EncodeVector(vector)))
+			continue
+		}
+
+		values, ok := value.([]interface{})
+		if !ok {
+			values = []interface{}{value}
+		}
+		for _, v := range values {
+			if v == nil {
+				if v = prop.NullValue; v == nil {
+					continue
+				}
+			}
+			if err := buildField(mappings, analyzers, bdoc, key, v); err != nil {
+				return nil, err
+			}
+			for _, target := range prop.CopyTo {
+				if err := buildCopyField(mappings, analyzers, bdoc, target, v); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return allExcluding, nil
+}
+
+func buildField(mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer, bdoc *bluge.Document, key string, value interface{}) error {
 	var field *bluge.TermField
-	prop, _ := mappings.GetProperty(key)
+	prop, ok := mappings.GetProperty(key)
+	if !ok {
+		return nil // sub field is not mapped yet
+	}
 	switch prop.Type {
 	case "text":
 		v := value.(string)
@@ -107,17 +156,36 @@ func (s *IndexShard) buildField(mappings *meta.Mappings, bdoc *bluge.Document, k
 			return nil
 		}
 		field = bluge.NewTextField(key, v).SearchTermPositions()
-		fieldAnalyzer, _ := zincanalysis.QueryAnalyzerForField(s.root.GetAnalyzers(), mappings, key)
+		fieldAnalyzer, _ := zincanalysis.QueryAnalyzerForField(analyzers, mappings, key)
 		if fieldAnalyzer != nil {
 			field.WithAnalyzer(fieldAnalyzer)
 		}
 	case "numeric":
-		field = bluge.NewNumericField(key, value.(float64))
+		if prop.IsLong() {
+			v, err := prop.ToLong(value)
+			if err != nil {
+				return fmt.Errorf("field [%s] value [%v] convert to [%s] err: %s", key, value, prop.NumericType, err.Error())
+			}
+			field = newLongField(key, v)
+		} else {
+			v, err := zutils.ToFloat64(value)
+			if err != nil {
+				return fmt.Errorf("field [%s] value [%v] convert to [numeric] err: %s", key, value, err.Error())
+			}
+			field = bluge.NewNumericField(key, v)
+		}
 	case "keyword":
 		v := value.(string)
-		if v == "" {
+		if v == "" || (prop.IgnoreAbove > 0 && utf8.RuneCountInString(v) > prop.IgnoreAbove) {
 			return nil
 		}
+		if prop.Normalizer != "" {
+			normalizer, err := zincanalysis.QueryNormalizer(analyzers, prop.Normalizer)
+			if err != nil {
+				return err
+			}
+			v = zincanalysis.Normalize(normalizer, v)
+		}
 		field = bluge.NewKeywordField(key, v)
 	case "bool":
 		field = bluge.NewKeywordField(key, strconv.FormatBool(value.(bool)))
@@ -127,6 +195,15 @@ func (s *IndexShard) buildField(mappings *meta.Mappings, bdoc *bluge.Document, k
 			return fmt.Errorf("field [%s] value [%v] parse err: %s", key, value, err.Error())
 		}
 		field = bluge.NewDateTimeField(key, v)
+	case "completion":
+		terms, err := suggest.CompletionTerms(prop, analyzers, value)
+		if err != nil {
+			return err
+		}
+		for _, term := range terms {
+			bdoc.AddField(bluge.NewKeywordField(key, term))
+		}
+		return nil
 	}
 	if prop.Store || prop.Highlightable {
 		field.StoreValue()
@@ -143,7 +220,7 @@ func (s *IndexShard) buildField(mappings *meta.Mappings, bdoc *bluge.Document, k
 	bdoc.AddField(field)
 
 	for propField := range prop.Fields {
-		err := s.buildField(mappings, bdoc, key+"."+propField, value)
+		err := buildField(mappings, analyzers, bdoc, key+"."+propField, value)
 		if err != nil {
 			return err
 		}
@@ -152,39 +229,95 @@ func (s *IndexShard) buildField(mappings *meta.Mappings, bdoc *bluge.Document, k
 	return nil
 }
 
+// newLongField returns the field of the exact int64, it is prefix coded as numeric without the float64 conversion
+func newLongField(key string, value int64) *bluge.TermField {
+	return bluge.NewKeywordFieldBytes(key, numeric.MustNewPrefixCodedInt64(value, 0)).
+		WithAnalyzer(longAnalyzer{}).Sortable().Aggregatable()
+}
+
+// longPrecisionStep is the shift step of the long terms, it is the same as the date time field what the range query expects
+const longPrecisionStep uint = 4
+
+// longAnalyzer adds the shifted terms of the prefix coded int64 for the range query
+type longAnalyzer struct{}
+
+func (longAnalyzer) Analyze(input []byte) analysis.TokenStream {
+	tokens := analysis.TokenStream{
+		&analysis.Token{End: len(input), Term: input, PositionIncr: 1, Type: analysis.Numeric},
+	}
+	value, err := numeric.PrefixCoded(input).Int64()
+	if err != nil {
+		return tokens
+	}
+	for shift := longPrecisionStep; shift < 64; shift += longPrecisionStep {
+		term, err := numeric.NewPrefixCodedInt64(value, shift)
+		if err != nil {
+			break
+		}
+		tokens = append(tokens, &analysis.Token{End: len(term), Term: term, Type: analysis.Numeric})
+	}
+	return tokens
+}
+
+// buildCopyField adds the value copied from other field, the value is converted to the type of target field
+func buildCopyField(mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer, bdoc *bluge.Document, target string, value interface{}) error {
+	prop, ok := mappings.GetProperty(target)
+	if !ok || !prop.Index {
+		return nil
+	}
+	var err error
+	v := value
+	switch prop.Type {
+	case "text", "keyword":
+		v, err = zutils.ToString(value)
+	case "numeric":
+		if prop.IsLong() {
+			_, err = prop.ToLong(value)
+		} else {
+			v, err = zutils.ToFloat64(value)
+		}
+	case "bool":
+		v, err = zutils.ToBool(value)
+	}
+	if err != nil {
+		return fmt.Errorf("field [%s] copy_to value [%v] can't convert to [%s]", target, value, prop.Type)
+	}
+	return buildField(mappings, analyzers, bdoc, target, v)
+}
+
 // CheckDocument checks if the document is valid.
 func (s *IndexShard) CheckDocument(docID string, doc map[string]interface{}, update bool, shard int64) ([]byte, error) {
 	// Pick the index mapping from the cache if it already exists
 	mappings := s.root.GetMappings()
+	dynamic := mappings.GetDynamic()
 	mappingsNeedsUpdate := false
 
+	analyzers := s.root.GetAnalyzers()
 	flatDoc, _ := flatten.Flatten(doc, "")
+	if err := checkCompletionFields(mappings, doc, flatDoc); err != nil {
+		return nil, err
+	}
+	if err := checkPercolatorFields(mappings, doc, flatDoc); err != nil {
+		return nil, err
+	}
 	// Iterate through each field and add it to the bluge document
 	for key, value := range flatDoc {
 		if value == nil {
 			continue
 		}
+		value = numberValue(mappings, key, value)
+		flatDoc[key] = value
 
-		if update := s.checkProperty(mappings, key, value); update {
-			mappingsNeedsUpdate = true
+		update, err := checkProperty(mappings, analyzers, dynamic, key, value)
+		if err != nil {
+			return nil, err
 		}
-
-		prop, ok := mappings.GetProperty(key)
-		if !ok || !prop.Index {
-			continue // not index, skip
+		if update {
+			mappingsNeedsUpdate = true
 		}
 
-		switch v := value.(type) {
-		case []interface{}:
-			for i, v := range v {
-				if err := s.checkField(mappings, flatDoc, key, v, i, true); err != nil {
-					return nil, err
-				}
-			}
-		default:
-			if err := s.checkField(mappings, flatDoc, key, v, 0, false); err != nil {
-				return nil, err
-			}
+		if err := checkFieldValue(mappings, analyzers, flatDoc, key, value); err != nil {
+			return nil, err
 		}
 	}
 
@@ -224,71 +357,94 @@ func (s *IndexShard) CheckDocument(docID string, doc map[string]interface{}, upd
 	return json.Marshal(flatDoc)
 }
 
-// checkProperty returns if need update mappings
-func (s *IndexShard) checkProperty(mappings *meta.Mappings, key string, value interface{}) bool {
-	prop, ok := mappings.GetProperty(key)
-	if ok {
-		if config.Global.EnableTextKeywordMapping && prop.Type == "text" {
-			if _, ok := mappings.GetProperty(key + ".keyword"); ok {
-				return false
-			}
-		} else {
-			return false
+// numberValue converts the json.Number of the document decoded by json.UnmarshalUseNumber,
+// it is kept as json.Number only if the field is mapped as long, otherwise converted by json.NumberValue
+func numberValue(mappings *meta.Mappings, key string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if prop, ok := mappings.GetProperty(key); ok && prop.IsLong() {
+			return v
+		}
+		return json.NumberValue(v)
+	case []interface{}:
+		for i := range v {
+			v[i] = numberValue(mappings, key, v[i])
 		}
 	}
+	return value
+}
 
-	// try to find the type of the value and use it to define default mapping
-	switch v := value.(type) {
-	case string:
-		if layout, ok := isDateProperty(v); ok {
-			prop = meta.NewProperty("date")
-			prop.Format = layout
-			mappings.SetProperty(key, prop)
-		} else {
-			newProp := meta.NewProperty("text")
-			if config.Global.EnableTextKeywordMapping {
-				p := meta.NewProperty("keyword")
-				newProp.AddField("keyword", p)
-				mappings.SetProperty(key+".keyword", p)
-			}
-			mappings.SetProperty(key, newProp)
+// checkProperty returns if need update mappings, the new field is mapped by the dynamic mapping options
+func checkProperty(mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer, dynamic meta.DynamicMapping, key string, value interface{}) (bool, error) {
+	if prop, ok := mappings.GetProperty(key); ok {
+		if !config.Global.EnableTextKeywordMapping || prop.Type != "text" {
+			return false, nil
 		}
-	case int, int64, float64:
-		mappings.SetProperty(key, meta.NewProperty("numeric"))
-	case bool:
-		mappings.SetProperty(key, meta.NewProperty("bool"))
+		if _, ok := mappings.GetProperty(key + ".keyword"); ok {
+			return false, nil
+		}
+		// add the keyword sub field for the exists text field
+		p := meta.NewProperty("keyword")
+		prop.AddField("keyword", p)
+		mappings.SetProperty(key+".keyword", p)
+		mappings.SetProperty(key, prop)
+		return true, nil
+	}
+
+	switch dynamic.Dynamic {
+	case meta.DynamicFalse:
+		return false, nil
+	case meta.DynamicStrict:
+		return false, errors.New(errors.ErrorTypeStrictDynamicMapping, fmt.Sprintf("mapping set to strict, dynamic introduction of [%s] within [_doc] is not allowed", key))
+	}
+
+	newMappings, err := dynamicMappings(analyzers, dynamic, key, value)
+	if err != nil || newMappings == nil || newMappings.Len() == 0 {
+		return false, err
+	}
+	if dynamic.TotalFieldsLimit > 0 && mappings.Len()+newMappings.Len() > dynamic.TotalFieldsLimit {
+		return false, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("Limit of total fields [%d] has been exceeded while adding new fields [%s]", dynamic.TotalFieldsLimit, key))
+	}
+	for field, prop := range newMappings.ListProperty() {
+		mappings.SetProperty(field, prop)
+	}
+
+	return true, nil
+}
+
+// checkFieldValue converts the value of the indexed field to the type of mappings, the value can be an array
+func checkFieldValue(mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer, data map[string]interface{}, key string, value interface{}) error {
+	prop, ok := mappings.GetProperty(key)
+	if !ok || !prop.Index {
+		return nil // not index, skip
+	}
+	if prop.Type == "dense_vector" {
+		// the vector is a single value
+		_, err := knn.Vector(key, prop, value)
+		return err
+	}
+	if prop.Type == "percolator" {
+		// the query is a single object, it is normalized by checkPercolatorFields
+		_, err := percolatorQuery(mappings, analyzers, key, value)
+		return err
+	}
+
+	switch v := value.(type) {
 	case []interface{}:
-		if v, ok := value.([]interface{}); ok {
-			for _, vv := range v {
-				switch val := vv.(type) {
-				case string:
-					if layout, ok := isDateProperty(val); ok {
-						prop = meta.NewProperty("date")
-						prop.Format = layout
-						mappings.SetProperty(key, prop)
-					} else {
-						newProp := meta.NewProperty("text")
-						if config.Global.EnableTextKeywordMapping {
-							p := meta.NewProperty("keyword")
-							newProp.AddField("keyword", p)
-							mappings.SetProperty(key+".keyword", p)
-						}
-						mappings.SetProperty(key, newProp)
-					}
-				case float64:
-					mappings.SetProperty(key, meta.NewProperty("numeric"))
-				case bool:
-					mappings.SetProperty(key, meta.NewProperty("bool"))
-				}
-				break
+		for i, v := range v {
+			if err := checkField(mappings, data, key, v, i, true); err != nil {
+				return err
 			}
 		}
+	default:
+		if err := checkField(mappings, data, key, v, 0, false); err != nil {
+			return err
+		}
 	}
-
-	return true
+	return nil
 }
 
-func (s *IndexShard) checkField(mappings *meta.Mappings, data map[string]interface{}, key string, value interface{}, id int, array bool) error {
+func checkField(mappings *meta.Mappings, data map[string]interface{}, key string, value interface{}, id int, array bool) error {
 	var err error
 	var v interface{}
 	prop, _ := mappings.GetProperty(key)
@@ -299,6 +455,14 @@ func (s *IndexShard) checkField(mappings *meta.Mappings, data map[string]interfa
 			return fmt.Errorf("field [%s] was set type to [text] but the value [%v] can't convert to string", key, value)
 		}
 	case "numeric":
+		if prop.IsLong() {
+			// keep the original value to avoid the precision loss of float64
+			if _, err = prop.ToLong(value); err != nil {
+				return fmt.Errorf("field [%s] was set type to [%s] but the value [%v] can't convert: %s", key, prop.NumericType, value, err.Error())
+			}
+			v = value
+			break
+		}
 		v, err = zutils.ToFloat64(value)
 		if err != nil {
 			return fmt.Errorf("field [%s] was set type to [numeric] but the value [%v] can't convert to int", key, value)
@@ -319,6 +483,9 @@ func (s *IndexShard) checkField(mappings *meta.Mappings, data map[string]interfa
 			return fmt.Errorf("field [%s] value [%v] parse err: %s", key, value, err.Error())
 		}
 		v = value
+	case "completion":
+		// normalized by checkCompletionFields
+		v = value
 	}
 	if array {
 		sub := data[key].([]interface{})
@@ -330,3 +497,77 @@ func (s *IndexShard) checkField(mappings *meta.Mappings, data map[string]interfa
 
 	return nil
 }
+
+// checkCompletionFields replaces the flattened values of completion fields with the normalized inputs,
+// the value of completion field can be an object, it is read from the nested document.
+func checkCompletionFields(mappings *meta.Mappings, doc, flatDoc map[string]interface{}) error {
+	for key, prop := range mappings.ListProperty() {
+		if prop.Type != "completion" {
+			continue
+		}
+		value, ok := nestedValue(doc, key)
+		if !ok {
+			continue
+		}
+		for k := range flatDoc {
+			if k == key || strings.HasPrefix(k, key+".") {
+				delete(flatDoc, k)
+			}
+		}
+		entries, err := suggest.CompletionInputs(key, prop, value, flatDoc)
+		if err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			flatDoc[key] = entries
+		}
+	}
+	return nil
+}
+
+// checkPercolatorFields replaces the flattened values of percolator fields with the query object,
+// the query is read from the nested document.
+func checkPercolatorFields(mappings *meta.Mappings, doc, flatDoc map[string]interface{}) error {
+	for key, prop := range mappings.ListProperty() {
+		if prop.Type != "percolator" {
+			continue
+		}
+		value, ok := nestedValue(doc, key)
+		if !ok {
+			continue
+		}
+		for k := range flatDoc {
+			if k == key || strings.HasPrefix(k, key+".") {
+				delete(flatDoc, k)
+			}
+		}
+		if value == nil {
+			continue
+		}
+		if _, ok := value.(map[string]interface{}); !ok {
+			return fmt.Errorf("field [%s] was set type to [percolator] but the value [%v] is not a query object", key, value)
+		}
+		// the query parser expects float64 numbers
+		json.NormalizeNumber(value)
+		flatDoc[key] = value
+	}
+	return nil
+}
+
+// nestedValue returns the value of the dotted path in the nested document
+func nestedValue(doc map[string]interface{}, path string) (interface{}, bool) {
+	if v, ok := doc[path]; ok {
+		return v, true
+	}
+	for i := 0; i < len(path); i++ {
+		if path[i] != '.' {
+			continue
+		}
+		if sub, ok := doc[path[:i]].(map[string]interface{}); ok {
+			if v, ok := nestedValue(sub, path[i+1:]); ok {
+				return v, true
+			}
+		}
+	}
+	return nil, false
+}
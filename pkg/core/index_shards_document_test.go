@@ -0,0 +1,224 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	zincanalysis "github.com/zincsearch/zincsearch/pkg/uquery/analysis"
+	"github.com/zincsearch/zincsearch/pkg/uquery/mappings"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+)
+
+func TestIndexShard_BuildFieldParameters(t *testing.T) {
+	indexName := "TestIndexShard_BuildFieldParameters.index_1"
+	var index *Index
+
+	t.Run("prepare", func(t *testing.T) {
+		var err error
+		index, err = NewIndex(indexName, "disk", 1)
+		assert.NoError(t, err)
+
+		settings := &meta.IndexSettings{Analysis: &meta.IndexAnalysis{
+			Normalizer: map[string]*meta.Analyzer{
+				"my_normalizer": {CharFilter: []string{"html_strip"}, Filter: []string{"lowercase"}},
+			},
+		}}
+		analyzers, err := zincanalysis.RequestAnalyzer(settings.Analysis)
+		assert.NoError(t, err)
+		assert.NoError(t, index.SetSettings(settings))
+		assert.NoError(t, index.SetAnalyzers(analyzers))
+
+		m, err := mappings.Request(analyzers, map[string]interface{}{
+			"properties": map[string]interface{}{
+				"email":    map[string]interface{}{"type": "keyword", "normalizer": "my_normalizer"},
+				"code":     map[string]interface{}{"type": "keyword", "ignore_above": 5},
+				"status":   map[string]interface{}{"type": "keyword", "null_value": "NULL"},
+				"first":    map[string]interface{}{"type": "keyword", "copy_to": "fullname"},
+				"last":     map[string]interface{}{"type": "keyword", "copy_to": []interface{}{"fullname"}},
+				"fullname": map[string]interface{}{"type": "text"},
+			},
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, index.SetMappings(m))
+		assert.NoError(t, StoreIndex(index))
+
+		assert.NoError(t, index.CreateDocument("1", map[string]interface{}{
+			"email":  "Prabhat@Example.com",
+			"code":   "abc",
+			"status": nil,
+			"first":  "Prabhat",
+			"last":   "Sharma",
+		}, false))
+		assert.NoError(t, index.CreateDocument("2", map[string]interface{}{
+			"email":  "hengfei@example.com",
+			"code":   "abcdefg",
+			"status": "active",
+		}, false))
+
+		waitWAL(t, index)
+	})
+
+	tests := []struct {
+		name  string
+		field string
+		value string
+		want  int
+	}{
+		{name: "normalizer", field: "email", value: "PRABHAT@example.COM", want: 1},
+		{name: "ignore_above keeps short value", field: "code", value: "abc", want: 1},
+		{name: "ignore_above skips long value", field: "code", value: "abcdefg", want: 0},
+		{name: "null_value", field: "status", value: "NULL", want: 1},
+		{name: "copy_to", field: "fullname", value: "sharma", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := index.Search(&meta.ZincQuery{
+				Query: &meta.Query{Term: map[string]*meta.TermQuery{tt.field: {Value: tt.value}}},
+				Size:  10,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, resp.Hits.Total.Value)
+		})
+	}
+
+	t.Run("invalid mappings", func(t *testing.T) {
+		_, err := mappings.Request(nil, map[string]interface{}{
+			"properties": map[string]interface{}{"email": map[string]interface{}{"type": "text", "normalizer": "lowercase"}},
+		})
+		assert.Error(t, err)
+		_, err = mappings.Request(nil, map[string]interface{}{
+			"properties": map[string]interface{}{"email": map[string]interface{}{"type": "keyword", "normalizer": "unknown"}},
+		})
+		assert.Error(t, err)
+		_, err = mappings.Request(nil, map[string]interface{}{
+			"properties": map[string]interface{}{"age": map[string]interface{}{"type": "numeric", "null_value": "abc"}},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		assert.NoError(t, DeleteIndex(indexName))
+	})
+}
+
+func TestIndexShard_BuildFieldNumericTypes(t *testing.T) {
+	indexName := "TestIndexShard_BuildFieldNumericTypes.index_1"
+	var index *Index
+
+	t.Run("prepare", func(t *testing.T) {
+		var err error
+		index, err = NewIndex(indexName, "disk", 1)
+		assert.NoError(t, err)
+
+		m, err := mappings.Request(nil, map[string]interface{}{
+			"properties": map[string]interface{}{
+				"id":    map[string]interface{}{"type": "long"},
+				"count": map[string]interface{}{"type": "integer"},
+				"price": map[string]interface{}{"type": "scaled_float", "scaling_factor": 100},
+				"score": map[string]interface{}{"type": "double"},
+			},
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, index.SetMappings(m))
+		assert.NoError(t, StoreIndex(index))
+
+		docs := []string{
+			`{"id": 1234567890123456789, "count": 1, "price": 1.234, "score": 1.5, "ratio": 0.5}`,
+			`{"id": 1234567890123456790, "count": 2, "price": 2.5, "score": 2.5}`,
+		}
+		for i, data := range docs {
+			doc := make(map[string]interface{})
+			assert.NoError(t, json.UnmarshalUseNumber([]byte(data), &doc))
+			assert.NoError(t, index.CreateDocument(strconv.Itoa(i+1), doc, false))
+		}
+		// the number of dynamic field isn't kept as json.Number
+		prop, ok := index.GetMappings().GetProperty("ratio")
+		assert.True(t, ok)
+		assert.False(t, prop.IsLong())
+		assert.Error(t, index.CreateDocument("3", map[string]interface{}{"count": float64(1 << 40)}, false))
+
+		waitWAL(t, index)
+	})
+
+	t.Run("term", func(t *testing.T) {
+		resp, err := index.Search(&meta.ZincQuery{
+			Query: map[string]interface{}{"term": map[string]interface{}{"id": json.Number("1234567890123456789")}},
+			Size:  10,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, resp.Hits.Total.Value)
+		require.Len(t, resp.Hits.Hits, 1)
+		assert.Equal(t, json.Number("1234567890123456789"), resp.Hits.Hits[0].Source.(map[string]interface{})["id"])
+	})
+
+	t.Run("range", func(t *testing.T) {
+		resp, err := index.Search(&meta.ZincQuery{
+			Query: map[string]interface{}{"range": map[string]interface{}{"id": map[string]interface{}{"gt": json.Number("1234567890123456789")}}},
+			Size:  10,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, resp.Hits.Total.Value)
+		require.Len(t, resp.Hits.Hits, 1)
+		assert.Equal(t, "2", resp.Hits.Hits[0].ID)
+
+		resp, err = index.Search(&meta.ZincQuery{
+			Query: map[string]interface{}{"range": map[string]interface{}{"price": map[string]interface{}{"lte": 1.23}}},
+			Size:  10,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, resp.Hits.Total.Value)
+	})
+
+	t.Run("aggregations", func(t *testing.T) {
+		resp, err := index.Search(&meta.ZincQuery{
+			Size: 0,
+			Aggregations: map[string]meta.Aggregations{
+				"ids":       {Terms: &meta.AggregationsTerms{Field: "id", Size: 10}},
+				"price":     {Sum: &meta.AggregationMetric{Field: "price"}},
+				"max_count": {Max: &meta.AggregationMetric{Field: "count"}},
+			},
+		})
+		assert.NoError(t, err)
+		keys := []interface{}{}
+		for _, bucket := range resp.Aggregations["ids"].Buckets.([]map[string]interface{}) {
+			keys = append(keys, bucket["key"])
+		}
+		assert.ElementsMatch(t, []interface{}{int64(1234567890123456789), int64(1234567890123456790)}, keys)
+		assert.InDelta(t, 3.73, resp.Aggregations["price"].Value, 0.0001)
+		assert.Equal(t, float64(2), resp.Aggregations["max_count"].Value)
+	})
+
+	t.Run("invalid mappings", func(t *testing.T) {
+		_, err := mappings.Request(nil, map[string]interface{}{
+			"properties": map[string]interface{}{"price": map[string]interface{}{"type": "scaled_float"}},
+		})
+		assert.Error(t, err)
+		_, err = mappings.Request(nil, map[string]interface{}{
+			"properties": map[string]interface{}{"count": map[string]interface{}{"type": "byte", "null_value": 1000}},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		assert.NoError(t, DeleteIndex(indexName))
+	})
+}
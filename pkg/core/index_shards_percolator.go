@@ -0,0 +1,107 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"fmt"
+
+	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/analysis"
+
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/uquery/query"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+	"github.com/zincsearch/zincsearch/pkg/zutils/flatten"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+)
+
+func init() {
+	query.PercolateDocumentBuilder = buildPercolateDocument
+}
+
+// percolatorQuery parses the query stored in percolator field
+func percolatorQuery(mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer, key string, value interface{}) (bluge.Query, error) {
+	q, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("field [%s] was set type to [percolator] but the value [%v] is not a query object", key, value)
+	}
+	subq, err := query.Query(q, mappings, analyzers)
+	if err != nil {
+		return nil, fmt.Errorf("field [%s] percolator query parse err: %s", key, err.Error())
+	}
+	return subq, nil
+}
+
+// buildPercolatorField stores the query of percolator field and indexes the terms extracted from it,
+// the terms are used to select the candidate queries of the percolated documents.
+func buildPercolatorField(mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer, bdoc *bluge.Document, key string, value interface{}) error {
+	subq, err := percolatorQuery(mappings, analyzers, key, value)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	bdoc.AddField(bluge.NewStoredOnlyField(key, data))
+	for _, term := range query.PercolatorTerms(subq) {
+		bdoc.AddField(bluge.NewKeywordField(key, term))
+	}
+	return nil
+}
+
+// buildPercolateDocument builds the document of percolate query like a new document of the index,
+// the new fields are mapped dynamically in a copy of mappings, the mappings of index are not changed.
+func buildPercolateDocument(mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer, docID string, doc map[string]interface{}) (*bluge.Document, error) {
+	mappings = mappings.DeepClone()
+	dynamic := mappings.GetDynamic()
+
+	flatDoc, _ := flatten.Flatten(doc, "")
+	if err := checkCompletionFields(mappings, doc, flatDoc); err != nil {
+		return nil, err
+	}
+	if err := checkPercolatorFields(mappings, doc, flatDoc); err != nil {
+		return nil, err
+	}
+	for key, value := range flatDoc {
+		if value == nil {
+			continue
+		}
+		if _, err := checkProperty(mappings, analyzers, dynamic, key, value); err != nil {
+			return nil, err
+		}
+		if err := checkFieldValue(mappings, analyzers, flatDoc, key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	bdoc := bluge.NewDocument(docID)
+	allExcluding, err := buildDocumentFields(mappings, analyzers, bdoc, flatDoc)
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := flatDoc[meta.TimeFieldName]; ok {
+		prop, _ := mappings.GetProperty(meta.TimeFieldName)
+		timestamp, err := zutils.ParseTime(value, prop.Format, prop.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("field [%s] value [%v] parse err: %s", meta.TimeFieldName, value, err.Error())
+		}
+		bdoc.AddField(bluge.NewDateTimeField(meta.TimeFieldName, timestamp))
+	}
+	bdoc.AddField(bluge.NewCompositeFieldExcluding("_all", allExcluding))
+
+	return bdoc, nil
+}
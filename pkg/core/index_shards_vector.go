@@ -0,0 +1,184 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"sync"
+
+	"github.com/blugelabs/bluge"
+
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/uquery/knn"
+	"github.com/zincsearch/zincsearch/pkg/zutils/hnsw"
+)
+
+// shardVectors is the HNSW graphs of the dense_vector fields in a second layer shard.
+// The graphs are kept in memory, they are loaded from the stored vectors at the first knn search,
+// and then updated when the documents are written to the shard.
+type shardVectors struct {
+	graphs map[string]*hnsw.Graph
+	loaded bool
+	lock   sync.Mutex
+}
+
+// vectorFields returns the indexed dense_vector fields of mappings
+func vectorFields(mappings *meta.Mappings) map[string]meta.Property {
+	var fields map[string]meta.Property
+	for field, prop := range mappings.ListProperty() {
+		if prop.Type == "dense_vector" && prop.Index {
+			if fields == nil {
+				fields = make(map[string]meta.Property)
+			}
+			fields[field] = prop
+		}
+	}
+	return fields
+}
+
+func newVectorGraph(prop meta.Property) *hnsw.Graph {
+	m, efConstruction := hnsw.DefaultM, hnsw.DefaultEfConstruction
+	if prop.IndexOptions != nil {
+		if prop.IndexOptions.M > 0 {
+			m = prop.IndexOptions.M
+		}
+		if prop.IndexOptions.EfConstruction > 0 {
+			efConstruction = prop.IndexOptions.EfConstruction
+		}
+	}
+	return hnsw.New(knn.Similarity(prop.Similarity), m, efConstruction)
+}
+
+// graph returns the graph of field, it is created if not exists
+func (v *shardVectors) graph(field string, prop meta.Property) *hnsw.Graph {
+	if v.graphs == nil {
+		v.graphs = make(map[string]*hnsw.Graph)
+	}
+	g, ok := v.graphs[field]
+	if !ok {
+		g = newVectorGraph(prop)
+		v.graphs[field] = g
+	}
+	return g
+}
+
+// reset drops the graphs, they are loaded again at the next knn search
+func (v *shardVectors) reset() {
+	v.lock.Lock()
+	v.graphs = nil
+	v.loaded = false
+	v.lock.Unlock()
+}
+
+// VectorGraph returns the HNSW graph of the field in the second layer shard, nil if the shard has no vectors of field
+func (s *IndexShard) VectorGraph(shardID int64, field string) (*hnsw.Graph, error) {
+	s.lock.RLock()
+	secondShard := s.shards[shardID]
+	s.lock.RUnlock()
+	v := &secondShard.vectors
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if !v.loaded {
+		if err := s.loadVectors(shardID, v); err != nil {
+			return nil, err
+		}
+		v.loaded = true
+	}
+	return v.graphs[field], nil
+}
+
+// loadVectors builds the graphs from the stored vectors of documents
+func (s *IndexShard) loadVectors(shardID int64, v *shardVectors) error {
+	fields := vectorFields(s.root.GetMappings())
+	v.graphs = nil
+	if len(fields) == 0 {
+		return nil
+	}
+	w, err := s.GetWriter(shardID)
+	if err != nil {
+		return err
+	}
+	r, err := w.Reader()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	dmi, err := r.Search(context.Background(), bluge.NewAllMatches(bluge.NewMatchAllQuery()))
+	if err != nil {
+		return err
+	}
+	next, err := dmi.Next()
+	for err == nil && next != nil {
+		var id string
+		vectors := make(map[string][]float32)
+		err = next.VisitStoredFields(func(field string, value []byte) bool {
+			if field == "_id" {
+				id = string(value)
+			} else if _, ok := fields[field]; ok {
+				vectors[field] = knn.DecodeVector(value)
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		for field, vector := range vectors {
+			prop := fields[field]
+			v.graph(field, prop).Add(id, knn.GraphVector(prop.Similarity, vector))
+		}
+		next, err = dmi.Next()
+	}
+	return err
+}
+
+// updateVectors updates the loaded graphs of the second layer shard by the written documents,
+// the documents are removed if deleted is true.
+func (s *IndexShard) updateVectors(shardID int64, fields map[string]meta.Property, docs map[string]*walDocument, deleted func(doc *walDocument) bool) {
+	s.lock.RLock()
+	secondShard := s.shards[shardID]
+	s.lock.RUnlock()
+	v := &secondShard.vectors
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if !v.loaded {
+		return // the documents will be loaded at the next knn search
+	}
+	for _, doc := range docs {
+		for field, prop := range fields {
+			var vector []float32
+			if !deleted(doc) {
+				if value, ok := doc.data[field]; ok {
+					vector, _ = knn.Vector(field, prop, value)
+				}
+			}
+			if vector == nil {
+				if g, ok := v.graphs[field]; ok {
+					g.Delete(doc.docID)
+				}
+				continue
+			}
+			v.graph(field, prop).Add(doc.docID, knn.GraphVector(prop.Similarity, vector))
+		}
+	}
+}
+
+// resetVectors drops the graphs of the second layer shard
+func (s *IndexShard) resetVectors(shardID int64) {
+	s.lock.RLock()
+	secondShard := s.shards[shardID]
+	s.lock.RUnlock()
+	secondShard.vectors.reset()
+}
@@ -113,7 +113,7 @@ func (s *IndexShard) Rollback() error {
 		}
 
 		doc := make(map[string]interface{})
-		err = json.Unmarshal(entry, &doc)
+		err = json.UnmarshalNumber(entry, &doc)
 		if err != nil {
 			log.Error().Err(err).Str("index", s.GetIndexName()).Str("shard", s.GetID()).Msg("rollback wal.entry.Unmarshal()")
 			return err
@@ -177,7 +177,7 @@ func (s *IndexShard) ConsumeWAL() bool {
 		}
 
 		doc := make(map[string]interface{})
-		err = json.Unmarshal(entry, &doc)
+		err = json.UnmarshalNumber(entry, &doc)
 		if err != nil {
 			log.Error().Err(err).Str("index", s.GetIndexName()).Str("shard", s.GetID()).Msg("consume wal.entry.Unmarshal()")
 			return false
@@ -307,7 +307,7 @@ func (w *walMergeDocs) Reset() {
 func (w *walMergeDocs) AddDocument(data map[string]interface{}) {
 	action := data[meta.ActionFieldName].(string)
 	docID := data[meta.IDFieldName].(string)
-	shardID := int64(data[meta.ShardFieldName].(float64))
+	shardID, _ := zutils.ToInt64(data[meta.ShardFieldName])
 	shard, ok := (*w)[shardID]
 	if !ok {
 		shard = make(map[string]*walDocument)
@@ -367,6 +367,7 @@ func (w *walMergeDocs) WriteToShard(shard *IndexShard, shardID int64, batch *blu
 		writer = ws[len(ws)-1]
 		otherWriters = append(otherWriters, ws...)
 		otherWriters = otherWriters[:len(ws)-1]
+		shardID = int64(len(ws) - 1)
 	}
 	var firstAction, lastAction string
 	for _, doc := range docs {
@@ -441,6 +442,16 @@ func (w *walMergeDocs) WriteToShard(shard *IndexShard, shardID int64, batch *blu
 			return err
 		}
 	}
+
+	// update the vector graphs, the document is only kept in the latest written shard
+	if fields := vectorFields(shard.root.GetMappings()); len(fields) > 0 {
+		shard.updateVectors(shardID, fields, docs, func(doc *walDocument) bool {
+			return doc.actions[len(doc.actions)-1] == meta.ActionTypeDelete
+		})
+		for id := range otherWriters {
+			shard.updateVectors(int64(id), fields, docs, func(doc *walDocument) bool { return true })
+		}
+	}
 	return nil
 }
 
@@ -476,5 +487,9 @@ func (w *walMergeDocs) WriteToShardRollback(shard *IndexShard, shardID int64, ba
 		}
 	}
 
-	return writer.Batch(batch)
+	if err := writer.Batch(batch); err != nil {
+		return err
+	}
+	shard.resetVectors(shardID)
+	return nil
 }
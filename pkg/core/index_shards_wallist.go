@@ -45,9 +45,13 @@ func (t *IndexShardWALList) Add(shard *IndexShard) {
 	t.lock.Unlock()
 }
 
-func (t *IndexShardWALList) Remove(name string) {
+// Remove removes the shard from list, a new opened shard with the same name is kept,
+// it happens when an index deleted and then created or restored with the same name.
+func (t *IndexShardWALList) Remove(shard *IndexShard) {
 	t.lock.Lock()
-	delete(t.Shards, name)
+	if t.Shards[shard.GetShardName()] == shard {
+		delete(t.Shards, shard.GetShardName())
+	}
 	t.lock.Unlock()
 }
 
@@ -75,7 +79,7 @@ func (t *IndexShardWALList) ConsumeWAL() {
 	eg.SetLimit(config.Global.Shard.GoroutineNum)
 	tick := time.NewTicker(config.Global.WalSyncInterval)
 	for range tick.C {
-		shardClosed := make(chan string, t.Len())
+		shardClosed := make(chan *IndexShard, t.Len())
 		indexUpdated := make(chan string, t.Len())
 		for _, shard := range t.List() {
 			shard := shard
@@ -83,12 +87,17 @@ func (t *IndexShardWALList) ConsumeWAL() {
 			eg.Go(func() error {
 				select {
 				case <-shard.close:
-					shardClosed <- shard.GetShardName()
+					shardClosed <- shard
 					return nil
 				default:
 					// continue
 				}
+				// skip the shard which WAL consumption is paused
+				if !shard.consume.TryLock() {
+					return nil
+				}
 				updated := shard.ConsumeWAL()
+				shard.consume.Unlock()
 				if updated {
 					indexUpdated <- shard.GetIndexName()
 				}
@@ -100,8 +109,8 @@ func (t *IndexShardWALList) ConsumeWAL() {
 		close(indexUpdated)
 
 		// check shard closed
-		for name := range shardClosed {
-			t.Remove(name)
+		for shard := range shardClosed {
+			t.Remove(shard)
 		}
 
 		// update index stats
@@ -121,6 +130,9 @@ func (t *IndexShardWALList) ConsumeWAL() {
 			stats := index.GetStats()
 			SetMetricStatsByIndex(name, "doc_num", float64(atomic.LoadUint64(&stats.DocNum)))
 			SetMetricStatsByIndex(name, "storage_size", float64(atomic.LoadUint64(&stats.StorageSize)/1024/1024)) // convert to MB
+			indexStats := index.GetIndexStats(false)
+			SetMetricStatsByIndex(name, "wal_lag", float64(indexStats.WAL.Lag))
+			SetMetricStatsByIndex(name, "segments", float64(indexStats.Segments.Count))
 
 			delete(indexes, name)
 		}
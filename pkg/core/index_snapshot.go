@@ -0,0 +1,230 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/blugelabs/bluge"
+	blugeindex "github.com/blugelabs/bluge/index"
+	"github.com/rs/zerolog/log"
+
+	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/upgrade"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+)
+
+// PauseWAL stops consuming WAL for all shards of the index,
+// it waits for the running consumer finished and blocks until ResumeWAL
+func (index *Index) PauseWAL() {
+	for _, shard := range index.shards {
+		shard.consume.Lock()
+	}
+}
+
+// ResumeWAL continues consuming WAL what paused by PauseWAL
+func (index *Index) ResumeWAL() {
+	for _, shard := range index.shards {
+		shard.consume.Unlock()
+	}
+}
+
+// Backup copies the segments of all second layer shards into dir, layout is dir/shardID/secondShardID.
+// It writes the documents in WAL to index and pauses WAL consumption during copying,
+// returns the index metadata what consistent with the copied segments.
+// The segment files what skip reports as already stored, by the path relative to dir and the source file, are not copied.
+func (index *Index) Backup(dir string, skip func(name, file string) bool, cancel chan struct{}) (*meta.Index, error) {
+	// the closed index opens writers just for backup, close them after WAL resumed
+	if index.IsClosed() {
+		defer index.Close()
+	}
+
+	// open all writers, it also opens WAL
+	for _, shard := range index.shards {
+		if _, err := shard.GetWriters(); err != nil {
+			return nil, err
+		}
+	}
+
+	index.PauseWAL()
+	defer index.ResumeWAL()
+
+	index.flushPausedWAL()
+	if err := index.UpdateMetadata(); err != nil {
+		return nil, err
+	}
+
+	for id, shard := range index.shards {
+		for i := int64(0); i < shard.GetShardNum(); i++ {
+			w, err := shard.GetWriter(i)
+			if err != nil {
+				return nil, err
+			}
+			if err = waitPersisted(w); err != nil {
+				return nil, err
+			}
+			name := path.Join(id, fmt.Sprintf("%06x", i))
+			src := path.Join(IndexDataPath(index.GetName()), name)
+			if err = backupSecondShard(src, dir, name, skip, cancel); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// copy metadata
+	data, err := index.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	ref := new(meta.Index)
+	if err = json.Unmarshal(data, ref); err != nil {
+		return nil, err
+	}
+	ref.Stats.WALSize = 0
+	return ref, nil
+}
+
+// waitPersisted waits for the writer persisting its latest snapshot into disk,
+// the root loaded from disk is persisted already if nothing introduced after opened
+func waitPersisted(w *bluge.Writer) error {
+	for i := 0; i < 1000; i++ {
+		stats := w.Status()
+		if stats.LastPersistedEpoch >= stats.CurRootEpoch ||
+			stats.TotIntroduceSegmentBeg+stats.TotIntroduceMergeBeg+stats.TotIntroducePersistBeg == 0 {
+			return nil
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return errors.New(errors.ErrorTypeRuntimeException, "wait for index persisting timeout")
+}
+
+// backupSecondShard copies the latest persisted snapshot in src into dir/name
+func backupSecondShard(src, dir, name string, skip func(name, file string) bool, cancel chan struct{}) error {
+	dst := path.Join(dir, name)
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	snapshots, err := blugeindex.NewFileSystemDirectory(src).List(blugeindex.ItemKindSnapshot)
+	if err != nil || len(snapshots) == 0 {
+		// nothing persisted yet, the shard is empty
+		return nil
+	}
+	r, err := blugeindex.OpenReader(blugeindex.DefaultConfig(src))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return r.Backup(&backupDirectory{
+		FileSystemDirectory: blugeindex.NewFileSystemDirectory(dst),
+		src:                 src,
+		name:                name,
+		skip:                skip,
+	}, cancel)
+}
+
+// backupDirectory is the backup target directory, it skips persisting the segments what already stored
+type backupDirectory struct {
+	*blugeindex.FileSystemDirectory
+	src  string
+	name string
+	skip func(name, file string) bool
+}
+
+func (d *backupDirectory) Persist(kind string, id uint64, w blugeindex.WriterTo, closeCh chan struct{}) error {
+	if kind == blugeindex.ItemKindSegment && d.skip != nil {
+		file := fmt.Sprintf("%012x", id) + kind
+		if d.skip(path.Join(d.name, file), path.Join(d.src, file)) {
+			return nil
+		}
+	}
+	return d.FileSystemDirectory.Persist(kind, id, w, closeCh)
+}
+
+// flushWAL consumes the WAL entries of all shards and updates the shards stats
+func (index *Index) flushWAL() {
+	index.PauseWAL()
+	defer index.ResumeWAL()
+	index.flushPausedWAL()
+}
+
+// flushPausedWAL is flushWAL for the caller already paused WAL consumption
+func (index *Index) flushPausedWAL() {
+	for id, shard := range index.shards {
+		shard.flushWAL()
+		index.UpdateMetadataByShard(id)
+	}
+}
+
+// flushWAL consumes the WAL entries what already written before call it
+func (s *IndexShard) flushWAL() {
+	s.lock.RLock()
+	opened := s.wal != nil
+	s.lock.RUnlock()
+	if !opened {
+		return
+	}
+	lag, err := s.GetWALLag()
+	if err != nil {
+		log.Error().Err(err).Str("index", s.GetIndexName()).Str("shard", s.GetID()).Msg("flush wal.GetWALLag()")
+		return
+	}
+	for n := lag/MaxBatchSize + 1; n > 0 && s.ConsumeWAL(); n-- {
+		// continue
+	}
+}
+
+// RestoreIndex creates an index from the metadata, the segments should already copied into data path.
+// It stores the index to metadata and cache.
+func RestoreIndex(ref *meta.Index) (*Index, error) {
+	if err := CheckIndexName(ref.Name); err != nil {
+		return nil, err
+	}
+	if _, ok := GetIndex(ref.Name); ok {
+		return nil, errors.New(errors.ErrorTypeInvalidArgument, "index ["+ref.Name+"] already exists")
+	}
+
+	version := ref.Version
+	if version == "" {
+		version = meta.Version
+	}
+	if version != meta.Version {
+		log.Info().Msgf("Upgrade index[%s] from version[%s] to version[%s]", ref.Name, version, meta.Version)
+		if err := upgrade.Do(version, ref); err != nil {
+			return nil, err
+		}
+		ref.Version = meta.Version
+	}
+	ref.Stats.WALSize = 0
+
+	index, err := newIndexFromMetadata(ref)
+	if err != nil {
+		return nil, err
+	}
+	if err = StoreIndex(index); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// IndexDataPath returns the data directory of the index
+func IndexDataPath(name string) string {
+	return path.Join(config.Global.DataPath, name)
+}
@@ -0,0 +1,163 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/blugelabs/bluge"
+
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+)
+
+// indexCounters runtime counters of index, they are not persistent and reset after restart
+type indexCounters struct {
+	indexTotal  uint64
+	indexFailed uint64
+	deleteTotal uint64
+	queryTotal  uint64
+	queryTimeNs uint64
+	openReaders int64
+}
+
+// recordIndexing update indexing counters by the result of document write
+func (index *Index) recordIndexing(action string, err error) {
+	if err != nil {
+		n := atomic.AddUint64(&index.counters.indexFailed, 1)
+		SetMetricStatsByIndex(index.GetName(), "index_failed", float64(n))
+		return
+	}
+	if action == meta.ActionTypeDelete {
+		n := atomic.AddUint64(&index.counters.deleteTotal, 1)
+		SetMetricStatsByIndex(index.GetName(), "delete_total", float64(n))
+		return
+	}
+	n := atomic.AddUint64(&index.counters.indexTotal, 1)
+	SetMetricStatsByIndex(index.GetName(), "index_total", float64(n))
+}
+
+// recordQuery update search counters
+func (index *Index) recordQuery(took time.Duration) {
+	n := atomic.AddUint64(&index.counters.queryTotal, 1)
+	t := atomic.AddUint64(&index.counters.queryTimeNs, uint64(took))
+	SetMetricStatsByIndex(index.GetName(), "query_total", float64(n))
+	SetMetricStatsByIndex(index.GetName(), "query_time_ms", float64(t/uint64(time.Millisecond)))
+}
+
+// CloseReaders close readers what returned by GetReaders
+func (index *Index) CloseReaders(readers []*bluge.Reader) {
+	for _, reader := range readers {
+		_ = reader.Close()
+	}
+	n := atomic.AddInt64(&index.counters.openReaders, -int64(len(readers)))
+	SetMetricStatsByIndex(index.GetName(), "open_readers", float64(n))
+}
+
+// GetIndexStats returns runtime stats of index, with withShards it returns stats for every layer shards
+func (index *Index) GetIndexStats(withShards bool) *meta.IndexStats {
+	stats := index.GetStats()
+	s := &meta.IndexStats{
+		Docs:  meta.DocsStats{Count: stats.DocNum},
+		Store: meta.StoreStats{SizeInBytes: stats.StorageSize},
+		Indexing: meta.IndexingStats{
+			IndexTotal:  atomic.LoadUint64(&index.counters.indexTotal),
+			IndexFailed: atomic.LoadUint64(&index.counters.indexFailed),
+			DeleteTotal: atomic.LoadUint64(&index.counters.deleteTotal),
+		},
+		Search: meta.SearchStats{
+			QueryTotal:        atomic.LoadUint64(&index.counters.queryTotal),
+			QueryTimeInMillis: atomic.LoadUint64(&index.counters.queryTimeNs) / uint64(time.Millisecond),
+		},
+		WAL:     meta.WALStats{Operations: stats.WALSize},
+		Readers: meta.ReadersStats{Open: atomic.LoadInt64(&index.counters.openReaders)},
+	}
+	if s.Search.QueryTotal > 0 {
+		s.Search.QueryAvgTimeInMillis = float64(atomic.LoadUint64(&index.counters.queryTimeNs)) /
+			float64(time.Millisecond) / float64(s.Search.QueryTotal)
+	}
+	if withShards {
+		s.Shards = make(map[string]*meta.IndexShardStats, len(index.shards))
+	}
+
+	for id, shard := range index.shards {
+		shardStats := shard.GetShardStats()
+		s.WAL.Lag += shardStats.WAL.Lag
+		for _, secondShard := range shardStats.Shards {
+			s.Segments.Count += secondShard.Segments.Count
+		}
+		if withShards {
+			s.Shards[id] = shardStats
+		}
+	}
+
+	return s
+}
+
+// GetShardStats returns the WAL stats of first layer shard and stats of all second layer shards
+func (s *IndexShard) GetShardStats() *meta.IndexShardStats {
+	stats := &meta.IndexShardStats{
+		Shards: make([]*meta.IndexSecondShardStats, 0, s.GetShardNum()),
+	}
+	stats.WAL.Operations, _ = s.GetWALSize()
+	stats.WAL.Lag, _ = s.GetWALLag()
+
+	s.lock.RLock()
+	secondShards := make([]*IndexSecondShard, len(s.shards))
+	copy(secondShards, s.shards)
+	s.lock.RUnlock()
+	for _, secondShard := range secondShards {
+		s.root.lock.RLock()
+		ref := *secondShard.ref
+		s.root.lock.RUnlock()
+		secondStats := &meta.IndexSecondShardStats{
+			ID:    ref.ID,
+			Docs:  meta.DocsStats{Count: ref.Stats.DocNum},
+			Store: meta.StoreStats{SizeInBytes: ref.Stats.StorageSize},
+		}
+		// don't open the closed writer just for stats
+		secondShard.lock.RLock()
+		if secondShard.writer != nil {
+			status := secondShard.writer.Status()
+			secondStats.Segments.Count = status.TotFileSegmentsAtRoot + status.TotMemorySegmentsAtRoot
+		}
+		secondShard.lock.RUnlock()
+		stats.Shards = append(stats.Shards, secondStats)
+	}
+	return stats
+}
+
+// GetWALLag returns the number of WAL entries what not consumed into index yet
+func (s *IndexShard) GetWALLag() (uint64, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if s.wal == nil {
+		return 0, nil
+	}
+	lastID, err := s.wal.LastIndex()
+	if err != nil {
+		return 0, err
+	}
+	_, committedID, err := s.readRedoLog(RedoActionWrite)
+	if err != nil && err.Error() != errors.ErrNotFound.Error() {
+		return 0, err
+	}
+	if lastID < committedID {
+		return 0, nil
+	}
+	return lastID - committedID, nil
+}
@@ -0,0 +1,97 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/meta"
+)
+
+func TestIndex_GetIndexStats(t *testing.T) {
+	indexName := "TestIndex_GetIndexStats.index_1"
+	index, err := NewIndex(indexName, "disk", 2)
+	assert.NoError(t, err)
+	assert.NoError(t, StoreIndex(index))
+	defer func() {
+		assert.NoError(t, DeleteIndex(indexName))
+	}()
+
+	t.Run("indexing", func(t *testing.T) {
+		err := index.CreateDocument("1", map[string]interface{}{"name": "Prabhat"}, false)
+		assert.NoError(t, err)
+		err = index.CreateDocument("2", map[string]interface{}{"name": "Sharma"}, false)
+		assert.NoError(t, err)
+		err = index.UpdateDocument("3", map[string]interface{}{"name": "Prabhat"}, false)
+		assert.Error(t, err)
+
+		stats := index.GetIndexStats(false)
+		assert.Equal(t, uint64(2), stats.Indexing.IndexTotal)
+		assert.Equal(t, uint64(1), stats.Indexing.IndexFailed)
+		assert.Equal(t, uint64(0), stats.Indexing.DeleteTotal)
+	})
+
+	t.Run("wal", func(t *testing.T) {
+		// wait for WAL write to index
+		assert.Eventually(t, func() bool {
+			for _, shard := range index.shards {
+				if lag, err := shard.GetWALLag(); err != nil || lag > 0 {
+					return false
+				}
+			}
+			return true
+		}, 10*time.Second, 10*time.Millisecond)
+		stats := index.GetIndexStats(true)
+		assert.Equal(t, uint64(0), stats.WAL.Lag)
+		assert.Len(t, stats.Shards, 2)
+		for _, shard := range stats.Shards {
+			assert.Len(t, shard.Shards, 1)
+		}
+
+		err := index.DeleteDocument("1")
+		assert.NoError(t, err)
+		stats = index.GetIndexStats(false)
+		assert.Equal(t, uint64(1), stats.Indexing.DeleteTotal)
+	})
+
+	t.Run("search", func(t *testing.T) {
+		_, err := index.Search(&meta.ZincQuery{
+			Query: &meta.Query{MatchAll: &meta.MatchAllQuery{}},
+			Size:  10,
+		})
+		assert.NoError(t, err)
+		_, err = MultiSearch([]string{indexName}, &meta.ZincQuery{
+			Query: &meta.Query{MatchAll: &meta.MatchAllQuery{}},
+			Size:  10,
+		})
+		assert.NoError(t, err)
+
+		stats := index.GetIndexStats(false)
+		assert.Equal(t, uint64(2), stats.Search.QueryTotal)
+		assert.Equal(t, int64(0), stats.Readers.Open)
+	})
+}
+
+func TestGetNodeStats(t *testing.T) {
+	stats := GetNodeStats()
+	assert.NotNil(t, stats)
+	assert.Greater(t, stats.Runtime.Goroutines, 0)
+	assert.Greater(t, stats.Runtime.Mem.HeapUsedInBytes, uint64(0))
+	assert.Greater(t, stats.FS.TotalInBytes, uint64(0))
+}
@@ -35,14 +35,6 @@ func LoadZincIndexesFromMetadata(version string) error {
 
 	for i := range indexes {
 		readIndex := indexes[i]
-		index := new(Index)
-		index.ref = new(meta.Index)
-		index.ref.Name = readIndex.Name
-		index.ref.StorageType = readIndex.StorageType
-		index.ref.Settings = readIndex.Settings
-		index.ref.Mappings = readIndex.Mappings
-		index.ref.Stats = readIndex.Stats
-
 		// upgrade from old version
 		if readIndex.Version != "" {
 			version = readIndex.Version
@@ -63,63 +55,83 @@ func LoadZincIndexesFromMetadata(version string) error {
 			}
 		}
 
-		// init shards
-		index.ref.ShardNum = readIndex.ShardNum
-		index.ref.Shards = make(map[string]*meta.IndexShard, index.shardNum)
-		for id := range readIndex.Shards {
-			index.ref.Shards[id] = &meta.IndexShard{
-				ID:       readIndex.Shards[id].ID,
-				ShardNum: readIndex.Shards[id].ShardNum,
-				Stats:    readIndex.Shards[id].Stats,
-			}
-			index.ref.Shards[id].Shards = make([]*meta.IndexSecondShard, index.ref.Shards[id].ShardNum)
-			for j := range readIndex.Shards[id].Shards {
-				index.ref.Shards[id].Shards[j] = &meta.IndexSecondShard{
-					ID:    readIndex.Shards[id].Shards[j].ID,
-					Stats: readIndex.Shards[id].Shards[j].Stats,
-				}
+		index, err := newIndexFromMetadata(readIndex)
+		if err != nil {
+			return err
+		}
+
+		// load in memory
+		ZINC_INDEX_LIST.Add(index)
+	}
+
+	return nil
+}
+
+// newIndexFromMetadata init an index and its shards from the stored metadata
+func newIndexFromMetadata(readIndex *meta.Index) (*Index, error) {
+	index := new(Index)
+	index.ref = new(meta.Index)
+	index.ref.Name = readIndex.Name
+	index.ref.StorageType = readIndex.StorageType
+	index.ref.Settings = readIndex.Settings
+	index.ref.Mappings = readIndex.Mappings
+	index.ref.Stats = readIndex.Stats
+	index.ref.Closed = readIndex.Closed
+
+	// init shards
+	index.ref.ShardNum = readIndex.ShardNum
+	index.ref.Shards = make(map[string]*meta.IndexShard, index.shardNum)
+	for id := range readIndex.Shards {
+		index.ref.Shards[id] = &meta.IndexShard{
+			ID:       readIndex.Shards[id].ID,
+			ShardNum: readIndex.Shards[id].ShardNum,
+			Stats:    readIndex.Shards[id].Stats,
+		}
+		index.ref.Shards[id].Shards = make([]*meta.IndexSecondShard, index.ref.Shards[id].ShardNum)
+		for j := range readIndex.Shards[id].Shards {
+			index.ref.Shards[id].Shards[j] = &meta.IndexSecondShard{
+				ID:    readIndex.Shards[id].Shards[j].ID,
+				Stats: readIndex.Shards[id].Shards[j].Stats,
 			}
 		}
+	}
 
-		// init shards wrapper
-		totalShardNum := 0
-		index.shardNum = index.ref.ShardNum
-		index.shards = make(map[string]*IndexShard, index.shardNum)
-		for id := range index.ref.Shards {
-			index.shards[id] = &IndexShard{
+	// init shards wrapper
+	totalShardNum := 0
+	index.shardNum = index.ref.ShardNum
+	index.shards = make(map[string]*IndexShard, index.shardNum)
+	for id := range index.ref.Shards {
+		index.shards[id] = &IndexShard{
+			root: index,
+			ref:  index.ref.Shards[id],
+			name: index.ref.Name + "/" + index.ref.Shards[id].ID,
+		}
+		index.shards[id].shards = make([]*IndexSecondShard, index.ref.Shards[id].ShardNum)
+		for j := range index.ref.Shards[id].Shards {
+			index.shards[id].shards[j] = &IndexSecondShard{
 				root: index,
-				ref:  index.ref.Shards[id],
-				name: index.ref.Name + "/" + index.ref.Shards[id].ID,
-			}
-			index.shards[id].shards = make([]*IndexSecondShard, index.ref.Shards[id].ShardNum)
-			for j := range index.ref.Shards[id].Shards {
-				index.shards[id].shards[j] = &IndexSecondShard{
-					root: index,
-					ref:  index.ref.Shards[id].Shards[j],
-				}
-				totalShardNum++
+				ref:  index.ref.Shards[id].Shards[j],
 			}
+			totalShardNum++
 		}
+	}
 
-		// init shards hashing
-		index.shardHashing = rendezvous.New()
-		for id := range index.shards {
-			index.shardHashing.Add(id)
-		}
+	// init shards hashing
+	index.shardHashing = rendezvous.New()
+	for id := range index.shards {
+		index.shardHashing.Add(id)
+	}
 
-		log.Info().Msgf("Loading  index... [%s:%s] shards[%d:%d]", index.ref.Name, index.ref.StorageType, index.ref.ShardNum, totalShardNum)
+	log.Info().Msgf("Loading  index... [%s:%s] shards[%d:%d]", index.ref.Name, index.ref.StorageType, index.ref.ShardNum, totalShardNum)
 
-		// load index analysis
-		if index.ref.Settings != nil && index.ref.Settings.Analysis != nil {
-			index.analyzers, err = zincanalysis.RequestAnalyzer(index.ref.Settings.Analysis)
-			if err != nil {
-				return errors.New(errors.ErrorTypeRuntimeException, "parse stored analysis error").Cause(err)
-			}
+	// load index analysis, the closed index loads it when opening
+	if !index.ref.Closed && index.ref.Settings != nil && index.ref.Settings.Analysis != nil {
+		var err error
+		index.analyzers, err = zincanalysis.RequestAnalyzer(index.ref.Settings.Analysis)
+		if err != nil {
+			return nil, errors.New(errors.ErrorTypeRuntimeException, "parse stored analysis error").Cause(err)
 		}
-
-		// load in memory
-		ZINC_INDEX_LIST.Add(index)
 	}
 
-	return nil
+	return index, nil
 }
@@ -23,29 +23,52 @@ import (
 
 	"github.com/blugelabs/bluge"
 	"github.com/blugelabs/bluge/analysis"
+	"github.com/blugelabs/bluge/search"
 	"github.com/rs/zerolog/log"
 
 	zincsearch "github.com/zincsearch/zincsearch/pkg/bluge/search"
 	"github.com/zincsearch/zincsearch/pkg/meta"
 	"github.com/zincsearch/zincsearch/pkg/uquery"
+	"github.com/zincsearch/zincsearch/pkg/uquery/sort"
+	"github.com/zincsearch/zincsearch/pkg/uquery/suggest"
 	"github.com/zincsearch/zincsearch/pkg/uquery/timerange"
 )
 
 func MultiSearch(indexNames []string, query *meta.ZincQuery) (*meta.SearchResponse, error) {
+	return MultiSearchWithProgress(context.Background(), indexNames, query, nil)
+}
+
+// MultiSearchWithProgress searches like MultiSearch, the search is canceled by ctx,
+// progress is called with the partial response merged from the completed readers.
+func MultiSearchWithProgress(ctx context.Context, indexNames []string, query *meta.ZincQuery, progress func(resp *meta.SearchResponse)) (*meta.SearchResponse, error) {
 	var mappings *meta.Mappings
 	var analyzers map[string]*analysis.Analyzer
+	var indexMappings []*meta.Mappings
 	var readers []*bluge.Reader
 	var shardNum int64
 
+	indexReaders := make(map[*Index][]*bluge.Reader)
+	defer func() {
+		for index, rs := range indexReaders {
+			index.CloseReaders(rs)
+		}
+	}()
+
 	timeMin, timeMax := timerange.Query(query.Query)
+	if query.KNN != nil {
+		// the knn hits aren't limited by the time range of query
+		timeMin, timeMax = 0, 0
+	}
 	isMatched := false
 	hasIndex := false
 	for _, index := range ZINC_INDEX_LIST.List() {
+		matchedName := ""
 		if len(indexNames) > 0 {
 			for _, indexName := range indexNames {
 				isMatched = isMatchIndex(index.GetName(), indexName)
 				if isMatched {
 					hasIndex = true
+					matchedName = indexName
 					break
 				}
 			}
@@ -53,13 +76,22 @@ func MultiSearch(indexNames []string, query *meta.ZincQuery) (*meta.SearchRespon
 				continue
 			}
 		}
+		// the closed index matched by wildcard is ignored
+		if index.IsClosed() {
+			if matchedName == "" || strings.Contains(matchedName, "*") {
+				continue
+			}
+			return nil, index.CheckOpen()
+		}
 
 		reader, err := index.GetReaders(timeMin, timeMax)
 		if err != nil {
 			return nil, err
 		}
+		indexReaders[index] = reader
 		readers = append(readers, reader...)
 		shardNum += index.GetShardNum()
+		indexMappings = append(indexMappings, index.GetMappings())
 		if mappings == nil {
 			mappings = index.GetMappings()
 			analyzers = index.GetAnalyzers()
@@ -74,26 +106,57 @@ func MultiSearch(indexNames []string, query *meta.ZincQuery) (*meta.SearchRespon
 		return &meta.SearchResponse{}, nil
 	}
 
-	defer func() {
-		for _, reader := range readers {
-			reader.Close()
+	// the sort field only needs to be mapped in one of the indexes
+	if query.Sort != nil {
+		order, err := sort.Request(query.Sort, indexMappings...)
+		if err != nil {
+			return nil, err
 		}
-	}()
-
+		query.Sort = order
+	}
 	_, err := uquery.ParseQueryDSL(query, mappings, analyzers)
 	if err != nil {
 		return nil, err
 	}
+	suggesters, err := suggest.Request(query, mappings, analyzers)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	defer func() {
+		took := time.Since(start)
+		for index := range indexReaders {
+			index.recordQuery(took)
+		}
+	}()
 
-	ctx := context.Background()
 	var cancel context.CancelFunc
 	if query.Timeout > 0 {
-		ctx, cancel = context.WithTimeout(context.Background(), time.Duration(query.Timeout)*time.Second)
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(query.Timeout)*time.Second)
 		defer cancel()
 	}
 
+	indexes := make([]*Index, 0, len(indexReaders))
+	for index := range indexReaders {
+		indexes = append(indexes, index)
+	}
+	if err = searchKNN(ctx, indexes, readers, query, mappings, analyzers); err != nil {
+		return nil, err
+	}
+
+	var readerProgress zincsearch.Progress
+	if progress != nil {
+		readerProgress = func(dmi search.DocumentMatchIterator, completed int) {
+			resp, err := searchV2(shardNum, int64(completed), dmi, query, mappings)
+			if err == nil {
+				progress(resp)
+			}
+		}
+	}
+
 	// dmi, err := bluge.MultiSearch(ctx, searchRequest, readers...)
-	dmi, err := zincsearch.MultiSearch(ctx, query, mappings, analyzers, readers...)
+	dmi, err := zincsearch.MultiSearchWithProgress(ctx, query, mappings, analyzers, readerProgress, readers...)
 	if err != nil {
 		log.Printf("core.MultiSearchV2: error executing search: %s", err.Error())
 		if err == context.DeadlineExceeded {
@@ -106,12 +169,20 @@ func MultiSearch(indexNames []string, query *meta.ZincQuery) (*meta.SearchRespon
 		return nil, err
 	}
 
-	return searchV2(shardNum, int64(len(readers)), dmi, query, mappings)
+	resp, err := searchV2(shardNum, int64(len(readers)), dmi, query, mappings)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Suggest, err = suggest.Response(suggesters, readers); err != nil {
+		return nil, err
+	}
+	return resp, nil
 }
 
 // isMatchIndex("abc", "a")  false
 // isMatchIndex("abc", "a*") true
 // isMatchIndex("abc", "*bc") true
+// isMatchIndex("abc", "a*c") true
 // isMatchIndex("abc", "bc") false
 // isMatchIndex("abc", "abc") true
 func isMatchIndex(zincIndexName, indexName string) bool {
@@ -119,15 +190,5 @@ func isMatchIndex(zincIndexName, indexName string) bool {
 		return true
 	}
 
-	// eg.: *-test
-	if strings.HasPrefix(indexName, "*") {
-		return strings.HasSuffix(zincIndexName, indexName[1:])
-	}
-
-	// eg.: test-*
-	if strings.HasSuffix(indexName, "*") {
-		return strings.HasPrefix(zincIndexName, indexName[:len(indexName)-1])
-	}
-
-	return zincIndexName == indexName
+	return MatchName(indexName, zincIndexName)
 }
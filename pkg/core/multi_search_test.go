@@ -19,8 +19,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/uquery/mappings"
 )
 
 func TestMultiSearch(t *testing.T) {
@@ -123,6 +125,54 @@ func TestMultiSearch(t *testing.T) {
 	})
 }
 
+func TestMultiSearchSort(t *testing.T) {
+	indexNames := []string{"TestMultiSearchSort.index_1", "TestMultiSearchSort.index_2"}
+	t.Run("prepare", func(t *testing.T) {
+		index, err := NewIndex(indexNames[0], "disk", 1)
+		require.NoError(t, err)
+		require.NoError(t, StoreIndex(index))
+		assert.NoError(t, index.CreateDocument("1", map[string]interface{}{"title": "no rank"}, false))
+		waitWAL(t, index)
+
+		// only the second index maps the sort field
+		index, err = NewIndex(indexNames[1], "disk", 1)
+		require.NoError(t, err)
+		m, err := mappings.Request(nil, map[string]interface{}{
+			"properties": map[string]interface{}{"rank": map[string]interface{}{"type": "integer"}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, index.SetMappings(m))
+		require.NoError(t, StoreIndex(index))
+		assert.NoError(t, index.CreateDocument("2", map[string]interface{}{"title": "rank 10", "rank": 10}, false))
+		assert.NoError(t, index.CreateDocument("3", map[string]interface{}{"title": "rank 2", "rank": 2}, false))
+		waitWAL(t, index)
+	})
+
+	t.Run("field mapped in one index", func(t *testing.T) {
+		// the order of indexes is random, search several times
+		for i := 0; i < 10; i++ {
+			got, err := MultiSearch([]string{"TestMultiSearchSort.*"}, &meta.ZincQuery{Sort: []interface{}{"rank"}, Size: 10})
+			require.NoError(t, err)
+			ids := make([]string, 0, len(got.Hits.Hits))
+			for _, hit := range got.Hits.Hits {
+				ids = append(ids, hit.ID)
+			}
+			assert.Equal(t, []string{"3", "2", "1"}, ids)
+		}
+	})
+
+	t.Run("field mapped in no index", func(t *testing.T) {
+		_, err := MultiSearch([]string{"TestMultiSearchSort.*"}, &meta.ZincQuery{Sort: []interface{}{"not_exists"}, Size: 10})
+		assert.Error(t, err)
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		for _, indexName := range indexNames {
+			assert.NoError(t, DeleteIndex(indexName))
+		}
+	})
+}
+
 func TestIsMatchIndex(t *testing.T) {
 	ret := isMatchIndex("abc", "a") //  false
 	assert.False(t, ret)
@@ -48,6 +48,11 @@ func CheckIndexName(name string) error {
 
 // NewIndex creates an instance of a physical zinc index that can be used to store and retrieve data.
 func NewIndex(name, storageType string, shardNum int64) (*Index, error) {
+	return newIndex(name, storageType, shardNum, true)
+}
+
+// newIndex creates an index, the settings of matched template are used if useTemplate is true
+func newIndex(name, storageType string, shardNum int64, useTemplate bool) (*Index, error) {
 	if err := CheckIndexName(name); err != nil {
 		return nil, err
 	}
@@ -67,12 +72,14 @@ func NewIndex(name, storageType string, shardNum int64) (*Index, error) {
 	index.ref.Version = meta.Version
 
 	// use template
-	if err := index.UseTemplate(); err != nil {
-		return nil, err
-	}
-	if index.ref.Settings != nil {
-		if index.ref.Settings.NumberOfShards != 0 {
-			shardNum = index.ref.Settings.NumberOfShards
+	if useTemplate {
+		if err := index.UseTemplate(); err != nil {
+			return nil, err
+		}
+		if index.ref.Settings != nil {
+			if index.ref.Settings.NumberOfShards != 0 {
+				shardNum = index.ref.Settings.NumberOfShards
+			}
 		}
 	}
 
@@ -143,6 +150,16 @@ func StoreIndex(index *Index) error {
 	}
 	// cache index
 	ZINC_INDEX_LIST.Add(index)
+	// add the aliases of template for the new index
+	index.lock.Lock()
+	aliases := index.aliases
+	index.aliases = nil
+	index.lock.Unlock()
+	for _, alias := range aliases {
+		if err := ZINC_INDEX_ALIAS_LIST.AddIndexesToAlias(alias, []string{index.GetName()}); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
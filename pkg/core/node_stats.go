@@ -0,0 +1,82 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/process"
+
+	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+)
+
+var processStartTime = time.Now()
+
+// GetNodeStats returns the process, go runtime and disk stats of current node
+func GetNodeStats() *meta.NodeStats {
+	now := time.Now()
+	stats := &meta.NodeStats{
+		Name:      "zinc-" + strconv.Itoa(config.Global.NodeID),
+		Timestamp: now.UnixMilli(),
+	}
+
+	if p, err := process.NewProcess(int32(os.Getpid())); err == nil {
+		if m, err := p.MemoryInfo(); err == nil {
+			stats.Process.Mem.ResidentInBytes = m.RSS
+			stats.Process.Mem.VirtualInBytes = m.VMS
+		}
+		if cpu, err := p.CPUPercent(); err == nil {
+			stats.Process.CPUPercent = cpu
+		}
+	} else {
+		log.Error().Err(err).Msg("core.GetNodeStats: error getting process info")
+	}
+
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	stats.Runtime.UptimeInMillis = now.Sub(processStartTime).Milliseconds()
+	stats.Runtime.Goroutines = runtime.NumGoroutine()
+	stats.Runtime.Mem.HeapUsedInBytes = m.HeapAlloc
+	stats.Runtime.Mem.HeapCommittedInBytes = m.HeapSys
+	stats.Runtime.Mem.SysInBytes = m.Sys
+	stats.Runtime.GC.CollectionCount = m.NumGC
+	stats.Runtime.GC.CollectionTimeInMillis = m.PauseTotalNs / uint64(time.Millisecond)
+	if m.NumGC > 0 {
+		stats.Runtime.GC.LastPauseInMicros = m.PauseNs[(m.NumGC+255)%256] / uint64(time.Microsecond)
+	}
+
+	stats.FS.Path = config.Global.DataPath
+	if usage, err := disk.Usage(config.Global.DataPath); err == nil {
+		stats.FS.TotalInBytes = usage.Total
+		stats.FS.FreeInBytes = usage.Free
+	} else {
+		log.Error().Err(err).Msg("core.GetNodeStats: error getting disk usage")
+	}
+
+	for _, index := range ZINC_INDEX_LIST.List() {
+		indexStats := index.GetIndexStats(false)
+		stats.Indices.Add(indexStats)
+		stats.FS.DataInBytes += indexStats.Store.SizeInBytes
+	}
+
+	return stats
+}
@@ -0,0 +1,113 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"fmt"
+
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/metadata"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+	"github.com/zincsearch/zincsearch/pkg/zutils/mustache"
+)
+
+// NewStoredScript create or update a stored script, the source must be a valid mustache template
+func NewStoredScript(id string, script *meta.StoredScript) error {
+	if id == "" {
+		return errors.New(errors.ErrorTypeIllegalArgumentException, "script id should be not empty")
+	}
+	if script.Lang == "" {
+		script.Lang = "mustache"
+	}
+	if script.Lang != "mustache" {
+		return errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("script lang [%s] is not supported, only [mustache] is supported", script.Lang))
+	}
+	if _, err := mustache.Parse(script.Source); err != nil {
+		return errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("failed to compile script [%s]", id)).Cause(err)
+	}
+	return metadata.StoredScript.Set(id, *script)
+}
+
+// LoadStoredScript returns the stored script by id
+func LoadStoredScript(id string) (*meta.StoredScript, bool, error) {
+	if id == "" {
+		return nil, false, nil
+	}
+
+	script, err := metadata.StoredScript.Get(id)
+	if err != nil {
+		if err == errors.ErrKeyNotFound {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return script, true, nil
+}
+
+// DeleteStoredScript delete a stored script
+func DeleteStoredScript(id string) error {
+	return metadata.StoredScript.Delete(id)
+}
+
+// RenderSearchTemplate renders the inline source or the stored script of search template request,
+// it returns the rendered query DSL.
+func RenderSearchTemplate(req *meta.SearchTemplateRequest) ([]byte, error) {
+	var source string
+	switch {
+	case req.ID != "" && req.Source != nil:
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "search template can't specify both [id] and [source]")
+	case req.ID != "":
+		script, exists, err := LoadStoredScript(req.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("unable to find script [%s]", req.ID))
+		}
+		source = script.Source
+	case req.Source != nil:
+		var err error
+		if source, err = ScriptSource(req.Source); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "search template requires [id] or [source]")
+	}
+
+	params := req.Params
+	if params == nil {
+		params = make(map[string]interface{})
+	}
+	rendered, err := mustache.Render(source, params)
+	if err != nil {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "failed to render search template").Cause(err)
+	}
+	return []byte(rendered), nil
+}
+
+// ScriptSource returns the template source, the object source is converted to json
+func ScriptSource(source interface{}) (string, error) {
+	switch v := source.(type) {
+	case string:
+		return v, nil
+	case map[string]interface{}:
+		data, err := json.Marshal(v)
+		return string(data), err
+	default:
+		return "", errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("script source should be a string or an object, but got %T", source))
+	}
+}
@@ -24,11 +24,14 @@ import (
 	"github.com/blugelabs/bluge/search/highlight"
 	"github.com/rs/zerolog/log"
 
+	"github.com/zincsearch/zincsearch/pkg/bluge/collector"
 	zincsearch "github.com/zincsearch/zincsearch/pkg/bluge/search"
 	"github.com/zincsearch/zincsearch/pkg/meta"
 	"github.com/zincsearch/zincsearch/pkg/uquery"
 	"github.com/zincsearch/zincsearch/pkg/uquery/fields"
+	"github.com/zincsearch/zincsearch/pkg/uquery/sort"
 	"github.com/zincsearch/zincsearch/pkg/uquery/source"
+	"github.com/zincsearch/zincsearch/pkg/uquery/suggest"
 	"github.com/zincsearch/zincsearch/pkg/uquery/timerange"
 )
 
@@ -39,17 +42,26 @@ func (index *Index) Search(query *meta.ZincQuery) (*meta.SearchResponse, error)
 	if err != nil {
 		return nil, err
 	}
+	suggesters, err := suggest.Request(query, mappings, analyzers)
+	if err != nil {
+		return nil, err
+	}
 
 	timeMin, timeMax := timerange.Query(query.Query)
+	if query.KNN != nil {
+		// the knn hits aren't limited by the time range of query
+		timeMin, timeMax = 0, 0
+	}
 	readers, err := index.GetReaders(timeMin, timeMax)
 	if err != nil {
 		log.Printf("index.SearchV2: error accessing reader: %s", err.Error())
 		return nil, err
 	}
+	defer index.CloseReaders(readers)
+
+	start := time.Now()
 	defer func() {
-		for _, reader := range readers {
-			reader.Close()
-		}
+		index.recordQuery(time.Since(start))
 	}()
 
 	ctx := context.Background()
@@ -59,6 +71,10 @@ func (index *Index) Search(query *meta.ZincQuery) (*meta.SearchResponse, error)
 		defer cancel()
 	}
 
+	if err = searchKNN(ctx, []*Index{index}, readers, query, mappings, analyzers); err != nil {
+		return nil, err
+	}
+
 	// dmi, err := bluge.MultiSearch(ctx, searchRequest, readers...)
 	dmi, err := zincsearch.MultiSearch(ctx, query, mappings, analyzers, readers...)
 	if err != nil {
@@ -73,7 +89,14 @@ func (index *Index) Search(query *meta.ZincQuery) (*meta.SearchResponse, error)
 		return nil, err
 	}
 
-	return searchV2(index.GetAllShardNum(), int64(len(readers)), dmi, query, mappings)
+	resp, err := searchV2(index.GetAllShardNum(), int64(len(readers)), dmi, query, mappings)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Suggest, err = suggest.Response(suggesters, readers); err != nil {
+		return nil, err
+	}
+	return resp, nil
 }
 
 func searchV2(shardNum, readerNum int64, dmi search.DocumentMatchIterator, query *meta.ZincQuery, mappings *meta.Mappings) (*meta.SearchResponse, error) {
@@ -94,67 +117,16 @@ func searchV2(shardNum, readerNum int64, dmi search.DocumentMatchIterator, query
 	Hits := make([]meta.Hit, 0)
 	next, err := dmi.Next()
 	for err == nil && next != nil {
-		var id string
-		var indexName string
-		var timestamp time.Time
-		var sourceData map[string]interface{}
-		var fieldsData map[string]interface{}
-		var highlightData map[string]interface{}
-		if query.Highlight != nil {
-			highlightData = make(map[string]interface{})
-		}
-		err = next.VisitStoredFields(func(field string, value []byte) bool {
-			switch field {
-			case "_id":
-				id = string(value)
-			case "_index":
-				indexName = string(value)
-			case "@timestamp":
-				timestamp, _ = bluge.DecodeDateTime(value)
-			case "_source":
-				sourceData = source.Response(query.Source.(*meta.Source), value)
-				if query.Fields != nil {
-					fieldsData = fields.Response(query.Fields.([]*meta.Field), value, mappings)
-				}
-			default:
-				// highlight
-				if query.Highlight != nil && query.Highlight.Fields != nil {
-					if options, ok := query.Highlight.Fields[field]; ok {
-						if v, ok := next.Locations[field]; ok {
-							if len(options.PreTags) > 0 && len(options.PostTags) > 0 {
-								highlighter := highlight.NewHTMLHighlighterTags(options.PreTags[0], options.PostTags[0])
-								highlightData[field] = highlighter.BestFragments(v, value, options.NumberOfFragments)
-							} else {
-								highlightData[field] = highlighter.BestFragments(v, value, options.NumberOfFragments)
-							}
-						}
-					}
-				}
+		hit, hitErr := searchHit(next, query, mappings, highlighter)
+		if hitErr != nil {
+			log.Printf("core.SearchV2: error accessing stored fields: %s", hitErr.Error())
+		} else {
+			if it, ok := dmi.(collector.InnerHitsIterator); ok {
+				hit.InnerHits = searchInnerHits(it.InnerHits(next), query, mappings, highlighter)
 			}
-
-			return true
-		})
-		if err != nil {
-			log.Printf("core.SearchV2: error accessing stored fields: %s", err.Error())
-			continue
-		}
-
-		if query.Source.(*meta.Source) == nil || !query.Source.(*meta.Source).Enable || len(query.Source.(*meta.Source).Fields) == 0 {
-			sourceData["@timestamp"] = timestamp
+			Hits = append(Hits, hit)
 		}
 
-		hit := meta.Hit{
-			Index:     indexName,
-			Type:      "_doc",
-			ID:        id,
-			Score:     next.Score,
-			Timestamp: timestamp,
-			Source:    sourceData,
-			Fields:    fieldsData,
-			Highlight: highlightData,
-		}
-		Hits = append(Hits, hit)
-
 		next, err = dmi.Next()
 	}
 	if err != nil {
@@ -175,3 +147,103 @@ func searchV2(shardNum, readerNum int64, dmi search.DocumentMatchIterator, query
 
 	return resp, nil
 }
+
+// searchHit builds the hit of the document
+func searchHit(next *search.DocumentMatch, query *meta.ZincQuery, mappings *meta.Mappings, highlighter *highlight.SimpleHighlighter) (meta.Hit, error) {
+	var id string
+	var indexName string
+	var timestamp time.Time
+	var sourceData map[string]interface{}
+	var fieldsData map[string]interface{}
+	var highlightData map[string]interface{}
+	if query.Highlight != nil {
+		highlightData = make(map[string]interface{})
+	}
+	err := next.VisitStoredFields(func(field string, value []byte) bool {
+		switch field {
+		case "_id":
+			id = string(value)
+		case "_index":
+			indexName = string(value)
+		case "@timestamp":
+			timestamp, _ = bluge.DecodeDateTime(value)
+		case "_source":
+			sourceData = source.Response(query.Source.(*meta.Source), value)
+			if query.Fields != nil {
+				fieldsData = fields.Response(query.Fields.([]*meta.Field), value, mappings)
+			}
+		default:
+			// highlight
+			if query.Highlight != nil && query.Highlight.Fields != nil {
+				if options, ok := query.Highlight.Fields[field]; ok {
+					if v, ok := next.Locations[field]; ok {
+						if len(options.PreTags) > 0 && len(options.PostTags) > 0 {
+							highlighter := highlight.NewHTMLHighlighterTags(options.PreTags[0], options.PostTags[0])
+							highlightData[field] = highlighter.BestFragments(v, value, options.NumberOfFragments)
+						} else {
+							highlightData[field] = highlighter.BestFragments(v, value, options.NumberOfFragments)
+						}
+					}
+				}
+			}
+		}
+
+		return true
+	})
+	if err != nil {
+		return meta.Hit{}, err
+	}
+
+	if query.Source.(*meta.Source) == nil || !query.Source.(*meta.Source).Enable || len(query.Source.(*meta.Source).Fields) == 0 {
+		sourceData["@timestamp"] = timestamp
+	}
+
+	hit := meta.Hit{
+		Index:     indexName,
+		Type:      "_doc",
+		ID:        id,
+		Score:     next.Score,
+		Timestamp: timestamp,
+		Source:    sourceData,
+		Fields:    fieldsData,
+		Highlight: highlightData,
+	}
+	if order, ok := query.Sort.(*sort.Order); ok {
+		hit.Sort = order.Values(next)
+	}
+	return hit, nil
+}
+
+// searchInnerHits builds the inner hits of a collapsed hit
+func searchInnerHits(innerHits []*collector.InnerHits, query *meta.ZincQuery, mappings *meta.Mappings, highlighter *highlight.SimpleHighlighter) map[string]meta.InnerHitsResponse {
+	if len(innerHits) == 0 {
+		return nil
+	}
+	resp := make(map[string]meta.InnerHitsResponse, len(innerHits))
+	for _, inner := range innerHits {
+		hits := make([]meta.Hit, 0, len(inner.Hits))
+		var maxScore float64
+		for _, doc := range inner.Hits {
+			if doc.Score > maxScore {
+				maxScore = doc.Score
+			}
+		}
+		for _, doc := range inner.Page() {
+			hit, err := searchHit(doc, query, mappings, highlighter)
+			if err != nil {
+				log.Printf("core.SearchV2: error accessing stored fields of inner hits: %s", err.Error())
+				continue
+			}
+			hit.Sort = nil
+			hits = append(hits, hit)
+		}
+		resp[inner.Name] = meta.InnerHitsResponse{
+			Hits: meta.Hits{
+				Total:    meta.Total{Value: inner.Total},
+				MaxScore: maxScore,
+				Hits:     hits,
+			},
+		}
+	}
+	return resp
+}
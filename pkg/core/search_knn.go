@@ -0,0 +1,190 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"sync"
+
+	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/analysis"
+	"golang.org/x/sync/errgroup"
+
+	zincsearch "github.com/zincsearch/zincsearch/pkg/bluge/search"
+	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/uquery/knn"
+	"github.com/zincsearch/zincsearch/pkg/zutils/hnsw"
+)
+
+// searchKNN executes the knn searches of query on the indexes and sets the hits to the knn request of query,
+// the query is executed on the readers to rank the documents if it is fused by RRF.
+func searchKNN(ctx context.Context, indexes []*Index, readers []*bluge.Reader, query *meta.ZincQuery, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) error {
+	r, ok := query.KNN.(*knn.Request)
+	if !ok {
+		return nil
+	}
+	hits := make([][]knn.Hit, len(r.Searches))
+	for i, s := range r.Searches {
+		var all []knn.Hit
+		for _, index := range indexes {
+			indexHits, err := index.SearchKNN(ctx, s)
+			if err != nil {
+				return err
+			}
+			all = append(all, indexHits...)
+		}
+		all = knn.TopHits(all, s.K)
+		for j := range all {
+			all[j].Score *= s.Boost
+		}
+		hits[i] = all
+	}
+
+	var queryHits []knn.Hit
+	if r.RRF != nil && query.Query != nil {
+		var err error
+		if queryHits, err = rankQuery(ctx, readers, query, mappings, analyzers, r.RRF.WindowSize); err != nil {
+			return err
+		}
+	}
+	r.SetHits(hits, queryHits)
+	return nil
+}
+
+// rankQuery returns the top hits of the query without knn
+func rankQuery(ctx context.Context, readers []*bluge.Reader, query *meta.ZincQuery, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer, size int) ([]knn.Hit, error) {
+	dmi, err := zincsearch.MultiSearch(ctx, &meta.ZincQuery{Query: query.Query, Size: size}, mappings, analyzers, readers...)
+	if err != nil {
+		return nil, err
+	}
+	var hits []knn.Hit
+	next, err := dmi.Next()
+	for err == nil && next != nil {
+		hit := knn.Hit{Score: next.Score}
+		err = next.VisitStoredFields(func(field string, value []byte) bool {
+			if field == "_id" {
+				hit.ID = string(value)
+				return false
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		hits = append(hits, hit)
+		next, err = dmi.Next()
+	}
+	return hits, err
+}
+
+// SearchKNN returns the k nearest neighbors of the index, each second layer shard returns k neighbors
+// found from num_candidates candidates, the documents not matched by filter are skipped.
+func (index *Index) SearchKNN(ctx context.Context, s *knn.Search) ([]knn.Hit, error) {
+	var hits []knn.Hit
+	var lock sync.Mutex
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(config.Global.Shard.GoroutineNum)
+	for _, shard := range index.shards {
+		shard := shard
+		for id := shard.GetLatestShardID(); id >= 0; id-- {
+			id := id
+			eg.Go(func() error {
+				shardHits, err := shard.searchKNN(ctx, id, s)
+				if err != nil {
+					return err
+				}
+				lock.Lock()
+				hits = append(hits, shardHits...)
+				lock.Unlock()
+				return nil
+			})
+		}
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+func (s *IndexShard) searchKNN(ctx context.Context, shardID int64, search *knn.Search) ([]knn.Hit, error) {
+	g, err := s.VectorGraph(shardID, search.Field)
+	if err != nil || g == nil {
+		return nil, err
+	}
+	var results []hnsw.Result
+	if search.Filter == nil {
+		results = g.Search(search.Vector, search.K, search.NumCandidates, nil)
+	} else {
+		ids, err := s.filterIDs(ctx, shardID, search.Filter)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) <= search.NumCandidates {
+			// the exact search is faster for the few documents
+			results = g.Exact(search.Vector, search.K, ids)
+		} else {
+			accepted := make(map[string]struct{}, len(ids))
+			for _, id := range ids {
+				accepted[id] = struct{}{}
+			}
+			results = g.Search(search.Vector, search.K, search.NumCandidates, func(id string) bool {
+				_, ok := accepted[id]
+				return ok
+			})
+		}
+	}
+	hits := make([]knn.Hit, 0, len(results))
+	for _, r := range results {
+		if search.Accept(r.Score) {
+			hits = append(hits, knn.Hit{ID: r.ID, Score: r.Score})
+		}
+	}
+	return hits, nil
+}
+
+// filterIDs returns the ids of documents matched by the filter in the second layer shard
+func (s *IndexShard) filterIDs(ctx context.Context, shardID int64, filter bluge.Query) ([]string, error) {
+	w, err := s.GetWriter(shardID)
+	if err != nil {
+		return nil, err
+	}
+	r, err := w.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	dmi, err := r.Search(ctx, bluge.NewAllMatches(filter))
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	next, err := dmi.Next()
+	for err == nil && next != nil {
+		err = next.VisitStoredFields(func(field string, value []byte) bool {
+			if field == "_id" {
+				ids = append(ids, string(value))
+				return false
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		next, err = dmi.Next()
+	}
+	return ids, err
+}
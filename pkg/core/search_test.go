@@ -22,8 +22,11 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/uquery/mappings"
+	"github.com/zincsearch/zincsearch/pkg/uquery/query"
 )
 
 func TestIndex_Search(t *testing.T) {
@@ -265,8 +268,7 @@ func TestIndex_Search(t *testing.T) {
 			assert.NoError(t, err)
 		}
 
-		// wait for WAL write to index
-		time.Sleep(time.Second)
+		waitWAL(t, index)
 	})
 
 	for _, tt := range tests {
@@ -286,3 +288,1576 @@ func TestIndex_Search(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestIndex_SearchMatchOptions(t *testing.T) {
+	prepareData := []map[string]interface{}{
+		{"title": "the quick brown fox", "count": 1},
+		{"title": "the lazy dog", "count": 2},
+		{"title": "the quick dog", "count": 3},
+		{"title": "a brown cat", "count": 4},
+	}
+	should := []interface{}{
+		map[string]interface{}{"term": map[string]interface{}{"title": "quick"}},
+		map[string]interface{}{"term": map[string]interface{}{"title": "brown"}},
+		map[string]interface{}{"term": map[string]interface{}{"title": "dog"}},
+	}
+
+	tests := []struct {
+		name    string
+		query   *meta.Query
+		wantNum int
+		wantErr bool
+	}{
+		{
+			name:    "bool minimum_should_match integer",
+			query:   &meta.Query{Bool: &meta.BoolQuery{Should: should, MinimumShouldMatch: 2}},
+			wantNum: 2,
+		},
+		{
+			name:    "bool minimum_should_match negative",
+			query:   &meta.Query{Bool: &meta.BoolQuery{Should: should, MinimumShouldMatch: "-1"}},
+			wantNum: 2,
+		},
+		{
+			name:    "bool minimum_should_match percentage",
+			query:   &meta.Query{Bool: &meta.BoolQuery{Should: should, MinimumShouldMatch: "100%"}},
+			wantNum: 0,
+		},
+		{
+			name:    "bool minimum_should_match combination",
+			query:   &meta.Query{Bool: &meta.BoolQuery{Should: should, MinimumShouldMatch: "2<67%"}},
+			wantNum: 2,
+		},
+		{
+			name:    "bool minimum_should_match invalid",
+			query:   &meta.Query{Bool: &meta.BoolQuery{Should: should, MinimumShouldMatch: "many"}},
+			wantErr: true,
+		},
+		{
+			name:    "match minimum_should_match",
+			query:   &meta.Query{Match: map[string]*meta.MatchQuery{"title": {Query: "quick brown dog", MinimumShouldMatch: "67%"}}},
+			wantNum: 2,
+		},
+		{
+			name:    "match operator and",
+			query:   &meta.Query{Match: map[string]*meta.MatchQuery{"title": {Query: "quick dog", Operator: "and"}}},
+			wantNum: 1,
+		},
+		{
+			name:    "match zero_terms_query none",
+			query:   &meta.Query{Match: map[string]*meta.MatchQuery{"title": {Query: "the a", Analyzer: "stop"}}},
+			wantNum: 0,
+		},
+		{
+			name:    "match zero_terms_query all",
+			query:   &meta.Query{Match: map[string]*meta.MatchQuery{"title": {Query: "the a", Analyzer: "stop", ZeroTermsQuery: "all"}}},
+			wantNum: 4,
+		},
+		{
+			name:    "match zero_terms_query invalid",
+			query:   &meta.Query{Match: map[string]*meta.MatchQuery{"title": {Query: "the", ZeroTermsQuery: "some"}}},
+			wantErr: true,
+		},
+		{
+			name:    "match phrase zero_terms_query all",
+			query:   &meta.Query{MatchPhrase: map[string]*meta.MatchPhraseQuery{"title": {Query: "the a", Analyzer: "stop", ZeroTermsQuery: "all"}}},
+			wantNum: 4,
+		},
+		{
+			name:    "match without cutoff_frequency",
+			query:   &meta.Query{Match: map[string]*meta.MatchQuery{"title": {Query: "the quick"}}},
+			wantNum: 3,
+		},
+		{
+			name:    "match cutoff_frequency",
+			query:   &meta.Query{Match: map[string]*meta.MatchQuery{"title": {Query: "the quick", CutoffFrequency: 0.5}}},
+			wantNum: 2,
+		},
+		{
+			name:    "match cutoff_frequency only high frequency terms",
+			query:   &meta.Query{Match: map[string]*meta.MatchQuery{"title": {Query: "the", CutoffFrequency: 2}}},
+			wantNum: 3,
+		},
+		{
+			name:    "match numeric field",
+			query:   &meta.Query{Match: map[string]*meta.MatchQuery{"count": {Query: "3"}}},
+			wantNum: 1,
+		},
+		{
+			name:    "match numeric field format error",
+			query:   &meta.Query{Match: map[string]*meta.MatchQuery{"count": {Query: "three"}}},
+			wantErr: true,
+		},
+		{
+			name:    "match numeric field lenient",
+			query:   &meta.Query{Match: map[string]*meta.MatchQuery{"count": {Query: "three", Lenient: true}}},
+			wantNum: 0,
+		},
+		{
+			name:    "multi_match minimum_should_match",
+			query:   &meta.Query{MultiMatch: &meta.MultiMatchQuery{Query: "quick brown dog", Fields: []string{"title"}, MinimumShouldMatch: "2"}},
+			wantNum: 2,
+		},
+		{
+			name:    "multi_match lenient",
+			query:   &meta.Query{MultiMatch: &meta.MultiMatchQuery{Query: "dog", Fields: []string{"title", "count"}, Lenient: true}},
+			wantNum: 2,
+		},
+		{
+			name:    "query_string minimum_should_match",
+			query:   &meta.Query{QueryString: &meta.QueryStringQuery{Query: "quick brown dog", Fields: []string{"title"}, MinimumShouldMatch: "2"}},
+			wantNum: 2,
+		},
+	}
+
+	var err error
+	var index *Index
+	indexName := "Search.v2.index_match_options"
+	t.Run("Prepare", func(t *testing.T) {
+		index, err = NewIndex(indexName, "disk", 1)
+		assert.NoError(t, err)
+		assert.NotNil(t, index)
+		err = StoreIndex(index)
+		assert.NoError(t, err)
+
+		for i, d := range prepareData {
+			err := index.CreateDocument(strconv.Itoa(i+1), d, false)
+			assert.NoError(t, err)
+		}
+
+		waitWAL(t, index)
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := index.Search(&meta.ZincQuery{Query: tt.query, Size: 10})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantNum, got.Hits.Total.Value)
+		})
+	}
+
+	t.Run("Cleanup", func(t *testing.T) {
+		err = DeleteIndex(indexName)
+		assert.NoError(t, err)
+	})
+}
+
+func TestIndex_SearchMultiMatch(t *testing.T) {
+	prepareData := []map[string]interface{}{
+		{"first_name": "will", "last_name": "smith", "title": "actor"},
+		{"first_name": "smith", "last_name": "jones", "title": "director"},
+		{"first_name": "john", "last_name": "doe", "title": "will smith fan"},
+		{"first_name": "will", "last_name": "jones", "title": "smith and will"},
+	}
+	multiMatch := func(q *meta.MultiMatchQuery) *meta.Query {
+		return &meta.Query{MultiMatch: q}
+	}
+
+	tests := []struct {
+		name    string
+		query   *meta.Query
+		wantNum int
+		wantTop string
+		wantErr bool
+	}{
+		{
+			name:    "best_fields",
+			query:   multiMatch(&meta.MultiMatchQuery{Query: "will smith", Fields: []string{"first_name", "last_name"}}),
+			wantNum: 3,
+		},
+		{
+			name:    "best_fields operator and",
+			query:   multiMatch(&meta.MultiMatchQuery{Query: "will smith", Fields: []string{"*_name", "title"}, Operator: "and"}),
+			wantNum: 2,
+		},
+		{
+			name:    "best_fields field boost",
+			query:   multiMatch(&meta.MultiMatchQuery{Query: "smith", Fields: []string{"first_name^10", "last_name"}}),
+			wantNum: 2,
+			wantTop: "2",
+		},
+		{
+			name:    "field pattern",
+			query:   multiMatch(&meta.MultiMatchQuery{Query: "doe", Fields: []string{"*_name"}}),
+			wantNum: 1,
+			wantTop: "3",
+		},
+		{
+			name:    "most_fields",
+			query:   multiMatch(&meta.MultiMatchQuery{Query: "will", Type: "most_fields", Fields: []string{"first_name", "title^10"}}),
+			wantNum: 3,
+			wantTop: "4",
+		},
+		{
+			name:    "cross_fields",
+			query:   multiMatch(&meta.MultiMatchQuery{Query: "will smith", Type: "cross_fields", Fields: []string{"first_name", "last_name"}, Operator: "and"}),
+			wantNum: 1,
+			wantTop: "1",
+		},
+		{
+			name:    "phrase",
+			query:   multiMatch(&meta.MultiMatchQuery{Query: "will smith", Type: "phrase", Fields: []string{"first_name", "title"}}),
+			wantNum: 1,
+			wantTop: "3",
+		},
+		{
+			name:    "phrase with slop",
+			query:   multiMatch(&meta.MultiMatchQuery{Query: "smith will", Type: "phrase", Fields: []string{"title"}, Slop: 1}),
+			wantNum: 1,
+			wantTop: "4",
+		},
+		{
+			name:    "phrase_prefix",
+			query:   multiMatch(&meta.MultiMatchQuery{Query: "will sm", Type: "phrase_prefix", Fields: []string{"title"}}),
+			wantNum: 1,
+			wantTop: "3",
+		},
+		{
+			name:    "bool_prefix",
+			query:   multiMatch(&meta.MultiMatchQuery{Query: "jo", Type: "bool_prefix", Fields: []string{"*_name"}}),
+			wantNum: 3,
+		},
+		{
+			name:    "unknown type",
+			query:   multiMatch(&meta.MultiMatchQuery{Query: "will", Type: "some_fields", Fields: []string{"title"}}),
+			wantErr: true,
+		},
+		{
+			name:    "invalid field boost",
+			query:   multiMatch(&meta.MultiMatchQuery{Query: "will", Fields: []string{"title^x"}}),
+			wantErr: true,
+		},
+	}
+
+	var err error
+	var index *Index
+	indexName := "Search.v2.index_multi_match"
+	t.Run("Prepare", func(t *testing.T) {
+		index, err = NewIndex(indexName, "disk", 1)
+		assert.NoError(t, err)
+		assert.NotNil(t, index)
+		err = StoreIndex(index)
+		assert.NoError(t, err)
+
+		for i, d := range prepareData {
+			err := index.CreateDocument(strconv.Itoa(i+1), d, false)
+			assert.NoError(t, err)
+		}
+
+		waitWAL(t, index)
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := index.Search(&meta.ZincQuery{Query: tt.query, Size: 10})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantNum, got.Hits.Total.Value)
+			if tt.wantTop != "" && len(got.Hits.Hits) > 0 {
+				assert.Equal(t, tt.wantTop, got.Hits.Hits[0].ID)
+			}
+		})
+	}
+
+	t.Run("Cleanup", func(t *testing.T) {
+		err = DeleteIndex(indexName)
+		assert.NoError(t, err)
+	})
+}
+
+func TestIndex_SearchMultiMatchCrossFields(t *testing.T) {
+	// smith is rare in first_name but frequent in last_name, will is frequent in first_name
+	names := [][2]string{{"will", "smith"}, {"smith", "lee"}}
+	for i := 0; i < 92; i++ {
+		names = append(names, [2]string{"will", "smith"})
+	}
+	for i := 0; i < 6; i++ {
+		names = append(names, [2]string{"anna", "brown"})
+	}
+
+	var err error
+	var index *Index
+	indexName := "Search.v2.index_multi_match_cross_fields"
+	t.Run("Prepare", func(t *testing.T) {
+		index, err = NewIndex(indexName, "disk", 1)
+		require.NoError(t, err)
+		require.NoError(t, StoreIndex(index))
+		for i, name := range names {
+			err := index.CreateDocument(strconv.Itoa(i+1), map[string]interface{}{"first_name": name[0], "last_name": name[1]}, false)
+			assert.NoError(t, err)
+		}
+		waitWAL(t, index)
+	})
+
+	scores := func(t *testing.T, typ string) map[string]float64 {
+		got, err := index.Search(&meta.ZincQuery{
+			Query: &meta.Query{MultiMatch: &meta.MultiMatchQuery{Query: "will smith", Type: typ, Fields: []string{"first_name", "last_name"}}},
+			Size:  len(names),
+		})
+		require.NoError(t, err)
+		scores := make(map[string]float64, len(got.Hits.Hits))
+		for _, hit := range got.Hits.Hits {
+			scores[hit.ID] = hit.Score
+		}
+		require.Contains(t, scores, "1")
+		require.Contains(t, scores, "2")
+		return scores
+	}
+
+	t.Run("best_fields scores the rare term of one field", func(t *testing.T) {
+		got := scores(t, "best_fields")
+		assert.Greater(t, got["2"], got["1"])
+	})
+
+	t.Run("cross_fields blends the document frequency of fields", func(t *testing.T) {
+		got := scores(t, "cross_fields")
+		assert.Greater(t, got["1"], got["2"])
+	})
+
+	t.Run("Cleanup", func(t *testing.T) {
+		err = DeleteIndex(indexName)
+		assert.NoError(t, err)
+	})
+}
+
+func TestIndex_SearchFunctionScore(t *testing.T) {
+	prepareData := []map[string]interface{}{
+		{"title": "zinc search", "likes": 10, "published": "2022-06-01 00:00:00"},
+		{"title": "zinc search engine", "likes": 100, "published": "2022-01-01 00:00:00"},
+		{"title": "search engine", "likes": 1, "published": "2022-06-10 00:00:00"},
+		{"title": "zinc", "published": "2021-01-01 00:00:00"},
+	}
+	minScore := func(v float64) *float64 {
+		return &v
+	}
+	matchSearch := map[string]interface{}{"match": map[string]interface{}{"title": "search"}}
+
+	tests := []struct {
+		name    string
+		query   *meta.Query
+		wantNum int
+		wantTop string
+		wantErr bool
+	}{
+		{
+			name: "field_value_factor",
+			query: &meta.Query{FunctionScore: &meta.FunctionScoreQuery{
+				Query:     matchSearch,
+				Functions: []*meta.ScoreFunction{{FieldValueFactor: &meta.FieldValueFactorFunction{Field: "likes", Modifier: "log1p"}}},
+				BoostMode: "replace",
+			}},
+			wantNum: 3,
+			wantTop: "2",
+		},
+		{
+			name: "field_value_factor missing value",
+			query: &meta.Query{FunctionScore: &meta.FunctionScoreQuery{
+				Functions: []*meta.ScoreFunction{{FieldValueFactor: &meta.FieldValueFactorFunction{Field: "likes"}}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "field_value_factor missing option",
+			query: &meta.Query{FunctionScore: &meta.FunctionScoreQuery{
+				Functions: []*meta.ScoreFunction{{FieldValueFactor: &meta.FieldValueFactorFunction{Field: "likes", Missing: minScore(1000)}}},
+				BoostMode: "replace",
+			}},
+			wantNum: 4,
+			wantTop: "4",
+		},
+		{
+			name: "weight with filter and min_score",
+			query: &meta.Query{FunctionScore: &meta.FunctionScoreQuery{
+				Functions: []*meta.ScoreFunction{
+					{Filter: map[string]interface{}{"match": map[string]interface{}{"title": "engine"}}, Weight: 10},
+					{Filter: map[string]interface{}{"match": map[string]interface{}{"title": "zinc"}}, Weight: 2},
+				},
+				ScoreMode: "sum",
+				BoostMode: "replace",
+				MinScore:  minScore(5),
+			}},
+			wantNum: 2,
+			wantTop: "2",
+		},
+		{
+			name: "max_boost",
+			query: &meta.Query{FunctionScore: &meta.FunctionScoreQuery{
+				Functions: []*meta.ScoreFunction{{Weight: 10}},
+				BoostMode: "replace",
+				MaxBoost:  2,
+				MinScore:  minScore(3),
+			}},
+			wantNum: 0,
+		},
+		{
+			name: "gauss date",
+			query: &meta.Query{FunctionScore: &meta.FunctionScoreQuery{
+				Functions: []*meta.ScoreFunction{{Gauss: map[string]interface{}{
+					"published": map[string]interface{}{"origin": "2022-06-10 00:00:00", "scale": "10d"},
+				}}},
+				BoostMode: "replace",
+			}},
+			wantNum: 4,
+			wantTop: "3",
+		},
+		{
+			name: "linear numeric",
+			query: &meta.Query{FunctionScore: &meta.FunctionScoreQuery{
+				Query: matchSearch,
+				Functions: []*meta.ScoreFunction{{Linear: map[string]interface{}{
+					"likes": map[string]interface{}{"origin": 12, "scale": 5, "offset": 1},
+				}}},
+				BoostMode: "replace",
+			}},
+			wantNum: 3,
+			wantTop: "1",
+		},
+		{
+			name: "random_score",
+			query: &meta.Query{FunctionScore: &meta.FunctionScoreQuery{
+				Functions: []*meta.ScoreFunction{{RandomScore: &meta.RandomScoreFunction{Seed: 42, Field: "_id"}}},
+			}},
+			wantNum: 4,
+		},
+		{
+			name: "illegal score_mode",
+			query: &meta.Query{FunctionScore: &meta.FunctionScoreQuery{
+				Functions: []*meta.ScoreFunction{{Weight: 2}},
+				ScoreMode: "median",
+			}},
+			wantErr: true,
+		},
+		{
+			name: "script_score",
+			query: &meta.Query{ScriptScore: &meta.ScriptScoreQuery{
+				Query:  matchSearch,
+				Script: &meta.Script{Source: "_score + doc['likes'].value * params.factor", Params: map[string]interface{}{"factor": 2}},
+			}},
+			wantNum: 3,
+			wantTop: "2",
+		},
+		{
+			name: "script_score min_score",
+			query: &meta.Query{ScriptScore: &meta.ScriptScoreQuery{
+				Query:    matchSearch,
+				Script:   &meta.Script{Source: "doc['likes'].value"},
+				MinScore: minScore(5),
+			}},
+			wantNum: 2,
+			wantTop: "2",
+		},
+		{
+			name: "script_score negative score",
+			query: &meta.Query{ScriptScore: &meta.ScriptScoreQuery{
+				Query:  matchSearch,
+				Script: &meta.Script{Source: "-1"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "script_score unknown field",
+			query: &meta.Query{ScriptScore: &meta.ScriptScoreQuery{
+				Query:  matchSearch,
+				Script: &meta.Script{Source: "doc['views'].value"},
+			}},
+			wantErr: true,
+		},
+	}
+
+	var err error
+	var index *Index
+	indexName := "Search.v2.index_function_score"
+	t.Run("Prepare", func(t *testing.T) {
+		index, err = NewIndex(indexName, "disk", 1)
+		assert.NoError(t, err)
+		assert.NotNil(t, index)
+		err = StoreIndex(index)
+		assert.NoError(t, err)
+
+		for i, d := range prepareData {
+			err := index.CreateDocument(strconv.Itoa(i+1), d, false)
+			assert.NoError(t, err)
+		}
+
+		waitWAL(t, index)
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := index.Search(&meta.ZincQuery{Query: tt.query, Size: 10})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantNum, got.Hits.Total.Value)
+			if tt.wantTop != "" && len(got.Hits.Hits) > 0 {
+				assert.Equal(t, tt.wantTop, got.Hits.Hits[0].ID)
+			}
+		})
+	}
+
+	t.Run("random_score is stable", func(t *testing.T) {
+		query := &meta.ZincQuery{
+			Query: &meta.Query{FunctionScore: &meta.FunctionScoreQuery{
+				Functions: []*meta.ScoreFunction{{RandomScore: &meta.RandomScoreFunction{Seed: "zinc", Field: "_id"}}},
+			}},
+			Size: 10,
+		}
+		first, err := index.Search(query)
+		assert.NoError(t, err)
+		second, err := index.Search(query)
+		assert.NoError(t, err)
+		require.Equal(t, len(first.Hits.Hits), len(second.Hits.Hits))
+		for i := range first.Hits.Hits {
+			assert.Equal(t, first.Hits.Hits[i].ID, second.Hits.Hits[i].ID)
+			assert.Equal(t, first.Hits.Hits[i].Score, second.Hits.Hits[i].Score)
+		}
+	})
+
+	t.Run("Cleanup", func(t *testing.T) {
+		err = DeleteIndex(indexName)
+		assert.NoError(t, err)
+	})
+}
+
+func TestIndex_SearchSort(t *testing.T) {
+	prepareData := []map[string]interface{}{
+		{"name": "a", "price": 10, "tags": []interface{}{3, 9}, "date": "2022-01-01 00:00:00"},
+		{"name": "b", "price": 5, "tags": []interface{}{5}, "date": "2022-03-01 00:00:00"},
+		{"name": "c", "tags": []interface{}{1, 4, 7}},
+		{"name": "d", "price": 20, "tags": []interface{}{6, 8}},
+	}
+
+	tests := []struct {
+		name     string
+		sort     interface{}
+		wantIDs  []string
+		wantSort []interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "field",
+			sort:     []interface{}{"price"},
+			wantIDs:  []string{"2", "1", "4", "3"},
+			wantSort: []interface{}{5.0},
+		},
+		{
+			name:    "field desc",
+			sort:    "-price",
+			wantIDs: []string{"4", "1", "2", "3"},
+		},
+		{
+			name:    "missing first",
+			sort:    []interface{}{map[string]interface{}{"price": map[string]interface{}{"order": "asc", "missing": "_first"}}},
+			wantIDs: []string{"3", "2", "1", "4"},
+		},
+		{
+			name:    "missing value",
+			sort:    []interface{}{map[string]interface{}{"price": map[string]interface{}{"missing": 7}}},
+			wantIDs: []string{"2", "3", "1", "4"},
+		},
+		{
+			name:    "mode min by default in asc",
+			sort:    []interface{}{"tags"},
+			wantIDs: []string{"3", "1", "2", "4"},
+		},
+		{
+			name:    "mode max by default in desc",
+			sort:    []interface{}{map[string]interface{}{"tags": "desc"}},
+			wantIDs: []string{"1", "4", "3", "2"},
+		},
+		{
+			name:     "mode avg",
+			sort:     []interface{}{map[string]interface{}{"tags": map[string]interface{}{"mode": "avg"}}},
+			wantIDs:  []string{"3", "2", "1", "4"},
+			wantSort: []interface{}{4.0},
+		},
+		{
+			name:    "mode median",
+			sort:    []interface{}{map[string]interface{}{"tags": map[string]interface{}{"mode": "median", "order": "desc"}}},
+			wantIDs: []string{"4", "1", "2", "3"},
+		},
+		{
+			name:     "numeric_type long",
+			sort:     []interface{}{map[string]interface{}{"tags": map[string]interface{}{"mode": "avg", "numeric_type": "long"}}},
+			wantIDs:  []string{"3", "2", "1", "4"},
+			wantSort: []interface{}{int64(4)},
+		},
+		{
+			name:     "date with format",
+			sort:     []interface{}{map[string]interface{}{"date": map[string]interface{}{"order": "desc", "format": "2006-01-02"}}, "name.keyword"},
+			wantIDs:  []string{"2", "1", "3", "4"},
+			wantSort: []interface{}{"2022-03-01", "b"},
+		},
+		{
+			name:     "multiple fields",
+			sort:     []interface{}{"-date", "name.keyword"},
+			wantIDs:  []string{"2", "1", "3", "4"},
+			wantSort: []interface{}{int64(1646092800000), "b"},
+		},
+		{
+			name:    "_score asc",
+			sort:    []interface{}{map[string]interface{}{"_score": "asc"}, "name.keyword"},
+			wantIDs: []string{"1", "2", "3", "4"},
+		},
+		{
+			name:    "unmapped field",
+			sort:    []interface{}{"rating"},
+			wantErr: true,
+		},
+		{
+			name:     "unmapped_type",
+			sort:     []interface{}{map[string]interface{}{"rating": map[string]interface{}{"unmapped_type": "long"}}, "name.keyword"},
+			wantIDs:  []string{"1", "2", "3", "4"},
+			wantSort: []interface{}{nil, "a"},
+		},
+		{
+			name:    "invalid order",
+			sort:    []interface{}{map[string]interface{}{"price": "up"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid mode for keyword",
+			sort:    []interface{}{map[string]interface{}{"name.keyword": map[string]interface{}{"mode": "avg"}}},
+			wantErr: true,
+		},
+	}
+
+	var err error
+	var index *Index
+	indexName := "Search.v2.index_sort"
+	t.Run("Prepare", func(t *testing.T) {
+		index, err = NewIndex(indexName, "disk", 1)
+		assert.NoError(t, err)
+		assert.NotNil(t, index)
+		err = StoreIndex(index)
+		assert.NoError(t, err)
+
+		mappings := meta.NewMappings()
+		name := meta.NewProperty("text")
+		name.AddField("keyword", meta.NewProperty("keyword"))
+		mappings.SetProperty("name", name)
+		mappings.SetProperty("name.keyword", meta.NewProperty("keyword"))
+		err = index.SetMappings(mappings)
+		require.NoError(t, err)
+
+		for i, d := range prepareData {
+			err := index.CreateDocument(strconv.Itoa(i+1), d, false)
+			assert.NoError(t, err)
+		}
+
+		waitWAL(t, index)
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := &meta.ZincQuery{Query: &meta.Query{MatchAll: &meta.MatchAllQuery{}}, Sort: tt.sort, Size: 10}
+			got, err := index.Search(query)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			ids := make([]string, 0, len(got.Hits.Hits))
+			for _, hit := range got.Hits.Hits {
+				ids = append(ids, hit.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+			if tt.wantSort != nil && len(got.Hits.Hits) > 0 {
+				assert.Equal(t, tt.wantSort, got.Hits.Hits[0].Sort)
+			}
+		})
+	}
+
+	t.Run("_doc", func(t *testing.T) {
+		var ids [2][]string
+		for i, sort := range []string{"_doc", "-_doc"} {
+			got, err := index.Search(&meta.ZincQuery{Sort: []interface{}{sort}, Size: 10})
+			require.NoError(t, err)
+			for _, hit := range got.Hits.Hits {
+				ids[i] = append(ids[i], hit.ID)
+			}
+		}
+		assert.Len(t, ids[0], 4)
+		for i := range ids[0] {
+			assert.Equal(t, ids[0][i], ids[1][len(ids[1])-1-i])
+		}
+	})
+
+	t.Run("Cleanup", func(t *testing.T) {
+		err = DeleteIndex(indexName)
+		assert.NoError(t, err)
+	})
+}
+
+func TestIndex_SearchRescoreCollapse(t *testing.T) {
+	prepareData := []map[string]interface{}{
+		{"brand": "x", "title": "red shoe", "price": 10},
+		{"brand": "x", "title": "blue shoe", "price": 30},
+		{"brand": "y", "title": "red hat", "price": 20},
+		{"brand": "y", "title": "green shoe", "price": 5},
+		{"title": "red sock", "price": 1},
+	}
+
+	var err error
+	var index *Index
+	indexName := "Search.v2.index_rescore_collapse"
+	t.Run("Prepare", func(t *testing.T) {
+		index, err = NewIndex(indexName, "disk", 2)
+		assert.NoError(t, err)
+		assert.NotNil(t, index)
+		err = StoreIndex(index)
+		assert.NoError(t, err)
+
+		mappings := meta.NewMappings()
+		brand := meta.NewProperty("text")
+		brand.AddField("keyword", meta.NewProperty("keyword"))
+		mappings.SetProperty("brand", brand)
+		mappings.SetProperty("brand.keyword", meta.NewProperty("keyword"))
+		err = index.SetMappings(mappings)
+		require.NoError(t, err)
+
+		for i, d := range prepareData {
+			err := index.CreateDocument(strconv.Itoa(i+1), d, false)
+			assert.NoError(t, err)
+		}
+
+		waitWAL(t, index)
+	})
+
+	t.Run("rescore", func(t *testing.T) {
+		got, err := index.Search(&meta.ZincQuery{
+			Query: &meta.Query{MatchAll: &meta.MatchAllQuery{}},
+			Rescore: map[string]interface{}{
+				"window_size": 10,
+				"query": map[string]interface{}{
+					"rescore_query":        map[string]interface{}{"match": map[string]interface{}{"title": "red"}},
+					"query_weight":         0.0,
+					"rescore_query_weight": 1.0,
+				},
+			},
+			Size: 10,
+		})
+		require.NoError(t, err)
+		require.Len(t, got.Hits.Hits, 5)
+		ids := make([]string, 0, 3)
+		for _, hit := range got.Hits.Hits[:3] {
+			ids = append(ids, hit.ID)
+			assert.Greater(t, hit.Score, 0.0)
+		}
+		assert.ElementsMatch(t, []string{"1", "3", "5"}, ids)
+		for _, hit := range got.Hits.Hits[3:] {
+			assert.Equal(t, 0.0, hit.Score)
+		}
+	})
+
+	t.Run("collapse", func(t *testing.T) {
+		got, err := index.Search(&meta.ZincQuery{
+			Query: &meta.Query{MatchAll: &meta.MatchAllQuery{}},
+			Sort:  []interface{}{"-price"},
+			Collapse: &meta.Collapse{
+				Field: "brand.keyword",
+				InnerHits: map[string]interface{}{
+					"name": "cheapest",
+					"size": 1,
+					"sort": []interface{}{"price"},
+				},
+			},
+			Size: 10,
+		})
+		require.NoError(t, err)
+		ids := make([]string, 0, len(got.Hits.Hits))
+		inner := make(map[string][]string)
+		totals := make(map[string]int)
+		for _, hit := range got.Hits.Hits {
+			ids = append(ids, hit.ID)
+			for _, innerHit := range hit.InnerHits["cheapest"].Hits.Hits {
+				inner[hit.ID] = append(inner[hit.ID], innerHit.ID)
+			}
+			totals[hit.ID] = hit.InnerHits["cheapest"].Hits.Total.Value
+		}
+		assert.Equal(t, []string{"2", "3", "5"}, ids)
+		assert.Equal(t, map[string][]string{"2": {"1"}, "3": {"4"}, "5": {"5"}}, inner)
+		assert.Equal(t, map[string]int{"2": 2, "3": 2, "5": 1}, totals)
+	})
+
+	t.Run("collapse paging", func(t *testing.T) {
+		got, err := index.Search(&meta.ZincQuery{
+			Query:    &meta.Query{MatchAll: &meta.MatchAllQuery{}},
+			Sort:     []interface{}{"-price"},
+			Collapse: &meta.Collapse{Field: "brand.keyword"},
+			From:     1,
+			Size:     1,
+		})
+		require.NoError(t, err)
+		require.Len(t, got.Hits.Hits, 1)
+		assert.Equal(t, "3", got.Hits.Hits[0].ID)
+	})
+
+	errTests := []struct {
+		name  string
+		query *meta.ZincQuery
+	}{
+		{
+			name: "rescore with sort",
+			query: &meta.ZincQuery{
+				Sort:    []interface{}{"price"},
+				Rescore: map[string]interface{}{"query": map[string]interface{}{"rescore_query": map[string]interface{}{"match_all": map[string]interface{}{}}}},
+			},
+		},
+		{
+			name:  "rescore without rescore_query",
+			query: &meta.ZincQuery{Rescore: map[string]interface{}{"window_size": 5}},
+		},
+		{
+			name:  "collapse on text field",
+			query: &meta.ZincQuery{Collapse: &meta.Collapse{Field: "title"}},
+		},
+		{
+			name:  "collapse on unmapped field",
+			query: &meta.ZincQuery{Collapse: &meta.Collapse{Field: "color"}},
+		},
+		{
+			name: "collapse with rescore",
+			query: &meta.ZincQuery{
+				Collapse: &meta.Collapse{Field: "brand.keyword"},
+				Rescore:  map[string]interface{}{"query": map[string]interface{}{"rescore_query": map[string]interface{}{"match_all": map[string]interface{}{}}}},
+			},
+		},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.Size = 10
+			_, err := index.Search(tt.query)
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("Cleanup", func(t *testing.T) {
+		err = DeleteIndex(indexName)
+		assert.NoError(t, err)
+	})
+}
+
+func TestIndex_SearchMoreLikeThis(t *testing.T) {
+	prepareData := []map[string]interface{}{
+		{"title": "the quick brown fox jumps", "tag": "animal"},
+		{"title": "a quick brown dog runs", "tag": "animal"},
+		{"title": "brown bread and butter", "tag": "food"},
+		{"title": "quick lunch with bread", "tag": "food"},
+		{"title": "slow green turtle", "tag": "animal"},
+	}
+
+	var err error
+	var index *Index
+	indexName := "Search.v2.index_more_like_this"
+	t.Run("Prepare", func(t *testing.T) {
+		// term statistics are per shard
+		index, err = NewIndex(indexName, "disk", 1)
+		assert.NoError(t, err)
+		assert.NotNil(t, index)
+		err = StoreIndex(index)
+		assert.NoError(t, err)
+
+		mappings := meta.NewMappings()
+		tag := meta.NewProperty("text")
+		tag.AddField("keyword", meta.NewProperty("keyword"))
+		mappings.SetProperty("tag", tag)
+		mappings.SetProperty("tag.keyword", meta.NewProperty("keyword"))
+		err = index.SetMappings(mappings)
+		require.NoError(t, err)
+
+		for i, d := range prepareData {
+			err := index.CreateDocument(strconv.Itoa(i+1), d, false)
+			assert.NoError(t, err)
+		}
+
+		waitWAL(t, index)
+	})
+
+	hitIDs := func(resp *meta.SearchResponse) []string {
+		ids := make([]string, 0, len(resp.Hits.Hits))
+		for _, hit := range resp.Hits.Hits {
+			ids = append(ids, hit.ID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name  string
+		query *meta.MoreLikeThisQuery
+		want  []string
+	}{
+		{
+			name:  "like text",
+			query: &meta.MoreLikeThisQuery{Fields: []string{"title"}, Like: "quick brown", MinTermFreq: 1, MinDocFreq: 1},
+			want:  []string{"1", "2", "3", "4"},
+		},
+		{
+			name: "like text with minimum_should_match",
+			query: &meta.MoreLikeThisQuery{
+				Fields: []string{"title"}, Like: "quick brown", MinTermFreq: 1, MinDocFreq: 1, MinimumShouldMatch: "100%",
+			},
+			want: []string{"1", "2"},
+		},
+		{
+			name: "like document excludes itself",
+			query: &meta.MoreLikeThisQuery{
+				Fields: []string{"title"}, Like: []interface{}{map[string]interface{}{"_index": indexName, "_id": "3"}},
+				MinTermFreq: 1, MinDocFreq: 1, StopWords: []string{"and"},
+			},
+			want: []string{"1", "2", "4"},
+		},
+		{
+			name: "like document include",
+			query: &meta.MoreLikeThisQuery{
+				Fields: []string{"title"}, Like: map[string]interface{}{"_index": indexName, "_id": "5"},
+				MinTermFreq: 1, MinDocFreq: 1, Include: true,
+			},
+			want: []string{"5"},
+		},
+		{
+			name: "like inline document on keyword field",
+			query: &meta.MoreLikeThisQuery{
+				Fields: []string{"tag.keyword"}, Like: map[string]interface{}{"doc": map[string]interface{}{"tag": "food"}},
+				MinTermFreq: 1, MinDocFreq: 1,
+			},
+			want: []string{"3", "4"},
+		},
+		{
+			name: "unlike",
+			query: &meta.MoreLikeThisQuery{
+				Fields: []string{"title"}, Like: "quick brown", Unlike: "brown", MinTermFreq: 1, MinDocFreq: 1,
+			},
+			want: []string{"1", "2", "4"},
+		},
+		{
+			name: "max_doc_freq",
+			query: &meta.MoreLikeThisQuery{
+				Fields: []string{"title"}, Like: "quick brown turtle", MinTermFreq: 1, MinDocFreq: 1, MaxDocFreq: 1,
+			},
+			want: []string{"5"},
+		},
+		{
+			name:  "default min_term_freq",
+			query: &meta.MoreLikeThisQuery{Fields: []string{"title"}, Like: "quick brown", MinDocFreq: 1},
+			want:  []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := index.Search(&meta.ZincQuery{
+				Query: &meta.Query{MoreLikeThis: tt.query},
+				Size:  10,
+			})
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, tt.want, hitIDs(got))
+		})
+	}
+
+	errTests := []struct {
+		name  string
+		query *meta.MoreLikeThisQuery
+	}{
+		{
+			name:  "missing like",
+			query: &meta.MoreLikeThisQuery{Fields: []string{"title"}},
+		},
+		{
+			name:  "unknown index",
+			query: &meta.MoreLikeThisQuery{Like: map[string]interface{}{"_index": "not_exists_index", "_id": "1"}},
+		},
+		{
+			name:  "unknown document",
+			query: &meta.MoreLikeThisQuery{Like: map[string]interface{}{"_index": indexName, "_id": "100"}},
+		},
+		{
+			name:  "invalid like",
+			query: &meta.MoreLikeThisQuery{Like: 1},
+		},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := index.Search(&meta.ZincQuery{
+				Query: &meta.Query{MoreLikeThis: tt.query},
+				Size:  10,
+			})
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("Cleanup", func(t *testing.T) {
+		err = DeleteIndex(indexName)
+		assert.NoError(t, err)
+	})
+}
+
+func TestIndex_SearchPercolate(t *testing.T) {
+	prepareData := []map[string]interface{}{
+		{"name": "error logs", "query": map[string]interface{}{"match": map[string]interface{}{"message": "error"}}},
+		{"name": "payment errors", "query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": []interface{}{
+					map[string]interface{}{"match": map[string]interface{}{"message": "error"}},
+					map[string]interface{}{"term": map[string]interface{}{"service": "payment"}},
+				},
+			},
+		}},
+		{"name": "timeouts", "query": map[string]interface{}{"match_phrase": map[string]interface{}{"message": "connection timeout"}}},
+		{"name": "slow requests", "query": map[string]interface{}{"range": map[string]interface{}{"latency": map[string]interface{}{"gte": 1000}}}},
+		{"name": "not info", "query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must_not": []interface{}{map[string]interface{}{"term": map[string]interface{}{"level": "info"}}},
+			},
+		}},
+	}
+
+	var err error
+	var index *Index
+	indexName := "Search.v2.index_percolate"
+	t.Run("Prepare", func(t *testing.T) {
+		index, err = NewIndex(indexName, "disk", 2)
+		assert.NoError(t, err)
+		assert.NotNil(t, index)
+		m, err := mappings.Request(nil, map[string]interface{}{
+			"properties": map[string]interface{}{
+				"name":    map[string]interface{}{"type": "keyword"},
+				"query":   map[string]interface{}{"type": "percolator"},
+				"message": map[string]interface{}{"type": "text"},
+				"service": map[string]interface{}{"type": "keyword"},
+				"level":   map[string]interface{}{"type": "keyword"},
+				"latency": map[string]interface{}{"type": "long"},
+			},
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, index.SetMappings(m))
+		err = StoreIndex(index)
+		assert.NoError(t, err)
+
+		for i, d := range prepareData {
+			err := index.CreateDocument(strconv.Itoa(i+1), d, false)
+			assert.NoError(t, err)
+		}
+		err = index.CreateDocument("6", map[string]interface{}{"query": "error"}, false)
+		assert.Error(t, err)
+		err = index.CreateDocument("6", map[string]interface{}{"query": map[string]interface{}{"unknown": map[string]interface{}{}}}, false)
+		assert.Error(t, err)
+		err = index.CreateDocument("7", map[string]interface{}{"message": "disk error", "level": "info"}, false)
+		assert.NoError(t, err)
+
+		waitWAL(t, index)
+	})
+
+	t.Run("terms", func(t *testing.T) {
+		bq, err := query.Query(prepareData[1]["query"], index.GetMappings(), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"message\x00error"}, query.PercolatorTerms(bq))
+		bq, err = query.Query(prepareData[3]["query"], index.GetMappings(), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{query.PercolatorAnyTerm}, query.PercolatorTerms(bq))
+	})
+
+	hitIDs := func(resp *meta.SearchResponse) []string {
+		ids := make([]string, 0, len(resp.Hits.Hits))
+		for _, hit := range resp.Hits.Hits {
+			ids = append(ids, hit.ID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name  string
+		query *meta.PercolateQuery
+		want  []string
+	}{
+		{
+			name: "document",
+			query: &meta.PercolateQuery{Field: "query", Document: map[string]interface{}{
+				"message": "An error occurred", "service": "payment", "level": "info", "latency": 10,
+			}},
+			want: []string{"1", "2"},
+		},
+		{
+			name: "phrase and range",
+			query: &meta.PercolateQuery{Field: "query", Document: map[string]interface{}{
+				"message": "connection timeout after retry", "service": "search", "level": "warn", "latency": 3000,
+			}},
+			want: []string{"3", "4", "5"},
+		},
+		{
+			name: "documents",
+			query: &meta.PercolateQuery{Field: "query", Documents: []map[string]interface{}{
+				{"message": "timeout of connection", "level": "info"},
+				{"message": "error", "service": "search", "level": "info"},
+			}},
+			want: []string{"1"},
+		},
+		{
+			name: "unmapped field",
+			query: &meta.PercolateQuery{Field: "query", Document: map[string]interface{}{
+				"host": "web-1", "level": "info",
+			}},
+			want: []string{},
+		},
+		{
+			name:  "existing document",
+			query: &meta.PercolateQuery{Field: "query", Index: indexName, ID: "7"},
+			want:  []string{"1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := index.Search(&meta.ZincQuery{
+				Query: &meta.Query{Percolate: tt.query},
+				Size:  10,
+			})
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, tt.want, hitIDs(got))
+		})
+	}
+
+	errTests := []struct {
+		name  string
+		query *meta.PercolateQuery
+	}{
+		{
+			name:  "missing field",
+			query: &meta.PercolateQuery{Document: map[string]interface{}{"message": "error"}},
+		},
+		{
+			name:  "not percolator field",
+			query: &meta.PercolateQuery{Field: "message", Document: map[string]interface{}{"message": "error"}},
+		},
+		{
+			name:  "missing document",
+			query: &meta.PercolateQuery{Field: "query"},
+		},
+		{
+			name:  "id without index",
+			query: &meta.PercolateQuery{Field: "query", ID: "7"},
+		},
+		{
+			name:  "unknown document",
+			query: &meta.PercolateQuery{Field: "query", Index: indexName, ID: "100"},
+		},
+		{
+			name:  "invalid document",
+			query: &meta.PercolateQuery{Field: "query", Document: map[string]interface{}{"latency": "slow"}},
+		},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := index.Search(&meta.ZincQuery{
+				Query: &meta.Query{Percolate: tt.query},
+				Size:  10,
+			})
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("Cleanup", func(t *testing.T) {
+		err = DeleteIndex(indexName)
+		assert.NoError(t, err)
+	})
+}
+
+func TestIndex_SearchKNN(t *testing.T) {
+	prepareData := []map[string]interface{}{
+		{"title": "red apple", "color": "red", "vec": []interface{}{1, 0, 0}, "pos": []interface{}{0, 0}},
+		{"title": "green apple", "color": "green", "vec": []interface{}{0.9, 0.1, 0}, "pos": []interface{}{1, 0}},
+		{"title": "red car", "color": "red", "vec": []interface{}{0, 1, 0}, "pos": []interface{}{2, 0}},
+		{"title": "blue car", "color": "blue", "vec": []interface{}{0, 0.9, 0.1}, "pos": []interface{}{3, 0}},
+		{"title": "red boat", "color": "red", "vec": []interface{}{0, 0, 1}, "pos": []interface{}{4, 0}},
+	}
+
+	var err error
+	var index *Index
+	indexName := "Search.v2.index_knn"
+	t.Run("Prepare", func(t *testing.T) {
+		index, err = NewIndex(indexName, "disk", 2)
+		assert.NoError(t, err)
+		assert.NotNil(t, index)
+		_, err = mappings.Request(nil, map[string]interface{}{
+			"properties": map[string]interface{}{"vec": map[string]interface{}{"type": "dense_vector"}},
+		})
+		assert.Error(t, err)
+		m, err := mappings.Request(nil, map[string]interface{}{
+			"properties": map[string]interface{}{
+				"title": map[string]interface{}{"type": "text"},
+				"color": map[string]interface{}{"type": "keyword"},
+				"vec":   map[string]interface{}{"type": "dense_vector", "dims": 3},
+				"pos": map[string]interface{}{
+					"type": "dense_vector", "dims": 2, "similarity": "l2_norm",
+					"index_options": map[string]interface{}{"type": "hnsw", "m": 8, "ef_construction": 50},
+				},
+			},
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, index.SetMappings(m))
+		err = StoreIndex(index)
+		assert.NoError(t, err)
+
+		for i, d := range prepareData {
+			err := index.CreateDocument(strconv.Itoa(i+1), d, false)
+			assert.NoError(t, err)
+		}
+		err = index.CreateDocument("6", map[string]interface{}{"vec": []interface{}{1, 0}}, false)
+		assert.Error(t, err)
+		err = index.CreateDocument("6", map[string]interface{}{"vec": []interface{}{0, 0, 0}}, false)
+		assert.Error(t, err)
+
+		waitWAL(t, index)
+	})
+
+	hitIDs := func(resp *meta.SearchResponse) []string {
+		ids := make([]string, 0, len(resp.Hits.Hits))
+		for _, hit := range resp.Hits.Hits {
+			ids = append(ids, hit.ID)
+		}
+		return ids
+	}
+	search := func(t *testing.T, query *meta.ZincQuery) *meta.SearchResponse {
+		if query.Size == 0 {
+			query.Size = 10
+		}
+		got, err := index.Search(query)
+		assert.NoError(t, err)
+		return got
+	}
+
+	t.Run("knn", func(t *testing.T) {
+		got := search(t, &meta.ZincQuery{
+			KNN: map[string]interface{}{"field": "vec", "query_vector": []interface{}{1, 0, 0}, "k": 2, "num_candidates": 5},
+		})
+		require.Equal(t, []string{"1", "2"}, hitIDs(got))
+		assert.Equal(t, 2, got.Hits.Total.Value)
+		assert.InDelta(t, 1.0, got.Hits.Hits[0].Score, 1e-6)
+		assert.Greater(t, got.Hits.Hits[0].Score, got.Hits.Hits[1].Score)
+	})
+
+	t.Run("knn with filter", func(t *testing.T) {
+		got := search(t, &meta.ZincQuery{
+			KNN: map[string]interface{}{
+				"field": "vec", "query_vector": []interface{}{1, 0.1, 0}, "k": 2,
+				"filter": map[string]interface{}{"term": map[string]interface{}{"color": "red"}},
+			},
+		})
+		assert.Equal(t, []string{"1", "3"}, hitIDs(got))
+	})
+
+	t.Run("knn with query", func(t *testing.T) {
+		got := search(t, &meta.ZincQuery{
+			Query: map[string]interface{}{"match": map[string]interface{}{"title": "car"}},
+			KNN:   map[string]interface{}{"field": "vec", "query_vector": []interface{}{1, 0, 0}, "k": 1, "boost": 2},
+		})
+		require.ElementsMatch(t, []string{"1", "3", "4"}, hitIDs(got))
+		assert.Equal(t, "1", got.Hits.Hits[0].ID)
+		assert.InDelta(t, 2.0, got.Hits.Hits[0].Score, 1e-6)
+	})
+
+	t.Run("multiple knn", func(t *testing.T) {
+		got := search(t, &meta.ZincQuery{
+			KNN: []interface{}{
+				map[string]interface{}{"field": "vec", "query_vector": []interface{}{1, 0, 0}, "k": 1},
+				map[string]interface{}{"field": "pos", "query_vector": []interface{}{4, 0}, "k": 1},
+			},
+		})
+		assert.ElementsMatch(t, []string{"1", "5"}, hitIDs(got))
+	})
+
+	t.Run("knn with similarity", func(t *testing.T) {
+		got := search(t, &meta.ZincQuery{
+			KNN: map[string]interface{}{"field": "pos", "query_vector": []interface{}{0, 0}, "k": 5, "similarity": 1.5},
+		})
+		require.Equal(t, []string{"1", "2"}, hitIDs(got))
+		assert.InDelta(t, 0.5, got.Hits.Hits[1].Score, 1e-6)
+	})
+
+	t.Run("rrf", func(t *testing.T) {
+		got := search(t, &meta.ZincQuery{
+			Query: map[string]interface{}{"match": map[string]interface{}{"title": "red"}},
+			KNN:   map[string]interface{}{"field": "vec", "query_vector": []interface{}{0, 1, 0}, "k": 2},
+			Rank:  map[string]interface{}{"rrf": map[string]interface{}{"window_size": 10, "rank_constant": 1}},
+		})
+		require.ElementsMatch(t, []string{"1", "3", "4", "5"}, hitIDs(got))
+		assert.Equal(t, "3", got.Hits.Hits[0].ID)
+	})
+
+	t.Run("update and delete", func(t *testing.T) {
+		err := index.UpdateDocument("1", map[string]interface{}{"title": "red apple", "color": "red", "vec": []interface{}{0, 0, 1}}, false)
+		assert.NoError(t, err)
+		err = index.DeleteDocument("4")
+		assert.NoError(t, err)
+		waitWAL(t, index)
+
+		got := search(t, &meta.ZincQuery{
+			KNN: map[string]interface{}{"field": "vec", "query_vector": []interface{}{1, 0, 0}, "k": 1},
+		})
+		assert.Equal(t, []string{"2"}, hitIDs(got))
+		got = search(t, &meta.ZincQuery{
+			KNN: map[string]interface{}{"field": "vec", "query_vector": []interface{}{0, 0.9, 0.1}, "k": 5, "num_candidates": 10},
+		})
+		require.ElementsMatch(t, []string{"1", "2", "3", "5"}, hitIDs(got))
+		assert.Equal(t, "3", got.Hits.Hits[0].ID)
+	})
+
+	errTests := []struct {
+		name  string
+		query *meta.ZincQuery
+	}{
+		{
+			name:  "dims mismatch",
+			query: &meta.ZincQuery{KNN: map[string]interface{}{"field": "vec", "query_vector": []interface{}{1, 0}}},
+		},
+		{
+			name:  "not dense_vector field",
+			query: &meta.ZincQuery{KNN: map[string]interface{}{"field": "title", "query_vector": []interface{}{1, 0, 0}}},
+		},
+		{
+			name:  "num_candidates less than k",
+			query: &meta.ZincQuery{KNN: map[string]interface{}{"field": "vec", "query_vector": []interface{}{1, 0, 0}, "k": 5, "num_candidates": 2}},
+		},
+		{
+			name:  "rank without knn",
+			query: &meta.ZincQuery{Rank: map[string]interface{}{"rrf": map[string]interface{}{}}},
+		},
+		{
+			name: "rank with sort",
+			query: &meta.ZincQuery{
+				Query: map[string]interface{}{"match_all": map[string]interface{}{}},
+				KNN:   map[string]interface{}{"field": "vec", "query_vector": []interface{}{1, 0, 0}},
+				Rank:  map[string]interface{}{"rrf": map[string]interface{}{}},
+				Sort:  []interface{}{"color"},
+			},
+		},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.Size = 10
+			_, err := index.Search(tt.query)
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("Cleanup", func(t *testing.T) {
+		err = DeleteIndex(indexName)
+		assert.NoError(t, err)
+	})
+}
+
+func TestIndex_SearchSuggest(t *testing.T) {
+	prepareData := []map[string]interface{}{
+		{"title": "nirvana nevermind album", "genre": "rock", "suggest": map[string]interface{}{"input": []interface{}{"Nevermind", "Nirvana"}, "weight": 34}},
+		{"title": "nirvana in utero", "genre": "rock", "suggest": map[string]interface{}{"input": "Nirvana In Utero", "weight": 10}},
+		{"title": "nine inch nails", "genre": "industrial", "suggest": []interface{}{"Nine Inch Nails"}},
+		{"title": "nina simone", "genre": "pop", "suggest": []interface{}{map[string]interface{}{"input": "Nina Simone", "weight": 20, "contexts": map[string]interface{}{"genre": []interface{}{"jazz"}}}}},
+		{"title": "tribute", "genre": "rock", "suggest": "Nirvana"},
+	}
+
+	var err error
+	var index *Index
+	indexName := "Search.v2.index_suggest"
+	t.Run("Prepare", func(t *testing.T) {
+		index, err = NewIndex(indexName, "disk", 2)
+		assert.NoError(t, err)
+		assert.NotNil(t, index)
+		m, err := mappings.Request(nil, map[string]interface{}{
+			"properties": map[string]interface{}{
+				"title": map[string]interface{}{"type": "text"},
+				"genre": map[string]interface{}{"type": "keyword"},
+				"suggest": map[string]interface{}{
+					"type":     "completion",
+					"contexts": []interface{}{map[string]interface{}{"name": "genre", "type": "category", "path": "genre"}},
+				},
+			},
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, index.SetMappings(m))
+		err = StoreIndex(index)
+		assert.NoError(t, err)
+
+		for i, d := range prepareData {
+			err := index.CreateDocument(strconv.Itoa(i+1), d, false)
+			assert.NoError(t, err)
+		}
+
+		waitWAL(t, index)
+	})
+
+	completionIDs := func(t *testing.T, resp *meta.SearchResponse, name string) []string {
+		ids := make([]string, 0)
+		if assert.Len(t, resp.Suggest[name], 1) {
+			for _, option := range resp.Suggest[name][0].Options {
+				ids = append(ids, option.ID)
+			}
+		}
+		return ids
+	}
+
+	completionTests := []struct {
+		name    string
+		suggest map[string]interface{}
+		wantIDs []string
+	}{
+		{
+			name:    "prefix",
+			suggest: map[string]interface{}{"prefix": "nir", "completion": map[string]interface{}{"field": "suggest"}},
+			wantIDs: []string{"1", "2", "5"},
+		},
+		{
+			name:    "weight and size",
+			suggest: map[string]interface{}{"prefix": "Ni", "completion": map[string]interface{}{"field": "suggest", "size": 2}},
+			wantIDs: []string{"1", "4"},
+		},
+		{
+			name:    "skip duplicates",
+			suggest: map[string]interface{}{"prefix": "nirvana", "completion": map[string]interface{}{"field": "suggest", "skip_duplicates": true}},
+			wantIDs: []string{"1", "2"},
+		},
+		{
+			name:    "fuzzy",
+			suggest: map[string]interface{}{"prefix": "nurv", "completion": map[string]interface{}{"field": "suggest", "fuzzy": map[string]interface{}{"fuzziness": 1}}},
+			wantIDs: []string{"1", "2", "5"},
+		},
+		{
+			name:    "context",
+			suggest: map[string]interface{}{"prefix": "n", "completion": map[string]interface{}{"field": "suggest", "contexts": map[string]interface{}{"genre": []interface{}{"industrial"}}}},
+			wantIDs: []string{"3"},
+		},
+		{
+			name: "context prefix and boost",
+			suggest: map[string]interface{}{"prefix": "n", "completion": map[string]interface{}{"field": "suggest", "contexts": map[string]interface{}{
+				"genre": []interface{}{"rock", map[string]interface{}{"context": "ja", "prefix": true, "boost": 2}},
+			}}},
+			wantIDs: []string{"4", "1", "2", "5"},
+		},
+		{
+			name:    "regex",
+			suggest: map[string]interface{}{"regex": "n[aeiou]n", "completion": map[string]interface{}{"field": "suggest"}},
+			wantIDs: []string{"4", "3"},
+		},
+	}
+	for _, tt := range completionTests {
+		t.Run("completion "+tt.name, func(t *testing.T) {
+			resp, err := index.Search(&meta.ZincQuery{Suggest: map[string]interface{}{"song": tt.suggest}})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantIDs, completionIDs(t, resp, "song"))
+		})
+	}
+
+	t.Run("completion option", func(t *testing.T) {
+		resp, err := index.Search(&meta.ZincQuery{Suggest: map[string]interface{}{
+			"song": map[string]interface{}{"prefix": "nevermi", "completion": map[string]interface{}{"field": "suggest", "contexts": map[string]interface{}{"genre": "rock"}}},
+		}})
+		assert.NoError(t, err)
+		if assert.Len(t, resp.Suggest["song"][0].Options, 1) {
+			option := resp.Suggest["song"][0].Options[0]
+			assert.Equal(t, "Nevermind", option.Text)
+			assert.Equal(t, 34.0, option.DocScore)
+			assert.Equal(t, indexName, option.Index)
+			assert.Equal(t, map[string][]string{"genre": {"rock"}}, option.Contexts)
+			assert.Equal(t, "rock", option.Source.(map[string]interface{})["genre"])
+		}
+	})
+
+	t.Run("term", func(t *testing.T) {
+		resp, err := index.Search(&meta.ZincQuery{Suggest: map[string]interface{}{
+			"text":  "nirvana nevermnd",
+			"typos": map[string]interface{}{"term": map[string]interface{}{"field": "title"}},
+		}})
+		assert.NoError(t, err)
+		entries := resp.Suggest["typos"]
+		if assert.Len(t, entries, 2) {
+			assert.Equal(t, "nirvana", entries[0].Text)
+			assert.Empty(t, entries[0].Options)
+			assert.Equal(t, "nevermnd", entries[1].Text)
+			assert.Equal(t, 8, entries[1].Offset)
+			assert.Equal(t, 8, entries[1].Length)
+			if assert.Len(t, entries[1].Options, 1) {
+				assert.Equal(t, "nevermind", entries[1].Options[0].Text)
+				assert.Equal(t, 1, entries[1].Options[0].Freq)
+			}
+		}
+	})
+
+	t.Run("term suggest_mode always", func(t *testing.T) {
+		resp, err := index.Search(&meta.ZincQuery{Suggest: map[string]interface{}{
+			"typos": map[string]interface{}{"text": "nines", "term": map[string]interface{}{"field": "title", "suggest_mode": "always", "sort": "frequency"}},
+		}})
+		assert.NoError(t, err)
+		texts := make([]string, 0)
+		for _, option := range resp.Suggest["typos"][0].Options {
+			texts = append(texts, option.Text)
+		}
+		assert.Equal(t, []string{"nine", "nina"}, texts)
+	})
+
+	t.Run("phrase", func(t *testing.T) {
+		resp, err := index.Search(&meta.ZincQuery{Suggest: map[string]interface{}{
+			"fix": map[string]interface{}{"text": "nirvana in uteru", "phrase": map[string]interface{}{
+				"field":     "title",
+				"size":      1,
+				"highlight": map[string]interface{}{"pre_tag": "<em>", "post_tag": "</em>"},
+			}},
+		}})
+		assert.NoError(t, err)
+		if assert.Len(t, resp.Suggest["fix"], 1) && assert.Len(t, resp.Suggest["fix"][0].Options, 1) {
+			option := resp.Suggest["fix"][0].Options[0]
+			assert.Equal(t, "nirvana in utero", option.Text)
+			assert.Equal(t, "nirvana in <em>utero</em>", option.Highlighted)
+			assert.Greater(t, option.Score, 0.0)
+		}
+	})
+
+	t.Run("phrase without correction", func(t *testing.T) {
+		resp, err := index.Search(&meta.ZincQuery{Suggest: map[string]interface{}{
+			"fix": map[string]interface{}{"text": "nirvana in utero", "phrase": map[string]interface{}{"field": "title"}},
+		}})
+		assert.NoError(t, err)
+		assert.Empty(t, resp.Suggest["fix"][0].Options)
+	})
+
+	errTests := []struct {
+		name    string
+		suggest map[string]interface{}
+	}{
+		{name: "no suggester", suggest: map[string]interface{}{"s": map[string]interface{}{"text": "a"}}},
+		{name: "two suggesters", suggest: map[string]interface{}{"s": map[string]interface{}{"text": "a", "term": map[string]interface{}{"field": "title"}, "phrase": map[string]interface{}{"field": "title"}}}},
+		{name: "unmapped field", suggest: map[string]interface{}{"s": map[string]interface{}{"text": "a", "term": map[string]interface{}{"field": "color"}}}},
+		{name: "completion on text field", suggest: map[string]interface{}{"s": map[string]interface{}{"prefix": "a", "completion": map[string]interface{}{"field": "title"}}}},
+		{name: "unknown context", suggest: map[string]interface{}{"s": map[string]interface{}{"prefix": "a", "completion": map[string]interface{}{"field": "suggest", "contexts": map[string]interface{}{"place": "x"}}}}},
+		{name: "regex with term", suggest: map[string]interface{}{"s": map[string]interface{}{"regex": "a", "term": map[string]interface{}{"field": "title"}}}},
+		{name: "invalid suggest_mode", suggest: map[string]interface{}{"s": map[string]interface{}{"text": "a", "term": map[string]interface{}{"field": "title", "suggest_mode": "never"}}}},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := index.Search(&meta.ZincQuery{Suggest: tt.suggest})
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("Cleanup", func(t *testing.T) {
+		err = DeleteIndex(indexName)
+		assert.NoError(t, err)
+	})
+}
+
+// waitWAL waits for the WAL of all shards written to the index
+func waitWAL(t *testing.T, index *Index) {
+	require.Eventually(t, func() bool {
+		for _, shard := range index.shards {
+			if lag, err := shard.GetWALLag(); err != nil || lag > 0 {
+				return false
+			}
+		}
+		return true
+	}, 10*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,182 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package core
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/ider"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+)
+
+// maxCompletedTasks is the number of completed tasks kept in memory for querying
+const maxCompletedTasks = 1000
+
+var ZINC_TASK_LIST = &TaskList{tasks: make(map[string]*Task)}
+
+type TaskList struct {
+	tasks map[string]*Task
+	lock  sync.RWMutex
+}
+
+// Task is a long running operation in background, it reports the progress by status
+type Task struct {
+	ref   meta.TaskResult
+	start time.Time
+	done  chan struct{}
+	lock  sync.RWMutex
+}
+
+// NewTask creates a running task and adds it to the task list
+func NewTask(action, description string) *Task {
+	node := strconv.Itoa(config.Global.NodeID)
+	t := &Task{start: time.Now(), done: make(chan struct{})}
+	t.ref.Task = meta.TaskInfo{
+		Node:              node,
+		ID:                node + ":" + ider.Generate(),
+		Type:              "transport",
+		Action:            action,
+		Description:       description,
+		StartTimeInMillis: t.start.UnixMilli(),
+	}
+	ZINC_TASK_LIST.Add(t)
+	return t
+}
+
+func (t *Task) GetID() string {
+	return t.ref.Task.ID
+}
+
+// SetTotal sets the number of documents need to process
+func (t *Task) SetTotal(n int64) {
+	t.lock.Lock()
+	t.ref.Task.Status.Total = n
+	t.lock.Unlock()
+}
+
+// UpdateStatus updates the progress of task by fn
+func (t *Task) UpdateStatus(fn func(status *meta.TaskStatus)) {
+	t.lock.Lock()
+	fn(&t.ref.Task.Status)
+	t.lock.Unlock()
+}
+
+// Finish marks the task completed with the response or error
+func (t *Task) Finish(response interface{}, err error) {
+	t.lock.Lock()
+	t.ref.Completed = true
+	t.ref.Task.RunningTimeInNanos = time.Since(t.start).Nanoseconds()
+	t.ref.Response = response
+	if err != nil {
+		var e *errors.Error
+		if !errors.As(err, &e) {
+			e = errors.New(errors.ErrorTypeRuntimeException, err.Error())
+		}
+		t.ref.Error = e
+	}
+	t.lock.Unlock()
+	close(t.done)
+}
+
+// Wait blocks until the task completed
+func (t *Task) Wait() {
+	<-t.done
+}
+
+func (t *Task) IsCompleted() bool {
+	t.lock.RLock()
+	completed := t.ref.Completed
+	t.lock.RUnlock()
+	return completed
+}
+
+// GetResult returns a copy of the task result
+func (t *Task) GetResult() meta.TaskResult {
+	t.lock.RLock()
+	ret := t.ref
+	t.lock.RUnlock()
+	if !ret.Completed {
+		ret.Task.RunningTimeInNanos = time.Since(t.start).Nanoseconds()
+	}
+	return ret
+}
+
+// GetTask returns the task by id
+func GetTask(id string) (*Task, bool) {
+	return ZINC_TASK_LIST.Get(id)
+}
+
+// Add adds the task, it removes the oldest completed tasks if there are too many
+func (tl *TaskList) Add(t *Task) {
+	tl.lock.Lock()
+	defer tl.lock.Unlock()
+	tl.tasks[t.GetID()] = t
+
+	completed := make([]*Task, 0)
+	for _, task := range tl.tasks {
+		if task.IsCompleted() {
+			completed = append(completed, task)
+		}
+	}
+	if len(completed) <= maxCompletedTasks {
+		return
+	}
+	sort.Slice(completed, func(i, j int) bool {
+		return completed[i].start.Before(completed[j].start)
+	})
+	for _, task := range completed[:len(completed)-maxCompletedTasks] {
+		delete(tl.tasks, task.GetID())
+	}
+}
+
+func (tl *TaskList) Get(id string) (*Task, bool) {
+	tl.lock.RLock()
+	t, ok := tl.tasks[id]
+	tl.lock.RUnlock()
+	return t, ok
+}
+
+// List returns the tasks sorted by start time, filters by action with wildcard if actions is not empty
+func (tl *TaskList) List(actions string) []*Task {
+	tl.lock.RLock()
+	tasks := make([]*Task, 0, len(tl.tasks))
+	for _, t := range tl.tasks {
+		if actions == "" || matchTaskAction(actions, t.ref.Task.Action) {
+			tasks = append(tasks, t)
+		}
+	}
+	tl.lock.RUnlock()
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].start.Before(tasks[j].start)
+	})
+	return tasks
+}
+
+// matchTaskAction checks action matches the comma separated patterns like indices:admin/resize/*
+func matchTaskAction(patterns, action string) bool {
+	for _, pattern := range strings.Split(patterns, ",") {
+		if isMatchIndex(action, strings.TrimSpace(pattern)) {
+			return true
+		}
+	}
+	return false
+}
@@ -78,6 +78,19 @@ func NewTemplate(name string, template *meta.IndexTemplate) error {
 		}
 	}
 
+	// check component templates are exists
+	var missing []string
+	for _, component := range template.ComposedOf {
+		if _, exists, err := LoadComponentTemplate(component); err != nil {
+			return err
+		} else if !exists {
+			missing = append(missing, component)
+		}
+	}
+	if len(missing) > 0 {
+		return errors.New(errors.ErrorTypeInvalidIndexTemplate, fmt.Sprintf("index template [%s] specifies component templates %s that do not exist", name, missing))
+	}
+
 	template.CreatedAt = time.Now()
 	template.UpdatedAt = time.Now()
 	tpl := meta.Template{
@@ -115,6 +128,15 @@ func DeleteTemplate(name string) error {
 
 // UseTemplate use a specific template for new index
 func UseTemplate(indexName string) (*meta.IndexTemplate, error) {
+	templates, err := matchTemplates(indexName)
+	if err != nil || len(templates) == 0 {
+		return nil, err
+	}
+	return templates[0].IndexTemplate, nil
+}
+
+// matchTemplates returns the templates match the index name, sorted by priority from high to low
+func matchTemplates(indexName string) ([]*meta.Template, error) {
 	templates, err := ListTemplates("")
 	if err != nil {
 		return nil, err
@@ -139,15 +161,230 @@ func UseTemplate(indexName string) (*meta.IndexTemplate, error) {
 		}
 	}
 
+	var matchedTemplates []*meta.Template
 	for _, tpl := range filteredTemplates {
 		for _, pattern := range tpl.IndexTemplate.IndexPatterns {
 			pattern := strings.TrimRight(strings.ReplaceAll(pattern, "*", ".*"), "$") + "$"
 			re := regexp.MustCompile(pattern)
 			if re.MatchString(indexName) {
-				return tpl.IndexTemplate, nil
+				matchedTemplates = append(matchedTemplates, tpl)
+				break
 			}
 		}
 	}
 
-	return nil, nil
+	return matchedTemplates, nil
+}
+
+// ResolveTemplate merges the component templates in the order of composed_of, then the template itself,
+// the later one overrides the same settings, mappings fields and aliases. {index} in alias names is replaced by the index name.
+func ResolveTemplate(indexName string, template *meta.IndexTemplate) (*meta.TemplateTemplate, error) {
+	resolved := new(meta.TemplateTemplate)
+	if template == nil {
+		return resolved, nil
+	}
+	for _, name := range template.ComposedOf {
+		component, exists, err := LoadComponentTemplate(name)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, errors.New(errors.ErrorTypeInvalidIndexTemplate, fmt.Sprintf("component template [%s] does not exist", name))
+		}
+		mergeTemplate(resolved, &component.Template)
+	}
+	mergeTemplate(resolved, &template.Template)
+
+	if resolved.Aliases != nil {
+		aliases := make(map[string]interface{}, len(resolved.Aliases))
+		for alias, v := range resolved.Aliases {
+			aliases[strings.ReplaceAll(alias, "{index}", indexName)] = v
+		}
+		resolved.Aliases = aliases
+	}
+	return resolved, nil
+}
+
+// SimulateIndexTemplate returns the resolved template would be used for a new index
+func SimulateIndexTemplate(indexName string) (*meta.IndexTemplateSimulateResponse, error) {
+	templates, err := matchTemplates(indexName)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &meta.IndexTemplateSimulateResponse{Overlapping: []meta.IndexTemplateSimulateMatch{}}
+	if len(templates) == 0 {
+		return resp, nil
+	}
+	resolved, err := ResolveTemplate(indexName, templates[0].IndexTemplate)
+	if err != nil {
+		return nil, err
+	}
+	resp.Template = *resolved
+	for _, tpl := range templates[1:] {
+		resp.Overlapping = append(resp.Overlapping, meta.IndexTemplateSimulateMatch{
+			Name:          tpl.Name,
+			IndexPatterns: tpl.IndexTemplate.IndexPatterns,
+		})
+	}
+	return resp, nil
+}
+
+// mergeTemplate merges src into dst, the values of src override dst
+func mergeTemplate(dst, src *meta.TemplateTemplate) {
+	if src.Settings != nil {
+		if dst.Settings == nil {
+			dst.Settings = new(meta.IndexSettings)
+		}
+		mergeSettings(dst.Settings, src.Settings)
+	}
+	if src.Mappings != nil {
+		if dst.Mappings == nil {
+			dst.Mappings = meta.NewMappings()
+		}
+		for field, prop := range src.Mappings.ListProperty() {
+			dst.Mappings.SetProperty(field, prop)
+		}
+		dst.Mappings.MergeDynamic(src.Mappings.GetDynamic())
+	}
+	if src.Aliases != nil {
+		if dst.Aliases == nil {
+			dst.Aliases = make(map[string]interface{}, len(src.Aliases))
+		}
+		for alias, v := range src.Aliases {
+			dst.Aliases[alias] = v
+		}
+	}
+}
+
+// mergeSettings merges src into dst, the analysis components are merged by name
+func mergeSettings(dst, src *meta.IndexSettings) {
+	if src.NumberOfShards > 0 {
+		dst.NumberOfShards = src.NumberOfShards
+	}
+	if src.NumberOfReplicas > 0 {
+		dst.NumberOfReplicas = src.NumberOfReplicas
+	}
+	if src.Blocks != nil {
+		if dst.Blocks == nil {
+			dst.Blocks = new(meta.IndexBlocks)
+		}
+		if src.Blocks.ReadOnly != nil {
+			dst.Blocks.ReadOnly = src.Blocks.ReadOnly
+		}
+		if src.Blocks.Write != nil {
+			dst.Blocks.Write = src.Blocks.Write
+		}
+	}
+	if src.Analysis == nil {
+		return
+	}
+	if dst.Analysis == nil {
+		dst.Analysis = new(meta.IndexAnalysis)
+	}
+	dst.Analysis.Analyzer = mergeAnalyzers(dst.Analysis.Analyzer, src.Analysis.Analyzer)
+	dst.Analysis.Normalizer = mergeAnalyzers(dst.Analysis.Normalizer, src.Analysis.Normalizer)
+	dst.Analysis.CharFilter = mergeComponents(dst.Analysis.CharFilter, src.Analysis.CharFilter)
+	dst.Analysis.Tokenizer = mergeComponents(dst.Analysis.Tokenizer, src.Analysis.Tokenizer)
+	dst.Analysis.TokenFilter = mergeComponents(dst.Analysis.TokenFilter, src.Analysis.TokenFilter)
+	dst.Analysis.Filter = mergeComponents(dst.Analysis.Filter, src.Analysis.Filter)
+}
+
+func mergeAnalyzers(dst, src map[string]*meta.Analyzer) map[string]*meta.Analyzer {
+	if src == nil {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]*meta.Analyzer, len(src))
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+func mergeComponents(dst, src map[string]interface{}) map[string]interface{} {
+	if src == nil {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]interface{}, len(src))
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+// ListComponentTemplates returns all component templates
+func ListComponentTemplates() ([]*meta.ComponentTemplate, error) {
+	templates, err := metadata.ComponentTemplate.List(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if templates == nil {
+		templates = make([]*meta.ComponentTemplate, 0)
+	}
+	return templates, nil
+}
+
+// NewComponentTemplate create or update a component template and store in local
+func NewComponentTemplate(name string, template *meta.ComponentTemplateData) error {
+	if name == "" || template == nil {
+		return nil
+	}
+
+	template.CreatedAt = time.Now()
+	if old, exists, err := LoadComponentTemplate(name); err != nil {
+		return err
+	} else if exists {
+		template.CreatedAt = old.CreatedAt
+	}
+	template.UpdatedAt = time.Now()
+	err := metadata.ComponentTemplate.Set(name, meta.ComponentTemplate{
+		Name:              name,
+		ComponentTemplate: template,
+	})
+	if err != nil {
+		return fmt.Errorf("component template: error updating document: %s", err.Error())
+	}
+	return nil
+}
+
+// LoadComponentTemplate load a specific component template from local
+func LoadComponentTemplate(name string) (*meta.ComponentTemplateData, bool, error) {
+	if name == "" {
+		return nil, false, nil
+	}
+
+	tpl, err := metadata.ComponentTemplate.Get(name)
+	if err != nil {
+		if err == errors.ErrKeyNotFound {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return tpl.ComponentTemplate, true, nil
+}
+
+// DeleteComponentTemplate delete a component template from local, it can't be deleted if any index template uses it
+func DeleteComponentTemplate(name string) error {
+	templates, err := ListTemplates("")
+	if err != nil {
+		return err
+	}
+	var used []string
+	for _, tpl := range templates {
+		for _, component := range tpl.IndexTemplate.ComposedOf {
+			if component == name {
+				used = append(used, tpl.Name)
+				break
+			}
+		}
+	}
+	if len(used) > 0 {
+		sort.Strings(used)
+		return errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("component templates [%s] cannot be removed as they are still in use by index templates %s", name, used))
+	}
+	return metadata.ComponentTemplate.Delete(name)
 }
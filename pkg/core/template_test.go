@@ -447,3 +447,91 @@ func TestUseTemplate(t *testing.T) {
 		assert.Equal(t, 0, len(tpls))
 	})
 }
+
+func TestComponentTemplate(t *testing.T) {
+	indexName := "TestComponentTemplate-log-2022.02.02"
+
+	t.Run("prepare", func(t *testing.T) {
+		assert.NoError(t, NewComponentTemplate("TestComponentTemplate.settings", &meta.ComponentTemplateData{
+			Template: meta.TemplateTemplate{
+				Settings: &meta.IndexSettings{NumberOfShards: 2},
+				Mappings: &meta.Mappings{Properties: map[string]meta.Property{
+					"name": meta.NewProperty("text"),
+					"code": meta.NewProperty("text"),
+				}},
+			},
+		}))
+		assert.NoError(t, NewComponentTemplate("TestComponentTemplate.mappings", &meta.ComponentTemplateData{
+			Template: meta.TemplateTemplate{
+				Mappings: &meta.Mappings{Properties: map[string]meta.Property{"code": meta.NewProperty("keyword")}},
+				Aliases:  map[string]interface{}{"TestComponentTemplate-alias": map[string]interface{}{}},
+			},
+		}))
+
+		err := NewTemplate("TestComponentTemplate.missing", &meta.IndexTemplate{
+			IndexPatterns: []string{"TestComponentTemplate-missing-*"},
+			ComposedOf:    []string{"TestComponentTemplate.not_exists"},
+		})
+		assert.Error(t, err)
+
+		assert.NoError(t, NewTemplate("TestComponentTemplate.log", &meta.IndexTemplate{
+			IndexPatterns: []string{"TestComponentTemplate-log-*"},
+			Priority:      200,
+			ComposedOf:    []string{"TestComponentTemplate.settings", "TestComponentTemplate.mappings"},
+			Template: meta.TemplateTemplate{
+				Aliases: map[string]interface{}{"{index}-alias": map[string]interface{}{}},
+			},
+		}))
+		assert.NoError(t, NewTemplate("TestComponentTemplate.all", &meta.IndexTemplate{
+			IndexPatterns: []string{"TestComponentTemplate-*"},
+			Priority:      100,
+		}))
+	})
+
+	t.Run("simulate", func(t *testing.T) {
+		resp, err := SimulateIndexTemplate(indexName)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), resp.Template.Settings.NumberOfShards)
+		prop, ok := resp.Template.Mappings.GetProperty("name")
+		assert.True(t, ok)
+		assert.Equal(t, "text", prop.Type)
+		prop, _ = resp.Template.Mappings.GetProperty("code")
+		assert.Equal(t, "keyword", prop.Type)
+		assert.Contains(t, resp.Template.Aliases, "TestComponentTemplate-alias")
+		assert.Contains(t, resp.Template.Aliases, indexName+"-alias")
+		assert.Len(t, resp.Overlapping, 1)
+		assert.Equal(t, "TestComponentTemplate.all", resp.Overlapping[0].Name)
+
+		resp, err = SimulateIndexTemplate("TestNoComponentTemplate")
+		assert.NoError(t, err)
+		assert.Nil(t, resp.Template.Mappings)
+	})
+
+	t.Run("new index", func(t *testing.T) {
+		index, err := NewIndex(indexName, "disk", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), index.GetShardNum())
+		assert.NoError(t, StoreIndex(index))
+		prop, _ := index.GetMappings().GetProperty("code")
+		assert.Equal(t, "keyword", prop.Type)
+		assert.ElementsMatch(t, []string{"TestComponentTemplate-alias", indexName + "-alias"}, ZINC_INDEX_ALIAS_LIST.GetAliasesForIndex(indexName))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.Error(t, DeleteComponentTemplate("TestComponentTemplate.mappings"))
+		assert.NoError(t, DeleteTemplate("TestComponentTemplate.log"))
+		assert.NoError(t, DeleteTemplate("TestComponentTemplate.all"))
+		assert.NoError(t, DeleteComponentTemplate("TestComponentTemplate.mappings"))
+		assert.NoError(t, DeleteComponentTemplate("TestComponentTemplate.settings"))
+		_, exists, err := LoadComponentTemplate("TestComponentTemplate.settings")
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		for _, alias := range []string{"TestComponentTemplate-alias", indexName + "-alias"} {
+			assert.NoError(t, ZINC_INDEX_ALIAS_LIST.RemoveIndexesFromAlias(alias, []string{indexName}))
+		}
+		assert.NoError(t, DeleteIndex(indexName))
+	})
+}
@@ -26,8 +26,17 @@ const (
 	ErrorTypeXContentParseException   = "x_content_parse_exception"
 	ErrorTypeIllegalArgumentException = "illegal_argument_exception"
 	ErrorTypeRuntimeException         = "runtime_exception"
+	ErrorTypeScriptException          = "script_exception"
 	ErrorTypeNotImplemented           = "not_implemented"
 	ErrorTypeInvalidArgument          = "invalid_argument"
+	ErrorTypeRepositoryMissing        = "repository_missing_exception"
+	ErrorTypeSnapshotMissing          = "snapshot_missing_exception"
+	ErrorTypeIndexNotFoundException   = "index_not_found_exception"
+	ErrorTypeIndexClosedException     = "index_closed_exception"
+	ErrorTypeClusterBlockException    = "cluster_block_exception"
+	ErrorTypeStrictDynamicMapping     = "strict_dynamic_mapping_exception"
+	ErrorTypeInvalidIndexTemplate     = "invalid_index_template_exception"
+	ErrorTypeResourceNotFound         = "resource_not_found_exception"
 )
 
 var (
@@ -25,7 +25,7 @@ func HandleError(c *gin.Context, err error) {
 	if err != nil {
 		switch v := err.(type) {
 		case *Error:
-			c.JSON(http.StatusBadRequest, gin.H{"error": v})
+			c.JSON(HTTPStatus(v, http.StatusBadRequest), gin.H{"error": v})
 		default:
 			c.JSON(http.StatusBadRequest, gin.H{"error": v.Error()})
 		}
@@ -33,3 +33,21 @@ func HandleError(c *gin.Context, err error) {
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "ok"})
 }
+
+// HTTPStatus returns the status code for the error type, it returns code if the type has no special status
+func HTTPStatus(err error, code int) int {
+	var e *Error
+	if !As(err, &e) {
+		return code
+	}
+	switch e.Type {
+	case ErrorTypeClusterBlockException:
+		return http.StatusForbidden
+	case ErrorTypeIndexNotFoundException, ErrorTypeResourceNotFound:
+		return http.StatusNotFound
+	case ErrorTypeIndexClosedException, ErrorTypeStrictDynamicMapping:
+		return http.StatusBadRequest
+	default:
+		return code
+	}
+}
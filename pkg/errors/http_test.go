@@ -59,6 +59,14 @@ func TestHandleError(t *testing.T) {
 				result: `{"error":{"type":"runtime_exception","reason":"error message","cause":"reason"}}`,
 			},
 		},
+		{
+			name: "errorx with status",
+			args: args{
+				err:    New(ErrorTypeClusterBlockException, "blocked"),
+				code:   http.StatusForbidden,
+				result: `{"error":{"type":"cluster_block_exception","reason":"blocked"}}`,
+			},
+		},
 		{
 			name: "nil",
 			args: args{
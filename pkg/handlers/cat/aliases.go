@@ -0,0 +1,72 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package cat
+
+import (
+	"sort"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zincsearch/zincsearch/pkg/core"
+)
+
+var aliasesColumns = []column{
+	{name: "alias", aliases: []string{"a"}, desc: "alias name"},
+	{name: "index", aliases: []string{"i", "idx"}, desc: "index alias points to"},
+	{name: "filter", aliases: []string{"f", "fi"}, desc: "filter"},
+	{name: "routing.index", aliases: []string{"ri", "routingIndex"}, desc: "index routing"},
+	{name: "routing.search", aliases: []string{"rs", "routingSearch"}, desc: "search routing"},
+	{name: "is_write_index", aliases: []string{"w", "isWriteIndex"}, desc: "write index"},
+}
+
+// @Id ESCatAliases
+// @Summary List aliases for compatible ES
+// @security BasicAuth
+// @Tags    Cat
+// @Produce plain
+// @Param   alias  path   string  false  "Alias"
+// @Param   h      query  string  false  "Columns"
+// @Param   s      query  string  false  "Sort columns"
+// @Param   v      query  bool    false  "Verbose"
+// @Param   format query  string  false  "Format, text or json"
+// @Success 200 {string} string
+// @Router /es/_cat/aliases/{alias} [get]
+func Aliases(c *gin.Context) {
+	patterns := c.Param("target_alias")
+
+	aliases := core.ZINC_INDEX_ALIAS_LIST.GetAliasMap(nil, nil)
+	indexNames := make([]string, 0, len(aliases))
+	for name := range aliases {
+		indexNames = append(indexNames, name)
+	}
+	sort.Strings(indexNames)
+
+	t := newTable(aliasesColumns)
+	for _, indexName := range indexNames {
+		indexAliases, _ := aliases[indexName].(core.M)["aliases"].(core.M)
+		names := make([]string, 0, len(indexAliases))
+		for name := range indexAliases {
+			if core.MatchNames(patterns, name) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			t.addRow(name, indexName, "-", "-", "-", "-")
+		}
+	}
+	t.render(c)
+}
@@ -0,0 +1,46 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+// Package cat implements the ES compatible _cat APIs, they output human readable tables for operators.
+package cat
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Id ESCat
+// @Summary List all _cat APIs for compatible ES
+// @security BasicAuth
+// @Tags    Cat
+// @Produce plain
+// @Success 200 {string} string
+// @Router /es/_cat [get]
+func Cat(c *gin.Context) {
+	c.String(http.StatusOK, strings.Join([]string{
+		"=^.^=",
+		"/_cat/aliases",
+		"/_cat/aliases/{alias}",
+		"/_cat/health",
+		"/_cat/indices",
+		"/_cat/indices/{index}",
+		"/_cat/shards",
+		"/_cat/shards/{index}",
+		"/_cat/templates",
+		"/_cat/templates/{name}",
+	}, "\n")+"\n")
+}
@@ -0,0 +1,108 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package cat
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/test/utils"
+)
+
+func TestCat(t *testing.T) {
+	indexName := "TestCat.index_1"
+
+	t.Run("prepare", func(t *testing.T) {
+		index, err := core.NewIndex(indexName, "disk", 2)
+		assert.NoError(t, err)
+		assert.NotNil(t, index)
+		err = core.StoreIndex(index)
+		assert.NoError(t, err)
+		err = core.ZINC_INDEX_ALIAS_LIST.AddIndexesToAlias("TestCat.alias_1", []string{indexName})
+		assert.NoError(t, err)
+		err = core.NewTemplate("TestCat.template_1", &meta.IndexTemplate{
+			IndexPatterns: []string{"TestCat.template*"},
+			Priority:      17,
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("indices", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"target": "TestCat.*"})
+		utils.SetGinRequestURL(c, "/es/_cat/indices/TestCat.*", map[string]string{"h": "index,pri,ss2"})
+		Indices(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, indexName+" 2 2\n", w.Body.String())
+
+		c, w = utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"target": "TestCat.not_exists"})
+		utils.SetGinRequestURL(c, "/es/_cat/indices/TestCat.not_exists", nil)
+		Indices(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("shards", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"target": indexName})
+		utils.SetGinRequestURL(c, "/es/_cat/shards/"+indexName, map[string]string{"h": "index,second,state"})
+		Shards(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		assert.Len(t, lines, 2)
+		assert.Equal(t, indexName+" 0 STARTED", lines[0])
+	})
+
+	t.Run("aliases", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"target_alias": "TestCat.*"})
+		utils.SetGinRequestURL(c, "/es/_cat/aliases/TestCat.*", map[string]string{"h": "alias,index"})
+		Aliases(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "TestCat.alias_1 "+indexName+"\n", w.Body.String())
+	})
+
+	t.Run("templates", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"target": "TestCat.*"})
+		utils.SetGinRequestURL(c, "/es/_cat/templates/TestCat.*", map[string]string{"h": "n,t,o"})
+		Templates(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "TestCat.template_1 [TestCat.template*] 17\n", w.Body.String())
+	})
+
+	t.Run("health", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestURL(c, "/es/_cat/health", map[string]string{"h": "status", "format": "json"})
+		Health(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `[{"status":"green"}]`, w.Body.String())
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		err := core.ZINC_INDEX_ALIAS_LIST.RemoveIndexesFromAlias("TestCat.alias_1", []string{indexName})
+		assert.NoError(t, err)
+		err = core.DeleteTemplate("TestCat.template_1")
+		assert.NoError(t, err)
+		err = core.DeleteIndex(indexName)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,78 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package cat
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/core"
+)
+
+var healthColumns = []column{
+	{name: "epoch", aliases: []string{"t", "time"}, desc: "seconds since 1970-01-01 00:00:00"},
+	{name: "timestamp", aliases: []string{"ts", "hms", "hhmmss"}, desc: "time in HH:MM:SS"},
+	{name: "cluster", aliases: []string{"cl"}, desc: "cluster name"},
+	{name: "status", aliases: []string{"st"}, desc: "health status"},
+	{name: "node.total", aliases: []string{"nt", "nodeTotal"}, desc: "total number of nodes"},
+	{name: "node.data", aliases: []string{"nd", "nodeData"}, desc: "number of nodes that can store data"},
+	{name: "shards", aliases: []string{"sh", "shards.total", "shardsTotal"}, desc: "total number of shards"},
+	{name: "pri", aliases: []string{"p", "shards.primary", "shardsPrimary"}, desc: "number of first layer shards"},
+	{name: "relo", aliases: []string{"r", "shards.relocating", "shardsRelocating"}, desc: "number of relocating nodes"},
+	{name: "init", aliases: []string{"i", "shards.initializing", "shardsInitializing"}, desc: "number of initializing nodes"},
+	{name: "unassign", aliases: []string{"u", "shards.unassigned", "shardsUnassigned"}, desc: "number of unassigned shards"},
+	{name: "pending_tasks", aliases: []string{"pt", "pendingTasks"}, desc: "number of pending tasks"},
+	{name: "active_shards_percent", aliases: []string{"asp", "activeShardsPercent"}, desc: "active number of shards in percent"},
+}
+
+// @Id ESCatHealth
+// @Summary Show cluster health for compatible ES
+// @security BasicAuth
+// @Tags    Cat
+// @Produce plain
+// @Param   h      query  string  false  "Columns"
+// @Param   v      query  bool    false  "Verbose"
+// @Param   format query  string  false  "Format, text or json"
+// @Success 200 {string} string
+// @Router /es/_cat/health [get]
+func Health(c *gin.Context) {
+	var shards, primaries int64
+	for _, index := range core.ZINC_INDEX_LIST.List() {
+		shards += index.GetAllShardNum()
+		primaries += index.GetShardNum()
+	}
+
+	now := time.Now()
+	t := newTable(healthColumns)
+	t.addRow(
+		now.Unix(),
+		now.Format("15:04:05"),
+		config.Global.Cluster.Name,
+		"green",
+		int64(1),
+		int64(1),
+		shards,
+		primaries,
+		int64(0),
+		int64(0),
+		int64(0),
+		int64(0),
+		"100.0%",
+	)
+	t.render(c)
+}
@@ -0,0 +1,116 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package cat
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+var indicesColumns = []column{
+	{name: "health", aliases: []string{"h"}, desc: "current health status"},
+	{name: "status", aliases: []string{"s"}, desc: "open/close status"},
+	{name: "index", aliases: []string{"i", "idx"}, desc: "index name"},
+	{name: "pri", aliases: []string{"p", "shards.primary"}, desc: "number of first layer shards"},
+	{name: "rep", aliases: []string{"r", "shards.replica"}, desc: "number of replica shards"},
+	{name: "shards.second", aliases: []string{"ss2", "secondShards"}, desc: "number of second layer shards"},
+	{name: "docs.count", aliases: []string{"dc", "docsCount"}, desc: "available docs"},
+	{name: "store.size", aliases: []string{"ss", "storeSize"}, desc: "store size of all shards"},
+	{name: "pri.store.size", desc: "store size of primaries"},
+	{name: "wal.operations", aliases: []string{"wo", "walOperations"}, desc: "number of operations in the write ahead log"},
+	{name: "storage.type", aliases: []string{"st", "storageType"}, desc: "storage type", hidden: true},
+	{name: "doc.time.min", aliases: []string{"dtmin"}, desc: "min document @timestamp", hidden: true},
+	{name: "doc.time.max", aliases: []string{"dtmax"}, desc: "max document @timestamp", hidden: true},
+}
+
+// @Id ESCatIndices
+// @Summary List indexes for compatible ES
+// @security BasicAuth
+// @Tags    Cat
+// @Produce plain
+// @Param   index  path   string  false  "Index"
+// @Param   h      query  string  false  "Columns"
+// @Param   s      query  string  false  "Sort columns"
+// @Param   v      query  bool    false  "Verbose"
+// @Param   bytes  query  string  false  "Bytes unit"
+// @Param   format query  string  false  "Format, text or json"
+// @Success 200 {string} string
+// @Router /es/_cat/indices/{index} [get]
+func Indices(c *gin.Context) {
+	indexes, err := core.MatchIndexes(c.Param("target"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	t := newTable(indicesColumns)
+	for _, index := range indexes {
+		stats := index.GetStats()
+		var replicas int64
+		if settings := index.GetSettings(); settings != nil {
+			replicas = settings.NumberOfReplicas
+		}
+		status := "open"
+		if index.IsClosed() {
+			status = "close"
+		}
+		var timeMin, timeMax int64
+		for _, shard := range index.GetShards() {
+			timeMin, timeMax = mergeTimeRange(timeMin, timeMax, shard.Stats.DocTimeMin, shard.Stats.DocTimeMax)
+			for _, secondShard := range shard.Shards {
+				timeMin, timeMax = mergeTimeRange(timeMin, timeMax, secondShard.Stats.DocTimeMin, secondShard.Stats.DocTimeMax)
+			}
+		}
+		t.addRow(
+			"green",
+			status,
+			index.GetName(),
+			index.GetShardNum(),
+			replicas,
+			index.GetAllShardNum(),
+			stats.DocNum,
+			byteSize(stats.StorageSize),
+			byteSize(stats.StorageSize),
+			stats.WALSize,
+			index.GetStorageType(),
+			unixTime(timeMin),
+			unixTime(timeMax),
+		)
+	}
+	t.render(c)
+}
+
+func mergeTimeRange(min, max, tMin, tMax int64) (int64, int64) {
+	if tMin > 0 && (min == 0 || tMin < min) {
+		min = tMin
+	}
+	if tMax > max {
+		max = tMax
+	}
+	return min, max
+}
+
+func unixTime(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return zutils.Unix(n)
+}
@@ -0,0 +1,91 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package cat
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+)
+
+var shardsColumns = []column{
+	{name: "index", aliases: []string{"i", "idx"}, desc: "index name"},
+	{name: "shard", aliases: []string{"s", "sh"}, desc: "first layer shard id"},
+	{name: "second", aliases: []string{"ss2", "secondShard"}, desc: "second layer shard id"},
+	{name: "prirep", aliases: []string{"p", "pr", "primaryOrReplica"}, desc: "primary or replica"},
+	{name: "state", aliases: []string{"st"}, desc: "shard state"},
+	{name: "docs", aliases: []string{"d", "dc"}, desc: "number of docs in shard"},
+	{name: "store", aliases: []string{"sto"}, desc: "store size of shard"},
+	{name: "node", aliases: []string{"n"}, desc: "name of node where it lives"},
+	{name: "time.min", aliases: []string{"tmin"}, desc: "min document @timestamp"},
+	{name: "time.max", aliases: []string{"tmax"}, desc: "max document @timestamp"},
+}
+
+// @Id ESCatShards
+// @Summary List second layer shards for compatible ES
+// @security BasicAuth
+// @Tags    Cat
+// @Produce plain
+// @Param   index  path   string  false  "Index"
+// @Param   h      query  string  false  "Columns"
+// @Param   s      query  string  false  "Sort columns"
+// @Param   v      query  bool    false  "Verbose"
+// @Param   bytes  query  string  false  "Bytes unit"
+// @Param   format query  string  false  "Format, text or json"
+// @Success 200 {string} string
+// @Router /es/_cat/shards/{index} [get]
+func Shards(c *gin.Context) {
+	indexes, err := core.MatchIndexes(c.Param("target"))
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	t := newTable(shardsColumns)
+	for _, index := range indexes {
+		for _, shard := range index.GetShards() {
+			node := shard.NodeID
+			if node == "" {
+				node = strconv.Itoa(config.Global.NodeID)
+			}
+			latestID := int64(len(shard.Shards) - 1)
+			for _, secondShard := range shard.Shards {
+				timeMin, timeMax := secondShard.Stats.DocTimeMin, secondShard.Stats.DocTimeMax
+				// the latest second layer shard time range is maintained by the first layer shard
+				if secondShard.ID == latestID {
+					timeMin, timeMax = mergeTimeRange(timeMin, timeMax, shard.Stats.DocTimeMin, shard.Stats.DocTimeMax)
+				}
+				t.addRow(
+					index.GetName(),
+					shard.ID,
+					secondShard.ID,
+					"p",
+					"STARTED",
+					secondShard.Stats.DocNum,
+					byteSize(secondShard.Stats.StorageSize),
+					node,
+					unixTime(timeMin),
+					unixTime(timeMax),
+				)
+			}
+		}
+	}
+	t.render(c)
+}
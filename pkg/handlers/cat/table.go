@@ -0,0 +1,370 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package cat
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+// column describe a _cat table column
+type column struct {
+	name    string
+	aliases []string
+	desc    string
+	hidden  bool // not display by default, need request by h=
+}
+
+func (col column) match(name string) bool {
+	if ok, _ := path.Match(name, col.name); ok {
+		return true
+	}
+	for _, alias := range col.aliases {
+		if alias == name {
+			return true
+		}
+	}
+	return false
+}
+
+// byteSize a cell value what need format by bytes= unit
+type byteSize uint64
+
+// table is the ES _cat response, rows are typed values and will be formatted when render
+type table struct {
+	columns []column
+	rows    [][]interface{}
+}
+
+func newTable(columns []column) *table {
+	return &table{columns: columns}
+}
+
+func (t *table) addRow(cells ...interface{}) {
+	t.rows = append(t.rows, cells)
+}
+
+// render output table by the ES _cat query parameters:
+//
+//	h      comma separated column names, support wildcards
+//	s      comma separated sort columns, column:asc or column:desc
+//	v      output header line
+//	help   output columns help
+//	bytes  unit for byte values: b, kb, mb, gb, tb, pb
+//	format text or json
+func (t *table) render(c *gin.Context) {
+	if _, ok := c.GetQuery("help"); ok {
+		t.renderHelp(c)
+		return
+	}
+
+	columns, err := t.selectColumns(c.Query("h"))
+	if err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	if err := t.sortRows(c.Query("s")); err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	unit := strings.ToLower(c.Query("bytes"))
+	if _, ok := byteUnits[unit]; !ok && unit != "" {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: "unsupported bytes unit [" + unit + "]"})
+		return
+	}
+
+	values := make([][]string, 0, len(t.rows))
+	for _, row := range t.rows {
+		line := make([]string, len(columns))
+		for i, col := range columns {
+			line[i] = formatCell(row[col], unit)
+		}
+		values = append(values, line)
+	}
+
+	if strings.EqualFold(c.Query("format"), "json") {
+		items := make([]map[string]string, 0, len(values))
+		for _, line := range values {
+			item := make(map[string]string, len(columns))
+			for i, col := range columns {
+				item[t.columns[col].name] = line[i]
+			}
+			items = append(items, item)
+		}
+		zutils.GinRenderJSON(c, http.StatusOK, items)
+		return
+	}
+
+	var header []string
+	if v, ok := c.GetQuery("v"); ok && v != "false" {
+		header = make([]string, len(columns))
+		for i, col := range columns {
+			header[i] = t.columns[col].name
+		}
+	}
+	rightAlign := make([]bool, len(columns))
+	for i, col := range columns {
+		rightAlign[i] = t.isNumeric(col)
+	}
+	c.String(http.StatusOK, formatText(header, values, rightAlign))
+}
+
+func (t *table) renderHelp(c *gin.Context) {
+	lines := make([][]string, 0, len(t.columns))
+	for _, col := range t.columns {
+		aliases := strings.Join(col.aliases, ",")
+		if aliases == "" {
+			aliases = "-"
+		}
+		lines = append(lines, []string{col.name, "|", aliases, "|", col.desc})
+	}
+	c.String(http.StatusOK, formatText(nil, lines, make([]bool, 5)))
+}
+
+// selectColumns returns the column positions what need output
+func (t *table) selectColumns(h string) ([]int, error) {
+	columns := make([]int, 0, len(t.columns))
+	if h == "" {
+		for i, col := range t.columns {
+			if !col.hidden {
+				columns = append(columns, i)
+			}
+		}
+		return columns, nil
+	}
+
+	for _, name := range strings.Split(h, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for i, col := range t.columns {
+			if col.match(name) {
+				columns = append(columns, i)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("header [%s] is not supported", name)
+		}
+	}
+	return columns, nil
+}
+
+func (t *table) sortRows(s string) error {
+	if s == "" {
+		return nil
+	}
+
+	type sortField struct {
+		column int
+		desc   bool
+	}
+	fields := make([]sortField, 0)
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		desc := false
+		if i := strings.LastIndex(name, ":"); i > 0 {
+			switch strings.ToLower(name[i+1:]) {
+			case "asc":
+			case "desc":
+				desc = true
+			default:
+				return fmt.Errorf("unsupported sort order [%s]", name[i+1:])
+			}
+			name = name[:i]
+		}
+		found := false
+		for i, col := range t.columns {
+			if col.name == name || zutils.SliceExists(col.aliases, name) {
+				fields = append(fields, sortField{column: i, desc: desc})
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unable to sort by unknown sort key [%s]", name)
+		}
+	}
+
+	sort.SliceStable(t.rows, func(i, j int) bool {
+		for _, field := range fields {
+			cmp := compareCell(t.rows[i][field.column], t.rows[j][field.column])
+			if cmp == 0 {
+				continue
+			}
+			if field.desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+	return nil
+}
+
+// isNumeric numeric columns are right aligned like ES
+func (t *table) isNumeric(column int) bool {
+	for _, row := range t.rows {
+		switch row[column].(type) {
+		case int, int64, uint64, float64, byteSize:
+			return true
+		case nil:
+			continue
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+func compareCell(a, b interface{}) int {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		default:
+			return 1
+		}
+	}
+	switch av := a.(type) {
+	case string:
+		return strings.Compare(av, b.(string))
+	case time.Time:
+		bv := b.(time.Time)
+		switch {
+		case av.Before(bv):
+			return -1
+		case av.After(bv):
+			return 1
+		default:
+			return 0
+		}
+	}
+	af, _ := zutils.ToFloat64(toNumber(a))
+	bf, _ := zutils.ToFloat64(toNumber(b))
+	switch {
+	case af < bf:
+		return -1
+	case af > bf:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func toNumber(v interface{}) interface{} {
+	switch v := v.(type) {
+	case byteSize:
+		return uint64(v)
+	default:
+		return v
+	}
+}
+
+func formatCell(v interface{}, unit string) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case byteSize:
+		return formatBytes(uint64(v), unit)
+	case time.Time:
+		if v.IsZero() {
+			return "-"
+		}
+		return v.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+var byteUnits = map[string]uint64{
+	"b":  1,
+	"kb": 1 << 10,
+	"mb": 1 << 20,
+	"gb": 1 << 30,
+	"tb": 1 << 40,
+	"pb": 1 << 50,
+}
+
+// formatBytes format bytes by unit, if unit is empty, use human readable format
+func formatBytes(size uint64, unit string) string {
+	if unit != "" {
+		return strconv.FormatUint(size/byteUnits[unit], 10)
+	}
+	for _, u := range []string{"pb", "tb", "gb", "mb", "kb"} {
+		if size >= byteUnits[u] {
+			return strconv.FormatFloat(float64(size)/float64(byteUnits[u]), 'f', 1, 64) + u
+		}
+	}
+	return strconv.FormatUint(size, 10) + "b"
+}
+
+func formatText(header []string, lines [][]string, rightAlign []bool) string {
+	widths := make([]int, len(rightAlign))
+	for _, line := range append([][]string{header}, lines...) {
+		for i, v := range line {
+			if len(v) > widths[i] {
+				widths[i] = len(v)
+			}
+		}
+	}
+
+	buf := new(strings.Builder)
+	write := func(line []string, isHeader bool) {
+		for i, v := range line {
+			if i > 0 {
+				buf.WriteByte(' ')
+			}
+			pad := strings.Repeat(" ", widths[i]-len(v))
+			if rightAlign[i] && !isHeader {
+				buf.WriteString(pad + v)
+			} else {
+				buf.WriteString(v + pad)
+			}
+		}
+		buf.WriteByte('\n')
+	}
+	if header != nil {
+		write(header, true)
+	}
+	for _, line := range lines {
+		write(line, false)
+	}
+	return buf.String()
+}
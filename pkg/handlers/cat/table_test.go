@@ -0,0 +1,126 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package cat
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+	"github.com/zincsearch/zincsearch/test/utils"
+)
+
+func testTable() *table {
+	t := newTable([]column{
+		{name: "index", aliases: []string{"i"}},
+		{name: "docs.count", aliases: []string{"dc"}},
+		{name: "store.size", aliases: []string{"ss"}},
+		{name: "hidden", hidden: true},
+	})
+	t.addRow("b", uint64(10), byteSize(2048), "x")
+	t.addRow("a", uint64(200), byteSize(1536), "y")
+	t.addRow("c", uint64(3), byteSize(5<<20), "z")
+	return t
+}
+
+func TestTableRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]string
+		wantCode int
+		want     string
+	}{
+		{
+			name:     "default",
+			params:   map[string]string{},
+			wantCode: http.StatusOK,
+			want:     "b  10 2.0kb\na 200 1.5kb\nc   3 5.0mb\n",
+		},
+		{
+			name:     "verbose",
+			params:   map[string]string{"v": ""},
+			wantCode: http.StatusOK,
+			want:     "index docs.count store.size\nb             10      2.0kb\na            200      1.5kb\nc              3      5.0mb\n",
+		},
+		{
+			name:     "headers and sort",
+			params:   map[string]string{"h": "i,dc", "s": "dc:desc"},
+			wantCode: http.StatusOK,
+			want:     "a 200\nb  10\nc   3\n",
+		},
+		{
+			name:     "hidden column and wildcard",
+			params:   map[string]string{"h": "hidden,docs.*", "s": "index"},
+			wantCode: http.StatusOK,
+			want:     "y 200\nx  10\nz   3\n",
+		},
+		{
+			name:     "bytes unit",
+			params:   map[string]string{"h": "ss", "bytes": "kb", "s": "ss"},
+			wantCode: http.StatusOK,
+			want:     "   1\n   2\n5120\n",
+		},
+		{
+			name:     "unknown header",
+			params:   map[string]string{"h": "foo"},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "unknown sort key",
+			params:   map[string]string{"s": "foo"},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "unknown bytes unit",
+			params:   map[string]string{"bytes": "xb"},
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := utils.NewGinContext()
+			utils.SetGinRequestURL(c, "/es/_cat/indices", tt.params)
+			testTable().render(c)
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.want != "" {
+				assert.Equal(t, tt.want, w.Body.String())
+			}
+		})
+	}
+
+	t.Run("json format", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestURL(c, "/es/_cat/indices", map[string]string{"format": "json", "bytes": "b", "s": "i"})
+		testTable().render(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var items []map[string]string
+		err := json.Unmarshal(w.Body.Bytes(), &items)
+		assert.NoError(t, err)
+		assert.Len(t, items, 3)
+		assert.Equal(t, map[string]string{"index": "a", "docs.count": "200", "store.size": "1536"}, items[0])
+	})
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "100b", formatBytes(100, ""))
+	assert.Equal(t, "1.0kb", formatBytes(1024, ""))
+	assert.Equal(t, "1.5gb", formatBytes(3<<29, ""))
+	assert.Equal(t, "1", formatBytes(3<<29, "gb"))
+	assert.Equal(t, "1610612736", formatBytes(3<<29, "b"))
+}
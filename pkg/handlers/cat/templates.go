@@ -0,0 +1,77 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package cat
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+var templatesColumns = []column{
+	{name: "name", aliases: []string{"n"}, desc: "template name"},
+	{name: "index_patterns", aliases: []string{"t"}, desc: "template index patterns"},
+	{name: "order", aliases: []string{"o", "p"}, desc: "template priority"},
+	{name: "version", aliases: []string{"v"}, desc: "version"},
+	{name: "composed_of", aliases: []string{"c"}, desc: "component templates comprising index template"},
+}
+
+// @Id ESCatTemplates
+// @Summary List index templates for compatible ES
+// @security BasicAuth
+// @Tags    Cat
+// @Produce plain
+// @Param   name   path   string  false  "Template"
+// @Param   h      query  string  false  "Columns"
+// @Param   s      query  string  false  "Sort columns"
+// @Param   v      query  bool    false  "Verbose"
+// @Param   format query  string  false  "Format, text or json"
+// @Success 200 {string} string
+// @Failure 500 {object} meta.HTTPResponseError
+// @Router /es/_cat/templates/{name} [get]
+func Templates(c *gin.Context) {
+	patterns := c.Param("target")
+
+	templates, err := core.ListTemplates("")
+	if err != nil {
+		zutils.GinRenderJSON(c, http.StatusInternalServerError, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+
+	t := newTable(templatesColumns)
+	for _, tpl := range templates {
+		if !core.MatchNames(patterns, tpl.Name) {
+			continue
+		}
+		t.addRow(
+			tpl.Name,
+			"["+strings.Join(tpl.IndexTemplate.IndexPatterns, ", ")+"]",
+			int64(tpl.IndexTemplate.Priority),
+			"",
+			"[]",
+		)
+	}
+	t.render(c)
+}
@@ -111,7 +111,7 @@ func BulkWorker(target string, body io.Reader) (*BulkResponse, error) {
 		for k := range doc {
 			delete(doc, k)
 		}
-		if err = json.Unmarshal(scanner.Bytes(), &doc); err != nil {
+		if err = json.UnmarshalUseNumber(scanner.Bytes(), &doc); err != nil {
 			log.Error().Msgf("bulk.json.Unmarshal: %s, err %s", scanner.Text(), err.Error())
 			continue
 		}
@@ -21,6 +21,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/errors"
 	"github.com/zincsearch/zincsearch/pkg/ider"
 	"github.com/zincsearch/zincsearch/pkg/meta"
 	"github.com/zincsearch/zincsearch/pkg/zutils"
@@ -57,7 +58,7 @@ func Bulkv2(c *gin.Context) {
 	defer c.Request.Body.Close()
 	count, err := Bulkv2Worker(target, body)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, meta.HTTPResponseError{Error: err.Error()})
+		c.JSON(errors.HTTPStatus(err, http.StatusInternalServerError), meta.HTTPResponseError{Error: err.Error()})
 		return
 	}
 
@@ -21,6 +21,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/errors"
 	"github.com/zincsearch/zincsearch/pkg/ider"
 	"github.com/zincsearch/zincsearch/pkg/meta"
 	"github.com/zincsearch/zincsearch/pkg/zutils"
@@ -69,7 +70,7 @@ func CreateUpdate(c *gin.Context) {
 
 	err = index.CreateDocument(docID, doc, update)
 	if err != nil {
-		zutils.GinRenderJSON(c, http.StatusInternalServerError, meta.HTTPResponseError{Error: err.Error()})
+		zutils.GinRenderJSON(c, errors.HTTPStatus(err, http.StatusInternalServerError), meta.HTTPResponseError{Error: err.Error()})
 		return
 	}
 	zutils.GinRenderJSON(c, http.StatusOK, meta.HTTPResponseESID{
@@ -25,6 +25,7 @@ import (
 
 	"github.com/zincsearch/zincsearch/pkg/config"
 	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/errors"
 	"github.com/zincsearch/zincsearch/pkg/ider"
 	"github.com/zincsearch/zincsearch/pkg/meta"
 	"github.com/zincsearch/zincsearch/pkg/zutils/json"
@@ -55,7 +56,7 @@ func Multi(c *gin.Context) {
 	defer c.Request.Body.Close()
 	count, err := MultiWorker(target, c.Request.Body)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, meta.HTTPResponseError{Error: err.Error()})
+		c.JSON(errors.HTTPStatus(err, http.StatusInternalServerError), meta.HTTPResponseError{Error: err.Error()})
 		return
 	}
 
@@ -84,7 +85,7 @@ func MultiWorker(indexName string, body io.Reader) (int64, error) {
 		for k := range doc {
 			delete(doc, k)
 		}
-		if err = json.Unmarshal(scanner.Bytes(), &doc); err != nil {
+		if err = json.UnmarshalUseNumber(scanner.Bytes(), &doc); err != nil {
 			log.Error().Msgf("multi.json.Unmarshal: %s, err %s", scanner.Text(), err.Error())
 			continue
 		}
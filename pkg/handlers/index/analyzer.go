@@ -22,6 +22,7 @@ import (
 	"github.com/blugelabs/bluge/analysis"
 	"github.com/gin-gonic/gin"
 
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/morph"
 	"github.com/zincsearch/zincsearch/pkg/core"
 	"github.com/zincsearch/zincsearch/pkg/errors"
 	"github.com/zincsearch/zincsearch/pkg/meta"
@@ -48,6 +49,7 @@ func Analyze(c *gin.Context) {
 
 	var err error
 	var ana *analysis.Analyzer
+	var anaConfig *meta.Analyzer
 	indexName := c.Param("target")
 	if indexName != "" {
 		// use index analyzer
@@ -78,6 +80,9 @@ func Analyze(c *gin.Context) {
 				return
 			}
 		}
+		if settings := index.GetSettings(); settings != nil && settings.Analysis != nil {
+			anaConfig = settings.Analysis.Analyzer[query.Analyzer]
+		}
 	} else {
 		// none index specified
 		ana, _ = zincanalysis.QueryAnalyzer(nil, query.Analyzer)
@@ -91,7 +96,7 @@ func Analyze(c *gin.Context) {
 		}
 	}
 
-	charFilters, err := parseCharFilter(query.CharFilter)
+	charFilters, charFilterNames, err := parseCharFilter(query.CharFilter)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
@@ -101,28 +106,40 @@ func Analyze(c *gin.Context) {
 		query.TokenFilter = query.Filter
 		query.Filter = nil
 	}
-	tokenFilters, err := parseTokenFilter(query.TokenFilter)
+	tokenFilters, tokenFilterNames, err := parseTokenFilter(query.TokenFilter)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
 	}
 
-	tokenizers, err := parseTokenizer(query.Tokenizer)
+	tokenizers, tokenizerNames, err := parseTokenizer(query.Tokenizer)
 	if err != nil {
 		errors.HandleError(c, err)
 		return
 	}
 
+	// copy the analyzer, the index analyzer should not be changed by the request
+	tokenizer, filters := morph.Unwrap(ana)
+	ana = &analysis.Analyzer{
+		CharFilters:  append([]analysis.CharFilter{}, ana.CharFilters...),
+		Tokenizer:    tokenizer,
+		TokenFilters: append([]analysis.TokenFilter{}, filters...),
+	}
+	names := newAnalyzeNames(ana, anaConfig)
+
 	if len(charFilters) > 0 {
 		ana.CharFilters = append(ana.CharFilters, charFilters...)
+		names.charFilters = append(names.charFilters, charFilterNames...)
 	}
 
 	if len(tokenFilters) > 0 {
 		ana.TokenFilters = append(ana.TokenFilters, tokenFilters...)
+		names.tokenFilters = append(names.tokenFilters, tokenFilterNames...)
 	}
 
 	if len(tokenizers) > 0 {
 		ana.Tokenizer = tokenizers[0]
+		names.tokenizer = tokenizerNames[0]
 	}
 
 	if ana.Tokenizer == nil {
@@ -130,7 +147,13 @@ func Analyze(c *gin.Context) {
 		return
 	}
 
-	tokens := ana.Analyze([]byte(query.Text))
+	if query.Explain {
+		custom := query.Analyzer == "" || len(charFilters) > 0 || len(tokenFilters) > 0 || len(tokenizers) > 0
+		c.JSON(http.StatusOK, AnalyzeExplainResponse{Detail: explainAnalyze(ana, names, custom, query.Text, query.Attributes)})
+		return
+	}
+
+	tokens := morph.Wrap(ana).Analyze([]byte(query.Text))
 	ret := AnalyzeResponse{}
 	ret.Tokens = make([]AnalyzeResponseToken, 0, len(tokens))
 	for _, token := range tokens {
@@ -152,133 +175,154 @@ func Analyze(c *gin.Context) {
 // @Router /api/{index}/_analyze [post]
 func AnalyzeIndexForSDK() {}
 
-func parseTokenizer(data interface{}) ([]analysis.Tokenizer, error) {
+func parseTokenizer(data interface{}) ([]analysis.Tokenizer, []string, error) {
 	if data == nil {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	tokenizers := make([]analysis.Tokenizer, 0)
+	names := make([]string, 0)
 	switch v := data.(type) {
 	case string:
 		zer, err := zincanalysis.RequestTokenizerSingle(v, nil)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		tokenizers = append(tokenizers, zer)
+		names = append(names, v)
 	case []interface{}:
 		zers, err := zincanalysis.RequestTokenizerSlice(v)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		tokenizers = append(tokenizers, zers...)
+		for _, name := range v {
+			names = append(names, componentName(name))
+		}
 	case map[string]interface{}:
 		typ, err := zutils.GetStringFromMap(v, "type")
 		if typ != "" && err == nil {
 			zer, err := zincanalysis.RequestTokenizerSingle(typ, v)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			tokenizers = append(tokenizers, zer)
+			names = append(names, typ)
 		} else {
 			zers, err := zincanalysis.RequestTokenizer(v)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
-			for _, zer := range zers {
+			for name, zer := range zers {
 				tokenizers = append(tokenizers, zer)
+				names = append(names, name)
 			}
 		}
 	default:
-		return nil, fmt.Errorf("tokenizer unsuported type")
+		return nil, nil, fmt.Errorf("tokenizer unsuported type")
 	}
 
-	return tokenizers, nil
+	return tokenizers, names, nil
 }
 
-func parseTokenFilter(data interface{}) ([]analysis.TokenFilter, error) {
+func parseTokenFilter(data interface{}) ([]analysis.TokenFilter, []string, error) {
 	if data == nil {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	tokens := make([]analysis.TokenFilter, 0)
+	names := make([]string, 0)
 	switch v := data.(type) {
 	case string:
 		filter, err := zincanalysis.RequestTokenFilterSingle(v, nil)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		tokens = append(tokens, filter)
+		names = append(names, v)
 	case []interface{}:
 		filters, err := zincanalysis.RequestTokenFilterSlice(v)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		tokens = append(tokens, filters...)
+		for _, name := range v {
+			names = append(names, componentName(name))
+		}
 	case map[string]interface{}:
 		typ, err := zutils.GetStringFromMap(v, "type")
 		if typ != "" && err == nil {
 			filter, err := zincanalysis.RequestTokenFilterSingle(typ, v)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			tokens = append(tokens, filter)
+			names = append(names, typ)
 		} else {
 			filters, err := zincanalysis.RequestTokenFilter(v)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
-			for _, filter := range filters {
+			for name, filter := range filters {
 				tokens = append(tokens, filter)
+				names = append(names, name)
 			}
 		}
 	default:
-		return nil, fmt.Errorf("token_filter unsuported type")
+		return nil, nil, fmt.Errorf("token_filter unsuported type")
 	}
 
-	return tokens, nil
+	return tokens, names, nil
 }
 
-func parseCharFilter(data interface{}) ([]analysis.CharFilter, error) {
+func parseCharFilter(data interface{}) ([]analysis.CharFilter, []string, error) {
 	if data == nil {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	chars := make([]analysis.CharFilter, 0)
+	names := make([]string, 0)
 	switch v := data.(type) {
 	case string:
 		filter, err := zincanalysis.RequestCharFilterSingle(v, nil)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		chars = append(chars, filter)
+		names = append(names, v)
 	case []interface{}:
 		filters, err := zincanalysis.RequestCharFilterSlice(v)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		chars = append(chars, filters...)
+		for _, name := range v {
+			names = append(names, componentName(name))
+		}
 	case map[string]interface{}:
 		typ, err := zutils.GetStringFromMap(v, "type")
 		if typ != "" && err == nil {
 			filter, err := zincanalysis.RequestCharFilterSingle(typ, v)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			chars = append(chars, filter)
+			names = append(names, typ)
 		} else {
 			filters, err := zincanalysis.RequestCharFilter(v)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
-			for _, filter := range filters {
+			for name, filter := range filters {
 				chars = append(chars, filter)
+				names = append(names, name)
 			}
 		}
 	default:
-		return nil, fmt.Errorf("char_filter unsuported type")
+		return nil, nil, fmt.Errorf("char_filter unsuported type")
 	}
 
-	return chars, nil
+	return chars, names, nil
 }
 
 func formatToken(token *analysis.Token) AnalyzeResponseToken {
@@ -322,7 +366,9 @@ type AnalyzeRequest struct {
 	Tokenizer   interface{} `json:"tokenizer"`
 	CharFilter  interface{} `json:"char_filter"`
 	TokenFilter interface{} `json:"token_filter"`
-	Filter      interface{} `json:"filter"` // compatibility with es, alias for TokenFilter
+	Filter      interface{} `json:"filter"`     // compatibility with es, alias for TokenFilter
+	Explain     bool        `json:"explain"`    // returns the output of each stage of the analyzer
+	Attributes  []string    `json:"attributes"` // filters the token attributes of explain output
 }
 
 type AnalyzeResponse struct {
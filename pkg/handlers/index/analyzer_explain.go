@@ -0,0 +1,184 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package index
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/blugelabs/bluge/analysis"
+
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/morph"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+)
+
+// analyzeNames is the names of the char filters, tokenizer and token filters of an analyzer, used by explain
+type analyzeNames struct {
+	charFilters  []string
+	tokenizer    string
+	tokenFilters []string
+}
+
+// newAnalyzeNames returns the names of the analyzer components, it uses the names in the index settings
+// if the analyzer is a custom analyzer of the index, otherwise it uses the type names of the components.
+func newAnalyzeNames(ana *analysis.Analyzer, config *meta.Analyzer) *analyzeNames {
+	names := &analyzeNames{
+		charFilters:  make([]string, 0, len(ana.CharFilters)),
+		tokenFilters: make([]string, 0, len(ana.TokenFilters)),
+	}
+
+	var charFilters, tokenFilters []string
+	var tokenizer string
+	if config != nil && (config.Type == "" || config.Type == "custom") {
+		charFilters = config.CharFilter
+		tokenizer = config.Tokenizer
+		tokenFilters = config.TokenFilter
+		if tokenFilters == nil {
+			tokenFilters = config.Filter
+		}
+	}
+
+	if len(charFilters) == len(ana.CharFilters) {
+		names.charFilters = append(names.charFilters, charFilters...)
+	} else {
+		for _, filter := range ana.CharFilters {
+			names.charFilters = append(names.charFilters, typeName(filter))
+		}
+	}
+	if tokenizer != "" {
+		names.tokenizer = tokenizer
+	} else if ana.Tokenizer != nil {
+		names.tokenizer = typeName(ana.Tokenizer)
+	}
+	if len(tokenFilters) == len(ana.TokenFilters) {
+		names.tokenFilters = append(names.tokenFilters, tokenFilters...)
+	} else {
+		for _, filter := range ana.TokenFilters {
+			names.tokenFilters = append(names.tokenFilters, typeName(filter))
+		}
+	}
+
+	return names
+}
+
+// componentName returns the name of the component in request, it is a name or an object with type
+func componentName(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		typ, _ := v["type"].(string)
+		return typ
+	default:
+		return ""
+	}
+}
+
+// typeName returns the type name of the component, e.g. LowerCaseFilter
+func typeName(v interface{}) string {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// explainAnalyze runs the analyzer stage by stage and records the output of each stage
+func explainAnalyze(ana *analysis.Analyzer, names *analyzeNames, custom bool, text string, attributes []string) AnalyzeExplainDetail {
+	var attrs map[string]bool
+	if len(attributes) > 0 {
+		attrs = make(map[string]bool, len(attributes))
+		for _, attr := range attributes {
+			attrs[attr] = true
+		}
+	}
+
+	detail := AnalyzeExplainDetail{CustomAnalyzer: custom}
+	input := []byte(text)
+	for i, filter := range ana.CharFilters {
+		input = filter.Filter(input)
+		detail.CharFilters = append(detail.CharFilters, AnalyzeExplainCharFilter{
+			Name:         names.charFilters[i],
+			FilteredText: []string{string(input)},
+		})
+	}
+
+	var tokens analysis.TokenStream
+	var morphemes morph.Morphemes
+	if tokenizer, ok := ana.Tokenizer.(morph.Tokenizer); ok {
+		tokens, morphemes = tokenizer.TokenizeMorphemes(input)
+	} else {
+		tokens = ana.Tokenizer.Tokenize(input)
+	}
+	detail.Tokenizer = AnalyzeExplainTokens{Name: names.tokenizer, Tokens: formatExplainTokens(tokens, attrs)}
+	for i, filter := range ana.TokenFilters {
+		tokens = morph.FilterTokens(filter, tokens, morphemes)
+		detail.TokenFilters = append(detail.TokenFilters, AnalyzeExplainTokens{
+			Name:   names.tokenFilters[i],
+			Tokens: formatExplainTokens(tokens, attrs),
+		})
+	}
+
+	return detail
+}
+
+// formatExplainTokens formats the tokens with the attributes, all attributes are returned if attrs is nil.
+// The tokens are formatted immediately because the following token filters change the tokens in place.
+func formatExplainTokens(tokens analysis.TokenStream, attrs map[string]bool) []map[string]interface{} {
+	ret := make([]map[string]interface{}, 0, len(tokens))
+	for _, token := range tokens {
+		t := formatToken(token)
+		item := map[string]interface{}{
+			"token":        t.Token,
+			"start_offset": t.StartOffset,
+			"end_offset":   t.EndOffset,
+			"position":     t.Position,
+			"type":         t.Type,
+		}
+		for name, value := range map[string]interface{}{
+			"bytes":          fmt.Sprintf("[% x]", token.Term),
+			"keyword":        t.Keyword,
+			"positionLength": 1,
+		} {
+			if attrs == nil || attrs[name] {
+				item[name] = value
+			}
+		}
+		ret = append(ret, item)
+	}
+	return ret
+}
+
+type AnalyzeExplainResponse struct {
+	Detail AnalyzeExplainDetail `json:"detail"`
+}
+
+type AnalyzeExplainDetail struct {
+	CustomAnalyzer bool                       `json:"custom_analyzer"`
+	CharFilters    []AnalyzeExplainCharFilter `json:"charfilters,omitempty"`
+	Tokenizer      AnalyzeExplainTokens       `json:"tokenizer"`
+	TokenFilters   []AnalyzeExplainTokens     `json:"tokenfilters,omitempty"`
+}
+
+type AnalyzeExplainCharFilter struct {
+	Name         string   `json:"name"`
+	FilteredText []string `json:"filtered_text"`
+}
+
+type AnalyzeExplainTokens struct {
+	Name   string                   `json:"name"`
+	Tokens []map[string]interface{} `json:"tokens"`
+}
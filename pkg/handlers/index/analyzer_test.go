@@ -295,3 +295,93 @@ func getTokenStrings(data []byte) (string, error) {
 
 	return "[" + strings.Join(strs, " ") + "]", nil
 }
+
+func TestAnalyzeExplain(t *testing.T) {
+	indexName := "TestAnalyzeExplain.index_1"
+
+	analyze := func(t *testing.T, target, data string) (int, []byte) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestData(c, data)
+		utils.SetGinRequestParams(c, map[string]string{"target": target})
+		Analyze(c)
+		return w.Code, w.Body.Bytes()
+	}
+
+	t.Run("prepare", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestData(c, `{
+			"name":"`+indexName+`",
+			"settings":{"analysis":{
+				"analyzer":{"my_analyzer":{"type":"custom","char_filter":["html_strip"],"tokenizer":"standard","filter":["lowercase","my_stop"]}},
+				"filter":{"my_stop":{"type":"stop","stopwords":["the"]}}
+			}}
+		}`)
+		Create(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("explain custom analyzer in request", func(t *testing.T) {
+		code, body := analyze(t, "", `{"char_filter":["html_strip"],"tokenizer":"standard","filter":["lowercase",{"type":"stop","stopwords":["the"]}],"text":"<b>The Quick</b> fox","explain":true}`)
+		assert.Equal(t, http.StatusOK, code)
+		ret := new(AnalyzeExplainResponse)
+		assert.NoError(t, json.Unmarshal(body, ret))
+		assert.True(t, ret.Detail.CustomAnalyzer)
+		assert.Len(t, ret.Detail.CharFilters, 1)
+		assert.Equal(t, "html_strip", ret.Detail.CharFilters[0].Name)
+		assert.NotContains(t, ret.Detail.CharFilters[0].FilteredText[0], "<b>")
+		assert.Equal(t, "standard", ret.Detail.Tokenizer.Name)
+		assert.Equal(t, "[The Quick fox]", explainTokens(ret.Detail.Tokenizer))
+		assert.Len(t, ret.Detail.TokenFilters, 2)
+		assert.Equal(t, "lowercase", ret.Detail.TokenFilters[0].Name)
+		assert.Equal(t, "[the quick fox]", explainTokens(ret.Detail.TokenFilters[0]))
+		assert.Equal(t, "stop", ret.Detail.TokenFilters[1].Name)
+		assert.Equal(t, "[quick fox]", explainTokens(ret.Detail.TokenFilters[1]))
+		token := ret.Detail.TokenFilters[1].Tokens[0]
+		assert.Equal(t, float64(2), token["position"])
+		assert.Equal(t, "AlphaNumeric", token["type"])
+		assert.Equal(t, false, token["keyword"])
+		assert.Equal(t, "[71 75 69 63 6b]", token["bytes"])
+	})
+
+	t.Run("explain index analyzer", func(t *testing.T) {
+		code, body := analyze(t, indexName, `{"analyzer":"my_analyzer","text":"<b>The Quick</b> fox","explain":true,"attributes":["keyword"]}`)
+		assert.Equal(t, http.StatusOK, code)
+		ret := new(AnalyzeExplainResponse)
+		assert.NoError(t, json.Unmarshal(body, ret))
+		assert.False(t, ret.Detail.CustomAnalyzer)
+		assert.Equal(t, "html_strip", ret.Detail.CharFilters[0].Name)
+		assert.Equal(t, "standard", ret.Detail.Tokenizer.Name)
+		assert.Len(t, ret.Detail.TokenFilters, 2)
+		assert.Equal(t, "my_stop", ret.Detail.TokenFilters[1].Name)
+		assert.Equal(t, "[quick fox]", explainTokens(ret.Detail.TokenFilters[1]))
+		token := ret.Detail.TokenFilters[1].Tokens[0]
+		assert.Contains(t, token, "keyword")
+		assert.NotContains(t, token, "bytes")
+	})
+
+	t.Run("request filters do not change index analyzer", func(t *testing.T) {
+		code, body := analyze(t, indexName, `{"analyzer":"my_analyzer","filter":["uppercase"],"text":"The Quick fox"}`)
+		assert.Equal(t, http.StatusOK, code)
+		tokens, err := getTokenStrings(body)
+		assert.NoError(t, err)
+		assert.Equal(t, "[QUICK FOX]", tokens)
+
+		code, body = analyze(t, indexName, `{"analyzer":"my_analyzer","text":"The Quick fox"}`)
+		assert.Equal(t, http.StatusOK, code)
+		tokens, err = getTokenStrings(body)
+		assert.NoError(t, err)
+		assert.Equal(t, "[quick fox]", tokens)
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		assert.NoError(t, core.DeleteIndex(indexName))
+	})
+}
+
+func explainTokens(stage AnalyzeExplainTokens) string {
+	strs := make([]string, 0, len(stage.Tokens))
+	for _, token := range stage.Tokens {
+		strs = append(strs, token["token"].(string))
+	}
+	return "[" + strings.Join(strs, " ") + "]"
+}
@@ -0,0 +1,104 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package index
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+// Close closes the indexes, the closed index keeps its data but can not be searched or written
+//
+// @Id CloseIndex
+// @Summary Close index for compatible ES
+// @security BasicAuth
+// @Tags    Index
+// @Produce json
+// @Param   index  path  string  true  "Index"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} meta.HTTPResponseError
+// @Failure 404 {object} meta.HTTPResponseError
+// @Router /es/{index}/_close [post]
+func Close(c *gin.Context) {
+	names, err := resolveIndexNames(c.Param("target"))
+	if err != nil {
+		zutils.GinRenderJSON(c, errors.HTTPStatus(err, http.StatusBadRequest), meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+
+	indices := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		if err := core.CloseIndex(name); err != nil {
+			zutils.GinRenderJSON(c, errors.HTTPStatus(err, http.StatusInternalServerError), meta.HTTPResponseError{Error: err.Error()})
+			return
+		}
+		indices[name] = gin.H{"closed": true}
+	}
+
+	zutils.GinRenderJSON(c, http.StatusOK, gin.H{"acknowledged": true, "shards_acknowledged": true, "indices": indices})
+}
+
+// Open opens the closed indexes
+//
+// @Id OpenIndex
+// @Summary Open index for compatible ES
+// @security BasicAuth
+// @Tags    Index
+// @Produce json
+// @Param   index  path  string  true  "Index"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} meta.HTTPResponseError
+// @Failure 404 {object} meta.HTTPResponseError
+// @Router /es/{index}/_open [post]
+func Open(c *gin.Context) {
+	names, err := resolveIndexNames(c.Param("target"))
+	if err != nil {
+		zutils.GinRenderJSON(c, errors.HTTPStatus(err, http.StatusBadRequest), meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+
+	for _, name := range names {
+		if err := core.OpenIndex(name); err != nil {
+			zutils.GinRenderJSON(c, errors.HTTPStatus(err, http.StatusInternalServerError), meta.HTTPResponseError{Error: err.Error()})
+			return
+		}
+	}
+
+	zutils.GinRenderJSON(c, http.StatusOK, gin.H{"acknowledged": true, "shards_acknowledged": true})
+}
+
+// resolveIndexNames returns the index names match the comma separated target, it supports wildcard and _all
+func resolveIndexNames(target string) ([]string, error) {
+	if target == "" {
+		return nil, errors.New(errors.ErrorTypeInvalidArgument, "index name cannot be empty")
+	}
+
+	indexes, err := core.MatchIndexes(target)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(indexes))
+	for _, index := range indexes {
+		names = append(names, index.GetName())
+	}
+	return names, nil
+}
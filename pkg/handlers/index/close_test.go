@@ -0,0 +1,130 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package index
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/test/utils"
+)
+
+func TestCloseOpen(t *testing.T) {
+	indexName := "TestCloseOpen.index_1"
+
+	type args struct {
+		target string
+		data   string
+	}
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+		args    args
+		code    int
+		result  string
+	}{
+		{
+			name:    "close",
+			handler: Close,
+			args:    args{target: indexName},
+			code:    http.StatusOK,
+			result:  `"closed":true`,
+		},
+		{
+			name:    "close with wildcard",
+			handler: Close,
+			args:    args{target: "TestCloseOpen.*"},
+			code:    http.StatusOK,
+			result:  `"acknowledged":true`,
+		},
+		{
+			name:    "close not exists",
+			handler: Close,
+			args:    args{target: "TestCloseOpen.not_exists"},
+			code:    http.StatusNotFound,
+			result:  "does not exists",
+		},
+		{
+			name:    "set mapping of closed index",
+			handler: SetMapping,
+			args:    args{target: indexName, data: `{"properties":{"title":{"type":"text"}}}`},
+			code:    http.StatusOK,
+			result:  "ok",
+		},
+		{
+			name:    "open",
+			handler: Open,
+			args:    args{target: indexName},
+			code:    http.StatusOK,
+			result:  `"acknowledged":true`,
+		},
+		{
+			name:    "set read only",
+			handler: SetSettings,
+			args:    args{target: indexName, data: `{"index.blocks.read_only":true}`},
+			code:    http.StatusOK,
+			result:  "ok",
+		},
+		{
+			name:    "set mapping of read only index",
+			handler: SetMapping,
+			args:    args{target: indexName, data: `{"properties":{"content":{"type":"text"}}}`},
+			code:    http.StatusForbidden,
+			result:  "read-only",
+		},
+		{
+			name:    "set replicas of read only index",
+			handler: SetSettings,
+			args:    args{target: indexName, data: `{"number_of_replicas":2}`},
+			code:    http.StatusForbidden,
+			result:  "read-only",
+		},
+		{
+			name:    "unset read only",
+			handler: SetSettings,
+			args:    args{target: indexName, data: `{"index":{"blocks":{"read_only":false}}}`},
+			code:    http.StatusOK,
+			result:  "ok",
+		},
+	}
+
+	t.Run("prepare", func(t *testing.T) {
+		index, err := core.NewIndex(indexName, "disk", 2)
+		assert.NoError(t, err)
+		assert.NoError(t, core.StoreIndex(index))
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := utils.NewGinContext()
+			utils.SetGinRequestParams(c, map[string]string{"target": tt.args.target})
+			if tt.args.data != "" {
+				utils.SetGinRequestData(c, tt.args.data)
+			}
+			tt.handler(c)
+			assert.Equal(t, tt.code, w.Code)
+			assert.Contains(t, w.Body.String(), tt.result)
+		})
+	}
+
+	t.Run("cleanup", func(t *testing.T) {
+		assert.NoError(t, core.DeleteIndex(indexName))
+	})
+}
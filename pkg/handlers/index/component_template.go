@@ -0,0 +1,137 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package index
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/uquery/template"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+// @Id ListComponentTemplates
+// @Summary List component templates
+// @security BasicAuth
+// @Tags    Index
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} meta.HTTPResponseError
+// @Router /es/_component_template [get]
+func ListComponentTemplate(c *gin.Context) {
+	templates, err := core.ListComponentTemplates()
+	if err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, gin.H{"component_templates": templates})
+}
+
+// @Id GetComponentTemplate
+// @Summary Get component template
+// @security BasicAuth
+// @Tags    Index
+// @Produce json
+// @Param   name path  string  true  "Component template"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} meta.HTTPResponseError
+// @Failure 404 {object} meta.HTTPResponseError
+// @Router /es/_component_template/{name} [get]
+func GetComponentTemplate(c *gin.Context) {
+	name := c.Param("target")
+	if name == "" {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: "component_template.name should be not empty"})
+		return
+	}
+	tpl, exists, err := core.LoadComponentTemplate(name)
+	if err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	if !exists {
+		zutils.GinRenderJSON(c, http.StatusNotFound, meta.HTTPResponseError{Error: "component template " + name + " does not exists"})
+		return
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, gin.H{"component_templates": []meta.ComponentTemplate{{Name: name, ComponentTemplate: tpl}}})
+}
+
+// @Id CreateComponentTemplate
+// @Summary Create update component template
+// @security BasicAuth
+// @Tags    Index
+// @Accept  json
+// @Produce json
+// @Param   name     path string                     true "Component template"
+// @Param   template body meta.ComponentTemplateData true "Template data"
+// @Success 200 {object} meta.HTTPResponseTemplate
+// @Failure 400 {object} meta.HTTPResponseError
+// @Router /es/_component_template/{name} [put]
+func CreateComponentTemplate(c *gin.Context) {
+	name := c.Param("target")
+	if name == "" {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: "component_template.name should be not empty"})
+		return
+	}
+
+	data := make(map[string]interface{})
+	if err := zutils.GinBindJSON(c, &data); err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+
+	tpl, err := template.RequestComponent(data)
+	if err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+
+	if err = core.NewComponentTemplate(name, tpl); err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+
+	zutils.GinRenderJSON(c, http.StatusOK, meta.HTTPResponseTemplate{Message: "ok", Template: name})
+}
+
+// @Id DeleteComponentTemplate
+// @Summary Delete component template
+// @security BasicAuth
+// @Tags    Index
+// @Produce json
+// @Param   name  path  string  true  "Component template"
+// @Success 200 {object} meta.HTTPResponse
+// @Failure 400 {object} meta.HTTPResponseError
+// @Failure 404 {object} meta.HTTPResponseError
+// @Router /es/_component_template/{name} [delete]
+func DeleteComponentTemplate(c *gin.Context) {
+	name := c.Param("target")
+	if _, exists, err := core.LoadComponentTemplate(name); err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	} else if !exists {
+		zutils.GinRenderJSON(c, http.StatusNotFound, meta.HTTPResponseError{Error: "component template " + name + " does not exists"})
+		return
+	}
+	if err := core.DeleteComponentTemplate(name); err != nil {
+		zutils.GinRenderJSON(c, errors.HTTPStatus(err, http.StatusBadRequest), meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, meta.HTTPResponse{Message: "ok"})
+}
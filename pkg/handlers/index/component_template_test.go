@@ -0,0 +1,148 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package index
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/test/utils"
+)
+
+func TestComponentTemplate(t *testing.T) {
+	t.Run("create component template", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			target string
+			data   interface{}
+			code   int
+			result string
+		}{
+			{
+				name:   "normal",
+				target: "TestComponentTemplate.component_1",
+				data: map[string]interface{}{
+					"template": map[string]interface{}{
+						"settings": map[string]interface{}{"number_of_shards": 2},
+						"mappings": map[string]interface{}{
+							"properties": map[string]interface{}{"code": map[string]interface{}{"type": "keyword"}},
+						},
+						"aliases": map[string]interface{}{"TestComponentTemplate.alias": map[string]interface{}{}},
+					},
+					"version": 1,
+					"_meta":   map[string]interface{}{"managed": true},
+				},
+				code:   http.StatusOK,
+				result: `{"message":"ok"`,
+			},
+			{
+				name:   "empty name",
+				target: "",
+				data:   map[string]interface{}{},
+				code:   http.StatusBadRequest,
+				result: `should be not empty`,
+			},
+			{
+				name:   "without template",
+				target: "TestComponentTemplate.component_2",
+				data:   map[string]interface{}{"version": 1},
+				code:   http.StatusBadRequest,
+				result: `template should be an object`,
+			},
+			{
+				name:   "unknown option",
+				target: "TestComponentTemplate.component_2",
+				data:   map[string]interface{}{"template": map[string]interface{}{}, "priority": 1},
+				code:   http.StatusBadRequest,
+				result: `unknown option [priority]`,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				c, w := utils.NewGinContext()
+				utils.SetGinRequestData(c, tt.data)
+				utils.SetGinRequestParams(c, map[string]string{"target": tt.target})
+				CreateComponentTemplate(c)
+				assert.Equal(t, tt.code, w.Code)
+				assert.Contains(t, w.Body.String(), tt.result)
+			})
+		}
+	})
+
+	t.Run("get component template", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"target": "TestComponentTemplate.component_1"})
+		GetComponentTemplate(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"component_templates":[{"name":"TestComponentTemplate.component_1"`)
+
+		c, w = utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"target": "TestComponentTemplate.component_N"})
+		GetComponentTemplate(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		c, w = utils.NewGinContext()
+		ListComponentTemplate(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"name":"TestComponentTemplate.component_1"`)
+	})
+
+	t.Run("index template composed of", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestData(c, map[string]interface{}{
+			"index_patterns": []string{"TestComponentTemplate-*"},
+			"composed_of":    []string{"TestComponentTemplate.component_1"},
+			"priority":       10,
+		})
+		utils.SetGinRequestParams(c, map[string]string{"target": "TestComponentTemplate.template_1"})
+		CreateTemplate(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		c, w = utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"target": "TestComponentTemplate-2022"})
+		SimulateIndexTemplate(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"number_of_shards":2`)
+		assert.Contains(t, w.Body.String(), `"TestComponentTemplate.alias":{}`)
+
+		c, w = utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"target": "_invalid"})
+		SimulateIndexTemplate(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("delete component template", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"target": "TestComponentTemplate.component_1"})
+		DeleteComponentTemplate(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `still in use`)
+
+		assert.NoError(t, core.DeleteTemplate("TestComponentTemplate.template_1"))
+		c, w = utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"target": "TestComponentTemplate.component_1"})
+		DeleteComponentTemplate(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		c, w = utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"target": "TestComponentTemplate.component_1"})
+		DeleteComponentTemplate(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
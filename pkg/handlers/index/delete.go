@@ -17,7 +17,6 @@ package index
 
 import (
 	"net/http"
-	"regexp"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -68,23 +67,11 @@ func Delete(c *gin.Context) {
 	})
 }
 
+// deleteIndexWithWildcard deletes the indexes match the pattern, * matches any characters include none,
+// e.g. log-* deletes log- and log-2022, but not log
 func deleteIndexWithWildcard(indexName string, indexList []*core.Index) error {
-	parts := strings.Split(indexName, "*")
-	pattern := ""
-	for i, part := range parts {
-		pattern += part
-		if i < len(parts)-1 {
-			pattern += "[[:ascii:]]+"
-		}
-	}
-
-	p, err := regexp.Compile(pattern)
-	if err != nil {
-		return err
-	}
-
 	for _, i := range indexList {
-		if p.MatchString(i.GetName()) {
+		if core.MatchName(indexName, i.GetName()) {
 			if err := core.DeleteIndex(i.GetName()); err != nil {
 				return err
 			}
@@ -97,6 +97,45 @@ func TestDelete(t *testing.T) {
 	}
 }
 
+func TestDeleteWithWildcard(t *testing.T) {
+	prepareIndex(t, "TestDeleteWithWildcard.log", "disk")
+	prepareIndex(t, "TestDeleteWithWildcard.log-1", "disk")
+	prepareIndex(t, "TestDeleteWithWildcard.log-1-bak", "disk")
+	prepareIndex(t, "TestDeleteWithWildcard.app-log-1", "disk")
+	prepareIndex(t, "TestDeleteWithWildcard.lo", "disk")
+	defer func() {
+		for _, name := range []string{"TestDeleteWithWildcard.app-log-1", "TestDeleteWithWildcard.lo"} {
+			assert.NoError(t, core.DeleteIndex(name))
+		}
+	}()
+
+	exists := func(name string) bool {
+		_, ok := core.GetIndex(name)
+		return ok
+	}
+
+	t.Run("match middle", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"target": "TestDeleteWithWildcard.*-bak"})
+		Delete(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.False(t, exists("TestDeleteWithWildcard.log-1-bak"))
+		assert.True(t, exists("TestDeleteWithWildcard.log-1"))
+		assert.True(t, exists("TestDeleteWithWildcard.app-log-1"))
+	})
+
+	t.Run("match prefix include the prefix itself", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"target": "TestDeleteWithWildcard.log*"})
+		Delete(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.False(t, exists("TestDeleteWithWildcard.log"))
+		assert.False(t, exists("TestDeleteWithWildcard.log-1"))
+		assert.True(t, exists("TestDeleteWithWildcard.app-log-1"))
+		assert.True(t, exists("TestDeleteWithWildcard.lo"))
+	})
+}
+
 func prepareIndex(t *testing.T, name, storageType string) {
 	index, err := core.NewIndex(name, storageType, 2)
 	assert.NoError(t, err)
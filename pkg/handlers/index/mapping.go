@@ -18,9 +18,11 @@ package index
 import (
 	"net/http"
 
+	"github.com/blugelabs/bluge/analysis"
 	"github.com/gin-gonic/gin"
 
 	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/errors"
 	"github.com/zincsearch/zincsearch/pkg/meta"
 	"github.com/zincsearch/zincsearch/pkg/uquery/mappings"
 	"github.com/zincsearch/zincsearch/pkg/zutils"
@@ -59,6 +61,7 @@ func GetMapping(c *gin.Context) {
 // @Param   mapping body  meta.Mappings true  "Mapping"
 // @Success 200 {object} meta.HTTPResponse
 // @Failure 400 {object} meta.HTTPResponseError
+// @Failure 403 {object} meta.HTTPResponseError
 // @Failure 500 {object} meta.HTTPResponseError
 // @Router /api/{index}/_mapping [put]
 func SetMapping(c *gin.Context) {
@@ -74,7 +77,12 @@ func SetMapping(c *gin.Context) {
 		return
 	}
 
-	mappings, err := mappings.Request(nil, mappingRequest)
+	// custom analyzers and normalizers of exists index can be used in mappings
+	var analyzers map[string]*analysis.Analyzer
+	if index, ok := core.GetIndex(indexName); ok {
+		analyzers = index.GetAnalyzers()
+	}
+	mappings, err := mappings.Request(analyzers, mappingRequest)
 	if err != nil {
 		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
 		return
@@ -86,26 +94,8 @@ func SetMapping(c *gin.Context) {
 		return
 	}
 
-	// check if mapping field is exists
-	if exists {
-		indexMappings := index.GetMappings()
-		if indexMappings != nil && indexMappings.Len() > 0 {
-			for field := range mappings.ListProperty() {
-				if _, ok := indexMappings.GetProperty(field); ok {
-					zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: "index [" + indexName + "] already exists mapping of field [" + field + "]"})
-					return
-				}
-			}
-		}
-		// add mappings
-		for field, prop := range mappings.ListProperty() {
-			indexMappings.SetProperty(field, prop)
-		}
-		mappings = indexMappings
-	}
-
 	// update mappings
-	if mappings != nil && mappings.Len() > 0 {
+	if mappings != nil {
 		for k, v := range mappings.Properties {
 			if v.Fields == nil {
 				continue
@@ -124,6 +114,23 @@ func SetMapping(c *gin.Context) {
 				mappings.Properties[k] = v
 			}
 		}
+	}
+
+	// the changes of exists index are validated and the new fields are applied to the exists documents
+	if exists {
+		task, err := index.UpdateMappings(mappings)
+		if err != nil {
+			zutils.GinRenderJSON(c, errors.HTTPStatus(err, http.StatusBadRequest), meta.HTTPResponseError{Error: err.Error()})
+			return
+		}
+		if task != nil {
+			zutils.GinRenderJSON(c, http.StatusOK, gin.H{"message": "ok", "task": task.GetID()})
+			return
+		}
+		zutils.GinRenderJSON(c, http.StatusOK, meta.HTTPResponse{Message: "ok"})
+		return
+	}
+	if mappings != nil {
 		_ = index.SetMappings(mappings)
 	}
 
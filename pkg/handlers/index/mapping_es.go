@@ -139,6 +139,33 @@ func convertToESProperty(p meta.Property) elastic.Property {
 	prop.Analyzer = p.Analyzer
 	prop.SearchAnalyzer = p.SearchAnalyzer
 	prop.Format = p.Format
+	prop.Normalizer = p.Normalizer
+	prop.IgnoreAbove = uint(p.IgnoreAbove)
+	prop.NullValue = p.NullValue
+	prop.CopyTo = p.CopyTo
+	if p.NumericType != "" {
+		prop.Type = p.NumericType
+		prop.ScalingFactor = p.ScalingFactor
+	}
+	prop.MaxInputLength = p.MaxInputLength
+	for _, context := range p.Contexts {
+		c := map[string]string{"name": context.Name, "type": context.Type}
+		if context.Path != "" {
+			c["path"] = context.Path
+		}
+		prop.Contexts = append(prop.Contexts, c)
+	}
+	prop.Dims = p.Dims
+	prop.Similarity = p.Similarity
+	if p.IndexOptions != nil {
+		prop.IndexOptions = map[string]interface{}{"type": p.IndexOptions.Type}
+		if p.IndexOptions.M > 0 {
+			prop.IndexOptions["m"] = p.IndexOptions.M
+		}
+		if p.IndexOptions.EfConstruction > 0 {
+			prop.IndexOptions["ef_construction"] = p.IndexOptions.EfConstruction
+		}
+	}
 
 	if p.Fields != nil {
 		for k, v := range p.Fields {
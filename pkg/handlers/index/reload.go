@@ -0,0 +1,69 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package index
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	zincanalysis "github.com/zincsearch/zincsearch/pkg/uquery/analysis"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+// ReloadSearchAnalyzers reloads the dictionaries of the analyzers used by the indexes, e.g. the gse user dictionary
+//
+// @Id ReloadSearchAnalyzers
+// @Summary Reload search analyzers
+// @security BasicAuth
+// @Tags    Index
+// @Produce json
+// @Param   index  path  string  true  "Index"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} meta.HTTPResponseError
+// @Failure 404 {object} meta.HTTPResponseError
+// @Router /api/{index}/_reload_search_analyzers [post]
+func ReloadSearchAnalyzers(c *gin.Context) {
+	names, err := resolveIndexNames(c.Param("target"))
+	if err != nil {
+		zutils.GinRenderJSON(c, errors.HTTPStatus(err, http.StatusBadRequest), meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+
+	var shardNum int64
+	details := make([]gin.H, 0, len(names))
+	for _, name := range names {
+		index, exists := core.GetIndex(name)
+		if !exists {
+			continue
+		}
+		reloaded, err := zincanalysis.ReloadAnalyzers(index.GetAnalyzers(), index.GetMappings())
+		if err != nil {
+			zutils.GinRenderJSON(c, errors.HTTPStatus(err, http.StatusBadRequest), meta.HTTPResponseError{Error: err.Error()})
+			return
+		}
+		shardNum += index.GetShardNum()
+		details = append(details, gin.H{"index": name, "reloaded_analyzers": reloaded})
+	}
+
+	zutils.GinRenderJSON(c, http.StatusOK, gin.H{
+		"_shards":        gin.H{"total": shardNum, "successful": shardNum, "failed": 0},
+		"reload_details": details,
+	})
+}
@@ -0,0 +1,96 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package index
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/test/utils"
+)
+
+func TestReloadSearchAnalyzers(t *testing.T) {
+	indexName := "TestReloadSearchAnalyzers.index_1"
+	dictPath := config.Global.Plugin.GSE.DictPath
+	config.Global.Plugin.GSE.DictPath = t.TempDir()
+	defer func() {
+		config.Global.Plugin.GSE.DictPath = dictPath
+	}()
+	userDict := filepath.Join(config.Global.Plugin.GSE.DictPath, "products.txt")
+
+	analyze := func(t *testing.T) string {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestData(c, `{"analyzer":"my_chs","text":"复仇者联盟"}`)
+		utils.SetGinRequestParams(c, map[string]string{"target": indexName})
+		Analyze(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		tokens, err := getTokenStrings(w.Body.Bytes())
+		assert.NoError(t, err)
+		return tokens
+	}
+
+	t.Run("prepare", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(userDict, []byte("灭霸 100 n\n"), 0644))
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestData(c, `{
+			"name":"`+indexName+`",
+			"settings":{"analysis":{
+				"tokenizer":{"my_gse":{"type":"gse_standard","user_dictionary":"products.txt"}},
+				"analyzer":{"my_chs":{"type":"custom","tokenizer":"my_gse"}}
+			}},
+			"mappings":{"properties":{"title":{"type":"text","analyzer":"my_chs"}}}
+		}`)
+		Create(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "[复 仇 者 联 盟]", analyze(t))
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(userDict, []byte("灭霸 100 n\n复仇者联盟 100 n\n"), 0644))
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"target": indexName})
+		ReloadSearchAnalyzers(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"reloaded_analyzers":["my_chs"]`)
+		assert.Equal(t, "[复仇者联盟]", analyze(t))
+	})
+
+	t.Run("reload with missing dictionary", func(t *testing.T) {
+		assert.NoError(t, os.Remove(userDict))
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"target": indexName})
+		ReloadSearchAnalyzers(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "[复仇者联盟]", analyze(t))
+	})
+
+	t.Run("reload not exists index", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"target": "TestReloadSearchAnalyzers.not_exists"})
+		ReloadSearchAnalyzers(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		assert.NoError(t, core.DeleteIndex(indexName))
+	})
+}
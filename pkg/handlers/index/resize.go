@@ -0,0 +1,108 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package index
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+// Split creates a new index with more first layer shards and copies the documents
+//
+// @Id SplitIndex
+// @Summary Split index for compatible ES
+// @security BasicAuth
+// @Tags    Index
+// @Accept  json
+// @Produce json
+// @Param   index         path  string                   true  "Index"
+// @Param   target_index  path  string                   true  "Target index"
+// @Param   data          body  meta.IndexResizeRequest  true  "Resize data"
+// @Success 200 {object} meta.IndexResizeResponse
+// @Failure 400 {object} meta.HTTPResponseError
+// @Router /es/{index}/_split/{target_index} [post]
+func Split(c *gin.Context) {
+	resize(c, meta.IndexResizeTypeSplit)
+}
+
+// Shrink creates a new index with less first layer shards and copies the documents
+//
+// @Id ShrinkIndex
+// @Summary Shrink index for compatible ES
+// @security BasicAuth
+// @Tags    Index
+// @Accept  json
+// @Produce json
+// @Param   index         path  string                   true  "Index"
+// @Param   target_index  path  string                   true  "Target index"
+// @Param   data          body  meta.IndexResizeRequest  true  "Resize data"
+// @Success 200 {object} meta.IndexResizeResponse
+// @Failure 400 {object} meta.HTTPResponseError
+// @Router /es/{index}/_shrink/{target_index} [post]
+func Shrink(c *gin.Context) {
+	resize(c, meta.IndexResizeTypeShrink)
+}
+
+// Clone creates a new index with the same first layer shards and copies the documents
+//
+// @Id CloneIndex
+// @Summary Clone index for compatible ES
+// @security BasicAuth
+// @Tags    Index
+// @Accept  json
+// @Produce json
+// @Param   index         path  string                   true  "Index"
+// @Param   target_index  path  string                   true  "Target index"
+// @Param   data          body  meta.IndexResizeRequest  false "Resize data"
+// @Success 200 {object} meta.IndexResizeResponse
+// @Failure 400 {object} meta.HTTPResponseError
+// @Router /es/{index}/_clone/{target_index} [post]
+func Clone(c *gin.Context) {
+	resize(c, meta.IndexResizeTypeClone)
+}
+
+// resize runs the resize task, it returns the task id if wait_for_completion is false
+func resize(c *gin.Context, typ string) {
+	req := new(meta.IndexResizeRequest)
+	if err := zutils.GinBindOptionalJSON(c, req); err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+
+	task, err := core.ResizeIndex(typ, c.Param("target"), c.Param("target_index"), req)
+	if err != nil {
+		zutils.GinRenderJSON(c, errors.HTTPStatus(err, http.StatusBadRequest), meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	if c.Query("wait_for_completion") == "false" {
+		zutils.GinRenderJSON(c, http.StatusOK, gin.H{"task": task.GetID()})
+		return
+	}
+
+	task.Wait()
+	result := task.GetResult()
+	if result.Error != nil {
+		zutils.GinRenderJSON(c, http.StatusInternalServerError, gin.H{"error": result.Error})
+		return
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, result.Response)
+}
@@ -0,0 +1,112 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package index
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/test/utils"
+)
+
+func TestResize(t *testing.T) {
+	indexName := "TestResize.index_1"
+
+	type args struct {
+		target string
+		query  map[string]string
+		data   string
+	}
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+		args    args
+		code    int
+		result  string
+	}{
+		{
+			name:    "split",
+			handler: Split,
+			args:    args{target: "TestResize.index_2", data: `{"settings":{"index.number_of_shards":4}}`},
+			code:    http.StatusOK,
+			result:  `"docs":2`,
+		},
+		{
+			name:    "split with less shards",
+			handler: Split,
+			args:    args{target: "TestResize.index_3", data: `{"settings":{"index.number_of_shards":1}}`},
+			code:    http.StatusBadRequest,
+			result:  "must be greater",
+		},
+		{
+			name:    "shrink",
+			handler: Shrink,
+			args:    args{target: "TestResize.index_3", data: `{"settings":{"index":{"number_of_shards":1}},"aliases":{"TestResize.alias":{}}}`},
+			code:    http.StatusOK,
+			result:  `"aliases":["TestResize.alias"]`,
+		},
+		{
+			name:    "clone in background",
+			handler: Clone,
+			args:    args{target: "TestResize.index_4", query: map[string]string{"wait_for_completion": "false"}},
+			code:    http.StatusOK,
+			result:  `"task":`,
+		},
+		{
+			name:    "clone to exists index",
+			handler: Clone,
+			args:    args{target: "TestResize.index_2"},
+			code:    http.StatusBadRequest,
+			result:  "already exists",
+		},
+	}
+
+	t.Run("prepare", func(t *testing.T) {
+		index, err := core.NewIndex(indexName, "disk", 2)
+		assert.NoError(t, err)
+		assert.NoError(t, core.StoreIndex(index))
+		assert.NoError(t, index.CreateDocument("1", map[string]interface{}{"name": "Prabhat"}, false))
+		assert.NoError(t, index.CreateDocument("2", map[string]interface{}{"name": "Hengfei"}, false))
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := utils.NewGinContext()
+			utils.SetGinRequestParams(c, map[string]string{"target": indexName, "target_index": tt.args.target})
+			utils.SetGinRequestURL(c, "/es/"+indexName+"/_resize", tt.args.query)
+			if tt.args.data != "" {
+				utils.SetGinRequestData(c, tt.args.data)
+			}
+			tt.handler(c)
+			assert.Equal(t, tt.code, w.Code)
+			assert.Contains(t, w.Body.String(), tt.result)
+		})
+	}
+
+	t.Run("cleanup", func(t *testing.T) {
+		for _, task := range core.ZINC_TASK_LIST.List("indices:admin/resize/*") {
+			task.Wait()
+		}
+		assert.NoError(t, core.ZINC_INDEX_ALIAS_LIST.RemoveIndexesFromAlias("TestResize.alias", []string{"TestResize.index_3"}))
+		for _, name := range []string{indexName, "TestResize.index_2", "TestResize.index_3", "TestResize.index_4"} {
+			assert.NoError(t, core.DeleteIndex(name))
+		}
+	})
+}
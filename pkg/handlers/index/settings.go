@@ -23,6 +23,7 @@ import (
 
 	"github.com/zincsearch/zincsearch/pkg/config"
 	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/errors"
 	"github.com/zincsearch/zincsearch/pkg/meta"
 	zincanalysis "github.com/zincsearch/zincsearch/pkg/uquery/analysis"
 	"github.com/zincsearch/zincsearch/pkg/zutils"
@@ -63,6 +64,7 @@ func GetSettings(c *gin.Context) {
 // @Param   settings body  meta.IndexSettings true  "Settings"
 // @Success 200 {object} meta.HTTPResponse
 // @Failure 400 {object} meta.HTTPResponseError
+// @Failure 403 {object} meta.HTTPResponseError
 // @Failure 500 {object} meta.HTTPResponseError
 // @Router /api/{index}/_settings [put]
 func SetSettings(c *gin.Context) {
@@ -99,12 +101,22 @@ func SetSettings(c *gin.Context) {
 		return
 	}
 	if exists {
-		// it can only change settings.NumberOfReplicas when index exists
+		// read only index can only change the blocks
+		if settings.NumberOfReplicas > 0 || settings.Analysis != nil {
+			if err := index.CheckMetadataWrite(); err != nil {
+				c.JSON(errors.HTTPStatus(err, http.StatusBadRequest), meta.HTTPResponseError{Error: err.Error()})
+				return
+			}
+		}
+		// it can only change settings.NumberOfReplicas and settings.Blocks when index exists
+		if settings.Blocks != nil {
+			_ = index.SetSettings(&meta.IndexSettings{Blocks: settings.Blocks})
+		}
 		if settings.NumberOfReplicas > 0 {
 			indexSettings := index.GetSettings()
 			atomic.StoreInt64(&indexSettings.NumberOfReplicas, settings.NumberOfReplicas)
 		}
-		if settings.Analysis != nil && len(settings.Analysis.Analyzer) > 0 {
+		if settings.Analysis != nil && (len(settings.Analysis.Analyzer) > 0 || len(settings.Analysis.Normalizer) > 0) {
 			c.JSON(http.StatusBadRequest, meta.HTTPResponseError{Error: "can't update analyzer for existing index"})
 			return
 		}
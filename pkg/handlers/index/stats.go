@@ -0,0 +1,90 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package index
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+// @Id ESStats
+// @Summary Get index stats for compatible ES
+// @security BasicAuth
+// @Tags    Index
+// @Produce json
+// @Param   index  path   string  false  "Index"
+// @Param   level  query  string  false  "Level, indices or shards"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} meta.HTTPResponseError
+// @Router /es/{index}/_stats [get]
+func Stats(c *gin.Context) {
+	target := c.Param("target")
+	withShards := c.Query("level") == "shards"
+
+	indexes, err := core.MatchIndexes(target)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	var shardNum int64
+	all := new(meta.IndexStats)
+	indices := make(map[string]gin.H, len(indexes))
+	for _, index := range indexes {
+		stats := index.GetIndexStats(withShards)
+		all.Add(stats)
+		shardNum += index.GetShardNum()
+
+		shards := stats.Shards
+		stats.Shards = nil
+		item := gin.H{"primaries": stats, "total": stats}
+		if withShards {
+			item["shards"] = shards
+		}
+		indices[index.GetName()] = item
+	}
+
+	zutils.GinRenderJSON(c, http.StatusOK, gin.H{
+		"_shards": gin.H{"total": shardNum, "successful": shardNum, "failed": 0},
+		"_all":    gin.H{"primaries": all, "total": all},
+		"indices": indices,
+	})
+}
+
+// @Id ESNodesStats
+// @Summary Get node stats for compatible ES
+// @security BasicAuth
+// @Tags    Index
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /es/_nodes/stats [get]
+func NodesStats(c *gin.Context) {
+	zutils.GinRenderJSON(c, http.StatusOK, gin.H{
+		"_nodes":       gin.H{"total": 1, "successful": 1, "failed": 0},
+		"cluster_name": config.Global.Cluster.Name,
+		"nodes": gin.H{
+			strconv.Itoa(config.Global.NodeID): core.GetNodeStats(),
+		},
+	})
+}
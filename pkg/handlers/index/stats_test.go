@@ -0,0 +1,137 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package index
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+	"github.com/zincsearch/zincsearch/test/utils"
+)
+
+func TestStats(t *testing.T) {
+	type args struct {
+		code   int
+		params map[string]string
+		query  map[string]string
+		result string
+	}
+	tests := []struct {
+		name   string
+		args   args
+		shards bool
+	}{
+		{
+			name: "all",
+			args: args{
+				code:   http.StatusOK,
+				params: map[string]string{"target": ""},
+				result: "TestStats.index_1",
+			},
+		},
+		{
+			name: "index",
+			args: args{
+				code:   http.StatusOK,
+				params: map[string]string{"target": "TestStats.index_1"},
+				result: `"indexing":{"index_total":1`,
+			},
+		},
+		{
+			name: "wildcard",
+			args: args{
+				code:   http.StatusOK,
+				params: map[string]string{"target": "TestStats.*"},
+				result: "TestStats.index_1",
+			},
+		},
+		{
+			name: "level shards",
+			args: args{
+				code:   http.StatusOK,
+				params: map[string]string{"target": "TestStats.index_1"},
+				query:  map[string]string{"level": "shards"},
+				result: `"shards":{`,
+			},
+			shards: true,
+		},
+		{
+			name: "not exists",
+			args: args{
+				code:   http.StatusNotFound,
+				params: map[string]string{"target": "TestStats.index_2"},
+				result: "does not exists",
+			},
+		},
+	}
+
+	t.Run("prepare", func(t *testing.T) {
+		index, err := core.NewIndex("TestStats.index_1", "disk", 2)
+		assert.NoError(t, err)
+		assert.NotNil(t, index)
+
+		err = core.StoreIndex(index)
+		assert.NoError(t, err)
+
+		err = index.CreateDocument("1", map[string]interface{}{"name": "Prabhat"}, false)
+		assert.NoError(t, err)
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := utils.NewGinContext()
+			utils.SetGinRequestParams(c, tt.args.params)
+			utils.SetGinRequestURL(c, "/es/"+tt.args.params["target"]+"/_stats", tt.args.query)
+			Stats(c)
+			assert.Equal(t, tt.args.code, w.Code)
+			assert.Contains(t, w.Body.String(), tt.args.result)
+
+			resp := make(map[string]interface{})
+			err := json.Unmarshal(w.Body.Bytes(), &resp)
+			assert.NoError(t, err)
+			if tt.args.code != http.StatusOK {
+				return
+			}
+			indices := resp["indices"].(map[string]interface{})
+			item, ok := indices["TestStats.index_1"].(map[string]interface{})
+			assert.True(t, ok)
+			_, ok = item["shards"]
+			assert.Equal(t, tt.shards, ok)
+		})
+	}
+
+	t.Run("cleanup", func(t *testing.T) {
+		err := core.DeleteIndex("TestStats.index_1")
+		assert.NoError(t, err)
+	})
+}
+
+func TestNodesStats(t *testing.T) {
+	c, w := utils.NewGinContext()
+	NodesStats(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	resp := make(map[string]interface{})
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Contains(t, resp, "cluster_name")
+	nodes := resp["nodes"].(map[string]interface{})
+	assert.Len(t, nodes, 1)
+}
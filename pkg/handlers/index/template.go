@@ -145,3 +145,26 @@ func DeleteTemplate(c *gin.Context) {
 	}
 	zutils.GinRenderJSON(c, http.StatusOK, meta.HTTPResponse{Message: "ok"})
 }
+
+// @Id SimulateIndexTemplate
+// @Summary Simulate the index template for a new index
+// @security BasicAuth
+// @Tags    Index
+// @Produce json
+// @Param   name  path  string  true  "Index"
+// @Success 200 {object} meta.IndexTemplateSimulateResponse
+// @Failure 400 {object} meta.HTTPResponseError
+// @Router /es/_index_template/_simulate_index/{name} [post]
+func SimulateIndexTemplate(c *gin.Context) {
+	name := c.Param("target")
+	if err := core.CheckIndexName(name); err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	resp, err := core.SimulateIndexTemplate(name)
+	if err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, resp)
+}
@@ -0,0 +1,141 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package search
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+// SubmitAsyncSearch starts the search in background, it returns the partial response if the search
+// isn't completed in wait_for_completion_timeout, the response can be retrieved by the id later.
+//
+// @Id SubmitAsyncSearch
+// @Summary Submit async search for compatible ES
+// @security BasicAuth
+// @Tags    Search
+// @Accept  json
+// @Produce json
+// @Param   index                        path   string  true   "Index"
+// @Param   wait_for_completion_timeout  query  string  false  "Wait for the search completed, default 1s"
+// @Param   keep_alive                   query  string  false  "Keep the search available, default 5d"
+// @Param   keep_on_completion           query  bool    false  "Keep the search completed in wait_for_completion_timeout"
+// @Param   query  body  meta.ZincQueryForSDK true  "Query"
+// @Success 200 {object} meta.AsyncSearchResponse
+// @Failure 400 {object} meta.HTTPResponseError
+// @Router /es/{index}/_async_search [post]
+func SubmitAsyncSearch(c *gin.Context) {
+	wait, err := durationQuery(c, "wait_for_completion_timeout", time.Second)
+	if err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	keepAlive, err := durationQuery(c, "keep_alive", time.Hour*24*5)
+	if err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	if keepAlive <= 0 {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: "[keep_alive] should be greater than 0"})
+		return
+	}
+
+	query := &meta.ZincQuery{Size: 10}
+	if err = zutils.GinBindJSON(c, query); err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+
+	var indexNames []string
+	if indexName := c.Param("target"); indexName != "" {
+		indexNames = strings.Split(indexName, ",")
+	}
+	resp, err := core.SubmitAsyncSearch(indexNames, query, wait, keepAlive, c.Query("keep_on_completion") == "true")
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, resp)
+}
+
+// @Id GetAsyncSearch
+// @Summary Get async search for compatible ES
+// @security BasicAuth
+// @Tags    Search
+// @Produce json
+// @Param   id                           path   string  true   "Async search ID"
+// @Param   wait_for_completion_timeout  query  string  false  "Wait for the search completed"
+// @Param   keep_alive                   query  string  false  "Extend the expiration time"
+// @Success 200 {object} meta.AsyncSearchResponse
+// @Failure 404 {object} map[string]interface{}
+// @Router /es/_async_search/{id} [get]
+func GetAsyncSearch(c *gin.Context) {
+	wait, err := durationQuery(c, "wait_for_completion_timeout", 0)
+	if err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	keepAlive, err := durationQuery(c, "keep_alive", 0)
+	if err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	resp, err := core.GetAsyncSearch(c.Param("id"), wait, keepAlive)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, resp)
+}
+
+// @Id DeleteAsyncSearch
+// @Summary Delete async search for compatible ES
+// @security BasicAuth
+// @Tags    Search
+// @Produce json
+// @Param   id  path  string  true  "Async search ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /es/_async_search/{id} [delete]
+func DeleteAsyncSearch(c *gin.Context) {
+	if err := core.DeleteAsyncSearch(c.Param("id")); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, gin.H{"acknowledged": true})
+}
+
+// durationQuery parses the time value of query parameter like 1s or 5d, it returns def if the parameter is absent
+func durationQuery(c *gin.Context, name string, def time.Duration) (time.Duration, error) {
+	v := c.Query(name)
+	if v == "" {
+		return def, nil
+	}
+	d, err := zutils.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse [%s] with value [%s]", name, v)
+	}
+	return d, nil
+}
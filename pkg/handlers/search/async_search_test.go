@@ -0,0 +1,161 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package search
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+	"github.com/zincsearch/zincsearch/test/utils"
+)
+
+func TestAsyncSearch(t *testing.T) {
+	indexName := "TestAsyncSearch.index_1"
+	var id string
+
+	t.Run("prepare", func(t *testing.T) {
+		index, err := core.NewIndex(indexName, "disk", 2)
+		assert.NoError(t, err)
+		assert.NotNil(t, index)
+		err = core.StoreIndex(index)
+		assert.NoError(t, err)
+		mappings := meta.NewMappings()
+		mappings.SetProperty("name", meta.NewProperty("text"))
+		mappings.SetProperty("tag", meta.NewProperty("keyword"))
+		require.NoError(t, index.SetMappings(mappings))
+		for id, doc := range map[string]map[string]interface{}{
+			"1": {"name": "zinc search", "tag": "go"},
+			"2": {"name": "elastic search", "tag": "java"},
+			"3": {"name": "bluge", "tag": "go"},
+		} {
+			assert.NoError(t, index.CreateDocument(id, doc, false))
+		}
+		// wait for WAL write to index
+		assert.Eventually(t, func() bool {
+			resp, err := core.MultiSearch([]string{indexName}, &meta.ZincQuery{Size: 10})
+			return err == nil && resp.Hits.Total.Value == 3
+		}, 10*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("submit", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			params map[string]string
+			data   string
+			code   int
+			result string
+			stored bool
+		}{
+			{
+				name:   "keep on completion",
+				params: map[string]string{"wait_for_completion_timeout": "10s", "keep_on_completion": "true"},
+				data:   `{"query":{"match":{"name":"search"}},"aggs":{"tags":{"terms":{"field":"tag"}}}}`,
+				code:   http.StatusOK,
+				result: `"total":{"value":2}`,
+				stored: true,
+			},
+			{
+				name:   "completed without keeping",
+				params: map[string]string{"wait_for_completion_timeout": "10s"},
+				data:   `{"query":{"term":{"tag":"go"}}}`,
+				code:   http.StatusOK,
+				result: `"total":{"value":2}`,
+			},
+			{
+				name:   "invalid keep alive",
+				params: map[string]string{"keep_alive": "x"},
+				data:   `{}`,
+				code:   http.StatusBadRequest,
+				result: "failed to parse [keep_alive]",
+			},
+			{
+				name:   "invalid query",
+				params: map[string]string{"wait_for_completion_timeout": "10s"},
+				data:   `{"query":{"unknown":{}}}`,
+				code:   http.StatusBadRequest,
+				result: "unknown",
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				c, w := utils.NewGinContext()
+				utils.SetGinRequestData(c, tt.data)
+				utils.SetGinRequestParams(c, map[string]string{"target": indexName})
+				utils.SetGinRequestURL(c, "/es/"+indexName+"/_async_search", tt.params)
+				SubmitAsyncSearch(c)
+				assert.Equal(t, tt.code, w.Code)
+				assert.Contains(t, w.Body.String(), tt.result)
+				if tt.code != http.StatusOK {
+					return
+				}
+				resp := new(meta.AsyncSearchResponse)
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), resp))
+				assert.False(t, resp.IsRunning)
+				assert.False(t, resp.IsPartial)
+				assert.Equal(t, tt.stored, resp.ID != "")
+				if tt.stored {
+					id = resp.ID
+				}
+			})
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		require.NotEmpty(t, id, "no async search was stored by submit")
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"id": id})
+		utils.SetGinRequestURL(c, "/es/_async_search/"+id, map[string]string{"keep_alive": "1m"})
+		GetAsyncSearch(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":"`+id+`"`)
+		assert.Contains(t, w.Body.String(), `"key":"go"`)
+
+		c, w = utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"id": "not_exists"})
+		GetAsyncSearch(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NotEmpty(t, id, "no async search was stored by submit")
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"id": id})
+		DeleteAsyncSearch(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		c, w = utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"id": id})
+		GetAsyncSearch(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		c, w = utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"id": id})
+		DeleteAsyncSearch(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		err := core.DeleteIndex(indexName)
+		assert.NoError(t, err)
+	})
+}
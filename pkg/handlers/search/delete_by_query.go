@@ -25,6 +25,7 @@ import (
 // @Param   query  body  meta.ZincQueryForSDK true  "Query"
 // @Success 200 {object} meta.HTTPResponseDeleteByQuery
 // @Failure 400 {object} meta.HTTPResponseError
+// @Failure 403 {object} meta.HTTPResponseError
 // @Router /es/{index}/_delete_by_query [post]
 func DeleteByQuery(c *gin.Context) {
 	start := time.Now()
@@ -36,6 +37,12 @@ func DeleteByQuery(c *gin.Context) {
 	}
 
 	indexName := c.Param("target")
+	if index, ok := core.GetIndex(indexName); ok {
+		if err := index.CheckWrite(); err != nil {
+			errors.HandleError(c, err)
+			return
+		}
+	}
 	resp, err := searchIndex([]string{indexName}, query)
 	if err != nil {
 		errors.HandleError(c, err)
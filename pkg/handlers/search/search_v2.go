@@ -92,6 +92,17 @@ func SearchDSL(c *gin.Context) {
 // @Failure 400 {object} meta.HTTPResponseError
 // @Router /es/_msearch [post]
 func MultipleSearch(c *gin.Context) {
+	multipleSearch(c, func(indexNames []string, data []byte) (*meta.SearchResponse, error) {
+		query := &meta.ZincQuery{Size: 10}
+		if err := json.UnmarshalNumber(data, &query); err != nil {
+			return nil, err
+		}
+		return searchIndex(indexNames, query)
+	})
+}
+
+// multipleSearch reads the header and body lines of request, the body is searched by fn with the index names of header
+func multipleSearch(c *gin.Context, fn func(indexNames []string, data []byte) (*meta.SearchResponse, error)) {
 	indexName := c.Param("target")
 	defaultIndexNames := make([]string, 0)
 	if indexName != "" {
@@ -116,16 +127,10 @@ func MultipleSearch(c *gin.Context) {
 	for scanner.Scan() { // Read each line
 		if nextLineIsData {
 			nextLineIsData = false
-			query := &meta.ZincQuery{Size: 10}
-			if err = json.Unmarshal(scanner.Bytes(), &query); err != nil {
-				log.Error().Msgf("handlers.search.MultipleSearch.json.Unmarshal: %s, err %s", scanner.Text(), err.Error())
-				responses = append(responses, &meta.SearchResponse{Error: err.Error()})
-				continue
-			}
 			// search query
-			resp, err := searchIndex(indexNames, query)
+			resp, err := fn(indexNames, scanner.Bytes())
 			if err != nil {
-				log.Error().Msgf("handlers.search.MultipleSearch.searchIndex: err %s", err.Error())
+				log.Error().Msgf("handlers.search.MultipleSearch: %s, err %s", scanner.Text(), err.Error())
 				responses = append(responses, &meta.SearchResponse{Error: err.Error()})
 			} else {
 				responses = append(responses, resp)
@@ -0,0 +1,230 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package search
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+)
+
+// @Id PutScript
+// @Summary Create or update stored search template
+// @security BasicAuth
+// @Tags    Search
+// @Accept  json
+// @Produce json
+// @Param   id     path  string  true  "Script id"
+// @Param   script body  map[string]interface{} true  "Script"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} meta.HTTPResponseError
+// @Router /es/_scripts/{id} [put]
+func PutScript(c *gin.Context) {
+	id := c.Param("id")
+	var data struct {
+		Script *struct {
+			Lang    string            `json:"lang"`
+			Source  interface{}       `json:"source"`
+			Options map[string]string `json:"options"`
+		} `json:"script"`
+	}
+	if err := zutils.GinBindJSON(c, &data); err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	if data.Script == nil || data.Script.Source == nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: "must specify [script] with [source]"})
+		return
+	}
+	source, err := core.ScriptSource(data.Script.Source)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	script := &meta.StoredScript{Lang: data.Script.Lang, Source: source, Options: data.Script.Options}
+	if err = core.NewStoredScript(id, script); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, gin.H{"acknowledged": true})
+}
+
+// @Id GetScript
+// @Summary Get stored search template
+// @security BasicAuth
+// @Tags    Search
+// @Produce json
+// @Param   id  path  string  true  "Script id"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /es/_scripts/{id} [get]
+func GetScript(c *gin.Context) {
+	id := c.Param("id")
+	script, exists, err := core.LoadStoredScript(id)
+	if err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	if !exists {
+		zutils.GinRenderJSON(c, http.StatusNotFound, gin.H{"_id": id, "found": false})
+		return
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, gin.H{"_id": id, "found": true, "script": script})
+}
+
+// @Id DeleteScript
+// @Summary Delete stored search template
+// @security BasicAuth
+// @Tags    Search
+// @Produce json
+// @Param   id  path  string  true  "Script id"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} meta.HTTPResponseError
+// @Router /es/_scripts/{id} [delete]
+func DeleteScript(c *gin.Context) {
+	id := c.Param("id")
+	if _, exists, err := core.LoadStoredScript(id); err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	} else if !exists {
+		zutils.GinRenderJSON(c, http.StatusNotFound, meta.HTTPResponseError{Error: "stored script [" + id + "] does not exist"})
+		return
+	}
+	if err := core.DeleteStoredScript(id); err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, gin.H{"acknowledged": true})
+}
+
+// SearchTemplate renders the search template with params and searches the index with the rendered query
+//
+// @Id SearchTemplate
+// @Summary Search with search template
+// @security BasicAuth
+// @Tags    Search
+// @Accept  json
+// @Produce json
+// @Param   index  path  string  true  "Index"
+// @Param   template body meta.SearchTemplateRequest true "Template"
+// @Success 200 {object} meta.SearchResponse
+// @Failure 400 {object} meta.HTTPResponseError
+// @Router /es/{index}/_search/template [post]
+func SearchTemplate(c *gin.Context) {
+	indexName := c.Param("target")
+
+	req := new(meta.SearchTemplateRequest)
+	if err := zutils.GinBindJSON(c, req); err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	query, err := templateQuery(req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	resp, err := searchIndex(strings.Split(indexName, ","), query)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, resp)
+}
+
+// MultipleSearchTemplate like bulk searches with search templates
+//
+// @Id MSearchTemplate
+// @Summary Multiple search with search templates
+// @security BasicAuth
+// @Tags    Search
+// @Accept  plain
+// @Produce json
+// @Param   query  body  string  true  "Query"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} meta.HTTPResponseError
+// @Router /es/_msearch/template [post]
+func MultipleSearchTemplate(c *gin.Context) {
+	multipleSearch(c, func(indexNames []string, data []byte) (*meta.SearchResponse, error) {
+		req := new(meta.SearchTemplateRequest)
+		if err := json.UnmarshalNumber(data, req); err != nil {
+			return nil, err
+		}
+		query, err := templateQuery(req)
+		if err != nil {
+			return nil, err
+		}
+		return searchIndex(indexNames, query)
+	})
+}
+
+// RenderTemplate renders the search template for debugging
+//
+// @Id RenderTemplate
+// @Summary Render search template
+// @security BasicAuth
+// @Tags    Search
+// @Accept  json
+// @Produce json
+// @Param   id       path  string  false "Script id"
+// @Param   template body  meta.SearchTemplateRequest true "Template"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} meta.HTTPResponseError
+// @Router /es/_render/template/{id} [post]
+func RenderTemplate(c *gin.Context) {
+	req := new(meta.SearchTemplateRequest)
+	if err := zutils.GinBindOptionalJSON(c, req); err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	if id := c.Param("id"); id != "" {
+		req.ID = id
+	}
+	rendered, err := core.RenderSearchTemplate(req)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	var output interface{}
+	if err = json.UnmarshalNumber(rendered, &output); err != nil {
+		errors.HandleError(c, errors.New(errors.ErrorTypeParsingException, "the rendered template is not valid json: "+string(rendered)).Cause(err))
+		return
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, gin.H{"template_output": output})
+}
+
+// templateQuery renders the search template and parses the query DSL
+func templateQuery(req *meta.SearchTemplateRequest) (*meta.ZincQuery, error) {
+	rendered, err := core.RenderSearchTemplate(req)
+	if err != nil {
+		return nil, err
+	}
+	query := &meta.ZincQuery{Size: 10}
+	if err = json.UnmarshalNumber(rendered, query); err != nil {
+		return nil, errors.New(errors.ErrorTypeParsingException, "the rendered template is not a valid query: "+string(rendered)).Cause(err)
+	}
+	if req.Explain {
+		query.Explain = true
+	}
+	return query, nil
+}
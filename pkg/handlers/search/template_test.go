@@ -0,0 +1,201 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package search
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/test/utils"
+)
+
+func TestSearchTemplate(t *testing.T) {
+	indexName := "TestSearchTemplate.index_1"
+	scriptID := "TestSearchTemplate.script_1"
+
+	t.Run("prepare", func(t *testing.T) {
+		index, err := core.NewIndex(indexName, "disk", 2)
+		assert.NoError(t, err)
+		assert.NotNil(t, index)
+		err = core.StoreIndex(index)
+		assert.NoError(t, err)
+		for id, doc := range map[string]map[string]interface{}{
+			"1": {"name": "zinc search", "tag": "go"},
+			"2": {"name": "elastic search", "tag": "java"},
+			"3": {"name": "bluge", "tag": "go"},
+		} {
+			assert.NoError(t, index.CreateDocument(id, doc, false))
+		}
+		// wait for WAL write to index
+		time.Sleep(time.Second)
+	})
+
+	t.Run("put script", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			data   string
+			code   int
+			result string
+		}{
+			{
+				name:   "string source",
+				data:   `{"script":{"lang":"mustache","source":"{\"query\":{\"match\":{\"name\":\"{{text}}\"}},\"size\":{{size}}{{^size}}10{{/size}}}"}}`,
+				code:   http.StatusOK,
+				result: `"acknowledged":true`,
+			},
+			{
+				name:   "unsupported lang",
+				data:   `{"script":{"lang":"painless","source":"ctx._source"}}`,
+				code:   http.StatusBadRequest,
+				result: "not supported",
+			},
+			{
+				name:   "invalid template",
+				data:   `{"script":{"lang":"mustache","source":"{{#text}}"}}`,
+				code:   http.StatusBadRequest,
+				result: "failed to compile",
+			},
+			{
+				name:   "missing source",
+				data:   `{"script":{"lang":"mustache"}}`,
+				code:   http.StatusBadRequest,
+				result: "must specify",
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				c, w := utils.NewGinContext()
+				utils.SetGinRequestData(c, tt.data)
+				utils.SetGinRequestParams(c, map[string]string{"id": scriptID})
+				PutScript(c)
+				assert.Equal(t, tt.code, w.Code)
+				assert.Contains(t, w.Body.String(), tt.result)
+			})
+		}
+	})
+
+	t.Run("get script", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"id": scriptID})
+		GetScript(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"found":true`)
+
+		c, w = utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"id": "not_exists"})
+		GetScript(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("search template", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			data   string
+			code   int
+			result string
+		}{
+			{
+				name:   "stored script",
+				data:   `{"id":"` + scriptID + `","params":{"text":"search","size":1}}`,
+				code:   http.StatusOK,
+				result: `"total":{"value":2}`,
+			},
+			{
+				name:   "inline object source",
+				data:   `{"source":{"query":{"term":{"tag":"{{tag}}"}}},"params":{"tag":"java"}}`,
+				code:   http.StatusOK,
+				result: `"total":{"value":1}`,
+			},
+			{
+				name:   "toJson in string of object source",
+				data:   `{"source":{"query":{"terms":{"tag":"{{#toJson}}tags{{/toJson}}"}}},"params":{"tags":["go"]}}`,
+				code:   http.StatusBadRequest,
+				result: "not a valid query",
+			},
+			{
+				name:   "inline string source",
+				data:   `{"source":"{\"query\":{\"terms\":{\"tag\":{{#toJson}}tags{{/toJson}}}}}","params":{"tags":["go"]}}`,
+				code:   http.StatusOK,
+				result: `"total":{"value":2}`,
+			},
+			{
+				name:   "unknown script",
+				data:   `{"id":"not_exists"}`,
+				code:   http.StatusBadRequest,
+				result: "unable to find script",
+			},
+			{
+				name:   "invalid rendered query",
+				data:   `{"source":"{\"query\":{{text}}}","params":{"text":"x"}}`,
+				code:   http.StatusBadRequest,
+				result: "not a valid query",
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				c, w := utils.NewGinContext()
+				utils.SetGinRequestData(c, tt.data)
+				utils.SetGinRequestParams(c, map[string]string{"target": indexName})
+				SearchTemplate(c)
+				assert.Equal(t, tt.code, w.Code)
+				assert.Contains(t, w.Body.String(), tt.result)
+			})
+		}
+	})
+
+	t.Run("multiple search template", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestData(c, `{"index":"`+indexName+`"}
+{"id":"`+scriptID+`","params":{"text":"zinc"}}
+{}
+{"source":"{\"query\":{\"match\":{\"tag\":\"{{#join}}tags{{/join}}\"}}}","params":{"tags":["java"]}}`)
+		utils.SetGinRequestParams(c, map[string]string{"target": indexName})
+		MultipleSearchTemplate(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"_id":"1"`)
+		assert.Contains(t, w.Body.String(), `"_id":"2"`)
+	})
+
+	t.Run("render template", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestData(c, `{"params":{"text":"say \"hi\""}}`)
+		utils.SetGinRequestParams(c, map[string]string{"id": scriptID})
+		RenderTemplate(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"template_output":{"query":{"match":{"name":"say \"hi\""}},"size":10}}`, w.Body.String())
+	})
+
+	t.Run("delete script", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"id": scriptID})
+		DeleteScript(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		c, w = utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"id": scriptID})
+		DeleteScript(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		err := core.DeleteIndex(indexName)
+		assert.NoError(t, err)
+	})
+}
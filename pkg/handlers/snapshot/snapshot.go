@@ -0,0 +1,207 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package snapshot
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/snapshot"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+// @Id PutSnapshotRepository
+// @Summary Create or update snapshot repository for compatible ES
+// @security BasicAuth
+// @Tags    Snapshot
+// @Accept  json
+// @Produce json
+// @Param   repository path  string                   true  "Repository"
+// @Param   data       body  meta.SnapshotRepository  true  "Repository data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} meta.HTTPResponseError
+// @Router /es/_snapshot/{repository} [put]
+func PutRepository(c *gin.Context) {
+	repo := new(meta.SnapshotRepository)
+	if err := zutils.GinBindJSON(c, repo); err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	if err := snapshot.PutRepository(c.Param("repository"), repo); err != nil {
+		renderError(c, err)
+		return
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, gin.H{"acknowledged": true})
+}
+
+// @Id GetSnapshotRepository
+// @Summary Get snapshot repositories for compatible ES
+// @security BasicAuth
+// @Tags    Snapshot
+// @Produce json
+// @Param   repository path  string  false  "Repository"
+// @Success 200 {object} map[string]meta.SnapshotRepository
+// @Failure 404 {object} meta.HTTPResponseError
+// @Router /es/_snapshot/{repository} [get]
+func GetRepository(c *gin.Context) {
+	name := c.Param("repository")
+	repos, err := snapshot.ListRepositories()
+	if err != nil {
+		renderError(c, err)
+		return
+	}
+	resp := make(map[string]*meta.SnapshotRepository)
+	for _, repo := range repos {
+		if name == "" || name == "_all" || name == repo.Name {
+			resp[repo.Name] = repo
+		}
+	}
+	if len(resp) == 0 && name != "" && name != "_all" {
+		zutils.GinRenderJSON(c, http.StatusNotFound, meta.HTTPResponseError{Error: "repository [" + name + "] does not exists"})
+		return
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, resp)
+}
+
+// @Id DeleteSnapshotRepository
+// @Summary Delete snapshot repository for compatible ES, the snapshots are kept in location
+// @security BasicAuth
+// @Tags    Snapshot
+// @Produce json
+// @Param   repository path  string  true  "Repository"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} meta.HTTPResponseError
+// @Router /es/_snapshot/{repository} [delete]
+func DeleteRepository(c *gin.Context) {
+	if err := snapshot.DeleteRepository(c.Param("repository")); err != nil {
+		renderError(c, err)
+		return
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, gin.H{"acknowledged": true})
+}
+
+// @Id CreateSnapshot
+// @Summary Create snapshot for compatible ES
+// @security BasicAuth
+// @Tags    Snapshot
+// @Accept  json
+// @Produce json
+// @Param   repository          path   string                      true   "Repository"
+// @Param   snapshot            path   string                      true   "Snapshot"
+// @Param   wait_for_completion query  bool                        false  "Wait for completion"
+// @Param   data                body   meta.SnapshotCreateRequest  false  "Snapshot options"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} meta.HTTPResponseError
+// @Router /es/_snapshot/{repository}/{snapshot} [put]
+func CreateSnapshot(c *gin.Context) {
+	req := new(meta.SnapshotCreateRequest)
+	if err := zutils.GinBindOptionalJSON(c, req); err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	wait := c.Query("wait_for_completion") == "true"
+	snap, err := snapshot.CreateSnapshot(c.Param("repository"), c.Param("snapshot"), req, wait)
+	if err != nil {
+		renderError(c, err)
+		return
+	}
+	if !wait {
+		zutils.GinRenderJSON(c, http.StatusOK, gin.H{"accepted": true})
+		return
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, gin.H{"snapshot": snap})
+}
+
+// @Id GetSnapshot
+// @Summary Get snapshots for compatible ES
+// @security BasicAuth
+// @Tags    Snapshot
+// @Produce json
+// @Param   repository path  string  true  "Repository"
+// @Param   snapshot   path  string  true  "Snapshot, accept comma separated list, wildcards and _all"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} meta.HTTPResponseError
+// @Router /es/_snapshot/{repository}/{snapshot} [get]
+func GetSnapshot(c *gin.Context) {
+	snaps, err := snapshot.GetSnapshots(c.Param("repository"), c.Param("snapshot"))
+	if err != nil {
+		renderError(c, err)
+		return
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, gin.H{"snapshots": snaps, "total": len(snaps)})
+}
+
+// @Id DeleteSnapshot
+// @Summary Delete snapshot for compatible ES
+// @security BasicAuth
+// @Tags    Snapshot
+// @Produce json
+// @Param   repository path  string  true  "Repository"
+// @Param   snapshot   path  string  true  "Snapshot"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} meta.HTTPResponseError
+// @Router /es/_snapshot/{repository}/{snapshot} [delete]
+func DeleteSnapshot(c *gin.Context) {
+	if err := snapshot.DeleteSnapshot(c.Param("repository"), c.Param("snapshot")); err != nil {
+		renderError(c, err)
+		return
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, gin.H{"acknowledged": true})
+}
+
+// @Id RestoreSnapshot
+// @Summary Restore snapshot for compatible ES
+// @security BasicAuth
+// @Tags    Snapshot
+// @Accept  json
+// @Produce json
+// @Param   repository path  string                       true   "Repository"
+// @Param   snapshot   path  string                       true   "Snapshot"
+// @Param   data       body  meta.SnapshotRestoreRequest  false  "Restore options"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} meta.HTTPResponseError
+// @Router /es/_snapshot/{repository}/{snapshot}/_restore [post]
+func RestoreSnapshot(c *gin.Context) {
+	req := new(meta.SnapshotRestoreRequest)
+	if err := zutils.GinBindOptionalJSON(c, req); err != nil {
+		zutils.GinRenderJSON(c, http.StatusBadRequest, meta.HTTPResponseError{Error: err.Error()})
+		return
+	}
+	name := c.Param("snapshot")
+	indices, err := snapshot.RestoreSnapshot(c.Param("repository"), name, req)
+	if err != nil {
+		renderError(c, err)
+		return
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, gin.H{
+		"snapshot": gin.H{
+			"snapshot": name,
+			"indices":  indices,
+		},
+	})
+}
+
+func renderError(c *gin.Context, err error) {
+	code := http.StatusBadRequest
+	var e *errors.Error
+	if errors.As(err, &e) && (e.Type == errors.ErrorTypeRepositoryMissing || e.Type == errors.ErrorTypeSnapshotMissing) {
+		code = http.StatusNotFound
+	}
+	zutils.GinRenderJSON(c, code, meta.HTTPResponseError{Error: err.Error()})
+}
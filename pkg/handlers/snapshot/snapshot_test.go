@@ -0,0 +1,170 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package snapshot
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/test/utils"
+)
+
+func TestSnapshotHandlers(t *testing.T) {
+	config.Global.SnapshotRepoPath = []string{t.TempDir()}
+	indexName := "TestSnapshotHandlers.index_1"
+
+	type args struct {
+		params map[string]string
+		query  map[string]string
+		data   string
+	}
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+		args    args
+		code    int
+		result  string
+	}{
+		{
+			name:    "put repository",
+			handler: PutRepository,
+			args: args{
+				params: map[string]string{"repository": "repo"},
+				data:   `{"type":"fs","settings":{"location":"repo"}}`,
+			},
+			code:   http.StatusOK,
+			result: `"acknowledged":true`,
+		},
+		{
+			name:    "put repository with invalid type",
+			handler: PutRepository,
+			args: args{
+				params: map[string]string{"repository": "repo_s3"},
+				data:   `{"type":"s3","settings":{"location":"repo"}}`,
+			},
+			code:   http.StatusBadRequest,
+			result: "not supported",
+		},
+		{
+			name:    "get repository",
+			handler: GetRepository,
+			args:    args{params: map[string]string{"repository": "repo"}},
+			code:    http.StatusOK,
+			result:  `"location":"repo"`,
+		},
+		{
+			name:    "get missing repository",
+			handler: GetRepository,
+			args:    args{params: map[string]string{"repository": "missing"}},
+			code:    http.StatusNotFound,
+			result:  "does not exists",
+		},
+		{
+			name:    "create snapshot",
+			handler: CreateSnapshot,
+			args: args{
+				params: map[string]string{"repository": "repo", "snapshot": "snap_1"},
+				query:  map[string]string{"wait_for_completion": "true"},
+				data:   `{"indices":"TestSnapshotHandlers.*"}`,
+			},
+			code:   http.StatusOK,
+			result: `"state":"SUCCESS"`,
+		},
+		{
+			name:    "create snapshot in missing repository",
+			handler: CreateSnapshot,
+			args:    args{params: map[string]string{"repository": "missing", "snapshot": "snap_1"}},
+			code:    http.StatusNotFound,
+			result:  "does not exists",
+		},
+		{
+			name:    "get snapshot",
+			handler: GetSnapshot,
+			args:    args{params: map[string]string{"repository": "repo", "snapshot": "_all"}},
+			code:    http.StatusOK,
+			result:  `"snapshot":"snap_1"`,
+		},
+		{
+			name:    "restore snapshot with exists index",
+			handler: RestoreSnapshot,
+			args:    args{params: map[string]string{"repository": "repo", "snapshot": "snap_1"}},
+			code:    http.StatusBadRequest,
+			result:  "already exists",
+		},
+		{
+			name:    "restore snapshot with rename",
+			handler: RestoreSnapshot,
+			args: args{
+				params: map[string]string{"repository": "repo", "snapshot": "snap_1"},
+				data:   `{"rename_pattern":"(.+)_1","rename_replacement":"${1}_2"}`,
+			},
+			code:   http.StatusOK,
+			result: `"indices":["TestSnapshotHandlers.index_2"]`,
+		},
+		{
+			name:    "delete snapshot",
+			handler: DeleteSnapshot,
+			args:    args{params: map[string]string{"repository": "repo", "snapshot": "snap_1"}},
+			code:    http.StatusOK,
+			result:  `"acknowledged":true`,
+		},
+		{
+			name:    "delete missing snapshot",
+			handler: DeleteSnapshot,
+			args:    args{params: map[string]string{"repository": "repo", "snapshot": "snap_1"}},
+			code:    http.StatusNotFound,
+			result:  "is missing",
+		},
+		{
+			name:    "delete repository",
+			handler: DeleteRepository,
+			args:    args{params: map[string]string{"repository": "repo"}},
+			code:    http.StatusOK,
+			result:  `"acknowledged":true`,
+		},
+	}
+
+	t.Run("prepare", func(t *testing.T) {
+		index, err := core.NewIndex(indexName, "disk", 2)
+		assert.NoError(t, err)
+		assert.NoError(t, core.StoreIndex(index))
+		assert.NoError(t, index.CreateDocument("1", map[string]interface{}{"name": "Prabhat"}, false))
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := utils.NewGinContext()
+			utils.SetGinRequestParams(c, tt.args.params)
+			utils.SetGinRequestURL(c, "/es/_snapshot", tt.args.query)
+			if tt.args.data != "" {
+				utils.SetGinRequestData(c, tt.args.data)
+			}
+			tt.handler(c)
+			assert.Equal(t, tt.code, w.Code)
+			assert.Contains(t, w.Body.String(), tt.result)
+		})
+	}
+
+	t.Run("cleanup", func(t *testing.T) {
+		assert.NoError(t, core.DeleteIndex(indexName))
+		assert.NoError(t, core.DeleteIndex("TestSnapshotHandlers.index_2"))
+	})
+}
@@ -0,0 +1,71 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package task
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+// @Id ListTasks
+// @Summary List tasks for compatible ES
+// @security BasicAuth
+// @Tags    Task
+// @Produce json
+// @Param   actions  query  string  false  "Comma separated actions with wildcard"
+// @Success 200 {object} map[string]interface{}
+// @Router /es/_tasks [get]
+func List(c *gin.Context) {
+	nodes := make(map[string]gin.H)
+	for _, task := range core.ZINC_TASK_LIST.List(c.Query("actions")) {
+		result := task.GetResult()
+		node, ok := nodes[result.Task.Node]
+		if !ok {
+			node = gin.H{"tasks": make(map[string]meta.TaskInfo)}
+			nodes[result.Task.Node] = node
+		}
+		node["tasks"].(map[string]meta.TaskInfo)[result.Task.ID] = result.Task
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, gin.H{"nodes": nodes})
+}
+
+// @Id GetTask
+// @Summary Get task for compatible ES
+// @security BasicAuth
+// @Tags    Task
+// @Produce json
+// @Param   task_id              path   string  true   "Task ID"
+// @Param   wait_for_completion  query  bool    false  "Wait for the task completed"
+// @Success 200 {object} meta.TaskResult
+// @Failure 404 {object} meta.HTTPResponseError
+// @Router /es/_tasks/{task_id} [get]
+func Get(c *gin.Context) {
+	id := c.Param("task_id")
+	task, ok := core.GetTask(id)
+	if !ok {
+		zutils.GinRenderJSON(c, http.StatusNotFound, meta.HTTPResponseError{Error: "task [" + id + "] isn't running and hasn't stored its results"})
+		return
+	}
+	if c.Query("wait_for_completion") == "true" {
+		task.Wait()
+	}
+	zutils.GinRenderJSON(c, http.StatusOK, task.GetResult())
+}
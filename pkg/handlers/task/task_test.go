@@ -0,0 +1,76 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package task
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/test/utils"
+)
+
+func TestTasks(t *testing.T) {
+	task := core.NewTask("indices:admin/test", "test task")
+	task.SetTotal(2)
+
+	t.Run("list", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestURL(c, "/es/_tasks", map[string]string{"actions": "indices:admin/*"})
+		List(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), task.GetID())
+	})
+
+	t.Run("list with other actions", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestURL(c, "/es/_tasks", map[string]string{"actions": "indices:data/*"})
+		List(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), task.GetID())
+	})
+
+	t.Run("get running", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"task_id": task.GetID()})
+		utils.SetGinRequestURL(c, "/es/_tasks/"+task.GetID(), nil)
+		Get(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"completed":false`)
+		assert.Contains(t, w.Body.String(), `"total":2`)
+	})
+
+	t.Run("get with wait", func(t *testing.T) {
+		go task.Finish(map[string]interface{}{"acknowledged": true}, nil)
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"task_id": task.GetID()})
+		utils.SetGinRequestURL(c, "/es/_tasks/"+task.GetID(), map[string]string{"wait_for_completion": "true"})
+		Get(c)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"completed":true`)
+		assert.Contains(t, w.Body.String(), `"acknowledged":true`)
+	})
+
+	t.Run("get not exists", func(t *testing.T) {
+		c, w := utils.NewGinContext()
+		utils.SetGinRequestParams(c, map[string]string{"task_id": "1:not_exists"})
+		utils.SetGinRequestURL(c, "/es/_tasks/1:not_exists", nil)
+		Get(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -109,12 +109,28 @@ type Property struct {
 	// or the same string value analyzed by different analyzers.
 	Fields map[string]Property `json:"fields,omitempty"`
 	// IgnoreAbove prevents indexing of strings longer than the configured value.
-	// TODO: Should this be supported by Zinc?
 	IgnoreAbove    uint   `json:"ignore_above,omitempty"`
+	Normalizer     string `json:"normalizer,omitempty"`
 	Analyzer       string `json:"analyzer,omitempty"`
 	SearchAnalyzer string `json:"search_analyzer,omitempty"`
+	// NullValue is indexed instead of null value.
+	NullValue interface{} `json:"null_value,omitempty"`
+	// CopyTo copies the value into the other fields.
+	CopyTo []string `json:"copy_to,omitempty"`
 	// Format holds the property format.
 	Format string `json:"format,omitempty"`
+	// ScalingFactor is used by scaled_float to encode the value.
+	ScalingFactor float64 `json:"scaling_factor,omitempty"`
+	// MaxInputLength limits the length of completion input.
+	MaxInputLength int `json:"max_input_length,omitempty"`
+	// Contexts are the category contexts of completion field.
+	Contexts []map[string]string `json:"contexts,omitempty"`
+	// Dims is the number of dimensions of dense_vector.
+	Dims int `json:"dims,omitempty"`
+	// Similarity is the vector similarity of dense_vector.
+	Similarity string `json:"similarity,omitempty"`
+	// IndexOptions configures the HNSW graph of dense_vector.
+	IndexOptions map[string]interface{} `json:"index_options,omitempty"`
 }
 
 // NewProperty returns a new Property object.
@@ -15,6 +15,12 @@
 
 package meta
 
+import (
+	"strings"
+
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+)
+
 type Index struct {
 	ShardNum    int64                  `json:"shard_num"`
 	Name        string                 `json:"name"`
@@ -24,6 +30,7 @@ type Index struct {
 	Shards      map[string]*IndexShard `json:"shards"`
 	Stats       IndexStat              `json:"stats"`
 	Version     string                 `json:"version"`
+	Closed      bool                   `json:"closed,omitempty"`
 }
 
 type IndexShard struct {
@@ -59,10 +66,70 @@ type IndexSettings struct {
 	NumberOfShards   int64          `json:"number_of_shards,omitempty"`
 	NumberOfReplicas int64          `json:"number_of_replicas,omitempty"`
 	Analysis         *IndexAnalysis `json:"analysis,omitempty"`
+	Blocks           *IndexBlocks   `json:"blocks,omitempty"`
+}
+
+// IndexBlocks limits the operations allowed on the index, nil means the block is not changed
+type IndexBlocks struct {
+	ReadOnly *bool `json:"read_only,omitempty"` // blocks document writes and metadata changes
+	Write    *bool `json:"write,omitempty"`     // blocks document writes
+}
+
+// UnmarshalJSON accepts the es style settings also, like {"index":{"blocks":{"write":true}}} or {"index.blocks.write":true}
+func (s *IndexSettings) UnmarshalJSON(data []byte) error {
+	type indexSettings IndexSettings
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		// not an object, returns the error of decoding into struct
+		return json.Unmarshal(data, (*indexSettings)(s))
+	}
+	data, err := json.Marshal(normalizeSettings(raw))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, (*indexSettings)(s))
+}
+
+// normalizeSettings removes the prefix index and expands the dotted keys into objects
+func normalizeSettings(raw map[string]interface{}) map[string]interface{} {
+	settings := make(map[string]interface{}, len(raw))
+	for k, v := range raw {
+		if k == "index" {
+			if v, ok := v.(map[string]interface{}); ok {
+				for k, v := range normalizeSettings(v) {
+					settings[k] = v
+				}
+				continue
+			}
+		}
+		keys := strings.Split(strings.TrimPrefix(k, "index."), ".")
+		m := settings
+		for _, key := range keys[:len(keys)-1] {
+			sub, ok := m[key].(map[string]interface{})
+			if !ok {
+				sub = make(map[string]interface{})
+				m[key] = sub
+			}
+			m = sub
+		}
+		m[keys[len(keys)-1]] = v
+	}
+	return settings
+}
+
+// IsWriteBlocked returns true if the index.blocks.write or index.blocks.read_only is set
+func (s *IndexSettings) IsWriteBlocked() bool {
+	return s.IsReadOnly() || (s != nil && s.Blocks != nil && s.Blocks.Write != nil && *s.Blocks.Write)
+}
+
+// IsReadOnly returns true if the index.blocks.read_only is set
+func (s *IndexSettings) IsReadOnly() bool {
+	return s != nil && s.Blocks != nil && s.Blocks.ReadOnly != nil && *s.Blocks.ReadOnly
 }
 
 type IndexAnalysis struct {
 	Analyzer    map[string]*Analyzer   `json:"analyzer,omitempty"`
+	Normalizer  map[string]*Analyzer   `json:"normalizer,omitempty"` // normalizer has no tokenizer, used for keyword fields
 	CharFilter  map[string]interface{} `json:"char_filter,omitempty"`
 	Tokenizer   map[string]interface{} `json:"tokenizer,omitempty"`
 	TokenFilter map[string]interface{} `json:"token_filter,omitempty"`
@@ -0,0 +1,74 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package meta
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+)
+
+func TestIndexSettings_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		shards    int64
+		write     bool
+		readOnly  bool
+		hasBlocks bool
+	}{
+		{
+			name:   "flat",
+			data:   `{"number_of_shards":3}`,
+			shards: 3,
+		},
+		{
+			name:      "blocks",
+			data:      `{"blocks":{"write":true}}`,
+			write:     true,
+			hasBlocks: true,
+		},
+		{
+			name:      "index prefix",
+			data:      `{"index":{"number_of_shards":2,"blocks":{"read_only":true}}}`,
+			shards:    2,
+			readOnly:  true,
+			hasBlocks: true,
+		},
+		{
+			name:      "dotted keys",
+			data:      `{"index.blocks.write":true,"index.number_of_shards":4}`,
+			shards:    4,
+			write:     true,
+			hasBlocks: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := new(IndexSettings)
+			assert.NoError(t, json.Unmarshal([]byte(tt.data), settings))
+			assert.Equal(t, tt.shards, settings.NumberOfShards)
+			assert.Equal(t, tt.hasBlocks, settings.Blocks != nil)
+			assert.Equal(t, tt.write || tt.readOnly, settings.IsWriteBlocked())
+			assert.Equal(t, tt.readOnly, settings.IsReadOnly())
+		})
+	}
+
+	var settings *IndexSettings
+	assert.False(t, settings.IsWriteBlocked())
+}
@@ -17,14 +17,52 @@ package meta
 
 import (
 	"bytes"
+	"fmt"
+	"math"
 	"sync"
 
+	"github.com/zincsearch/zincsearch/pkg/zutils"
 	"github.com/zincsearch/zincsearch/pkg/zutils/json"
 )
 
 type Mappings struct {
 	Properties map[string]Property `json:"properties,omitempty"`
-	lock       sync.RWMutex
+	DynamicMapping
+	lock sync.RWMutex
+}
+
+const (
+	DynamicTrue   = "true"
+	DynamicFalse  = "false"
+	DynamicStrict = "strict"
+)
+
+// DynamicMapping controls how to map the fields what not defined in properties
+type DynamicMapping struct {
+	// Dynamic true adds new fields to mappings, false ignores new fields, strict rejects the document, empty means true
+	Dynamic string `json:"dynamic,omitempty"`
+	// DateDetection maps the string looks like a date to date field, nil means true
+	DateDetection *bool `json:"date_detection,omitempty"`
+	// DynamicDateFormats the layouts used for date detection, empty means the default layouts
+	DynamicDateFormats []string `json:"dynamic_date_formats,omitempty"`
+	// NumericDetection maps the string looks like a number to numeric field, nil means false
+	NumericDetection *bool `json:"numeric_detection,omitempty"`
+	// DynamicTemplates is a list of named templates, the first matched template is used for the new field
+	DynamicTemplates []map[string]*DynamicTemplate `json:"dynamic_templates,omitempty"`
+	// TotalFieldsLimit the maximum number of fields, 0 means no limit
+	TotalFieldsLimit int `json:"total_fields_limit,omitempty"`
+}
+
+// DynamicTemplate maps the new field matched by name, path or detected type to Mapping
+type DynamicTemplate struct {
+	Match            string `json:"match,omitempty"`
+	Unmatch          string `json:"unmatch,omitempty"`
+	PathMatch        string `json:"path_match,omitempty"`
+	PathUnmatch      string `json:"path_unmatch,omitempty"`
+	MatchPattern     string `json:"match_pattern,omitempty"`      // simple (default) or regex
+	MatchMappingType string `json:"match_mapping_type,omitempty"` // string, long, double, boolean, date or *
+	// Mapping is the property of the field, {name} and {dynamic_type} will be replaced
+	Mapping map[string]interface{} `json:"mapping"`
 }
 
 type Property struct {
@@ -38,6 +76,25 @@ type Property struct {
 	Sortable       bool   `json:"sortable"`
 	Aggregatable   bool   `json:"aggregatable"`
 	Highlightable  bool   `json:"highlightable"`
+	Normalizer     string `json:"normalizer,omitempty"`   // keyword only, normalizes the value at index and query time
+	IgnoreAbove    int    `json:"ignore_above,omitempty"` // keyword only, the value longer than it will not be indexed
+	// NullValue is indexed instead of JSON null, it is converted to the type of field already
+	NullValue interface{} `json:"null_value,omitempty"`
+	// CopyTo copies the value of field into the other fields, it isn't applied recursively
+	CopyTo []string `json:"copy_to,omitempty"`
+	// NumericType is the declared type of numeric field, empty means double
+	NumericType   string  `json:"numeric_type,omitempty"`
+	ScalingFactor float64 `json:"scaling_factor,omitempty"` // scaled_float only, the value is indexed as round(value * scaling_factor)
+	// MaxInputLength limits the length of completion input, the longer input is truncated, 0 means 50
+	MaxInputLength int `json:"max_input_length,omitempty"`
+	// Contexts are the category contexts of completion field, the suggestions can be filtered by them
+	Contexts []CompletionContext `json:"contexts,omitempty"`
+	// Dims is the number of dimensions of dense_vector field
+	Dims int `json:"dims,omitempty"`
+	// Similarity is the vector similarity of dense_vector field, cosine, dot_product or l2_norm
+	Similarity string `json:"similarity,omitempty"`
+	// IndexOptions configures the HNSW graph of dense_vector field
+	IndexOptions *VectorIndexOptions `json:"index_options,omitempty"`
 	// Fields allow the same string value to be indexed in multiple ways for different purposes,
 	// such as one field for search and a multi-field for sorting and aggregations,
 	// or the same string value analyzed by different analyzers.
@@ -47,6 +104,52 @@ type Property struct {
 	Fields map[string]Property `json:"fields,omitempty"`
 }
 
+// CompletionContext is a category context of completion field, the values are read from the path of document if set
+type CompletionContext struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // category
+	Path string `json:"path,omitempty"`
+}
+
+// VectorIndexOptions is the options of HNSW graph, the zero values mean the defaults
+type VectorIndexOptions struct {
+	Type           string `json:"type"` // hnsw
+	M              int    `json:"m,omitempty"`
+	EfConstruction int    `json:"ef_construction,omitempty"`
+}
+
+const (
+	SimilarityCosine     = "cosine"
+	SimilarityDotProduct = "dot_product"
+	SimilarityL2Norm     = "l2_norm"
+)
+
+const (
+	NumericTypeLong        = "long"
+	NumericTypeInteger     = "integer"
+	NumericTypeShort       = "short"
+	NumericTypeByte        = "byte"
+	NumericTypeDouble      = "double"
+	NumericTypeFloat       = "float"
+	NumericTypeHalfFloat   = "half_float"
+	NumericTypeScaledFloat = "scaled_float"
+)
+
+// numericRanges the value range of integer numeric types, long uses the full range of int64
+var numericRanges = map[string][2]int64{
+	NumericTypeInteger: {math.MinInt32, math.MaxInt32},
+	NumericTypeShort:   {math.MinInt16, math.MaxInt16},
+	NumericTypeByte:    {math.MinInt8, math.MaxInt8},
+}
+
+// MappingsReindexResponse is the response of the task re-indexing exists documents for the new fields
+type MappingsReindexResponse struct {
+	Index  string   `json:"index"`
+	Fields []string `json:"fields"`
+	Docs   int64    `json:"docs"`
+	Failed int64    `json:"failed"`
+}
+
 func NewMappings() *Mappings {
 	return &Mappings{
 		Properties: make(map[string]Property),
@@ -67,13 +170,59 @@ func NewProperty(typ string) Property {
 		Highlightable:  false,
 		Fields:         make(map[string]Property),
 	}
-	if typ == "text" {
+	if typ == "text" || typ == "completion" || typ == "dense_vector" || typ == "percolator" {
 		p.Sortable = false
 		p.Aggregatable = false
 	}
 	return p
 }
 
+// IsLong returns true if the numeric field is indexed as exact int64,
+// they are the integer types and scaled_float, the others are indexed as float64.
+func (p Property) IsLong() bool {
+	if p.Type != "numeric" {
+		return false
+	}
+	switch p.NumericType {
+	case NumericTypeLong, NumericTypeInteger, NumericTypeShort, NumericTypeByte, NumericTypeScaledFloat:
+		return true
+	default:
+		return false
+	}
+}
+
+// ToLong converts the value to the int64 indexed by the long field,
+// the fractional part is truncated for integer types and scaled_float multiplies the scaling factor.
+func (p Property) ToLong(value interface{}) (int64, error) {
+	if p.NumericType == NumericTypeScaledFloat {
+		f, err := zutils.ToFloat64(value)
+		if err != nil {
+			return 0, err
+		}
+		f = math.Round(f * p.ScalingFactor)
+		if math.IsNaN(f) || f >= math.MaxInt64 || f < math.MinInt64 {
+			return 0, fmt.Errorf("value [%v] is out of range for type [%s]", value, p.NumericType)
+		}
+		return int64(f), nil
+	}
+	i, err := zutils.ToInt64(value)
+	if err != nil {
+		return 0, err
+	}
+	if r, ok := numericRanges[p.NumericType]; ok && (i < r[0] || i > r[1]) {
+		return 0, fmt.Errorf("value [%v] is out of range for type [%s]", value, p.NumericType)
+	}
+	return i, nil
+}
+
+// FromLong converts the indexed int64 back to the value of long field, scaled_float returns float64
+func (p Property) FromLong(i int64) interface{} {
+	if p.NumericType == NumericTypeScaledFloat {
+		return float64(i) / p.ScalingFactor
+	}
+	return i
+}
+
 // AddField adds the given field to the property.
 func (p *Property) AddField(field string, value Property) {
 	if p.Fields == nil {
@@ -92,11 +241,30 @@ func (p *Property) DeepClone() Property {
 	prop.Analyzer = p.Analyzer
 	prop.SearchAnalyzer = p.SearchAnalyzer
 	prop.Format = p.Format
+	prop.TimeZone = p.TimeZone
 	prop.Index = p.Index
 	prop.Store = p.Store
 	prop.Sortable = p.Sortable
 	prop.Aggregatable = p.Aggregatable
 	prop.Highlightable = p.Highlightable
+	prop.NumericType = p.NumericType
+	prop.ScalingFactor = p.ScalingFactor
+	prop.Normalizer = p.Normalizer
+	prop.IgnoreAbove = p.IgnoreAbove
+	prop.NullValue = p.NullValue
+	if p.CopyTo != nil {
+		prop.CopyTo = append([]string{}, p.CopyTo...)
+	}
+	prop.MaxInputLength = p.MaxInputLength
+	if p.Contexts != nil {
+		prop.Contexts = append([]CompletionContext{}, p.Contexts...)
+	}
+	prop.Dims = p.Dims
+	prop.Similarity = p.Similarity
+	if p.IndexOptions != nil {
+		options := *p.IndexOptions
+		prop.IndexOptions = &options
+	}
 
 	if p.Fields != nil {
 		for k, v := range p.Fields {
@@ -149,10 +317,43 @@ func (t *Mappings) DeepClone() *Mappings {
 	for k, v := range t.Properties {
 		m.Properties[k] = v.DeepClone()
 	}
+	m.DynamicMapping = t.DynamicMapping
 
 	return m
 }
 
+// GetDynamic returns the dynamic mapping options
+func (t *Mappings) GetDynamic() DynamicMapping {
+	t.lock.RLock()
+	d := t.DynamicMapping
+	t.lock.RUnlock()
+	return d
+}
+
+// MergeDynamic overwrites the dynamic mapping options what set in d
+func (t *Mappings) MergeDynamic(d DynamicMapping) {
+	t.lock.Lock()
+	if d.Dynamic != "" {
+		t.Dynamic = d.Dynamic
+	}
+	if d.DateDetection != nil {
+		t.DateDetection = d.DateDetection
+	}
+	if d.DynamicDateFormats != nil {
+		t.DynamicDateFormats = d.DynamicDateFormats
+	}
+	if d.NumericDetection != nil {
+		t.NumericDetection = d.NumericDetection
+	}
+	if d.DynamicTemplates != nil {
+		t.DynamicTemplates = d.DynamicTemplates
+	}
+	if d.TotalFieldsLimit != 0 {
+		t.TotalFieldsLimit = d.TotalFieldsLimit
+	}
+	t.lock.Unlock()
+}
+
 func (t *Mappings) MarshalJSON() ([]byte, error) {
 	t.lock.RLock()
 	defer t.lock.RUnlock()
@@ -163,6 +364,15 @@ func (t *Mappings) MarshalJSON() ([]byte, error) {
 		return nil, err
 	}
 	b.Write(p)
+	// write the dynamic mapping options as fields of mappings
+	d, err := json.Marshal(t.DynamicMapping)
+	if err != nil {
+		return nil, err
+	}
+	if len(d) > 2 {
+		b.WriteByte(',')
+		b.Write(d[1 : len(d)-1])
+	}
 	b.WriteByte('}')
 	return b.Bytes(), nil
 }
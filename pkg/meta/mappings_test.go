@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
 )
 
 func TestProperty_DeepClone(t *testing.T) {
@@ -22,3 +24,25 @@ func TestProperty_DeepClone(t *testing.T) {
 	clone := prop.DeepClone()
 	assert.Equal(t, prop, clone)
 }
+
+func TestMappings_DynamicJSON(t *testing.T) {
+	m := NewMappings()
+	m.SetProperty("name", NewProperty("keyword"))
+	m.MergeDynamic(DynamicMapping{
+		Dynamic:          DynamicStrict,
+		TotalFieldsLimit: 10,
+		DynamicTemplates: []map[string]*DynamicTemplate{
+			{"ids": {Match: "*_id", Mapping: map[string]interface{}{"type": "keyword"}}},
+		},
+	})
+
+	data, err := json.Marshal(m)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"dynamic":"strict"`)
+
+	got := new(Mappings)
+	assert.NoError(t, json.Unmarshal(data, got))
+	assert.Equal(t, m.GetDynamic(), got.GetDynamic())
+	_, ok := got.GetProperty("name")
+	assert.True(t, ok)
+}
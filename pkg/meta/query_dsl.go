@@ -30,6 +30,11 @@ type ZincQuery struct {
 	Size           int                     `json:"size"`
 	Timeout        int                     `json:"timeout"`
 	TrackTotalHits bool                    `json:"track_total_hits"`
+	Rescore        interface{}             `json:"rescore"`  // {"window_size": 50, "query": {"rescore_query": {}, "query_weight": 1, "rescore_query_weight": 1}} or an array of them
+	Collapse       *Collapse               `json:"collapse"` // {"field": "group", "inner_hits": {"name": "top", "size": 3, "sort": []}}
+	Suggest        map[string]interface{}  `json:"suggest"`  // {"text": "global text", "my-suggest": {"text": "tring", "term": {"field": "message"}}}
+	KNN            interface{}             `json:"knn"`      // {"field": "vector", "query_vector": [0.1, 0.2], "k": 10, "num_candidates": 100, "filter": {}} or an array of them
+	Rank           interface{}             `json:"rank"`     // {"rrf": {"window_size": 100, "rank_constant": 60}}
 }
 
 type ZincQueryForSDK struct {
@@ -56,6 +61,10 @@ type Query struct {
 	MultiMatch        *MultiMatchQuery                   `json:"multi_match,omitempty"`         // .
 	MatchAll          *MatchAllQuery                     `json:"match_all,omitempty"`           // just set or null
 	MatchNone         *MatchNoneQuery                    `json:"match_none,omitempty"`          // just set or null
+	FunctionScore     *FunctionScoreQuery                `json:"function_score,omitempty"`      // .
+	ScriptScore       *ScriptScoreQuery                  `json:"script_score,omitempty"`        // .
+	MoreLikeThis      *MoreLikeThisQuery                 `json:"more_like_this,omitempty"`      // .
+	Percolate         *PercolateQuery                    `json:"percolate,omitempty"`           // .
 	CombinedFields    *CombinedFieldsQuery               `json:"combined_fields,omitempty"`     // TODO: not implemented
 	QueryString       *QueryStringQuery                  `json:"query_string,omitempty"`        // .
 	SimpleQueryString *SimpleQueryStringQuery            `json:"simple_query_string,omitempty"` // .
@@ -102,7 +111,7 @@ type BoolQuery struct {
 	Must               interface{} `json:"must,omitempty"`                 // query, [query1, query2]
 	MustNot            interface{} `json:"must_not,omitempty"`             // query, [query1, query2]
 	Filter             interface{} `json:"filter,omitempty"`               // query, [query1, query2]
-	MinimumShouldMatch float64     `json:"minimum_should_match,omitempty"` // only for should
+	MinimumShouldMatch interface{} `json:"minimum_should_match,omitempty"` // only for should, 2, -1, "75%", "3<90%"
 }
 
 type BoolQueryForSDK struct {
@@ -110,7 +119,7 @@ type BoolQueryForSDK struct {
 	Must               []*QueryForSDK `json:"must,omitempty"`                 // query, [query1, query2]
 	MustNot            []*QueryForSDK `json:"must_not,omitempty"`             // query, [query1, query2]
 	Filter             []*QueryForSDK `json:"filter,omitempty"`               // query, [query1, query2]
-	MinimumShouldMatch float64        `json:"minimum_should_match,omitempty"` // only for should
+	MinimumShouldMatch interface{}    `json:"minimum_should_match,omitempty"` // only for should, 2, -1, "75%", "3<90%"
 }
 
 type BoostingQuery struct {
@@ -119,71 +128,171 @@ type BoostingQuery struct {
 	NegativeBoost float64     `json:"negative_boost,omitempty"`
 }
 
+type FunctionScoreQuery struct {
+	Query     interface{}      `json:"query,omitempty"`
+	Functions []*ScoreFunction `json:"functions,omitempty"`
+	ScoreMode string           `json:"score_mode,omitempty"` // multiply(default), sum, avg, first, max, min
+	BoostMode string           `json:"boost_mode,omitempty"` // multiply(default), replace, sum, avg, max, min
+	MaxBoost  float64          `json:"max_boost,omitempty"`
+	MinScore  *float64         `json:"min_score,omitempty"`
+	Boost     float64          `json:"boost,omitempty"`
+}
+
+type ScoreFunction struct {
+	Filter           interface{}               `json:"filter,omitempty"`
+	Weight           float64                   `json:"weight,omitempty"`
+	FieldValueFactor *FieldValueFactorFunction `json:"field_value_factor,omitempty"`
+	RandomScore      *RandomScoreFunction      `json:"random_score,omitempty"`
+	ScriptScore      *ScriptScoreFunction      `json:"script_score,omitempty"`
+	Gauss            map[string]interface{}    `json:"gauss,omitempty"`  // {"field": {"origin": "now", "scale": "10d", "offset": "0d", "decay": 0.5}, "multi_value_mode": "min"}
+	Exp              map[string]interface{}    `json:"exp,omitempty"`    // same as gauss
+	Linear           map[string]interface{}    `json:"linear,omitempty"` // same as gauss
+}
+
+type FieldValueFactorFunction struct {
+	Field    string   `json:"field"`
+	Factor   float64  `json:"factor,omitempty"`
+	Modifier string   `json:"modifier,omitempty"` // none(default), log, log1p, log2p, ln, ln1p, ln2p, square, sqrt, reciprocal
+	Missing  *float64 `json:"missing,omitempty"`
+}
+
+type RandomScoreFunction struct {
+	Seed  interface{} `json:"seed,omitempty"` // number or string
+	Field string      `json:"field,omitempty"`
+}
+
+type ScriptScoreFunction struct {
+	Script *Script `json:"script"`
+}
+
+type ScriptScoreQuery struct {
+	Query    interface{} `json:"query,omitempty"`
+	Script   *Script     `json:"script"`
+	MinScore *float64    `json:"min_score,omitempty"`
+	Boost    float64     `json:"boost,omitempty"`
+}
+
+type Script struct {
+	Source string                 `json:"source"`
+	Lang   string                 `json:"lang,omitempty"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+type MoreLikeThisQuery struct {
+	Fields             []string    `json:"fields,omitempty"`
+	Like               interface{} `json:"like"`             // "text", {"_index": "index", "_id": "1"}, {"doc": {}} or an array of them
+	Unlike             interface{} `json:"unlike,omitempty"` // same as like
+	MaxQueryTerms      int         `json:"max_query_terms,omitempty"`
+	MinTermFreq        int         `json:"min_term_freq,omitempty"`
+	MinDocFreq         int         `json:"min_doc_freq,omitempty"`
+	MaxDocFreq         int         `json:"max_doc_freq,omitempty"`
+	MinWordLength      int         `json:"min_word_length,omitempty"`
+	MaxWordLength      int         `json:"max_word_length,omitempty"`
+	StopWords          []string    `json:"stop_words,omitempty"`
+	Analyzer           string      `json:"analyzer,omitempty"`
+	MinimumShouldMatch interface{} `json:"minimum_should_match,omitempty"` // default 30%
+	BoostTerms         float64     `json:"boost_terms,omitempty"`
+	Include            bool        `json:"include,omitempty"`
+	Boost              float64     `json:"boost,omitempty"`
+}
+
+type PercolateQuery struct {
+	Field     string                   `json:"field"`
+	Document  map[string]interface{}   `json:"document,omitempty"`
+	Documents []map[string]interface{} `json:"documents,omitempty"`
+	Index     string                   `json:"index,omitempty"` // index and id of the existing document to percolate
+	ID        string                   `json:"id,omitempty"`
+	Boost     float64                  `json:"boost,omitempty"`
+}
+
 type MatchAllQuery struct{}
 
 type MatchNoneQuery struct{}
 
 type MatchQuery struct {
-	Query        string      `json:"query,omitempty"`
-	Analyzer     string      `json:"analyzer,omitempty"`
-	Operator     string      `json:"operator,omitempty"`  // or(default), and
-	Fuzziness    interface{} `json:"fuzziness,omitempty"` // auto, 1,2,3,n
-	PrefixLength float64     `json:"prefix_length,omitempty"`
-	Boost        float64     `json:"boost,omitempty"`
+	Query                           string      `json:"query,omitempty"`
+	Analyzer                        string      `json:"analyzer,omitempty"`
+	Operator                        string      `json:"operator,omitempty"`  // or(default), and
+	Fuzziness                       interface{} `json:"fuzziness,omitempty"` // auto, 1,2,3,n
+	PrefixLength                    float64     `json:"prefix_length,omitempty"`
+	MinimumShouldMatch              interface{} `json:"minimum_should_match,omitempty"` // 2, -1, "75%", "3<90%"
+	ZeroTermsQuery                  string      `json:"zero_terms_query,omitempty"`     // none(default), all
+	CutoffFrequency                 float64     `json:"cutoff_frequency,omitempty"`     // absolute(>=1) or relative(<1)
+	Lenient                         bool        `json:"lenient,omitempty"`
+	AutoGenerateSynonymsPhraseQuery *bool       `json:"auto_generate_synonyms_phrase_query,omitempty"`
+	Boost                           float64     `json:"boost,omitempty"`
 }
 
 type MatchBoolPrefixQuery struct {
-	Query    string  `json:"query,omitempty"`
-	Analyzer string  `json:"analyzer,omitempty"`
-	Boost    float64 `json:"boost,omitempty"`
+	Query              string      `json:"query,omitempty"`
+	Analyzer           string      `json:"analyzer,omitempty"`
+	Operator           string      `json:"operator,omitempty"` // or(default), and
+	MinimumShouldMatch interface{} `json:"minimum_should_match,omitempty"`
+	Boost              float64     `json:"boost,omitempty"`
 }
 
 type MatchPhraseQuery struct {
-	Query    string  `json:"query,omitempty"`
-	Analyzer string  `json:"analyzer,omitempty"`
-	Boost    float64 `json:"boost,omitempty"`
+	Query          string  `json:"query,omitempty"`
+	Analyzer       string  `json:"analyzer,omitempty"`
+	Slop           int     `json:"slop,omitempty"`
+	ZeroTermsQuery string  `json:"zero_terms_query,omitempty"` // none(default), all
+	Boost          float64 `json:"boost,omitempty"`
 }
 
 type MatchPhrasePrefixQuery struct {
-	Query    string  `json:"query,omitempty"`
-	Analyzer string  `json:"analyzer,omitempty"`
-	Boost    float64 `json:"boost,omitempty"`
+	Query          string  `json:"query,omitempty"`
+	Analyzer       string  `json:"analyzer,omitempty"`
+	Slop           int     `json:"slop,omitempty"`
+	MaxExpansions  int     `json:"max_expansions,omitempty"`   // default 50
+	ZeroTermsQuery string  `json:"zero_terms_query,omitempty"` // none(default), all
+	Boost          float64 `json:"boost,omitempty"`
 }
 
 type MultiMatchQuery struct {
-	Query              string   `json:"query,omitempty"`
-	Analyzer           string   `json:"analyzer,omitempty"`
-	Fields             []string `json:"fields,omitempty"`
-	Boost              float64  `json:"boost,omitempty"`
-	Type               string   `json:"type,omitempty"`     // best_fields(default), most_fields, cross_fields, phrase, phrase_prefix, bool_prefix
-	Operator           string   `json:"operator,omitempty"` // or(default), and
-	MinimumShouldMatch float64  `json:"minimum_should_match,omitempty"`
+	Query              string      `json:"query,omitempty"`
+	Analyzer           string      `json:"analyzer,omitempty"`
+	Fields             []string    `json:"fields,omitempty"` // field, field^boost, *_pattern
+	Boost              float64     `json:"boost,omitempty"`
+	Type               string      `json:"type,omitempty"`     // best_fields(default), most_fields, cross_fields, phrase, phrase_prefix, bool_prefix
+	Operator           string      `json:"operator,omitempty"` // or(default), and
+	MinimumShouldMatch interface{} `json:"minimum_should_match,omitempty"`
+	TieBreaker         float64     `json:"tie_breaker,omitempty"`
+	Slop               int         `json:"slop,omitempty"`           // only for phrase, phrase_prefix
+	MaxExpansions      int         `json:"max_expansions,omitempty"` // only for phrase_prefix
+	Fuzziness          interface{} `json:"fuzziness,omitempty"`      // only for best_fields, most_fields
+	PrefixLength       float64     `json:"prefix_length,omitempty"`
+	ZeroTermsQuery     string      `json:"zero_terms_query,omitempty"` // none(default), all
+	Lenient            bool        `json:"lenient,omitempty"`
 }
 
 type CombinedFieldsQuery struct {
-	Query              string   `json:"query,omitempty"`
-	Analyzer           string   `json:"analyzer,omitempty"`
-	Fields             []string `json:"fields,omitempty"`
-	Operator           string   `json:"operator,omitempty"` // or(default), and
-	MinimumShouldMatch float64  `json:"minimum_should_match,omitempty"`
+	Query              string      `json:"query,omitempty"`
+	Analyzer           string      `json:"analyzer,omitempty"`
+	Fields             []string    `json:"fields,omitempty"`
+	Operator           string      `json:"operator,omitempty"` // or(default), and
+	MinimumShouldMatch interface{} `json:"minimum_should_match,omitempty"`
 }
 
 type QueryStringQuery struct {
-	Query           string   `json:"query,omitempty"`
-	Analyzer        string   `json:"analyzer,omitempty"`
-	Fields          []string `json:"fields,omitempty"`
-	DefaultField    string   `json:"default_field,omitempty"`
-	DefaultOperator string   `json:"default_operator,omitempty"` // or(default), and
-	Boost           float64  `json:"boost,omitempty"`
+	Query              string      `json:"query,omitempty"`
+	Analyzer           string      `json:"analyzer,omitempty"`
+	Fields             []string    `json:"fields,omitempty"`
+	DefaultField       string      `json:"default_field,omitempty"`
+	DefaultOperator    string      `json:"default_operator,omitempty"` // or(default), and
+	MinimumShouldMatch interface{} `json:"minimum_should_match,omitempty"`
+	Lenient            bool        `json:"lenient,omitempty"`
+	Boost              float64     `json:"boost,omitempty"`
 }
 
 type SimpleQueryStringQuery struct {
-	Query           string   `json:"query,omitempty"`
-	Analyzer        string   `json:"analyzer,omitempty"`
-	Fields          []string `json:"fields,omitempty"`
-	DefaultOperator string   `json:"default_operator,omitempty"` // or(default), and
-	AllFields       bool     `json:"all_fields,omitempty"`
-	Boost           float64  `json:"boost,omitempty"`
+	Query              string      `json:"query,omitempty"`
+	Analyzer           string      `json:"analyzer,omitempty"`
+	Fields             []string    `json:"fields,omitempty"`
+	DefaultOperator    string      `json:"default_operator,omitempty"` // or(default), and
+	AllFields          bool        `json:"all_fields,omitempty"`
+	MinimumShouldMatch interface{} `json:"minimum_should_match,omitempty"`
+	Lenient            bool        `json:"lenient,omitempty"`
+	Boost              float64     `json:"boost,omitempty"`
 }
 
 // ExistsQuery
@@ -377,6 +486,19 @@ type AggregationAutoDateHistogram struct {
 	Keyed           bool   `json:"keyed"`
 }
 
+type Collapse struct {
+	Field                      string      `json:"field"`
+	InnerHits                  interface{} `json:"inner_hits,omitempty"` // single or multiple inner hits
+	MaxConcurrentGroupSearches int         `json:"max_concurrent_group_searches,omitempty"`
+}
+
+type InnerHits struct {
+	Name string      `json:"name"`
+	From int         `json:"from"`
+	Size int         `json:"size"`
+	Sort interface{} `json:"sort"`
+}
+
 type Highlight struct {
 	NumberOfFragments int                   `json:"number_of_fragments"`
 	FragmentSize      int                   `json:"fragment_size"`
@@ -24,6 +24,7 @@ type SearchResponse struct {
 	Shards       Shards                         `json:"_shards"`
 	Hits         Hits                           `json:"hits"`
 	Aggregations map[string]AggregationResponse `json:"aggregations,omitempty"`
+	Suggest      map[string][]SuggestEntry      `json:"suggest,omitempty"`
 	Error        string                         `json:"error,omitempty"`
 }
 
@@ -41,14 +42,41 @@ type Hits struct {
 }
 
 type Hit struct {
-	Index     string                 `json:"_index"`
-	Type      string                 `json:"_type"`
-	ID        string                 `json:"_id"`
-	Score     float64                `json:"_score"`
-	Timestamp time.Time              `json:"@timestamp"`
-	Source    interface{}            `json:"_source,omitempty"`
-	Fields    map[string]interface{} `json:"fields,omitempty"`
-	Highlight map[string]interface{} `json:"highlight,omitempty"`
+	Index     string                       `json:"_index"`
+	Type      string                       `json:"_type"`
+	ID        string                       `json:"_id"`
+	Score     float64                      `json:"_score"`
+	Timestamp time.Time                    `json:"@timestamp"`
+	Source    interface{}                  `json:"_source,omitempty"`
+	Fields    map[string]interface{}       `json:"fields,omitempty"`
+	Highlight map[string]interface{}       `json:"highlight,omitempty"`
+	Sort      []interface{}                `json:"sort,omitempty"`
+	InnerHits map[string]InnerHitsResponse `json:"inner_hits,omitempty"`
+}
+
+type InnerHitsResponse struct {
+	Hits Hits `json:"hits"`
+}
+
+// SuggestEntry is the suggestions of a token of the suggest text, or the whole text for phrase and completion suggester
+type SuggestEntry struct {
+	Text    string          `json:"text"`
+	Offset  int             `json:"offset"`
+	Length  int             `json:"length"`
+	Options []SuggestOption `json:"options"`
+}
+
+// SuggestOption is a suggestion, the term and phrase suggester return score, the completion suggester returns the document
+type SuggestOption struct {
+	Text        string              `json:"text"`
+	Highlighted string              `json:"highlighted,omitempty"`
+	Score       float64             `json:"score,omitempty"`
+	Freq        int                 `json:"freq,omitempty"`
+	Index       string              `json:"_index,omitempty"`
+	ID          string              `json:"_id,omitempty"`
+	DocScore    float64             `json:"_score,omitempty"`
+	Source      interface{}         `json:"_source,omitempty"`
+	Contexts    map[string][]string `json:"contexts,omitempty"`
 }
 
 type Total struct {
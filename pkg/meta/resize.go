@@ -0,0 +1,38 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package meta
+
+const (
+	IndexResizeTypeSplit  = "split"
+	IndexResizeTypeShrink = "shrink"
+	IndexResizeTypeClone  = "clone"
+)
+
+// IndexResizeRequest is the body of split, shrink and clone APIs
+type IndexResizeRequest struct {
+	Settings *IndexSettings         `json:"settings"`
+	Aliases  map[string]interface{} `json:"aliases"`
+	// SwapAliases moves the aliases of source index to the target index when completed
+	SwapAliases bool `json:"swap_aliases"`
+}
+
+type IndexResizeResponse struct {
+	Acknowledged       bool     `json:"acknowledged"`
+	ShardsAcknowledged bool     `json:"shards_acknowledged"`
+	Index              string   `json:"index"`
+	Docs               int64    `json:"docs"`
+	Aliases            []string `json:"aliases,omitempty"`
+}
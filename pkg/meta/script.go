@@ -0,0 +1,32 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package meta
+
+// StoredScript is the stored script, only the mustache search template is supported
+type StoredScript struct {
+	Lang    string            `json:"lang"`
+	Source  string            `json:"source"`
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// SearchTemplateRequest renders the inline source or the stored script of id with params
+type SearchTemplateRequest struct {
+	ID      string                 `json:"id,omitempty"`
+	Source  interface{}            `json:"source,omitempty"` // string or object
+	Params  map[string]interface{} `json:"params,omitempty"`
+	Explain bool                   `json:"explain,omitempty"`
+	Profile bool                   `json:"profile,omitempty"`
+}
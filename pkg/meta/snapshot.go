@@ -0,0 +1,93 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package meta
+
+import "time"
+
+const (
+	SnapshotRepositoryTypeFS = "fs"
+
+	SnapshotStateInProgress = "IN_PROGRESS"
+	SnapshotStateSuccess    = "SUCCESS"
+	SnapshotStateFailed     = "FAILED"
+)
+
+type SnapshotRepository struct {
+	Name     string                     `json:"name"`
+	Type     string                     `json:"type"`
+	Settings SnapshotRepositorySettings `json:"settings"`
+}
+
+type SnapshotRepositorySettings struct {
+	Location string `json:"location"`
+}
+
+type Snapshot struct {
+	Snapshot         string        `json:"snapshot"`
+	UUID             string        `json:"uuid"`
+	Repository       string        `json:"repository"`
+	Version          string        `json:"version"`
+	Indices          []string      `json:"indices"`
+	State            string        `json:"state"`
+	Reason           string        `json:"reason,omitempty"`
+	StartTime        time.Time     `json:"start_time"`
+	EndTime          time.Time     `json:"end_time"`
+	DurationInMillis int64         `json:"duration_in_millis"`
+	Stats            SnapshotStats `json:"stats"`
+}
+
+type SnapshotStats struct {
+	IncrementalFileCount   int   `json:"incremental_file_count"`
+	IncrementalSizeInBytes int64 `json:"incremental_size_in_bytes"`
+	TotalFileCount         int   `json:"total_file_count"`
+	TotalSizeInBytes       int64 `json:"total_size_in_bytes"`
+}
+
+// SnapshotManifest is the content of a snapshot stored in repository
+type SnapshotManifest struct {
+	Snapshot
+	Indexes            map[string]*SnapshotIndex `json:"indexes"`
+	ComponentTemplates []*ComponentTemplate      `json:"component_templates"`
+	Templates          []*Template               `json:"templates"`
+	Aliases            map[string][]string       `json:"aliases"`
+	Users              []*User                   `json:"users"`
+	Roles              []*Role                   `json:"roles"`
+}
+
+type SnapshotIndex struct {
+	Index *Index `json:"index"`
+	// Files segment files of second layer shards, key is shardID/secondShardID
+	Files map[string][]SnapshotFile `json:"files"`
+}
+
+type SnapshotFile struct {
+	Name string `json:"name"`
+	Blob string `json:"blob"` // sha256 of file content
+	Size int64  `json:"size"`
+}
+
+type SnapshotCreateRequest struct {
+	Indices            string `json:"indices"`
+	IncludeGlobalState *bool  `json:"include_global_state"`
+}
+
+type SnapshotRestoreRequest struct {
+	Indices            string `json:"indices"`
+	IncludeGlobalState bool   `json:"include_global_state"`
+	IncludeAliases     *bool  `json:"include_aliases"`
+	RenamePattern      string `json:"rename_pattern"`
+	RenameReplacement  string `json:"rename_replacement"`
+}
@@ -0,0 +1,141 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package meta
+
+// IndexStats runtime stats of index, counters are reset after restart
+type IndexStats struct {
+	Docs     DocsStats     `json:"docs"`
+	Store    StoreStats    `json:"store"`
+	Indexing IndexingStats `json:"indexing"`
+	Search   SearchStats   `json:"search"`
+	WAL      WALStats      `json:"wal"`
+	Segments SegmentsStats `json:"segments"`
+	Readers  ReadersStats  `json:"readers"`
+	// Shards only returned when request with level=shards
+	Shards map[string]*IndexShardStats `json:"shards,omitempty"`
+}
+
+type DocsStats struct {
+	Count uint64 `json:"count"`
+}
+
+type StoreStats struct {
+	SizeInBytes uint64 `json:"size_in_bytes"`
+}
+
+type IndexingStats struct {
+	IndexTotal  uint64 `json:"index_total"`
+	IndexFailed uint64 `json:"index_failed"`
+	DeleteTotal uint64 `json:"delete_total"`
+}
+
+type SearchStats struct {
+	QueryTotal           uint64  `json:"query_total"`
+	QueryTimeInMillis    uint64  `json:"query_time_in_millis"`
+	QueryAvgTimeInMillis float64 `json:"query_avg_time_in_millis"`
+}
+
+type WALStats struct {
+	Operations uint64 `json:"operations"`
+	// Lag is the number of WAL entries not consumed into the index yet
+	Lag uint64 `json:"uncommitted_operations"`
+}
+
+type SegmentsStats struct {
+	Count uint64 `json:"count"`
+}
+
+type ReadersStats struct {
+	Open int64 `json:"open"`
+}
+
+// IndexShardStats stats of first layer shard
+type IndexShardStats struct {
+	WAL    WALStats                 `json:"wal"`
+	Shards []*IndexSecondShardStats `json:"shards"`
+}
+
+// IndexSecondShardStats stats of second layer shard
+type IndexSecondShardStats struct {
+	ID       int64         `json:"id"`
+	Docs     DocsStats     `json:"docs"`
+	Store    StoreStats    `json:"store"`
+	Segments SegmentsStats `json:"segments"`
+}
+
+// Add merge other stats into current stats, used to sum stats of indexes
+func (s *IndexStats) Add(o *IndexStats) {
+	s.Docs.Count += o.Docs.Count
+	s.Store.SizeInBytes += o.Store.SizeInBytes
+	s.Indexing.IndexTotal += o.Indexing.IndexTotal
+	s.Indexing.IndexFailed += o.Indexing.IndexFailed
+	s.Indexing.DeleteTotal += o.Indexing.DeleteTotal
+	s.Search.QueryTotal += o.Search.QueryTotal
+	s.Search.QueryTimeInMillis += o.Search.QueryTimeInMillis
+	if s.Search.QueryTotal > 0 {
+		s.Search.QueryAvgTimeInMillis = float64(s.Search.QueryTimeInMillis) / float64(s.Search.QueryTotal)
+	}
+	s.WAL.Operations += o.WAL.Operations
+	s.WAL.Lag += o.WAL.Lag
+	s.Segments.Count += o.Segments.Count
+	s.Readers.Open += o.Readers.Open
+}
+
+// NodeStats runtime stats of current node process
+type NodeStats struct {
+	Name      string      `json:"name"`
+	Timestamp int64       `json:"timestamp"`
+	Process   NodeProcess `json:"process"`
+	Runtime   NodeRuntime `json:"runtime"`
+	FS        NodeFS      `json:"fs"`
+	Indices   IndexStats  `json:"indices"`
+}
+
+type NodeProcess struct {
+	CPUPercent float64    `json:"cpu_percent"`
+	Mem        NodeMemory `json:"mem"`
+}
+
+type NodeMemory struct {
+	ResidentInBytes uint64 `json:"resident_in_bytes"`
+	VirtualInBytes  uint64 `json:"total_virtual_in_bytes"`
+}
+
+type NodeRuntime struct {
+	UptimeInMillis int64    `json:"uptime_in_millis"`
+	Goroutines     int      `json:"goroutines"`
+	Mem            NodeHeap `json:"mem"`
+	GC             NodeGC   `json:"gc"`
+}
+
+type NodeHeap struct {
+	HeapUsedInBytes      uint64 `json:"heap_used_in_bytes"`
+	HeapCommittedInBytes uint64 `json:"heap_committed_in_bytes"`
+	SysInBytes           uint64 `json:"sys_in_bytes"`
+}
+
+type NodeGC struct {
+	CollectionCount        uint32 `json:"collection_count"`
+	CollectionTimeInMillis uint64 `json:"collection_time_in_millis"`
+	LastPauseInMicros      uint64 `json:"last_pause_in_micros"`
+}
+
+type NodeFS struct {
+	Path         string `json:"path"`
+	TotalInBytes uint64 `json:"total_in_bytes"`
+	FreeInBytes  uint64 `json:"free_in_bytes"`
+	DataInBytes  uint64 `json:"data_in_bytes"`
+}
@@ -0,0 +1,52 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package meta
+
+const (
+	TaskActionResizeSplit  = "indices:admin/resize/split"
+	TaskActionResizeShrink = "indices:admin/resize/shrink"
+	TaskActionResizeClone  = "indices:admin/resize/clone"
+	TaskActionMappingPut   = "indices:admin/mapping/put"
+)
+
+// TaskResult is the response of the task API, it is compatible with ES
+type TaskResult struct {
+	Completed bool        `json:"completed"`
+	Task      TaskInfo    `json:"task"`
+	Response  interface{} `json:"response,omitempty"`
+	Error     interface{} `json:"error,omitempty"`
+}
+
+type TaskInfo struct {
+	Node               string     `json:"node"`
+	ID                 string     `json:"id"`
+	Type               string     `json:"type"`
+	Action             string     `json:"action"`
+	Status             TaskStatus `json:"status"`
+	Description        string     `json:"description"`
+	StartTimeInMillis  int64      `json:"start_time_in_millis"`
+	RunningTimeInNanos int64      `json:"running_time_in_nanos"`
+	Cancellable        bool       `json:"cancellable"`
+}
+
+// TaskStatus is the progress of the task, Total is the number of documents need to process
+type TaskStatus struct {
+	Total   int64 `json:"total"`
+	Created int64 `json:"created"`
+	Updated int64 `json:"updated"`
+	Deleted int64 `json:"deleted"`
+	Failed  int64 `json:"failed"`
+}
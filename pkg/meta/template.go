@@ -25,12 +25,39 @@ type Template struct {
 type IndexTemplate struct {
 	IndexPatterns []string         `json:"index_patterns"`
 	Priority      int              `json:"priority"` // highest priority is chosen
+	ComposedOf    []string         `json:"composed_of,omitempty"`
 	Template      TemplateTemplate `json:"template"`
 	CreatedAt     time.Time        `json:"created_at"`
 	UpdatedAt     time.Time        `json:"updated_at"`
 }
 
 type TemplateTemplate struct {
-	Settings *IndexSettings `json:"settings,omitempty"`
-	Mappings *Mappings      `json:"mappings,omitempty"`
+	Settings *IndexSettings         `json:"settings,omitempty"`
+	Mappings *Mappings              `json:"mappings,omitempty"`
+	Aliases  map[string]interface{} `json:"aliases,omitempty"`
+}
+
+type ComponentTemplate struct {
+	Name              string                 `json:"name"`
+	ComponentTemplate *ComponentTemplateData `json:"component_template"`
+}
+
+type ComponentTemplateData struct {
+	Template  TemplateTemplate       `json:"template"`
+	Version   int64                  `json:"version,omitempty"`
+	Meta      map[string]interface{} `json:"_meta,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+// IndexTemplateSimulateResponse the resolved template for a new index
+type IndexTemplateSimulateResponse struct {
+	Template    TemplateTemplate             `json:"template"`
+	Overlapping []IndexTemplateSimulateMatch `json:"overlapping"`
+}
+
+// IndexTemplateSimulateMatch the lower priority template which also matches the index name
+type IndexTemplateSimulateMatch struct {
+	Name          string   `json:"name"`
+	IndexPatterns []string `json:"index_patterns"`
 }
@@ -0,0 +1,68 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package metadata
+
+import (
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+)
+
+type asyncSearch struct{}
+
+var AsyncSearch = new(asyncSearch)
+
+func (t *asyncSearch) List(offset, limit int) ([]*meta.AsyncSearchResponse, error) {
+	data, err := db.List(t.key(""), offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	searches := make([]*meta.AsyncSearchResponse, 0, len(data))
+	for _, d := range data {
+		s := new(meta.AsyncSearchResponse)
+		err = json.Unmarshal(d, s)
+		if err != nil {
+			return nil, err
+		}
+		searches = append(searches, s)
+	}
+	return searches, nil
+}
+
+func (t *asyncSearch) Get(id string) (*meta.AsyncSearchResponse, error) {
+	data, err := db.Get(t.key(id))
+	if err != nil {
+		return nil, err
+	}
+	s := new(meta.AsyncSearchResponse)
+	err = json.Unmarshal(data, s)
+	return s, err
+}
+
+func (t *asyncSearch) Set(id string, val meta.AsyncSearchResponse) error {
+	data, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	return db.Set(t.key(id), data)
+}
+
+func (t *asyncSearch) Delete(id string) error {
+	return db.Delete(t.key(id))
+}
+
+func (t *asyncSearch) key(id string) string {
+	return "/async_search/" + id
+}
@@ -0,0 +1,51 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package metadata
+
+import (
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+)
+
+type storedScript struct{}
+
+var StoredScript = new(storedScript)
+
+func (t *storedScript) Get(id string) (*meta.StoredScript, error) {
+	data, err := db.Get(t.key(id))
+	if err != nil {
+		return nil, err
+	}
+	script := new(meta.StoredScript)
+	err = json.Unmarshal(data, script)
+	return script, err
+}
+
+func (t *storedScript) Set(id string, val meta.StoredScript) error {
+	data, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	return db.Set(t.key(id), data)
+}
+
+func (t *storedScript) Delete(id string) error {
+	return db.Delete(t.key(id))
+}
+
+func (t *storedScript) key(id string) string {
+	return "/script/" + id
+}
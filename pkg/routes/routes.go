@@ -31,9 +31,12 @@ import (
 	"github.com/zincsearch/zincsearch"
 	"github.com/zincsearch/zincsearch/pkg/config"
 	"github.com/zincsearch/zincsearch/pkg/handlers/auth"
+	"github.com/zincsearch/zincsearch/pkg/handlers/cat"
 	"github.com/zincsearch/zincsearch/pkg/handlers/document"
 	"github.com/zincsearch/zincsearch/pkg/handlers/index"
 	"github.com/zincsearch/zincsearch/pkg/handlers/search"
+	"github.com/zincsearch/zincsearch/pkg/handlers/snapshot"
+	"github.com/zincsearch/zincsearch/pkg/handlers/task"
 	"github.com/zincsearch/zincsearch/pkg/meta"
 	"github.com/zincsearch/zincsearch/pkg/meta/elastic"
 	"github.com/zincsearch/zincsearch/pkg/zutils"
@@ -114,6 +117,7 @@ func SetRoutes(r *gin.Engine) {
 	// analyze
 	r.POST("/api/_analyze", AuthMiddleware("index.Analyze"), index.Analyze)
 	r.POST("/api/:target/_analyze", AuthMiddleware("index.Analyze"), index.Analyze)
+	r.POST("/api/:target/_reload_search_analyzers", AuthMiddleware("index.ReloadSearchAnalyzers"), index.ReloadSearchAnalyzers)
 
 	// search
 	r.POST("/api/:target/_search", AuthMiddleware("search.SearchV1"), search.SearchV1)
@@ -156,6 +160,20 @@ func SetRoutes(r *gin.Engine) {
 	r.POST("/es/:target/_search", AuthMiddleware("search.SearchDSL"), ESMiddleware, IndexAliasMiddleware, search.SearchDSL)
 	r.POST("/es/:target/_msearch", AuthMiddleware("search.MultipleSearch"), ESMiddleware, IndexAliasMiddleware, search.MultipleSearch)
 	r.POST("/es/:target/_delete_by_query", AuthMiddleware("search.DeleteByQuery"), IndexAliasMiddleware, search.DeleteByQuery)
+	r.POST("/es/_search/template", AuthMiddleware("search.SearchTemplate"), ESMiddleware, IndexAliasMiddleware, search.SearchTemplate)
+	r.POST("/es/:target/_search/template", AuthMiddleware("search.SearchTemplate"), ESMiddleware, IndexAliasMiddleware, search.SearchTemplate)
+	r.POST("/es/_msearch/template", AuthMiddleware("search.MultipleSearchTemplate"), ESMiddleware, IndexAliasMiddleware, search.MultipleSearchTemplate)
+	r.POST("/es/:target/_msearch/template", AuthMiddleware("search.MultipleSearchTemplate"), ESMiddleware, IndexAliasMiddleware, search.MultipleSearchTemplate)
+	r.POST("/es/_render/template", AuthMiddleware("search.RenderTemplate"), ESMiddleware, search.RenderTemplate)
+	r.POST("/es/_render/template/:id", AuthMiddleware("search.RenderTemplate"), ESMiddleware, search.RenderTemplate)
+	r.POST("/es/_async_search", AuthMiddleware("search.SubmitAsyncSearch"), ESMiddleware, IndexAliasMiddleware, search.SubmitAsyncSearch)
+	r.POST("/es/:target/_async_search", AuthMiddleware("search.SubmitAsyncSearch"), ESMiddleware, IndexAliasMiddleware, search.SubmitAsyncSearch)
+	r.GET("/es/_async_search/:id", AuthMiddleware("search.GetAsyncSearch"), ESMiddleware, search.GetAsyncSearch)
+	r.DELETE("/es/_async_search/:id", AuthMiddleware("search.DeleteAsyncSearch"), ESMiddleware, search.DeleteAsyncSearch)
+	r.GET("/es/_scripts/:id", AuthMiddleware("search.GetScript"), ESMiddleware, search.GetScript)
+	r.PUT("/es/_scripts/:id", AuthMiddleware("search.PutScript"), ESMiddleware, search.PutScript)
+	r.POST("/es/_scripts/:id", AuthMiddleware("search.PutScript"), ESMiddleware, search.PutScript)
+	r.DELETE("/es/_scripts/:id", AuthMiddleware("search.DeleteScript"), ESMiddleware, search.DeleteScript)
 
 	r.GET("/es/_index_template", AuthMiddleware("index.ListTemplate"), ESMiddleware, index.ListTemplate)
 	r.POST("/es/_index_template", AuthMiddleware("index.CreateTemplate"), ESMiddleware, index.CreateTemplate)
@@ -163,11 +181,48 @@ func SetRoutes(r *gin.Engine) {
 	r.GET("/es/_index_template/:target", AuthMiddleware("index.GetTemplate"), ESMiddleware, index.GetTemplate)
 	r.HEAD("/es/_index_template/:target", AuthMiddleware("index.GetTemplate"), ESMiddleware, index.GetTemplate)
 	r.DELETE("/es/_index_template/:target", AuthMiddleware("index.DeleteTemplate"), ESMiddleware, index.DeleteTemplate)
+	r.POST("/es/_index_template/_simulate_index/:target", AuthMiddleware("index.SimulateIndexTemplate"), ESMiddleware, index.SimulateIndexTemplate)
+	r.GET("/es/_component_template", AuthMiddleware("index.ListComponentTemplate"), ESMiddleware, index.ListComponentTemplate)
+	r.PUT("/es/_component_template/:target", AuthMiddleware("index.CreateComponentTemplate"), ESMiddleware, index.CreateComponentTemplate)
+	r.POST("/es/_component_template/:target", AuthMiddleware("index.CreateComponentTemplate"), ESMiddleware, index.CreateComponentTemplate)
+	r.GET("/es/_component_template/:target", AuthMiddleware("index.GetComponentTemplate"), ESMiddleware, index.GetComponentTemplate)
+	r.HEAD("/es/_component_template/:target", AuthMiddleware("index.GetComponentTemplate"), ESMiddleware, index.GetComponentTemplate)
+	r.DELETE("/es/_component_template/:target", AuthMiddleware("index.DeleteComponentTemplate"), ESMiddleware, index.DeleteComponentTemplate)
 	// ES Compatible data stream
 	r.PUT("/es/_data_stream/:target", AuthMiddleware("elastic.PutDataStream"), ESMiddleware, elastic.PutDataStream)
 	r.GET("/es/_data_stream/:target", AuthMiddleware("elastic.GetDataStream"), ESMiddleware, elastic.GetDataStream)
 	r.HEAD("/es/_data_stream/:target", AuthMiddleware("elastic.GetDataStream"), ESMiddleware, elastic.GetDataStream)
 
+	// ES Compatible cat APIs
+	r.GET("/es/_cat", AuthMiddleware("cat.Cat"), ESMiddleware, cat.Cat)
+	r.GET("/es/_cat/indices", AuthMiddleware("cat.Indices"), ESMiddleware, cat.Indices)
+	r.GET("/es/_cat/indices/:target", AuthMiddleware("cat.Indices"), ESMiddleware, IndexAliasMiddleware, cat.Indices)
+	r.GET("/es/_cat/shards", AuthMiddleware("cat.Shards"), ESMiddleware, cat.Shards)
+	r.GET("/es/_cat/shards/:target", AuthMiddleware("cat.Shards"), ESMiddleware, IndexAliasMiddleware, cat.Shards)
+	r.GET("/es/_cat/aliases", AuthMiddleware("cat.Aliases"), ESMiddleware, cat.Aliases)
+	r.GET("/es/_cat/aliases/:target_alias", AuthMiddleware("cat.Aliases"), ESMiddleware, cat.Aliases)
+	r.GET("/es/_cat/templates", AuthMiddleware("cat.Templates"), ESMiddleware, cat.Templates)
+	r.GET("/es/_cat/templates/:target", AuthMiddleware("cat.Templates"), ESMiddleware, cat.Templates)
+	r.GET("/es/_cat/health", AuthMiddleware("cat.Health"), ESMiddleware, cat.Health)
+	// ES Compatible stats
+	r.GET("/es/_stats", AuthMiddleware("index.Stats"), ESMiddleware, index.Stats)
+	r.GET("/es/:target/_stats", AuthMiddleware("index.Stats"), ESMiddleware, IndexAliasMiddleware, index.Stats)
+	r.GET("/es/_nodes/stats", AuthMiddleware("index.NodesStats"), ESMiddleware, index.NodesStats)
+
+	r.GET("/es/_snapshot", AuthMiddleware("snapshot.GetRepository"), ESMiddleware, snapshot.GetRepository)
+	r.GET("/es/_snapshot/:repository", AuthMiddleware("snapshot.GetRepository"), ESMiddleware, snapshot.GetRepository)
+	r.PUT("/es/_snapshot/:repository", AuthMiddleware("snapshot.PutRepository"), ESMiddleware, snapshot.PutRepository)
+	r.POST("/es/_snapshot/:repository", AuthMiddleware("snapshot.PutRepository"), ESMiddleware, snapshot.PutRepository)
+	r.DELETE("/es/_snapshot/:repository", AuthMiddleware("snapshot.DeleteRepository"), ESMiddleware, snapshot.DeleteRepository)
+	r.GET("/es/_snapshot/:repository/:snapshot", AuthMiddleware("snapshot.GetSnapshot"), ESMiddleware, snapshot.GetSnapshot)
+	r.PUT("/es/_snapshot/:repository/:snapshot", AuthMiddleware("snapshot.CreateSnapshot"), ESMiddleware, snapshot.CreateSnapshot)
+	r.POST("/es/_snapshot/:repository/:snapshot", AuthMiddleware("snapshot.CreateSnapshot"), ESMiddleware, snapshot.CreateSnapshot)
+	r.DELETE("/es/_snapshot/:repository/:snapshot", AuthMiddleware("snapshot.DeleteSnapshot"), ESMiddleware, snapshot.DeleteSnapshot)
+	r.POST("/es/_snapshot/:repository/:snapshot/_restore", AuthMiddleware("snapshot.RestoreSnapshot"), ESMiddleware, snapshot.RestoreSnapshot)
+
+	r.GET("/es/_tasks", AuthMiddleware("task.List"), ESMiddleware, task.List)
+	r.GET("/es/_tasks/:task_id", AuthMiddleware("task.Get"), ESMiddleware, task.Get)
+
 	r.PUT("/es/:target", AuthMiddleware("index.CreateES"), ESMiddleware, index.CreateES)
 	r.HEAD("/es/:target", AuthMiddleware("index.Exists"), ESMiddleware, index.Exists)
 
@@ -179,6 +234,7 @@ func SetRoutes(r *gin.Engine) {
 
 	r.POST("/es/_analyze", AuthMiddleware("index.Analyze"), ESMiddleware, index.Analyze)
 	r.POST("/es/:target/_analyze", AuthMiddleware("index.Analyze"), ESMiddleware, index.Analyze)
+	r.POST("/es/:target/_reload_search_analyzers", AuthMiddleware("index.ReloadSearchAnalyzers"), ESMiddleware, index.ReloadSearchAnalyzers)
 
 	r.POST("/es/_aliases", AuthMiddleware("index.AddOrRemoveESAlias"), ESMiddleware, index.AddOrRemoveESAlias)
 
@@ -191,6 +247,14 @@ func SetRoutes(r *gin.Engine) {
 	r.POST("/es/:target/_bulk", AuthMiddleware("document.ESBulk"), ESMiddleware, document.ESBulk)
 	r.PUT("/es/:target/_bulk", AuthMiddleware("document.ESBulk"), ESMiddleware, document.ESBulk)
 	r.POST("/es/:target/_refresh", AuthMiddleware("index.Refresh"), index.Refresh)
+	r.POST("/es/:target/_close", AuthMiddleware("index.Close"), ESMiddleware, index.Close)
+	r.POST("/es/:target/_open", AuthMiddleware("index.Open"), ESMiddleware, index.Open)
+	r.POST("/es/:target/_split/:target_index", AuthMiddleware("index.Split"), ESMiddleware, index.Split)
+	r.PUT("/es/:target/_split/:target_index", AuthMiddleware("index.Split"), ESMiddleware, index.Split)
+	r.POST("/es/:target/_shrink/:target_index", AuthMiddleware("index.Shrink"), ESMiddleware, index.Shrink)
+	r.PUT("/es/:target/_shrink/:target_index", AuthMiddleware("index.Shrink"), ESMiddleware, index.Shrink)
+	r.POST("/es/:target/_clone/:target_index", AuthMiddleware("index.Clone"), ESMiddleware, index.Clone)
+	r.PUT("/es/:target/_clone/:target_index", AuthMiddleware("index.Clone"), ESMiddleware, index.Clone)
 	// ES Document
 	r.POST("/es/:target/_doc", AuthMiddleware("document.CreateUpdate"), ESMiddleware, document.CreateUpdate)        // create
 	r.PUT("/es/:target/_doc/:id", AuthMiddleware("document.CreateUpdate"), ESMiddleware, document.CreateUpdate)     // create or update
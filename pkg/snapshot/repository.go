@@ -0,0 +1,168 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/metadata"
+)
+
+const (
+	blobsDir     = "blobs"
+	snapshotsDir = "snapshots"
+	tmpDir       = "tmp"
+)
+
+var nameRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// repositoryLocks only one operation can change a repository at the same time
+var repositoryLocks = struct {
+	locks map[string]*sync.Mutex
+	lock  sync.Mutex
+}{locks: make(map[string]*sync.Mutex)}
+
+func lockRepository(name string) (func(), error) {
+	repositoryLocks.lock.Lock()
+	l, ok := repositoryLocks.locks[name]
+	if !ok {
+		l = new(sync.Mutex)
+		repositoryLocks.locks[name] = l
+	}
+	repositoryLocks.lock.Unlock()
+	if !l.TryLock() {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "repository ["+name+"] is busy with another snapshot operation")
+	}
+	return l.Unlock, nil
+}
+
+// CheckName checks the name of repository or snapshot
+func CheckName(name string) error {
+	if !nameRe.MatchString(name) {
+		return errors.New(errors.ErrorTypeInvalidArgument, "name ["+name+"] is invalid, just accept [a-zA-Z0-9_.-] and can not start with [_.-]")
+	}
+	return nil
+}
+
+// PutRepository registers a snapshot repository, it creates the location if not exists
+func PutRepository(name string, repo *meta.SnapshotRepository) error {
+	if err := CheckName(name); err != nil {
+		return err
+	}
+	if repo.Type == "" {
+		repo.Type = meta.SnapshotRepositoryTypeFS
+	}
+	if repo.Type != meta.SnapshotRepositoryTypeFS {
+		return errors.New(errors.ErrorTypeInvalidArgument, "repository type ["+repo.Type+"] is not supported, just accept [fs]")
+	}
+	location, err := resolveLocation(repo.Settings.Location)
+	if err != nil {
+		return err
+	}
+	for _, dir := range []string{blobsDir, snapshotsDir} {
+		if err = os.MkdirAll(filepath.Join(location, dir), 0755); err != nil {
+			return errors.New(errors.ErrorTypeRuntimeException, "create repository location error").Cause(err)
+		}
+	}
+
+	repo.Name = name
+	return metadata.Repository.Set(name, *repo)
+}
+
+// GetRepository returns the repository by name
+func GetRepository(name string) (*meta.SnapshotRepository, bool, error) {
+	repo, err := metadata.Repository.Get(name)
+	if err != nil {
+		if err == errors.ErrKeyNotFound {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return repo, true, nil
+}
+
+// ListRepositories returns all repositories
+func ListRepositories() ([]*meta.SnapshotRepository, error) {
+	return metadata.Repository.List(0, 0)
+}
+
+// DeleteRepository unregisters the repository, the snapshots in location are kept
+func DeleteRepository(name string) error {
+	_, ok, err := GetRepository(name)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New(errors.ErrorTypeRepositoryMissing, "repository ["+name+"] does not exists")
+	}
+	unlock, err := lockRepository(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return metadata.Repository.Delete(name)
+}
+
+// resolveLocation returns the absolute location, it must under one of config.Global.SnapshotRepoPath.
+// Relative location is relative to the first repo path.
+func resolveLocation(location string) (string, error) {
+	if location == "" {
+		return "", errors.New(errors.ErrorTypeInvalidArgument, "repository location is required")
+	}
+	if len(config.Global.SnapshotRepoPath) == 0 {
+		return "", errors.New(errors.ErrorTypeInvalidArgument, "ZINC_SNAPSHOT_REPO_PATH is not configured")
+	}
+	if !filepath.IsAbs(location) {
+		location = filepath.Join(config.Global.SnapshotRepoPath[0], location)
+	}
+	location, err := filepath.Abs(location)
+	if err != nil {
+		return "", errors.New(errors.ErrorTypeInvalidArgument, "repository location is invalid").Cause(err)
+	}
+	for _, repoPath := range config.Global.SnapshotRepoPath {
+		repoPath, err := filepath.Abs(strings.TrimSpace(repoPath))
+		if err != nil {
+			continue
+		}
+		if location == repoPath || strings.HasPrefix(location, repoPath+string(filepath.Separator)) {
+			return location, nil
+		}
+	}
+	return "", errors.New(errors.ErrorTypeInvalidArgument, "repository location ["+location+"] doesn't match any of the locations specified by ZINC_SNAPSHOT_REPO_PATH")
+}
+
+// getRepositoryLocation returns the repository and its resolved location
+func getRepositoryLocation(name string) (*meta.SnapshotRepository, string, error) {
+	repo, ok, err := GetRepository(name)
+	if err != nil {
+		return nil, "", err
+	}
+	if !ok {
+		return nil, "", errors.New(errors.ErrorTypeRepositoryMissing, "repository ["+name+"] does not exists")
+	}
+	location, err := resolveLocation(repo.Settings.Location)
+	if err != nil {
+		return nil, "", err
+	}
+	return repo, location, nil
+}
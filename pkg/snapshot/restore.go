@@ -0,0 +1,204 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package snapshot
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/zincsearch/zincsearch/pkg/auth"
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+)
+
+// RestoreSnapshot restores indexes from the snapshot, returns the restored index names.
+// Indexes can be renamed by RenamePattern and RenameReplacement, the target index must not exist.
+func RestoreSnapshot(repoName, name string, req *meta.SnapshotRestoreRequest) ([]string, error) {
+	if err := CheckName(name); err != nil {
+		return nil, err
+	}
+	_, location, err := getRepositoryLocation(repoName)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil {
+		req = new(meta.SnapshotRestoreRequest)
+	}
+	var renameRe *regexp.Regexp
+	if req.RenamePattern != "" {
+		if renameRe, err = regexp.Compile(req.RenamePattern); err != nil {
+			return nil, errors.New(errors.ErrorTypeInvalidArgument, "rename_pattern is invalid").Cause(err)
+		}
+	}
+
+	unlock, err := lockRepository(repoName)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	manifest, err := loadManifest(location, name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.New(errors.ErrorTypeSnapshotMissing, "snapshot ["+repoName+":"+name+"] is missing")
+		}
+		return nil, err
+	}
+	if manifest.State != meta.SnapshotStateSuccess {
+		return nil, errors.New(errors.ErrorTypeInvalidArgument, "snapshot ["+repoName+":"+name+"] state is ["+manifest.State+"], can not restore")
+	}
+
+	// resolve target names and check them before copying any data
+	renamed := make(map[string]string)
+	targets := make(map[string]struct{})
+	for indexName := range manifest.Indexes {
+		if !core.MatchNames(req.Indices, indexName) {
+			continue
+		}
+		target := indexName
+		if renameRe != nil {
+			target = renameRe.ReplaceAllString(indexName, req.RenameReplacement)
+		}
+		if err = core.CheckIndexName(target); err != nil {
+			return nil, errors.New(errors.ErrorTypeInvalidArgument, err.Error())
+		}
+		if _, ok := core.GetIndex(target); ok {
+			return nil, errors.New(errors.ErrorTypeInvalidArgument, "cannot restore index ["+target+"] because an open index with same name already exists")
+		}
+		if _, ok := targets[target]; ok {
+			return nil, errors.New(errors.ErrorTypeInvalidArgument, "indices ["+target+"] are renamed into the same index")
+		}
+		targets[target] = struct{}{}
+		renamed[indexName] = target
+	}
+	if len(renamed) == 0 && req.Indices != "" {
+		return nil, errors.New(errors.ErrorTypeInvalidArgument, "index ["+req.Indices+"] does not exists in snapshot ["+repoName+":"+name+"]")
+	}
+
+	restored := make([]string, 0, len(renamed))
+	for indexName, target := range renamed {
+		if err = restoreIndex(location, manifest, manifest.Indexes[indexName], target); err != nil {
+			return restored, err
+		}
+		restored = append(restored, target)
+	}
+	sort.Strings(restored)
+
+	if req.IncludeAliases == nil || *req.IncludeAliases {
+		for alias, names := range manifest.Aliases {
+			indexes := make([]string, 0, len(names))
+			for _, name := range names {
+				if target, ok := renamed[name]; ok {
+					indexes = append(indexes, target)
+				}
+			}
+			if len(indexes) == 0 {
+				continue
+			}
+			if err = core.ZINC_INDEX_ALIAS_LIST.AddIndexesToAlias(alias, indexes); err != nil {
+				return restored, err
+			}
+		}
+	}
+
+	if req.IncludeGlobalState {
+		if err = restoreGlobalState(manifest); err != nil {
+			return restored, err
+		}
+	}
+
+	return restored, nil
+}
+
+// restoreIndex copies the segments into data path of target and creates the index
+func restoreIndex(location string, manifest *meta.SnapshotManifest, item *meta.SnapshotIndex, target string) error {
+	dataPath := core.IndexDataPath(target)
+	// the index does not exist, so any data here is left over
+	if err := os.RemoveAll(dataPath); err != nil {
+		return err
+	}
+	for dir, files := range item.Files {
+		secondDir := filepath.Join(dataPath, filepath.FromSlash(dir))
+		if err := os.MkdirAll(secondDir, 0755); err != nil {
+			return err
+		}
+		for _, file := range files {
+			if err := copyFile(blobPath(location, file.Blob), filepath.Join(secondDir, file.Name)); err != nil {
+				_ = os.RemoveAll(dataPath)
+				return err
+			}
+		}
+	}
+
+	ref := item.Index
+	ref.Name = target
+	if ref.Version == "" {
+		ref.Version = manifest.Version
+	}
+	if _, err := core.RestoreIndex(ref); err != nil {
+		_ = os.RemoveAll(dataPath)
+		return err
+	}
+	return nil
+}
+
+// restoreGlobalState overwrites component templates, templates, users and roles by the snapshot
+func restoreGlobalState(manifest *meta.SnapshotManifest) error {
+	// component templates first, index templates check the components they are composed of
+	for _, tpl := range manifest.ComponentTemplates {
+		if err := core.NewComponentTemplate(tpl.Name, tpl.ComponentTemplate); err != nil {
+			return err
+		}
+	}
+	for _, tpl := range manifest.Templates {
+		if err := core.NewTemplate(tpl.Name, tpl.IndexTemplate); err != nil {
+			return err
+		}
+	}
+	for _, role := range manifest.Roles {
+		if _, err := auth.CreateRole(role.ID, role.Name, role.Permission); err != nil {
+			return err
+		}
+	}
+	for _, user := range manifest.Users {
+		if err := auth.SetUser(user.ID, *user); err != nil {
+			return err
+		}
+		auth.ZINC_CACHED_USERS.Set(user.ID, user)
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
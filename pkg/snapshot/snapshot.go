@@ -0,0 +1,405 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/zincsearch/zincsearch/pkg/auth"
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/ider"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/metadata"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+)
+
+// CreateSnapshot takes a snapshot of indexes into the repository,
+// if wait is false the snapshot runs in background and returns immediately with state IN_PROGRESS.
+func CreateSnapshot(repoName, name string, req *meta.SnapshotCreateRequest, wait bool) (*meta.Snapshot, error) {
+	if err := CheckName(name); err != nil {
+		return nil, err
+	}
+	_, location, err := getRepositoryLocation(repoName)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil {
+		req = new(meta.SnapshotCreateRequest)
+	}
+	indexes, err := core.MatchIndexes(req.Indices)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := lockRepository(repoName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = os.Stat(manifestPath(location, name)); err == nil {
+		unlock()
+		return nil, errors.New(errors.ErrorTypeInvalidArgument, "snapshot ["+repoName+":"+name+"] already exists")
+	}
+
+	manifest := &meta.SnapshotManifest{
+		Snapshot: meta.Snapshot{
+			Snapshot:   name,
+			UUID:       ider.Generate(),
+			Repository: repoName,
+			Version:    meta.Version,
+			Indices:    make([]string, 0, len(indexes)),
+			State:      meta.SnapshotStateInProgress,
+			StartTime:  time.Now(),
+		},
+		Indexes: make(map[string]*meta.SnapshotIndex, len(indexes)),
+	}
+	for _, index := range indexes {
+		manifest.Indices = append(manifest.Indices, index.GetName())
+	}
+	if err = writeManifest(location, manifest); err != nil {
+		unlock()
+		return nil, err
+	}
+
+	includeGlobalState := req.IncludeGlobalState == nil || *req.IncludeGlobalState
+	if !wait {
+		go func() {
+			defer unlock()
+			_ = doSnapshot(location, manifest, indexes, includeGlobalState)
+		}()
+		snap := manifest.Snapshot
+		return &snap, nil
+	}
+
+	defer unlock()
+	err = doSnapshot(location, manifest, indexes, includeGlobalState)
+	return &manifest.Snapshot, err
+}
+
+func doSnapshot(location string, manifest *meta.SnapshotManifest, indexes []*core.Index, includeGlobalState bool) error {
+	err := snapshotIndexes(location, manifest, indexes)
+	if err == nil {
+		err = snapshotMetadata(manifest, includeGlobalState)
+	}
+	manifest.EndTime = time.Now()
+	manifest.DurationInMillis = manifest.EndTime.Sub(manifest.StartTime).Milliseconds()
+	if err != nil {
+		log.Error().Err(err).Str("repository", manifest.Repository).Str("snapshot", manifest.Snapshot.Snapshot).Msg("snapshot failed")
+		manifest.State = meta.SnapshotStateFailed
+		manifest.Reason = err.Error()
+	} else {
+		manifest.State = meta.SnapshotStateSuccess
+	}
+	if werr := writeManifest(location, manifest); werr != nil {
+		log.Error().Err(werr).Str("repository", manifest.Repository).Str("snapshot", manifest.Snapshot.Snapshot).Msg("snapshot write manifest failed")
+		if err == nil {
+			err = werr
+		}
+	}
+	return err
+}
+
+// snapshotIndexes backups segments of indexes into a temporary directory and moves them into blobs,
+// the segment files already stored by previous snapshots are reused without copying. The segment id
+// can be reused after restoring an old snapshot, so the stored file is reused only if the content is same.
+func snapshotIndexes(location string, manifest *meta.SnapshotManifest, indexes []*core.Index) error {
+	tmp := filepath.Join(location, tmpDir, manifest.UUID)
+	defer os.RemoveAll(tmp)
+
+	stored, err := storedFiles(location)
+	if err != nil {
+		return err
+	}
+
+	for _, index := range indexes {
+		indexDir := filepath.Join(tmp, index.GetName())
+		item := &meta.SnapshotIndex{Files: make(map[string][]meta.SnapshotFile)}
+		addFile := func(rel string, file meta.SnapshotFile, isNew bool) {
+			key := filepath.ToSlash(filepath.Dir(rel))
+			item.Files[key] = append(item.Files[key], file)
+			manifest.Stats.TotalFileCount++
+			manifest.Stats.TotalSizeInBytes += file.Size
+			if isNew {
+				manifest.Stats.IncrementalFileCount++
+				manifest.Stats.IncrementalSizeInBytes += file.Size
+			}
+		}
+
+		ref, err := index.Backup(indexDir, func(name, src string) bool {
+			file, ok := stored[path.Join(index.GetName(), name)]
+			if !ok {
+				return false
+			}
+			if info, err := os.Stat(src); err != nil || info.Size() != file.Size {
+				return false
+			}
+			if blob, err := fileSHA256(src); err != nil || blob != file.Blob {
+				return false
+			}
+			addFile(name, file, false)
+			return true
+		}, nil)
+		if err != nil {
+			return err
+		}
+		item.Index = ref
+		err = filepath.Walk(indexDir, func(p string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(indexDir, p)
+			if err != nil {
+				return err
+			}
+			blob, isNew, err := storeBlob(location, p)
+			if err != nil {
+				return err
+			}
+			addFile(rel, meta.SnapshotFile{Name: info.Name(), Blob: blob, Size: info.Size()}, isNew)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		manifest.Indexes[index.GetName()] = item
+	}
+	return nil
+}
+
+// storedFiles returns the files of the successful snapshots in repository, keyed by index/shard/file
+func storedFiles(location string) (map[string]meta.SnapshotFile, error) {
+	manifests, err := listManifests(location)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]meta.SnapshotFile)
+	for _, manifest := range manifests {
+		if manifest.State != meta.SnapshotStateSuccess {
+			continue
+		}
+		for indexName, index := range manifest.Indexes {
+			for dir, items := range index.Files {
+				for _, file := range items {
+					files[path.Join(indexName, dir, file.Name)] = file
+				}
+			}
+		}
+	}
+	return files, nil
+}
+
+// snapshotMetadata records aliases of the indexes and the global state: component templates, templates, users and roles
+func snapshotMetadata(manifest *meta.SnapshotManifest, includeGlobalState bool) error {
+	aliases, err := metadata.Alias.Get()
+	if err != nil {
+		return err
+	}
+	manifest.Aliases = make(map[string][]string)
+	for alias, names := range aliases {
+		for _, name := range names {
+			if _, ok := manifest.Indexes[name]; ok {
+				manifest.Aliases[alias] = append(manifest.Aliases[alias], name)
+			}
+		}
+	}
+
+	if !includeGlobalState {
+		return nil
+	}
+	if manifest.ComponentTemplates, err = core.ListComponentTemplates(); err != nil {
+		return err
+	}
+	if manifest.Templates, err = core.ListTemplates(""); err != nil {
+		return err
+	}
+	if manifest.Users, err = auth.GetUsers(); err != nil {
+		return err
+	}
+	if manifest.Roles, err = auth.GetRoles(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// storeBlob moves the file into blobs named by its sha256, returns the blob name and whether it is a new blob
+func storeBlob(location, file string) (string, bool, error) {
+	blob, err := fileSHA256(file)
+	if err != nil {
+		return "", false, err
+	}
+	blobPath := blobPath(location, blob)
+	if _, err = os.Stat(blobPath); err == nil {
+		return blob, false, nil
+	}
+	if err = os.MkdirAll(filepath.Dir(blobPath), 0755); err != nil {
+		return "", false, err
+	}
+	if err = os.Rename(file, blobPath); err != nil {
+		return "", false, err
+	}
+	return blob, true, nil
+}
+
+// fileSHA256 returns the hex encoded sha256 of the file content
+func fileSHA256(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// GetSnapshots returns the snapshots in repository, name accepts comma separated list with wildcards or _all
+func GetSnapshots(repoName, name string) ([]*meta.Snapshot, error) {
+	_, location, err := getRepositoryLocation(repoName)
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := listManifests(location)
+	if err != nil {
+		return nil, err
+	}
+	snaps := make([]*meta.Snapshot, 0, len(manifests))
+	for _, manifest := range manifests {
+		if core.MatchNames(name, manifest.Snapshot.Snapshot) {
+			snap := manifest.Snapshot
+			snaps = append(snaps, &snap)
+		}
+	}
+	if len(snaps) == 0 && name != "" && name != "_all" && !strings.Contains(name, "*") {
+		return nil, errors.New(errors.ErrorTypeSnapshotMissing, "snapshot ["+repoName+":"+name+"] is missing")
+	}
+	sort.Slice(snaps, func(i, j int) bool {
+		return snaps[i].StartTime.Before(snaps[j].StartTime)
+	})
+	return snaps, nil
+}
+
+// DeleteSnapshot deletes the snapshot and removes the blobs what not used by other snapshots
+func DeleteSnapshot(repoName, name string) error {
+	if err := CheckName(name); err != nil {
+		return err
+	}
+	_, location, err := getRepositoryLocation(repoName)
+	if err != nil {
+		return err
+	}
+	unlock, err := lockRepository(repoName)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err = os.Remove(manifestPath(location, name)); err != nil {
+		if os.IsNotExist(err) {
+			return errors.New(errors.ErrorTypeSnapshotMissing, "snapshot ["+repoName+":"+name+"] is missing")
+		}
+		return err
+	}
+	return cleanupBlobs(location)
+}
+
+// cleanupBlobs removes the blobs what not referenced by any snapshot
+func cleanupBlobs(location string) error {
+	manifests, err := listManifests(location)
+	if err != nil {
+		return err
+	}
+	used := make(map[string]struct{})
+	for _, manifest := range manifests {
+		for _, index := range manifest.Indexes {
+			for _, files := range index.Files {
+				for _, file := range files {
+					used[file.Blob] = struct{}{}
+				}
+			}
+		}
+	}
+	return filepath.Walk(filepath.Join(location, blobsDir), func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		if _, ok := used[info.Name()]; ok {
+			return nil
+		}
+		return os.Remove(p)
+	})
+}
+
+func loadManifest(location, name string) (*meta.SnapshotManifest, error) {
+	data, err := os.ReadFile(manifestPath(location, name))
+	if err != nil {
+		return nil, err
+	}
+	manifest := new(meta.SnapshotManifest)
+	err = json.Unmarshal(data, manifest)
+	return manifest, err
+}
+
+func listManifests(location string) ([]*meta.SnapshotManifest, error) {
+	entries, err := os.ReadDir(filepath.Join(location, snapshotsDir))
+	if err != nil {
+		return nil, err
+	}
+	manifests := make([]*meta.SnapshotManifest, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		manifest, err := loadManifest(location, strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests, nil
+}
+
+// writeManifest writes to a temporary file first and renames it, so the manifest is always complete
+func writeManifest(location string, manifest *meta.SnapshotManifest) error {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	p := manifestPath(location, manifest.Snapshot.Snapshot)
+	if err = os.WriteFile(p+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(p+".tmp", p)
+}
+
+func manifestPath(location, name string) string {
+	return filepath.Join(location, snapshotsDir, name+".json")
+}
+
+func blobPath(location, blob string) string {
+	return filepath.Join(location, blobsDir, blob[:2], blob)
+}
@@ -0,0 +1,238 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zincsearch/zincsearch/pkg/config"
+	"github.com/zincsearch/zincsearch/pkg/core"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+)
+
+func TestSnapshot(t *testing.T) {
+	repoPath := t.TempDir()
+	config.Global.SnapshotRepoPath = []string{repoPath}
+	repoName := "TestSnapshot.repo"
+	indexName := "TestSnapshot.index_1"
+	restoredName := "TestSnapshot.restored_1"
+	globalName := "TestSnapshot.global_1"
+
+	countDocs := func(t *testing.T, name string) int {
+		index, ok := core.GetIndex(name)
+		assert.True(t, ok)
+		resp, err := index.Search(&meta.ZincQuery{
+			Query: &meta.Query{MatchAll: &meta.MatchAllQuery{}},
+			Size:  100,
+		})
+		assert.NoError(t, err)
+		return resp.Hits.Total.Value
+	}
+
+	t.Run("prepare", func(t *testing.T) {
+		index, err := core.NewIndex(indexName, "disk", 2)
+		assert.NoError(t, err)
+		assert.NoError(t, core.StoreIndex(index))
+		for i := 0; i < 10; i++ {
+			err = index.CreateDocument(strconv.Itoa(i), map[string]interface{}{"name": "doc " + strconv.Itoa(i)}, false)
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, core.ZINC_INDEX_ALIAS_LIST.AddIndexesToAlias("TestSnapshot.alias", []string{indexName}))
+	})
+
+	t.Run("repository", func(t *testing.T) {
+		err := PutRepository(repoName, &meta.SnapshotRepository{Settings: meta.SnapshotRepositorySettings{Location: "backup"}})
+		assert.NoError(t, err)
+		repo, ok, err := GetRepository(repoName)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, meta.SnapshotRepositoryTypeFS, repo.Type)
+		assert.DirExists(t, filepath.Join(repoPath, "backup", blobsDir))
+
+		err = PutRepository("TestSnapshot.outside", &meta.SnapshotRepository{Settings: meta.SnapshotRepositorySettings{Location: "../outside"}})
+		assert.Error(t, err)
+		err = PutRepository("TestSnapshot.s3", &meta.SnapshotRepository{Type: "s3", Settings: meta.SnapshotRepositorySettings{Location: "s3"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("create", func(t *testing.T) {
+		snap, err := CreateSnapshot(repoName, "snap_1", &meta.SnapshotCreateRequest{Indices: "TestSnapshot.*"}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, meta.SnapshotStateSuccess, snap.State)
+		assert.Equal(t, []string{indexName}, snap.Indices)
+		assert.Greater(t, snap.Stats.TotalFileCount, 0)
+		assert.Equal(t, snap.Stats.TotalFileCount, snap.Stats.IncrementalFileCount)
+
+		// nothing changed, the segments are reused
+		snap, err = CreateSnapshot(repoName, "snap_2", &meta.SnapshotCreateRequest{Indices: indexName}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, meta.SnapshotStateSuccess, snap.State)
+		assert.Less(t, snap.Stats.IncrementalFileCount, snap.Stats.TotalFileCount)
+
+		_, err = CreateSnapshot(repoName, "snap_1", nil, true)
+		assert.Error(t, err)
+		_, err = CreateSnapshot("TestSnapshot.missing", "snap_1", nil, true)
+		assert.Error(t, err)
+		_, err = CreateSnapshot(repoName, "snap_3", &meta.SnapshotCreateRequest{Indices: "TestSnapshot.not_exists"}, true)
+		assert.Error(t, err)
+	})
+
+	t.Run("stored file with different content", func(t *testing.T) {
+		// the stored files have the same names and sizes as the segments but different content,
+		// like the segment ids reused after restoring an old snapshot
+		manifests, err := listManifests(filepath.Join(repoPath, "backup"))
+		require.NoError(t, err)
+		require.NotEmpty(t, manifests)
+		stale := manifests[0]
+		for _, item := range stale.Indexes {
+			for _, files := range item.Files {
+				for i := range files {
+					files[i].Blob = strings.Repeat("0", 64)
+				}
+			}
+		}
+		location := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(location, snapshotsDir), 0755))
+		require.NoError(t, writeManifest(location, stale))
+
+		index, ok := core.GetIndex(indexName)
+		require.True(t, ok)
+		manifest := &meta.SnapshotManifest{
+			Snapshot: meta.Snapshot{Snapshot: "snap_check", UUID: "snap_check"},
+			Indexes:  make(map[string]*meta.SnapshotIndex),
+		}
+		require.NoError(t, snapshotIndexes(location, manifest, []*core.Index{index}))
+		assert.Greater(t, manifest.Stats.TotalFileCount, 0)
+		assert.Equal(t, manifest.Stats.TotalFileCount, manifest.Stats.IncrementalFileCount)
+		for _, files := range manifest.Indexes[indexName].Files {
+			for _, file := range files {
+				assert.FileExists(t, blobPath(location, file.Blob))
+			}
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		snaps, err := GetSnapshots(repoName, "_all")
+		assert.NoError(t, err)
+		assert.Len(t, snaps, 2)
+		snaps, err = GetSnapshots(repoName, "snap_1")
+		assert.NoError(t, err)
+		assert.Len(t, snaps, 1)
+		_, err = GetSnapshots(repoName, "snap_3")
+		var e *errors.Error
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, errors.ErrorTypeSnapshotMissing, e.Type)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		_, err := RestoreSnapshot(repoName, "snap_1", nil)
+		assert.Error(t, err, "index exists")
+		_, err = RestoreSnapshot(repoName, "../snap_1", nil)
+		assert.Error(t, err, "invalid name")
+
+		indices, err := RestoreSnapshot(repoName, "snap_1", &meta.SnapshotRestoreRequest{
+			RenamePattern:     "TestSnapshot.index_(.+)",
+			RenameReplacement: "TestSnapshot.restored_$1",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{restoredName}, indices)
+		assert.Equal(t, 10, countDocs(t, restoredName))
+
+		aliasIndexes, ok := core.ZINC_INDEX_ALIAS_LIST.GetIndexesForAlias("TestSnapshot.alias")
+		assert.True(t, ok)
+		assert.Contains(t, aliasIndexes, restoredName)
+
+		// restored index can be written
+		index, _ := core.GetIndex(restoredName)
+		assert.NoError(t, index.CreateDocument("10", map[string]interface{}{"name": "doc 10"}, false))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.Error(t, DeleteSnapshot(repoName, "../snapshots/snap_1"), "invalid name")
+		assert.NoError(t, DeleteSnapshot(repoName, "snap_1"))
+		_, err := RestoreSnapshot(repoName, "snap_1", nil)
+		assert.Error(t, err)
+
+		// blobs are still used by snap_2
+		assert.NoError(t, core.DeleteIndex(indexName))
+		indices, err := RestoreSnapshot(repoName, "snap_2", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{indexName}, indices)
+		assert.Equal(t, 10, countDocs(t, indexName))
+
+		assert.NoError(t, DeleteSnapshot(repoName, "snap_2"))
+		entries, err := os.ReadDir(filepath.Join(repoPath, "backup", blobsDir))
+		assert.NoError(t, err)
+		for _, entry := range entries {
+			files, _ := os.ReadDir(filepath.Join(repoPath, "backup", blobsDir, entry.Name()))
+			assert.Len(t, files, 0)
+		}
+		assert.Error(t, DeleteSnapshot(repoName, "snap_2"))
+	})
+
+	t.Run("global state", func(t *testing.T) {
+		component := "TestSnapshot.component"
+		template := "TestSnapshot.template"
+		assert.NoError(t, core.NewComponentTemplate(component, &meta.ComponentTemplateData{
+			Template: meta.TemplateTemplate{Settings: &meta.IndexSettings{NumberOfReplicas: 1}},
+		}))
+		assert.NoError(t, core.NewTemplate(template, &meta.IndexTemplate{
+			IndexPatterns: []string{"TestSnapshot.template_*"},
+			ComposedOf:    []string{component},
+		}))
+		snap, err := CreateSnapshot(repoName, "snap_3", &meta.SnapshotCreateRequest{Indices: indexName}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, meta.SnapshotStateSuccess, snap.State)
+
+		assert.NoError(t, core.DeleteTemplate(template))
+		assert.NoError(t, core.DeleteComponentTemplate(component))
+		indices, err := RestoreSnapshot(repoName, "snap_3", &meta.SnapshotRestoreRequest{
+			RenamePattern:      "TestSnapshot.index_(.+)",
+			RenameReplacement:  "TestSnapshot.global_$1",
+			IncludeGlobalState: true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{globalName}, indices)
+
+		_, ok, err := core.LoadComponentTemplate(component)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		tpl, ok, err := core.LoadTemplate(template)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, []string{component}, tpl.ComposedOf)
+
+		assert.NoError(t, core.DeleteTemplate(template))
+		assert.NoError(t, core.DeleteComponentTemplate(component))
+		assert.NoError(t, DeleteSnapshot(repoName, "snap_3"))
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		assert.NoError(t, DeleteRepository(repoName))
+		assert.NoError(t, core.ZINC_INDEX_ALIAS_LIST.RemoveIndexesFromAlias("TestSnapshot.alias", []string{indexName, restoredName, globalName}))
+		assert.NoError(t, core.DeleteIndex(indexName))
+		assert.NoError(t, core.DeleteIndex(restoredName))
+		assert.NoError(t, core.DeleteIndex(globalName))
+	})
+}
@@ -44,15 +44,15 @@ func Request(req zincaggregation.SearchAggregation, aggs map[string]meta.Aggrega
 	for name, agg := range aggs {
 		switch {
 		case agg.Avg != nil:
-			req.AddAggregation(name, aggregations.Avg(search.Field(agg.Avg.Field)))
+			req.AddAggregation(name, aggregations.Avg(numericField(mappings, agg.Avg.Field)))
 		case agg.WeightedAvg != nil:
-			req.AddAggregation(name, aggregations.WeightedAvg(search.Field(agg.WeightedAvg.Field), search.Field(agg.WeightedAvg.WeightField)))
+			req.AddAggregation(name, aggregations.WeightedAvg(numericField(mappings, agg.WeightedAvg.Field), numericField(mappings, agg.WeightedAvg.WeightField)))
 		case agg.Max != nil:
-			req.AddAggregation(name, aggregations.Max(search.Field(agg.Max.Field)))
+			req.AddAggregation(name, aggregations.Max(numericField(mappings, agg.Max.Field)))
 		case agg.Min != nil:
-			req.AddAggregation(name, aggregations.Min(search.Field(agg.Min.Field)))
+			req.AddAggregation(name, aggregations.Min(numericField(mappings, agg.Min.Field)))
 		case agg.Sum != nil:
-			req.AddAggregation(name, aggregations.Sum(search.Field(agg.Sum.Field)))
+			req.AddAggregation(name, aggregations.Sum(numericField(mappings, agg.Sum.Field)))
 		case agg.Count != nil:
 			req.AddAggregation(name, aggregations.CountMatches())
 		case agg.Cardinality != nil:
@@ -67,7 +67,12 @@ func Request(req zincaggregation.SearchAggregation, aggs map[string]meta.Aggrega
 			case "text", "keyword":
 				subreq = zincaggregation.NewTermsAggregation(search.Field(agg.Terms.Field), zincaggregation.TextValueSource, agg.Terms.Size)
 			case "numeric":
-				subreq = zincaggregation.NewTermsAggregation(search.Field(agg.Terms.Field), zincaggregation.NumericValueSource, agg.Terms.Size)
+				switch {
+				case prop.IsLong() && prop.ScalingFactor == 0:
+					subreq = zincaggregation.NewTermsAggregation(numericField(mappings, agg.Terms.Field), zincaggregation.LongValueSource, agg.Terms.Size)
+				default:
+					subreq = zincaggregation.NewTermsAggregation(numericField(mappings, agg.Terms.Field), zincaggregation.NumericValueSource, agg.Terms.Size)
+				}
 			case "bool", "boolean":
 				subreq = zincaggregation.NewTermsAggregation(search.Field(agg.Terms.Field), zincaggregation.BooleanValueSource, agg.Terms.Size)
 			default:
@@ -90,7 +95,7 @@ func Request(req zincaggregation.SearchAggregation, aggs map[string]meta.Aggrega
 			prop, _ := mappings.GetProperty(agg.Range.Field)
 			switch prop.Type {
 			case "numeric":
-				subreq = aggregations.Ranges(search.Field(agg.Range.Field))
+				subreq = aggregations.Ranges(numericField(mappings, agg.Range.Field))
 				for _, v := range agg.Range.Ranges {
 					subreq.AddRange(aggregations.Range(v.From, v.To))
 				}
@@ -159,7 +164,7 @@ func Request(req zincaggregation.SearchAggregation, aggs map[string]meta.Aggrega
 			switch prop.Type {
 			case "numeric":
 				subreq = zincaggregation.NewHistogramAggregation(
-					search.Field(agg.Histogram.Field),
+					numericField(mappings, agg.Histogram.Field),
 					agg.Histogram.Interval,
 					agg.Histogram.Offset,
 					agg.Histogram.ExtendedBounds,
@@ -330,6 +335,14 @@ func Request(req zincaggregation.SearchAggregation, aggs map[string]meta.Aggrega
 	return nil
 }
 
+// numericField returns the source of numeric field, the values of long field are decoded from the exact int64
+func numericField(mappings *meta.Mappings, field string) search.NumericValuesSource {
+	if prop, ok := mappings.GetProperty(field); ok && prop.IsLong() {
+		return zincaggregation.LongField(field, prop.ScalingFactor)
+	}
+	return search.Field(field)
+}
+
 func Response(bucket *search.Bucket) (map[string]meta.AggregationResponse, error) {
 	resp := make(map[string]meta.AggregationResponse)
 	aggs := bucket.Aggregations()
@@ -42,7 +42,15 @@ import (
 	"github.com/blugelabs/bluge/analysis/lang/sv"
 	"github.com/blugelabs/bluge/analysis/lang/tr"
 
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/bn"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/br"
 	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/chs"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/et"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/ja"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/ko"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/lv"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/morph"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/th"
 	"github.com/zincsearch/zincsearch/pkg/errors"
 	"github.com/zincsearch/zincsearch/pkg/meta"
 	zincanalyzer "github.com/zincsearch/zincsearch/pkg/uquery/analysis/analyzer"
@@ -53,7 +61,7 @@ func RequestAnalyzer(data *meta.IndexAnalysis) (map[string]*analysis.Analyzer, e
 		return nil, nil
 	}
 
-	if data.Analyzer == nil {
+	if data.Analyzer == nil && data.Normalizer == nil {
 		return nil, nil
 	}
 
@@ -168,7 +176,11 @@ func RequestAnalyzer(data *meta.IndexAnalysis) (map[string]*analysis.Analyzer, e
 		if len(tokens) > 0 {
 			ana.TokenFilters = append(ana.TokenFilters, tokens...)
 		}
-		analyzers[name] = ana
+		analyzers[name] = morph.Wrap(ana)
+	}
+
+	if err = requestNormalizer(analyzers, data.Normalizer, charFilters, tokenFilters); err != nil {
+		return nil, err
 	}
 
 	return analyzers, nil
@@ -204,9 +216,17 @@ func QueryAnalyzer(data map[string]*analysis.Analyzer, name string) (*analysis.A
 		return chs.NewGseStandardAnalyzer(), nil
 	case "gse_search": // for Chinese support
 		return chs.NewGseSearchAnalyzer(), nil
+	case "kuromoji", "ja", "japanese": // for Japanese support
+		return ja.Analyzer(), nil
+	case "nori", "ko", "korean": // for Korean support
+		return ko.Analyzer(), nil
 		// language filters
 	case "ar", "arabic":
 		return ar.Analyzer(), nil
+	case "bn", "bengali":
+		return bn.Analyzer(), nil
+	case "br", "brazilian":
+		return br.Analyzer(), nil
 	case "cjk": // for Asia language
 		return cjk.Analyzer(), nil
 	case "ckb", "sorani":
@@ -219,6 +239,8 @@ func QueryAnalyzer(data map[string]*analysis.Analyzer, name string) (*analysis.A
 		return en.NewAnalyzer(), nil
 	case "es", "spanish":
 		return es.Analyzer(), nil
+	case "et", "estonian":
+		return et.Analyzer(), nil
 	case "fa", "persian":
 		return fa.Analyzer(), nil
 	case "fi", "finnish":
@@ -231,6 +253,8 @@ func QueryAnalyzer(data map[string]*analysis.Analyzer, name string) (*analysis.A
 		return hu.Analyzer(), nil
 	case "it", "italian":
 		return it.Analyzer(), nil
+	case "lv", "latvian":
+		return lv.Analyzer(), nil
 	case "nl", "dutch":
 		return nl.Analyzer(), nil
 	case "no", "norwegian":
@@ -243,6 +267,8 @@ func QueryAnalyzer(data map[string]*analysis.Analyzer, name string) (*analysis.A
 		return ru.Analyzer(), nil
 	case "sv", "swedish":
 		return sv.Analyzer(), nil
+	case "th", "thai":
+		return th.Analyzer(), nil
 	case "tr", "turkish":
 		return tr.Analyzer(), nil
 	default:
@@ -261,6 +287,10 @@ func QueryAnalyzerForField(data map[string]*analysis.Analyzer, mappings *meta.Ma
 	if mappings != nil && mappings.Len() > 0 {
 		if v, ok := mappings.GetProperty(field); ok {
 			if v.Type != "text" {
+				// keyword field uses normalizer for both index and search
+				if normalizer := QueryNormalizerForField(data, mappings, field); normalizer != nil {
+					return normalizer, normalizer
+				}
 				return nil, nil
 			}
 			if v.Analyzer != "" {
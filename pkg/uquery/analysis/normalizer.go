@@ -0,0 +1,116 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blugelabs/bluge/analysis"
+	"github.com/blugelabs/bluge/analysis/token"
+	"github.com/blugelabs/bluge/analysis/tokenizer"
+
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+)
+
+// normalizerPrefix keeps normalizers and analyzers in the same map of index without name conflict
+const normalizerPrefix = "normalizer#"
+
+// requestNormalizer builds the normalizers into analyzers,
+// a normalizer is an analyzer with single token tokenizer, only has char filters and token filters.
+func requestNormalizer(analyzers map[string]*analysis.Analyzer, data map[string]*meta.Analyzer, charFilters map[string]analysis.CharFilter, tokenFilters map[string]analysis.TokenFilter) error {
+	for name, v := range data {
+		if v.Tokenizer != "" {
+			return errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[normalizer] [%s] doesn't support tokenizer", name))
+		}
+		if v.Type != "" && strings.ToLower(v.Type) != "custom" {
+			return errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[normalizer] [%s] unsupported type [%s]", name, v.Type))
+		}
+
+		ana := &analysis.Analyzer{Tokenizer: tokenizer.NewSingleTokenTokenizer()}
+		for _, filterName := range v.CharFilter {
+			filter, err := RequestCharFilterSingle(filterName, nil)
+			if filter == nil || err != nil {
+				var ok bool
+				if filter, ok = charFilters[filterName]; !ok {
+					return errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[normalizer] [%s] used undefined char_filter [%s]", name, filterName))
+				}
+			}
+			ana.CharFilters = append(ana.CharFilters, filter)
+		}
+		filters := v.TokenFilter
+		if filters == nil {
+			filters = v.Filter
+		}
+		for _, filterName := range filters {
+			filter, err := RequestTokenFilterSingle(filterName, nil)
+			if filter == nil || err != nil {
+				var ok bool
+				if filter, ok = tokenFilters[filterName]; !ok {
+					return errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[normalizer] [%s] used undefined token_filter [%s]", name, filterName))
+				}
+			}
+			ana.TokenFilters = append(ana.TokenFilters, filter)
+		}
+		analyzers[normalizerPrefix+name] = ana
+	}
+	return nil
+}
+
+// QueryNormalizer returns the normalizer by name, it supports the build-in normalizer lowercase
+func QueryNormalizer(data map[string]*analysis.Analyzer, name string) (*analysis.Analyzer, error) {
+	if data != nil {
+		if v, ok := data[normalizerPrefix+name]; ok {
+			return v, nil
+		}
+	}
+
+	switch name {
+	case "lowercase":
+		return &analysis.Analyzer{
+			Tokenizer:    tokenizer.NewSingleTokenTokenizer(),
+			TokenFilters: []analysis.TokenFilter{token.NewLowerCaseFilter()},
+		}, nil
+	default:
+		return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[normalizer] unknown normalizer [%s]", name))
+	}
+}
+
+// QueryNormalizerForField returns the normalizer of the keyword field, it returns nil if the field has no normalizer
+func QueryNormalizerForField(data map[string]*analysis.Analyzer, mappings *meta.Mappings, field string) *analysis.Analyzer {
+	if mappings == nil {
+		return nil
+	}
+	prop, ok := mappings.GetProperty(field)
+	if !ok || prop.Type != "keyword" || prop.Normalizer == "" {
+		return nil
+	}
+	normalizer, _ := QueryNormalizer(data, prop.Normalizer)
+	return normalizer
+}
+
+// Normalize returns the value normalized by the normalizer
+func Normalize(normalizer *analysis.Analyzer, value string) string {
+	if normalizer == nil {
+		return value
+	}
+	tokens := normalizer.Analyze([]byte(value))
+	if len(tokens) == 0 {
+		return ""
+	}
+	return string(tokens[0].Term)
+}
@@ -0,0 +1,94 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package analysis
+
+import (
+	"sort"
+
+	"github.com/blugelabs/bluge/analysis"
+
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/chs/dict"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/morph"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+)
+
+// dictionary is implemented by the tokenizers and token filters which load dictionaries from disk
+type dictionary interface {
+	Dictionary() dict.Reloadable
+}
+
+// ReloadAnalyzers reloads the dictionaries used by the custom analyzers and the analyzers of the text fields,
+// it returns the names of the reloaded analyzers.
+func ReloadAnalyzers(data map[string]*analysis.Analyzer, mappings *meta.Mappings) ([]string, error) {
+	analyzers := make(map[string]*analysis.Analyzer, len(data))
+	for name, analyzer := range data {
+		analyzers[name] = analyzer
+	}
+	if mappings != nil {
+		for _, prop := range mappings.ListProperty() {
+			if prop.Type != "text" {
+				continue
+			}
+			for _, name := range []string{prop.Analyzer, prop.SearchAnalyzer} {
+				if _, ok := analyzers[name]; ok || name == "" {
+					continue
+				}
+				if analyzer, err := QueryAnalyzer(data, name); err == nil {
+					analyzers[name] = analyzer
+				}
+			}
+		}
+	}
+
+	reloaded := make([]string, 0)
+	dicts := make(map[dict.Reloadable]struct{})
+	for name, analyzer := range analyzers {
+		found := false
+		for _, d := range analyzerDictionaries(analyzer) {
+			found = true
+			if _, ok := dicts[d]; ok {
+				continue
+			}
+			dicts[d] = struct{}{}
+			if err := d.Reload(); err != nil {
+				return nil, err
+			}
+		}
+		if found {
+			reloaded = append(reloaded, name)
+		}
+	}
+	sort.Strings(reloaded)
+
+	return reloaded, nil
+}
+
+func analyzerDictionaries(analyzer *analysis.Analyzer) []dict.Reloadable {
+	if analyzer == nil {
+		return nil
+	}
+	dicts := make([]dict.Reloadable, 0)
+	tokenizer, filters := morph.Unwrap(analyzer)
+	if v, ok := tokenizer.(dictionary); ok {
+		dicts = append(dicts, v.Dictionary())
+	}
+	for _, filter := range filters {
+		if v, ok := filter.(dictionary); ok {
+			dicts = append(dicts, v.Dictionary())
+		}
+	}
+	return dicts
+}
@@ -0,0 +1,49 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package token
+
+import (
+	"github.com/blugelabs/bluge/analysis"
+
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/chs/dict"
+	chstoken "github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/chs/token"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+// NewGseStopTokenFilter returns the gse stop filter, the option stop_dictionary and stopwords are added to the default stop words.
+func NewGseStopTokenFilter(options interface{}) (analysis.TokenFilter, error) {
+	var stopDicts, stopWords []string
+	if _, err := zutils.GetAnyFromMap(options, "stop_dictionary"); err == nil {
+		name, err := zutils.GetStringFromMap(options, "stop_dictionary")
+		if err != nil {
+			return nil, errors.New(errors.ErrorTypeParsingException, "[token_filter] gse_stop option [stop_dictionary] should be a string")
+		}
+		stopDicts = []string{name}
+	}
+	if _, err := zutils.GetAnyFromMap(options, "stopwords"); err == nil {
+		words, err := zutils.GetStringSliceFromMap(options, "stopwords")
+		if err != nil {
+			return nil, errors.New(errors.ErrorTypeParsingException, "[token_filter] gse_stop option [stopwords] should be an array of string")
+		}
+		stopWords = words
+	}
+	stop, err := dict.NewStop(stopDicts, stopWords)
+	if err != nil {
+		return nil, err
+	}
+	return chstoken.NewStopTokenFilter(stop), nil
+}
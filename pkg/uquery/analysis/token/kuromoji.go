@@ -0,0 +1,65 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package token
+
+import (
+	"github.com/blugelabs/bluge/analysis"
+
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/ja"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/ko"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/token"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+func NewKuromojiPartOfSpeechTokenFilter(options interface{}) (analysis.TokenFilter, error) {
+	stoptags, _ := zutils.GetStringSliceFromMap(options, "stoptags")
+	return ja.PartOfSpeechFilter(stoptags), nil
+}
+
+func NewKuromojiReadingFormTokenFilter(options interface{}) (analysis.TokenFilter, error) {
+	useRomaji := false
+	if v, err := zutils.GetAnyFromMap(options, "use_romaji"); err == nil {
+		if useRomaji, err = zutils.ToBool(v); err != nil {
+			return nil, errors.New(errors.ErrorTypeParsingException, "[token_filter] kuromoji_readingform use_romaji should be a boolean")
+		}
+	}
+	return ja.ReadingFormFilter(useRomaji), nil
+}
+
+func NewKuromojiStemmerTokenFilter(options interface{}) (analysis.TokenFilter, error) {
+	minimumLength := ja.DefaultStemmerMinimumLength
+	if v, err := zutils.GetAnyFromMap(options, "minimum_length"); err == nil {
+		if minimumLength, err = zutils.ToInt(v); err != nil || minimumLength <= 0 {
+			return nil, errors.New(errors.ErrorTypeParsingException, "[token_filter] kuromoji_stemmer minimum_length should be a positive integer")
+		}
+	}
+	return ja.StemmerFilter(minimumLength), nil
+}
+
+// NewJaStopTokenFilter removes the Japanese stop words, the stopwords option replaces the default words
+func NewJaStopTokenFilter(options interface{}) (analysis.TokenFilter, error) {
+	stopwords, _ := zutils.GetStringSliceFromMap(options, "stopwords")
+	if len(stopwords) == 0 || (len(stopwords) == 1 && stopwords[0] == "_japanese_") {
+		return ja.StopWordsFilter(), nil
+	}
+	return token.NewStopTokenFilter(stopwords), nil
+}
+
+func NewNoriPartOfSpeechTokenFilter(options interface{}) (analysis.TokenFilter, error) {
+	stoptags, _ := zutils.GetStringSliceFromMap(options, "stoptags")
+	return ko.PartOfSpeechFilter(stoptags), nil
+}
@@ -44,7 +44,14 @@ import (
 	"github.com/blugelabs/bluge/analysis/lang/tr"
 	"github.com/blugelabs/bluge/analysis/token"
 
-	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/chs"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/bn"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/br"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/et"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/ja"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/ko"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/lv"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/th"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/token/digit"
 	"github.com/zincsearch/zincsearch/pkg/errors"
 	zinctoken "github.com/zincsearch/zincsearch/pkg/uquery/analysis/token"
 	"github.com/zincsearch/zincsearch/pkg/zutils"
@@ -109,6 +116,8 @@ func RequestTokenFilterSingle(name string, options interface{}) (analysis.TokenF
 		return token.NewApostropheFilter(), nil
 	case "camel_case", "camelcase":
 		return token.NewCamelCaseFilter(), nil
+	case "decimal_digit":
+		return digit.NewDecimalDigitFilter(), nil
 	case "dict":
 		return zinctoken.NewDictTokenFilter(options)
 	case "edge_ngram":
@@ -144,12 +153,36 @@ func RequestTokenFilterSingle(name string, options interface{}) (analysis.TokenF
 	case "upper_case", "uppercase":
 		return zinctoken.NewUpperCaseTokenFilter()
 	case "gse_stop":
-		return chs.NewGseStopTokenFilter(), nil
+		return zinctoken.NewGseStopTokenFilter(options)
+	case "kuromoji_part_of_speech":
+		return zinctoken.NewKuromojiPartOfSpeechTokenFilter(options)
+	case "kuromoji_baseform":
+		return ja.BaseFormFilter(), nil
+	case "kuromoji_readingform":
+		return zinctoken.NewKuromojiReadingFormTokenFilter(options)
+	case "kuromoji_stemmer":
+		return zinctoken.NewKuromojiStemmerTokenFilter(options)
+	case "ja_stop":
+		return zinctoken.NewJaStopTokenFilter(options)
+	case "nori_part_of_speech":
+		return zinctoken.NewNoriPartOfSpeechTokenFilter(options)
+	case "nori_readingform":
+		return ko.ReadingFormFilter(), nil
 		// language filters
 	case "ar_normalization", "arabic_normalization":
 		return ar.NormalizeFilter(), nil
 	case "ar_stemmer", "arabic_stemmer":
 		return ar.StemmerFilter(), nil
+	case "bn_normalization", "bengali_normalization":
+		return bn.NormalizeFilter(), nil
+	case "bn_stemmer", "bengali_stemmer":
+		return bn.StemmerFilter(), nil
+	case "bn_stop", "bengali_stop":
+		return bn.StopWordsFilter(), nil
+	case "br_stemmer", "brazilian_stemmer":
+		return br.StemmerFilter(), nil
+	case "br_stop", "brazilian_stop":
+		return br.StopWordsFilter(), nil
 	case "cjk_bigram":
 		return cjk.NewBigramFilter(false), nil
 	case "cjk_width":
@@ -174,6 +207,10 @@ func RequestTokenFilterSingle(name string, options interface{}) (analysis.TokenF
 		return es.StemmerFilter(), nil
 	case "es_light_stemmer", "spanish_light_stemmer":
 		return es.LightStemmerFilter(), nil
+	case "et_stemmer", "estonian_stemmer":
+		return et.StemmerFilter(), nil
+	case "et_stop", "estonian_stop":
+		return et.StopWordsFilter(), nil
 	case "fa_normalization", "persian_normalization":
 		return fa.NormalizeFilter(), nil
 	case "fi_stemmer", "finnish_stemmer":
@@ -202,6 +239,10 @@ func RequestTokenFilterSingle(name string, options interface{}) (analysis.TokenF
 		return it.StemmerFilter(), nil
 	case "it_light_stemmer", "italian_light_stemmer":
 		return it.LightStemmerFilter(), nil
+	case "lv_stemmer", "latvian_stemmer":
+		return lv.StemmerFilter(), nil
+	case "lv_stop", "latvian_stop":
+		return lv.StopWordsFilter(), nil
 	case "nl_stemmer", "dutch_stemmer":
 		return nl.StemmerFilter(), nil
 	case "no_stemmer", "norwegian_stemmer":
@@ -214,6 +255,8 @@ func RequestTokenFilterSingle(name string, options interface{}) (analysis.TokenF
 		return ru.StemmerFilter(), nil
 	case "sv_stemmer", "swedish_stemmer":
 		return sv.StemmerFilter(), nil
+	case "th_stop", "thai_stop":
+		return th.StopWordsFilter(), nil
 	case "tr_stemmer", "turkish_stemmer":
 		return tr.StemmerFilter(), nil
 	default:
@@ -22,7 +22,7 @@ import (
 	"github.com/blugelabs/bluge/analysis"
 	"github.com/blugelabs/bluge/analysis/tokenizer"
 
-	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/chs"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/th"
 	"github.com/zincsearch/zincsearch/pkg/errors"
 	zinctokenizer "github.com/zincsearch/zincsearch/pkg/uquery/analysis/tokenizer"
 	"github.com/zincsearch/zincsearch/pkg/zutils"
@@ -100,9 +100,15 @@ func RequestTokenizerSingle(name string, options interface{}) (analysis.Tokenize
 	case "whitespace":
 		return tokenizer.NewWhitespaceTokenizer(), nil
 	case "gse_standard":
-		return chs.NewGseStandardTokenizer(), nil
+		return zinctokenizer.NewGseStandardTokenizer(options)
 	case "gse_search":
-		return chs.NewGseSearchTokenizer(), nil
+		return zinctokenizer.NewGseSearchTokenizer(options)
+	case "kuromoji_tokenizer", "kuromoji":
+		return zinctokenizer.NewKuromojiTokenizer(options)
+	case "nori_tokenizer", "nori":
+		return zinctokenizer.NewNoriTokenizer(options)
+	case "thai":
+		return th.DefaultTokenizer(), nil
 	default:
 		return nil, errors.New(errors.ErrorTypeXContentParseException, fmt.Sprintf("[tokenizer] unknown tokenizer [%s]", name))
 	}
@@ -0,0 +1,62 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package tokenizer
+
+import (
+	"github.com/blugelabs/bluge/analysis"
+
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/chs/dict"
+	chstokenizer "github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/chs/tokenizer"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+func NewGseStandardTokenizer(options interface{}) (analysis.Tokenizer, error) {
+	seg, err := getGseSegmenter(options)
+	if err != nil {
+		return nil, err
+	}
+	return chstokenizer.NewStandardTokenizer(seg), nil
+}
+
+func NewGseSearchTokenizer(options interface{}) (analysis.Tokenizer, error) {
+	seg, err := getGseSegmenter(options)
+	if err != nil {
+		return nil, err
+	}
+	return chstokenizer.NewSearchTokenizer(seg), nil
+}
+
+// getGseSegmenter returns the segmenter by the option user_dictionary and user_dictionary_rules,
+// it returns the default segmenter if neither is set.
+func getGseSegmenter(options interface{}) (*dict.Segmenter, error) {
+	var userDicts, userRules []string
+	if _, err := zutils.GetAnyFromMap(options, "user_dictionary"); err == nil {
+		name, err := zutils.GetStringFromMap(options, "user_dictionary")
+		if err != nil {
+			return nil, errors.New(errors.ErrorTypeParsingException, "[tokenizer] gse option [user_dictionary] should be a string")
+		}
+		userDicts = []string{name}
+	}
+	if _, err := zutils.GetAnyFromMap(options, "user_dictionary_rules"); err == nil {
+		rules, err := zutils.GetStringSliceFromMap(options, "user_dictionary_rules")
+		if err != nil {
+			return nil, errors.New(errors.ErrorTypeParsingException, "[tokenizer] gse option [user_dictionary_rules] should be an array of string")
+		}
+		userRules = rules
+	}
+	return dict.NewSegmenter(userDicts, userRules)
+}
@@ -0,0 +1,92 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package tokenizer
+
+import (
+	"github.com/blugelabs/bluge/analysis"
+	"github.com/ikawaha/kagome-dict/dict"
+
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/ja"
+	"github.com/zincsearch/zincsearch/pkg/bluge/analysis/lang/ko"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+func NewKuromojiTokenizer(options interface{}) (analysis.Tokenizer, error) {
+	if options == nil {
+		return ja.DefaultTokenizer(), nil
+	}
+	mode, _ := zutils.GetStringFromMap(options, "mode")
+	discardPunctuation, err := getDiscardPunctuation(options)
+	if err != nil {
+		return nil, err
+	}
+	userDict, err := getUserDict(options, ja.DefaultUserDict(), ja.LoadUserDict, ja.ParseUserDictRules)
+	if err != nil {
+		return nil, err
+	}
+	return ja.NewTokenizer(mode, discardPunctuation, userDict)
+}
+
+func NewNoriTokenizer(options interface{}) (analysis.Tokenizer, error) {
+	if options == nil {
+		return ko.DefaultTokenizer(), nil
+	}
+	mode, _ := zutils.GetStringFromMap(options, "decompound_mode")
+	discardPunctuation, err := getDiscardPunctuation(options)
+	if err != nil {
+		return nil, err
+	}
+	userDict, err := getUserDict(options, ko.DefaultUserDict(), ko.LoadUserDict, ko.ParseUserDictRules)
+	if err != nil {
+		return nil, err
+	}
+	return ko.NewTokenizer(mode, discardPunctuation, userDict)
+}
+
+// getDiscardPunctuation returns the option discard_punctuation, default is true
+func getDiscardPunctuation(options interface{}) (bool, error) {
+	v, err := zutils.GetAnyFromMap(options, "discard_punctuation")
+	if err != nil {
+		return true, nil
+	}
+	b, err := zutils.ToBool(v)
+	if err != nil {
+		return false, errors.New(errors.ErrorTypeParsingException, "[tokenizer] discard_punctuation should be a boolean")
+	}
+	return b, nil
+}
+
+// getUserDict returns the user dictionary by the option user_dictionary or user_dictionary_rules, they can't be used together.
+// It returns the default user dictionary if neither is set.
+func getUserDict(options interface{}, defaultDict *dict.UserDict, load func(string) (*dict.UserDict, error), parse func([]string) (*dict.UserDict, error)) (*dict.UserDict, error) {
+	name, _ := zutils.GetStringFromMap(options, "user_dictionary")
+	_, hasRules := zutils.GetAnyFromMap(options, "user_dictionary_rules")
+	if name != "" && hasRules == nil {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[tokenizer] it is not allowed to use [user_dictionary] in conjunction with [user_dictionary_rules]")
+	}
+	if name != "" {
+		return load(name)
+	}
+	if hasRules == nil {
+		rules, err := zutils.GetStringSliceFromMap(options, "user_dictionary_rules")
+		if err != nil {
+			return nil, errors.New(errors.ErrorTypeParsingException, "[tokenizer] user_dictionary_rules should be an array of string")
+		}
+		return parse(rules)
+	}
+	return defaultDict, nil
+}
@@ -0,0 +1,124 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package collapse
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blugelabs/bluge/search"
+
+	"github.com/zincsearch/zincsearch/pkg/bluge/collector"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/uquery/sort"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+const defaultInnerHitsSize = 3
+
+// Request parses the field collapsing of the request, the field must be a keyword or numeric field
+func Request(v *meta.Collapse, mappings *meta.Mappings) (*collector.Collapse, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if v.Field == "" {
+		return nil, errors.New(errors.ErrorTypeParsingException, "[collapse] field is required")
+	}
+	var prop meta.Property
+	var ok bool
+	if mappings != nil {
+		prop, ok = mappings.GetProperty(v.Field)
+	}
+	if !ok {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("no mapping found for `%s` in order to collapse on", v.Field))
+	}
+	switch prop.Type {
+	case "keyword", "numeric":
+	default:
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("unknown type for collapse field `%s`, only keywords and numbers are accepted", v.Field))
+	}
+
+	c := &collector.Collapse{Field: v.Field}
+	var items []interface{}
+	switch inner := v.InnerHits.(type) {
+	case nil:
+	case map[string]interface{}:
+		items = append(items, inner)
+	case []interface{}:
+		items = inner
+	default:
+		return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[collapse] inner_hits doesn't support values of type: %T", inner))
+	}
+	names := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		params, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, errors.New(errors.ErrorTypeParsingException, "[collapse] inner_hits should be an object")
+		}
+		inner, err := parseInnerHits(v.Field, params, mappings)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := names[inner.Name]; ok {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[collapse] inner_hits already contains an entry for key [%s]", inner.Name))
+		}
+		names[inner.Name] = struct{}{}
+		c.InnerHits = append(c.InnerHits, inner)
+	}
+	return c, nil
+}
+
+func parseInnerHits(field string, params map[string]interface{}, mappings *meta.Mappings) (*collector.InnerHitsRequest, error) {
+	value := &meta.InnerHits{Name: field, Size: defaultInnerHitsSize}
+	var err error
+	for k, v := range params {
+		k = strings.ToLower(k)
+		switch k {
+		case "name":
+			value.Name, _ = zutils.ToString(v)
+		case "from":
+			if value.From, err = zutils.ToInt(v); err != nil || value.From < 0 {
+				return nil, errors.New(errors.ErrorTypeParsingException, "[inner_hits] from should be a non-negative integer")
+			}
+		case "size":
+			if value.Size, err = zutils.ToInt(v); err != nil || value.Size < 0 {
+				return nil, errors.New(errors.ErrorTypeParsingException, "[inner_hits] size should be a non-negative integer")
+			}
+		case "sort":
+			value.Sort = v
+		default:
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[inner_hits] unknown field [%s]", k))
+		}
+	}
+
+	inner := &collector.InnerHitsRequest{
+		Name: value.Name,
+		From: value.From,
+		Size: value.Size,
+		Sort: search.SortOrder{search.SortBy(search.DocumentScore()).Desc()},
+	}
+	if value.Sort != nil {
+		order, err := sort.Request(value.Sort, mappings)
+		if err != nil {
+			return nil, err
+		}
+		if order != nil {
+			inner.Sort = order.SortOrder()
+		}
+	}
+	return inner, nil
+}
@@ -64,7 +64,7 @@ func Response(fields []*meta.Field, data []byte, mappings *meta.Mappings) map[st
 	}
 
 	ret := make(map[string]interface{})
-	err := json.Unmarshal(data, &ret)
+	err := json.UnmarshalNumber(data, &ret)
 	if err != nil {
 		return nil
 	}
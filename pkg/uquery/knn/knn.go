@@ -0,0 +1,352 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package knn
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/analysis"
+
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/uquery/query"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+const (
+	MaxNumCandidates    = 10000
+	DefaultWindowSize   = 100
+	DefaultRankConstant = 60
+)
+
+// Search is a knn search on a dense_vector field
+type Search struct {
+	Field         string
+	Vector        []float32
+	K             int
+	NumCandidates int
+	Filter        bluge.Query // nil means all documents
+	Similarity    string      // the similarity of field
+	MinSimilarity *float64    // the minimum raw similarity, it is the maximum distance for l2_norm
+	Boost         float64
+}
+
+// Accept returns if the score reaches the minimum similarity
+func (s *Search) Accept(score float64) bool {
+	if s.MinSimilarity == nil {
+		return true
+	}
+	raw := RawSimilarity(s.Similarity, score)
+	if s.Similarity == meta.SimilarityL2Norm {
+		return raw <= *s.MinSimilarity
+	}
+	return raw >= *s.MinSimilarity
+}
+
+// RRF is the options of reciprocal rank fusion, the score of document is the sum of 1 / (rank_constant + rank)
+type RRF struct {
+	WindowSize   int
+	RankConstant int
+}
+
+// Hit is a document matched by the knn search or ranked by RRF
+type Hit struct {
+	ID    string
+	Score float64
+}
+
+// Request is the knn searches of query, it replaces the knn section of query after parsed,
+// the hits are set after the searches executed and then the query is combined with them.
+type Request struct {
+	Searches []*Search
+	RRF      *RRF
+	// Hits are the k nearest neighbors of each search, the scores are boosted
+	Hits [][]Hit
+	// Ranked are the hits fused by RRF
+	Ranked []Hit
+	// executed marks the hits are set
+	executed bool
+}
+
+// Parse parses the knn section and the rank options of query, the query is kept if it is parsed already
+func Parse(q *meta.ZincQuery, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (*Request, error) {
+	if r, ok := q.KNN.(*Request); ok {
+		return r, nil
+	}
+	rrf, err := parseRank(q.Rank)
+	if err != nil {
+		return nil, err
+	}
+	if q.KNN == nil {
+		if rrf != nil {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[rank] requires a minimum of 2 results sets and [knn]")
+		}
+		return nil, nil
+	}
+
+	items, ok := q.KNN.([]interface{})
+	if !ok {
+		items = []interface{}{q.KNN}
+	}
+	if len(items) == 0 {
+		return nil, errors.New(errors.ErrorTypeParsingException, "[knn] should be an object or an array of objects")
+	}
+	r := &Request{RRF: rrf}
+	for _, item := range items {
+		v, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, errors.New(errors.ErrorTypeParsingException, "[knn] should be an object or an array of objects")
+		}
+		s, err := parseSearch(v, q.Size, mappings, analyzers)
+		if err != nil {
+			return nil, err
+		}
+		r.Searches = append(r.Searches, s)
+	}
+
+	if rrf != nil {
+		if len(r.Searches) < 2 && q.Query == nil {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[rank] requires a minimum of 2 results sets")
+		}
+		if q.Sort != nil {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[rank] cannot be used with [sort]")
+		}
+		if q.Rescore != nil {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[rank] cannot be used with [rescore]")
+		}
+		if q.Collapse != nil {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[rank] cannot be used with [collapse]")
+		}
+		if q.From+q.Size > rrf.WindowSize {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[rank] requires [window_size: %d] be greater than or equal to [size: %d] plus [from: %d]", rrf.WindowSize, q.Size, q.From))
+		}
+	}
+	return r, nil
+}
+
+func parseSearch(v map[string]interface{}, size int, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (*Search, error) {
+	s := &Search{Boost: 1}
+	var queryVector interface{}
+	var err error
+	for k, v := range v {
+		k := strings.ToLower(k)
+		switch k {
+		case "field":
+			s.Field, _ = zutils.ToString(v)
+		case "query_vector":
+			queryVector = v
+		case "k":
+			s.K, err = zutils.ToInt(v)
+		case "num_candidates":
+			s.NumCandidates, err = zutils.ToInt(v)
+		case "boost":
+			s.Boost, err = zutils.ToFloat64(v)
+		case "similarity":
+			var f float64
+			if f, err = zutils.ToFloat64(v); err == nil {
+				s.MinSimilarity = &f
+			}
+		case "filter":
+			if s.Filter, err = parseFilter(v, mappings, analyzers); err != nil {
+				return nil, err
+			}
+		case "query_vector_builder":
+			return nil, errors.New(errors.ErrorTypeNotImplemented, "[knn] query_vector_builder is not supported, use query_vector")
+		default:
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[knn] unknown field [%s]", k))
+		}
+		if err != nil {
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[knn] %s doesn't support value [%v]", k, v))
+		}
+	}
+
+	if s.Field == "" {
+		return nil, errors.New(errors.ErrorTypeParsingException, "[knn] field is required")
+	}
+	var prop meta.Property
+	var ok bool
+	if mappings != nil {
+		prop, ok = mappings.GetProperty(s.Field)
+	}
+	if !ok || prop.Type != "dense_vector" {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[knn] queries are only supported on [dense_vector] fields, field [%s] is not", s.Field))
+	}
+	if !prop.Index {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[knn] queries are not supported if [index] is disabled, field [%s]", s.Field))
+	}
+	s.Similarity = prop.Similarity
+	if queryVector == nil {
+		return nil, errors.New(errors.ErrorTypeParsingException, "[knn] query_vector is required")
+	}
+	if s.Vector, err = Vector(s.Field, prop, queryVector); err != nil {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[knn] query_vector is invalid: "+err.Error())
+	}
+	s.Vector = GraphVector(s.Similarity, s.Vector)
+
+	if s.K == 0 {
+		s.K = size
+		if s.K <= 0 {
+			s.K = 10
+		}
+	}
+	if s.K < 1 {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[k] must be greater than 0")
+	}
+	if s.NumCandidates == 0 {
+		s.NumCandidates = int(math.Min(math.Ceil(float64(s.K)*1.5), MaxNumCandidates))
+		if s.NumCandidates < s.K {
+			s.NumCandidates = s.K
+		}
+	}
+	if s.NumCandidates > MaxNumCandidates {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[num_candidates] cannot exceed [%d]", MaxNumCandidates))
+	}
+	if s.K > s.NumCandidates {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[num_candidates] cannot be less than [k], got [%d] < [%d]", s.NumCandidates, s.K))
+	}
+	if s.Boost < 0 {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[knn] boost must be a non-negative number")
+	}
+	return s, nil
+}
+
+// parseFilter parses the filter of knn, it can be a query or an array of queries
+func parseFilter(v interface{}, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (bluge.Query, error) {
+	items, ok := v.([]interface{})
+	if !ok {
+		items = []interface{}{v}
+	}
+	filter := bluge.NewBooleanQuery()
+	for _, item := range items {
+		q, err := query.Query(item, mappings, analyzers)
+		if err != nil {
+			return nil, errors.New(errors.ErrorTypeXContentParseException, "[knn] failed to parse field [filter]").Cause(err)
+		}
+		filter.AddMust(q)
+	}
+	return filter, nil
+}
+
+// parseRank parses the rank options, only rrf is supported
+func parseRank(v interface{}) (*RRF, error) {
+	if v == nil {
+		return nil, nil
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errors.New(errors.ErrorTypeParsingException, "[rank] should be an object")
+	}
+	var rrf *RRF
+	for k, v := range m {
+		if strings.ToLower(k) != "rrf" {
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[rank] unknown ranking method [%s]", k))
+		}
+		options, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, errors.New(errors.ErrorTypeParsingException, "[rrf] should be an object")
+		}
+		rrf = &RRF{WindowSize: DefaultWindowSize, RankConstant: DefaultRankConstant}
+		for k, v := range options {
+			var err error
+			k := strings.ToLower(k)
+			switch k {
+			case "window_size", "rank_window_size":
+				rrf.WindowSize, err = zutils.ToInt(v)
+			case "rank_constant":
+				rrf.RankConstant, err = zutils.ToInt(v)
+			default:
+				return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[rrf] unknown field [%s]", k))
+			}
+			if err != nil {
+				return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[rrf] %s doesn't support value [%v]", k, v))
+			}
+		}
+		if rrf.WindowSize < 1 {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[rrf] window_size must be greater than or equal to [1]")
+		}
+		if rrf.RankConstant < 1 {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[rrf] rank_constant must be greater than or equal to [1]")
+		}
+	}
+	return rrf, nil
+}
+
+// TopHits returns the k hits of highest score, the hits are collected from the shards
+func TopHits(hits []Hit, k int) []Hit {
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	if len(hits) > k {
+		hits = hits[:k]
+	}
+	return hits
+}
+
+// SetHits sets the results of searches, queryHits are the ranked hits of query used by RRF
+func (r *Request) SetHits(hits [][]Hit, queryHits []Hit) {
+	r.Hits = hits
+	r.executed = true
+	if r.RRF == nil {
+		return
+	}
+	lists := hits
+	if queryHits != nil {
+		lists = append([][]Hit{queryHits}, hits...)
+	}
+	scores := make(map[string]float64)
+	for _, list := range lists {
+		if len(list) > r.RRF.WindowSize {
+			list = list[:r.RRF.WindowSize]
+		}
+		for rank, hit := range list {
+			scores[hit.ID] += 1 / float64(r.RRF.RankConstant+rank+1)
+		}
+	}
+	r.Ranked = make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		r.Ranked = append(r.Ranked, Hit{ID: id, Score: score})
+	}
+	r.Ranked = TopHits(r.Ranked, r.RRF.WindowSize)
+}
+
+// Query combines the query with the hits of knn searches, the query is nil if it isn't specified.
+// The scores of knn hits are added to the score of query, or the hits ranked by RRF replace the query.
+func (r *Request) Query(q bluge.Query) bluge.Query {
+	if !r.executed {
+		if q == nil {
+			return bluge.NewMatchNoneQuery()
+		}
+		return q
+	}
+	if r.RRF != nil {
+		return NewHitsQuery(r.Ranked)
+	}
+	bq := bluge.NewBooleanQuery()
+	if q != nil {
+		bq.AddShould(q)
+	}
+	for _, hits := range r.Hits {
+		bq.AddShould(NewHitsQuery(hits))
+	}
+	return bq.SetMinShould(1)
+}
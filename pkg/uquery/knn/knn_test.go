@@ -0,0 +1,78 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package knn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/meta"
+)
+
+func TestVector(t *testing.T) {
+	prop := meta.NewProperty("dense_vector")
+	prop.Dims = 2
+	tests := []struct {
+		name       string
+		similarity string
+		value      interface{}
+		want       []float32
+		wantErr    bool
+	}{
+		{name: "cosine", similarity: meta.SimilarityCosine, value: []interface{}{3.0, 4}, want: []float32{3, 4}},
+		{name: "cosine zero", similarity: meta.SimilarityCosine, value: []interface{}{0, 0}, wantErr: true},
+		{name: "dot_product", similarity: meta.SimilarityDotProduct, value: []interface{}{0.6, 0.8}, want: []float32{0.6, 0.8}},
+		{name: "dot_product not unit", similarity: meta.SimilarityDotProduct, value: []interface{}{3, 4}, wantErr: true},
+		{name: "l2_norm", similarity: meta.SimilarityL2Norm, value: []interface{}{0, 0}, want: []float32{0, 0}},
+		{name: "dims", similarity: meta.SimilarityL2Norm, value: []interface{}{1, 2, 3}, wantErr: true},
+		{name: "not number", similarity: meta.SimilarityL2Norm, value: []interface{}{"1", 2}, wantErr: true},
+		{name: "not array", similarity: meta.SimilarityL2Norm, value: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prop.Similarity = tt.similarity
+			got, err := Vector("vec", prop, tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, got, DecodeVector(EncodeVector(got)))
+		})
+	}
+
+	t.Run("similarity", func(t *testing.T) {
+		a, b := GraphVector(meta.SimilarityCosine, []float32{3, 4}), GraphVector(meta.SimilarityCosine, []float32{-3, -4})
+		assert.InDelta(t, 1, Similarity(meta.SimilarityCosine)(a, a), 1e-6)
+		assert.InDelta(t, 0, Similarity(meta.SimilarityCosine)(a, b), 1e-6)
+		assert.InDelta(t, -1, RawSimilarity(meta.SimilarityCosine, 0), 1e-6)
+		score := Similarity(meta.SimilarityL2Norm)([]float32{0, 0}, []float32{3, 4})
+		assert.InDelta(t, 1.0/26, score, 1e-6)
+		assert.InDelta(t, 5, RawSimilarity(meta.SimilarityL2Norm, score), 1e-6)
+	})
+}
+
+func TestRequest_SetHits(t *testing.T) {
+	r := &Request{RRF: &RRF{WindowSize: 3, RankConstant: 1}}
+	r.SetHits(
+		[][]Hit{{{ID: "a", Score: 0.9}, {ID: "b", Score: 0.8}, {ID: "c", Score: 0.7}, {ID: "d", Score: 0.6}}},
+		[]Hit{{ID: "c", Score: 5}, {ID: "e", Score: 3}},
+	)
+	// c: 1/2 + 1/4, a: 1/2, e: 1/3, b: 1/3, d is out of window
+	assert.Equal(t, []Hit{{ID: "c", Score: 0.75}, {ID: "a", Score: 0.5}, {ID: "b", Score: 1.0 / 3}}, r.Ranked)
+}
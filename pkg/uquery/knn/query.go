@@ -0,0 +1,49 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package knn
+
+import (
+	"github.com/blugelabs/bluge/search"
+	"github.com/blugelabs/bluge/search/searcher"
+	"github.com/blugelabs/bluge/search/similarity"
+)
+
+// HitsQuery matches the documents of hits by _id, the score of document is the score of hit
+type HitsQuery struct {
+	hits []Hit
+}
+
+func NewHitsQuery(hits []Hit) *HitsQuery {
+	return &HitsQuery{hits: hits}
+}
+
+func (q *HitsQuery) Searcher(i search.Reader, options search.SearcherOptions) (search.Searcher, error) {
+	if len(q.hits) == 0 {
+		return searcher.NewMatchNoneSearcher(i, options)
+	}
+	searchers := make([]search.Searcher, 0, len(q.hits))
+	for _, hit := range q.hits {
+		s, err := searcher.NewTermSearcher(i, hit.ID, "_id", 1, similarity.ConstantScorer(hit.Score), options)
+		if err != nil {
+			for _, s := range searchers {
+				_ = s.Close()
+			}
+			return nil, err
+		}
+		searchers = append(searchers, s)
+	}
+	return searcher.NewDisjunctionSearcher(i, searchers, 1, similarity.NewCompositeSumScorer(), options)
+}
@@ -0,0 +1,146 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package knn
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/zutils/hnsw"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+)
+
+// Vector converts the value of dense_vector field, it checks the dimensions and the magnitude required by similarity
+func Vector(field string, prop meta.Property, value interface{}) ([]float32, error) {
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("field [%s] of type [dense_vector] should be an array of numbers", field)
+	}
+	if len(values) != prop.Dims {
+		return nil, fmt.Errorf("the [dense_vector] field [%s] has a different number of dimensions [%d] than defined in the mapping [%d]", field, len(values), prop.Dims)
+	}
+	vector := make([]float32, len(values))
+	for i, v := range values {
+		var f float64
+		var err error
+		switch v := v.(type) {
+		case float64:
+			f = v
+		case float32:
+			f = float64(v)
+		case int:
+			f = float64(v)
+		case int64:
+			f = float64(v)
+		case json.Number:
+			f, err = v.Float64()
+		default:
+			err = fmt.Errorf("unsupported type %T", v)
+		}
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("field [%s] of type [dense_vector] doesn't support value [%v]", field, v)
+		}
+		vector[i] = float32(f)
+	}
+	switch prop.Similarity {
+	case meta.SimilarityCosine:
+		if magnitude(vector) == 0 {
+			return nil, fmt.Errorf("the [cosine] similarity does not support vectors with zero magnitude, field [%s]", field)
+		}
+	case meta.SimilarityDotProduct:
+		if math.Abs(magnitude(vector)-1) > 1e-4 {
+			return nil, fmt.Errorf("the [dot_product] similarity can only be used with unit-length vectors, field [%s]", field)
+		}
+	}
+	return vector, nil
+}
+
+// EncodeVector encodes the vector as little endian float32 values
+func EncodeVector(vector []float32) []byte {
+	buf := make([]byte, len(vector)*4)
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(v))
+	}
+	return buf
+}
+
+// DecodeVector decodes the vector encoded by EncodeVector
+func DecodeVector(buf []byte) []float32 {
+	vector := make([]float32, len(buf)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[i*4:]))
+	}
+	return vector
+}
+
+// GraphVector returns the vector added to the graph, the vector of cosine similarity is normalized
+func GraphVector(similarity string, vector []float32) []float32 {
+	if similarity != meta.SimilarityCosine {
+		return vector
+	}
+	m := magnitude(vector)
+	if m == 0 {
+		return vector
+	}
+	rv := make([]float32, len(vector))
+	for i, v := range vector {
+		rv[i] = float32(float64(v) / m)
+	}
+	return rv
+}
+
+// Similarity returns the score function of similarity for the vectors returned by GraphVector,
+// the score is always positive and the greater is the more similar.
+func Similarity(similarity string) hnsw.Similarity {
+	switch similarity {
+	case meta.SimilarityL2Norm:
+		return func(a, b []float32) float64 {
+			var sum float64
+			for i := range a {
+				d := float64(a[i]) - float64(b[i])
+				sum += d * d
+			}
+			return 1 / (1 + sum)
+		}
+	default:
+		// cosine of the normalized vectors is the dot product
+		return func(a, b []float32) float64 {
+			return (1 + dot(a, b)) / 2
+		}
+	}
+}
+
+// RawSimilarity returns the similarity of the vectors computed from the score, it is the distance for l2_norm
+func RawSimilarity(similarity string, score float64) float64 {
+	if similarity == meta.SimilarityL2Norm {
+		return math.Sqrt(1/score - 1)
+	}
+	return 2*score - 1
+}
+
+func dot(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+func magnitude(vector []float32) float64 {
+	return math.Sqrt(dot(vector, vector))
+}
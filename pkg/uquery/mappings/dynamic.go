@@ -0,0 +1,261 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package mappings
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/blugelabs/bluge/analysis"
+
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+)
+
+const (
+	MatchPatternSimple = "simple"
+	MatchPatternRegex  = "regex"
+)
+
+// dynamicTypes the types can be used in match_mapping_type of dynamic templates
+var dynamicTypes = map[string]string{
+	"string":  "text",
+	"long":    "long",
+	"double":  "double",
+	"boolean": "boolean",
+	"date":    "date",
+	"object":  "object",
+}
+
+// dynamicKeys the options of dynamic mapping
+var dynamicKeys = []string{"dynamic", "date_detection", "dynamic_date_formats", "numeric_detection", "dynamic_templates", "total_fields_limit", "total_fields"}
+
+// hasDynamic returns true if the request contains any dynamic mapping option
+func hasDynamic(data map[string]interface{}) bool {
+	for _, k := range dynamicKeys {
+		if _, ok := data[k]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// requestDynamic parses the dynamic mapping options
+func requestDynamic(analyzers map[string]*analysis.Analyzer, data map[string]interface{}) (meta.DynamicMapping, error) {
+	dynamic := meta.DynamicMapping{}
+	for k, v := range data {
+		switch k {
+		case "dynamic":
+			switch v := v.(type) {
+			case bool:
+				if v {
+					dynamic.Dynamic = meta.DynamicTrue
+				} else {
+					dynamic.Dynamic = meta.DynamicFalse
+				}
+			case string:
+				switch strings.ToLower(v) {
+				case meta.DynamicTrue, "runtime":
+					dynamic.Dynamic = meta.DynamicTrue
+				case meta.DynamicFalse:
+					dynamic.Dynamic = meta.DynamicFalse
+				case meta.DynamicStrict:
+					dynamic.Dynamic = meta.DynamicStrict
+				default:
+					return dynamic, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] dynamic doesn't support value [%s]", v))
+				}
+			default:
+				return dynamic, errors.New(errors.ErrorTypeParsingException, "[mappings] dynamic should be a boolean or string")
+			}
+		case "date_detection":
+			b, err := zutils.ToBool(v)
+			if err != nil {
+				return dynamic, errors.New(errors.ErrorTypeParsingException, "[mappings] date_detection should be a boolean")
+			}
+			dynamic.DateDetection = &b
+		case "numeric_detection":
+			b, err := zutils.ToBool(v)
+			if err != nil {
+				return dynamic, errors.New(errors.ErrorTypeParsingException, "[mappings] numeric_detection should be a boolean")
+			}
+			dynamic.NumericDetection = &b
+		case "dynamic_date_formats":
+			formats, ok := v.([]interface{})
+			if !ok {
+				return dynamic, errors.New(errors.ErrorTypeParsingException, "[mappings] dynamic_date_formats should be an array")
+			}
+			dynamic.DynamicDateFormats = make([]string, 0, len(formats))
+			for _, f := range formats {
+				s, ok := f.(string)
+				if !ok || s == "" {
+					return dynamic, errors.New(errors.ErrorTypeParsingException, "[mappings] dynamic_date_formats should be an array of string")
+				}
+				dynamic.DynamicDateFormats = append(dynamic.DynamicDateFormats, s)
+			}
+		case "total_fields_limit":
+			limit, err := zutils.ToInt(v)
+			if err != nil || limit < 0 {
+				return dynamic, errors.New(errors.ErrorTypeParsingException, "[mappings] total_fields_limit should be a positive integer")
+			}
+			dynamic.TotalFieldsLimit = limit
+		case "total_fields":
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				return dynamic, errors.New(errors.ErrorTypeParsingException, "[mappings] total_fields should be an object")
+			}
+			limit, err := zutils.ToInt(obj["limit"])
+			if err != nil || limit < 0 {
+				return dynamic, errors.New(errors.ErrorTypeParsingException, "[mappings] total_fields.limit should be a positive integer")
+			}
+			dynamic.TotalFieldsLimit = limit
+		case "dynamic_templates":
+			templates, err := requestDynamicTemplates(analyzers, v)
+			if err != nil {
+				return dynamic, err
+			}
+			dynamic.DynamicTemplates = templates
+		}
+	}
+	return dynamic, nil
+}
+
+func requestDynamicTemplates(analyzers map[string]*analysis.Analyzer, data interface{}) ([]map[string]*meta.DynamicTemplate, error) {
+	items, ok := data.([]interface{})
+	if !ok {
+		return nil, errors.New(errors.ErrorTypeParsingException, "[mappings] dynamic_templates should be an array")
+	}
+	templates := make([]map[string]*meta.DynamicTemplate, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok || len(obj) != 1 {
+			return nil, errors.New(errors.ErrorTypeParsingException, "[mappings] dynamic_templates should be an array of object with a single name")
+		}
+		for name, v := range obj {
+			raw, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			tpl := new(meta.DynamicTemplate)
+			if err = json.Unmarshal(raw, tpl); err != nil {
+				return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] dynamic_templates [%s] parse error: %s", name, err.Error()))
+			}
+			if err = checkDynamicTemplate(analyzers, name, tpl); err != nil {
+				return nil, err
+			}
+			templates = append(templates, map[string]*meta.DynamicTemplate{name: tpl})
+		}
+	}
+	return templates, nil
+}
+
+// checkDynamicTemplate validates the options and the mapping of the template
+func checkDynamicTemplate(analyzers map[string]*analysis.Analyzer, name string, tpl *meta.DynamicTemplate) error {
+	if tpl.Mapping == nil {
+		return errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] dynamic_templates [%s] mapping should be defined", name))
+	}
+	switch tpl.MatchPattern {
+	case "", MatchPatternSimple:
+	case MatchPatternRegex:
+		for _, pattern := range []string{tpl.Match, tpl.Unmatch, tpl.PathMatch, tpl.PathUnmatch} {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] dynamic_templates [%s] pattern [%s] is invalid: %s", name, pattern, err.Error()))
+			}
+		}
+	default:
+		return errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] dynamic_templates [%s] doesn't support match_pattern [%s]", name, tpl.MatchPattern))
+	}
+	if _, ok := dynamicTypes[tpl.MatchMappingType]; !ok && tpl.MatchMappingType != "" && tpl.MatchMappingType != "*" {
+		return errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] dynamic_templates [%s] doesn't support match_mapping_type [%s]", name, tpl.MatchMappingType))
+	}
+
+	// validate the mapping with a detected type of string
+	if _, err := DynamicTemplate(analyzers, tpl, "field", "string"); err != nil {
+		return errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] dynamic_templates [%s] mapping is invalid: %s", name, err.Error()))
+	}
+	return nil
+}
+
+// MatchDynamicTemplate returns true if the new field matches the template,
+// match and unmatch check the last part of field, path_match and path_unmatch check the full dotted path.
+func MatchDynamicTemplate(tpl *meta.DynamicTemplate, field, mappingType string) bool {
+	if tpl.MatchMappingType != "" && tpl.MatchMappingType != "*" && tpl.MatchMappingType != mappingType {
+		return false
+	}
+	name := field[strings.LastIndex(field, ".")+1:]
+	match := func(pattern, s string) bool {
+		if tpl.MatchPattern == MatchPatternRegex {
+			ok, _ := regexp.MatchString(pattern, s)
+			return ok
+		}
+		return simpleMatch(pattern, s)
+	}
+	if tpl.Match != "" && !match(tpl.Match, name) {
+		return false
+	}
+	if tpl.Unmatch != "" && match(tpl.Unmatch, name) {
+		return false
+	}
+	if tpl.PathMatch != "" && !match(tpl.PathMatch, field) {
+		return false
+	}
+	if tpl.PathUnmatch != "" && match(tpl.PathUnmatch, field) {
+		return false
+	}
+	return true
+}
+
+// DynamicTemplate returns the mappings of the new field by the template,
+// {name} in mapping is replaced by the last part of field and {dynamic_type} by the detected type.
+func DynamicTemplate(analyzers map[string]*analysis.Analyzer, tpl *meta.DynamicTemplate, field, mappingType string) (*meta.Mappings, error) {
+	name := field[strings.LastIndex(field, ".")+1:]
+	dynamicType := dynamicTypes[mappingType]
+	mapping := make(map[string]interface{}, len(tpl.Mapping)+1)
+	for k, v := range tpl.Mapping {
+		if s, ok := v.(string); ok {
+			s = strings.ReplaceAll(s, "{name}", name)
+			s = strings.ReplaceAll(s, "{dynamic_type}", dynamicType)
+			v = s
+		}
+		mapping[k] = v
+	}
+	if _, ok := mapping["type"]; !ok {
+		mapping["type"] = dynamicType
+	}
+	return Request(analyzers, map[string]interface{}{"properties": map[string]interface{}{field: mapping}})
+}
+
+// simpleMatch matches s by pattern which only supports * as wildcard
+func simpleMatch(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, parts[len(parts)-1])
+}
@@ -29,12 +29,26 @@ import (
 	"github.com/zincsearch/zincsearch/pkg/zutils/json"
 )
 
+// MaxVectorDims is the maximum number of dimensions of dense_vector field
+const MaxVectorDims = 4096
+
 func Request(analyzers map[string]*analysis.Analyzer, data map[string]interface{}) (*meta.Mappings, error) {
 	if len(data) == 0 {
 		return nil, nil
 	}
 
+	mappings := meta.NewMappings()
+	dynamic, err := requestDynamic(analyzers, data)
+	if err != nil {
+		return nil, err
+	}
+	mappings.DynamicMapping = dynamic
+
 	if data["properties"] == nil {
+		// only dynamic mapping options
+		if hasDynamic(data) {
+			return mappings, nil
+		}
 		return nil, errors.New(errors.ErrorTypeParsingException, "[mappings] properties should be defined")
 	}
 
@@ -43,7 +57,6 @@ func Request(analyzers map[string]*analysis.Analyzer, data map[string]interface{
 		return nil, errors.New(errors.ErrorTypeParsingException, "[mappings] properties should be an object")
 	}
 
-	mappings := meta.NewMappings()
 	for field, prop := range properties {
 		var propFields map[string]interface{}
 
@@ -101,18 +114,31 @@ func Request(analyzers map[string]*analysis.Analyzer, data map[string]interface{
 			newProp = meta.NewProperty("keyword")
 		case "match_only_text":
 			newProp = meta.NewProperty("text")
-		case "integer", "double", "long", "short", "int", "float":
+		case "integer", "int":
+			newProp = meta.NewProperty("numeric")
+			newProp.NumericType = meta.NumericTypeInteger
+		case "long", "short", "byte", "double", "float", "half_float", "scaled_float":
 			newProp = meta.NewProperty("numeric")
+			newProp.NumericType = propTypeStr
 		case "boolean":
 			newProp = meta.NewProperty("bool")
+		case "completion":
+			newProp = meta.NewProperty("completion")
+			newProp.Analyzer = "simple"
+		case "dense_vector":
+			newProp = meta.NewProperty("dense_vector")
+			newProp.Similarity = meta.SimilarityCosine
+		case "percolator":
+			newProp = meta.NewProperty("percolator")
 		case "time", "datetime":
 			newProp = meta.NewProperty("date")
-		case "flattened", "object", "nested", "wildcard", "byte", "alias", "geo_point", "ip", "ip_range", "scaled_float":
+		case "flattened", "object", "nested", "wildcard", "alias", "geo_point", "ip", "ip_range":
 			// ignore
 		default:
 			return nil, errors.New(errors.ErrorTypeXContentParseException, fmt.Sprintf("[mappings] properties [%s] doesn't support type [%s]", field, propTypeStr))
 		}
 
+		var nullValue interface{}
 		for k, v := range prop {
 			switch k {
 			case "type":
@@ -139,6 +165,78 @@ func Request(analyzers map[string]*analysis.Analyzer, data map[string]interface{
 				newProp.Aggregatable = v.(bool)
 			case "highlightable":
 				newProp.Highlightable = v.(bool)
+			case "normalizer":
+				name, ok := v.(string)
+				if !ok || newProp.Type != "keyword" {
+					return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] normalizer only supports keyword field", field))
+				}
+				if _, err := zincanalysis.QueryNormalizer(analyzers, name); err != nil {
+					return nil, err
+				}
+				newProp.Normalizer = name
+			case "ignore_above":
+				n, err := zutils.ToInt(v)
+				if err != nil || n < 0 {
+					return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] ignore_above should be a positive integer", field))
+				}
+				newProp.IgnoreAbove = n
+			case "null_value":
+				// converted after format and time_zone are parsed
+				nullValue = v
+			case "scaling_factor":
+				f, err := zutils.ToFloat64(v)
+				if err != nil || f <= 0 || newProp.NumericType != meta.NumericTypeScaledFloat {
+					return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] scaling_factor should be a positive number of scaled_float field", field))
+				}
+				newProp.ScalingFactor = f
+			case "max_input_length":
+				n, err := zutils.ToInt(v)
+				if err != nil || n <= 0 || newProp.Type != "completion" {
+					return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] max_input_length should be a positive integer of completion field", field))
+				}
+				newProp.MaxInputLength = n
+			case "contexts":
+				if newProp.Type != "completion" {
+					return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] contexts only supports completion field", field))
+				}
+				contexts, err := convertContexts(field, v)
+				if err != nil {
+					return nil, err
+				}
+				newProp.Contexts = contexts
+			case "dims":
+				n, err := zutils.ToInt(v)
+				if err != nil || n <= 0 || n > MaxVectorDims || newProp.Type != "dense_vector" {
+					return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] dims should be an integer between 1 and %d of dense_vector field", field, MaxVectorDims))
+				}
+				newProp.Dims = n
+			case "similarity":
+				similarity, _ := zutils.ToString(v)
+				similarity = strings.ToLower(similarity)
+				switch similarity {
+				case meta.SimilarityCosine, meta.SimilarityDotProduct, meta.SimilarityL2Norm:
+				default:
+					return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] doesn't support similarity [%v], it should be one of [cosine, dot_product, l2_norm]", field, v))
+				}
+				if newProp.Type != "dense_vector" {
+					return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] similarity only supports dense_vector field", field))
+				}
+				newProp.Similarity = similarity
+			case "index_options":
+				if newProp.Type != "dense_vector" {
+					return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] index_options only supports dense_vector field", field))
+				}
+				options, err := convertVectorIndexOptions(field, v)
+				if err != nil {
+					return nil, err
+				}
+				newProp.IndexOptions = options
+			case "copy_to":
+				copyTo, err := convertCopyTo(field, v)
+				if err != nil {
+					return nil, err
+				}
+				newProp.CopyTo = copyTo
 			default:
 				// ignore unknown options
 				// return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] unknown option [%s]", field, k))
@@ -149,10 +247,35 @@ func Request(analyzers map[string]*analysis.Analyzer, data map[string]interface{
 			newProp.Store = true
 		}
 
+		if newProp.NumericType == meta.NumericTypeScaledFloat && newProp.ScalingFactor == 0 {
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] scaling_factor should be defined for scaled_float field", field))
+		}
+
+		if newProp.Type == "dense_vector" && newProp.Dims == 0 {
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] dims should be defined for dense_vector field", field))
+		}
+
+		if nullValue != nil {
+			if newProp.NullValue, err = convertNullValue(newProp, nullValue); err != nil {
+				return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] null_value %s", field, err.Error()))
+			}
+		}
+
 		if newProp.Type != "" {
 			mappings.SetProperty(field, newProp)
 		}
 
+		if newProp.Type == "completion" {
+			if _, err := zincanalysis.QueryAnalyzer(analyzers, newProp.Analyzer); err != nil {
+				return nil, err
+			}
+			if newProp.SearchAnalyzer != "" {
+				if _, err := zincanalysis.QueryAnalyzer(analyzers, newProp.SearchAnalyzer); err != nil {
+					return nil, err
+				}
+			}
+		}
+
 		if newProp.Type == "text" {
 			fields, err := convertToField(propFields)
 			if err != nil {
@@ -161,6 +284,9 @@ func Request(analyzers map[string]*analysis.Analyzer, data map[string]interface{
 
 			for k, v := range fields {
 				newProp.AddField(k, v)
+			}
+			// includes the keyword sub field added by default
+			for k, v := range newProp.Fields {
 				mappings.SetProperty(field+"."+k, v)
 			}
 
@@ -204,3 +330,123 @@ func convertToField(v map[string]interface{}) (map[string]meta.Property, error)
 
 	return r, nil
 }
+
+// convertNullValue converts the null_value to the type of property
+func convertNullValue(prop meta.Property, v interface{}) (interface{}, error) {
+	if v == nil || prop.Type == "" {
+		return nil, nil
+	}
+	switch prop.Type {
+	case "keyword":
+		return zutils.ToString(v)
+	case "numeric":
+		if prop.IsLong() {
+			if _, err := prop.ToLong(v); err != nil {
+				return nil, err
+			}
+		}
+		return zutils.ToFloat64(v)
+	case "bool":
+		return zutils.ToBool(v)
+	case "date":
+		if _, err := zutils.ParseTime(v, prop.Format, prop.TimeZone); err != nil {
+			return nil, err
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("doesn't support type [%s]", prop.Type)
+	}
+}
+
+// convertCopyTo converts the copy_to to a list of field
+func convertCopyTo(field string, v interface{}) ([]string, error) {
+	var fields []string
+	switch v := v.(type) {
+	case string:
+		fields = []string{v}
+	case []interface{}:
+		for _, vv := range v {
+			s, ok := vv.(string)
+			if !ok {
+				return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] copy_to should be a string or an array of string", field))
+			}
+			fields = append(fields, s)
+		}
+	default:
+		return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] copy_to should be a string or an array of string", field))
+	}
+	for _, f := range fields {
+		if f == "" || f == field {
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] copy_to [%s] is invalid", field, f))
+		}
+	}
+	return fields, nil
+}
+
+// convertContexts converts the contexts of completion field, only category context is supported
+func convertContexts(field string, v interface{}) ([]meta.CompletionContext, error) {
+	items, ok := v.([]interface{})
+	if !ok {
+		items = []interface{}{v}
+	}
+	contexts := make([]meta.CompletionContext, 0, len(items))
+	names := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] contexts should be an array of object", field))
+		}
+		var context meta.CompletionContext
+		context.Name, _ = zutils.ToString(m["name"])
+		context.Type, _ = zutils.ToString(m["type"])
+		if path, ok := m["path"]; ok {
+			context.Path, _ = zutils.ToString(path)
+		}
+		context.Type = strings.ToLower(context.Type)
+		if context.Name == "" {
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] context name is required", field))
+		}
+		if context.Type != "category" {
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] context [%s] doesn't support type [%s], only category is supported", field, context.Name, context.Type))
+		}
+		if _, ok := names[context.Name]; ok {
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] context [%s] is duplicated", field, context.Name))
+		}
+		names[context.Name] = struct{}{}
+		contexts = append(contexts, context)
+	}
+	return contexts, nil
+}
+
+// convertVectorIndexOptions converts the index_options of dense_vector field, only hnsw is supported
+func convertVectorIndexOptions(field string, v interface{}) (*meta.VectorIndexOptions, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] index_options should be an object", field))
+	}
+	options := &meta.VectorIndexOptions{Type: "hnsw"}
+	for k, v := range m {
+		var err error
+		switch k {
+		case "type":
+			options.Type, _ = zutils.ToString(v)
+			if options.Type != "hnsw" {
+				return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] index_options doesn't support type [%v], only hnsw is supported", field, v))
+			}
+		case "m":
+			if options.M, err = zutils.ToInt(v); err == nil && (options.M < 2 || options.M > 100) {
+				err = fmt.Errorf("out of range")
+			}
+		case "ef_construction":
+			if options.EfConstruction, err = zutils.ToInt(v); err == nil && (options.EfConstruction < 1 || options.EfConstruction > 3200) {
+				err = fmt.Errorf("out of range")
+			}
+		default:
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] index_options unknown option [%s]", field, k))
+		}
+		if err != nil {
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[mappings] properties [%s] index_options [%s] doesn't support value [%v]", field, k, v))
+		}
+	}
+	return options, nil
+}
@@ -0,0 +1,100 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package query
+
+import (
+	"github.com/blugelabs/bluge/search"
+	"github.com/blugelabs/bluge/search/searcher"
+)
+
+// blendedTermQuery matches the terms at the same position of the cross_fields query in the fields as one big field.
+// The document frequency of each term is blended to the max of the fields, so a term rare in one field doesn't
+// score higher than the term frequent in the others. The score is the best field plus tie_breaker * the others.
+type blendedTermQuery struct {
+	fields     []multiMatchField
+	terms      []string
+	tieBreaker float64
+}
+
+func (q *blendedTermQuery) Searcher(i search.Reader, options search.SearcherOptions) (search.Searcher, error) {
+	docFreqs, err := q.docFreqs(i)
+	if err != nil {
+		return nil, err
+	}
+
+	searchers := make([]search.Searcher, 0, len(q.fields)*len(q.terms))
+	closeSearchers := func() {
+		for _, s := range searchers {
+			_ = s.Close()
+		}
+	}
+	for _, field := range q.fields {
+		stats, err := i.CollectionStats(field.name)
+		if err != nil {
+			closeSearchers()
+			return nil, err
+		}
+		boost := 1.0
+		if field.boost >= 0 {
+			boost = field.boost
+		}
+		for idx, term := range q.terms {
+			// the document frequency can't be more than the documents of the field
+			docFreq := docFreqs[idx]
+			if n := stats.DocumentCount(); docFreq > n {
+				docFreq = n
+			}
+			scorer := options.SimilarityForField(field.name).Scorer(boost, stats, blendedTermStats(docFreq))
+			s, err := searcher.NewTermSearcher(i, term, field.name, boost, scorer, options)
+			if err != nil {
+				closeSearchers()
+				return nil, err
+			}
+			searchers = append(searchers, s)
+		}
+	}
+	if len(searchers) == 0 {
+		return searcher.NewMatchNoneSearcher(i, options)
+	}
+	return searcher.NewDisjunctionSearcher(i, searchers, 1, &disMaxScorer{tieBreaker: q.tieBreaker, boost: 1.0}, options)
+}
+
+// docFreqs returns the max document frequency of each term in the fields
+func (q *blendedTermQuery) docFreqs(i search.Reader) ([]uint64, error) {
+	docFreqs := make([]uint64, len(q.terms))
+	for idx, term := range q.terms {
+		for _, field := range q.fields {
+			it, err := i.PostingsIterator([]byte(term), field.name, false, false, false)
+			if err != nil {
+				return nil, err
+			}
+			if n := it.Count(); n > docFreqs[idx] {
+				docFreqs[idx] = n
+			}
+			if err = it.Close(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return docFreqs, nil
+}
+
+// blendedTermStats is the blended document frequency of a term
+type blendedTermStats uint64
+
+func (s blendedTermStats) DocumentFrequency() uint64 {
+	return uint64(s)
+}
@@ -17,7 +17,6 @@ package query
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/blugelabs/bluge"
@@ -29,6 +28,7 @@ import (
 
 func BoolQuery(query map[string]interface{}, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (bluge.Query, error) {
 	boolQuery := bluge.NewBooleanQuery()
+	minimumShouldMatch := ""
 	for k, v := range query {
 		k := strings.ToLower(k)
 		switch k {
@@ -111,27 +111,20 @@ func BoolQuery(query map[string]interface{}, mappings *meta.Mappings, analyzers
 			}
 			boolQuery.AddMust(filterQuery)
 		case "minimum_should_match":
-			switch v := v.(type) {
-			case string:
-				if strings.Contains(v, "%") || strings.Contains(v, "<") {
-					return nil, errors.New(errors.ErrorTypeXContentParseException, fmt.Sprintf("[bool] %s value only support integer", k))
-				}
-				vi, err := strconv.ParseInt(v, 10, 64)
-				if err != nil {
-					return nil, errors.New(errors.ErrorTypeXContentParseException, fmt.Sprintf("[bool] %s type string convert to int error: %s", k, err))
-				}
-				boolQuery.SetMinShould(int(vi)) // lgtm[go/hardcoded-credentials]
-			case int:
-				boolQuery.SetMinShould(v)
-			case float64:
-				boolQuery.SetMinShould(int(v))
-			default:
-				return nil, errors.New(errors.ErrorTypeXContentParseException, fmt.Sprintf("[bool] %s doesn't support values of type: %T", k, v))
+			spec, err := ParseMinimumShouldMatch(v)
+			if err != nil {
+				return nil, minimumShouldMatchError("bool", err)
 			}
+			minimumShouldMatch = spec
 		default:
 			return nil, errors.New(errors.ErrorTypeXContentParseException, fmt.Sprintf("[bool] unknown field [%s]", k))
 		}
 	}
 
+	if minimumShouldMatch != "" {
+		minShould, _ := CalculateMinimumShouldMatch(len(boolQuery.Shoulds()), minimumShouldMatch)
+		boolQuery.SetMinShould(minShould) // lgtm[go/hardcoded-credentials]
+	}
+
 	return boolQuery, nil
 }
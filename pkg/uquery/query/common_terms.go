@@ -0,0 +1,96 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package query
+
+import (
+	"math"
+
+	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/search"
+)
+
+// commonTermsQuery implements the cutoff_frequency of match query.
+// The terms are split by document frequency when searching, the low frequency terms
+// are required by the operator and minimum_should_match, the high frequency terms
+// (more than cutoff) only contribute to the score, unless all the terms are high frequency.
+type commonTermsQuery struct {
+	field              string
+	terms              [][]string
+	clauses            []bluge.Query
+	cutoff             float64 // absolute (>=1) or relative to the number of documents (<1)
+	operator           bluge.MatchQueryOperator
+	minimumShouldMatch string
+	boost              float64
+}
+
+func (q *commonTermsQuery) Searcher(i search.Reader, options search.SearcherOptions) (search.Searcher, error) {
+	stats, err := i.CollectionStats(q.field)
+	if err != nil {
+		return nil, err
+	}
+	maxTermFrequency := q.cutoff
+	if maxTermFrequency < 1 {
+		maxTermFrequency = math.Ceil(maxTermFrequency * float64(stats.TotalDocumentCount()))
+	}
+
+	var lowFreq, highFreq []bluge.Query
+	for idx, terms := range q.terms {
+		docFreq, err := q.docFreq(i, terms)
+		if err != nil {
+			return nil, err
+		}
+		if float64(docFreq) > maxTermFrequency {
+			highFreq = append(highFreq, q.clauses[idx])
+		} else {
+			lowFreq = append(lowFreq, q.clauses[idx])
+		}
+	}
+
+	var subq *bluge.BooleanQuery
+	switch {
+	case len(lowFreq) == 0:
+		subq = combineMatchClauses(highFreq, q.operator, "")
+	case len(highFreq) == 0:
+		subq = combineMatchClauses(lowFreq, q.operator, q.minimumShouldMatch)
+	default:
+		subq = bluge.NewBooleanQuery()
+		subq.AddMust(combineMatchClauses(lowFreq, q.operator, q.minimumShouldMatch))
+		subq.AddShould(combineMatchClauses(highFreq, q.operator, ""))
+	}
+	if q.boost >= 0 {
+		subq.SetBoost(q.boost)
+	}
+
+	return subq.Searcher(i, options)
+}
+
+// docFreq returns the max document frequency of the terms at the same position
+func (q *commonTermsQuery) docFreq(i search.Reader, terms []string) (uint64, error) {
+	var max uint64
+	for _, term := range terms {
+		it, err := i.PostingsIterator([]byte(term), q.field, false, false, false)
+		if err != nil {
+			return 0, err
+		}
+		if n := it.Count(); n > max {
+			max = n
+		}
+		if err = it.Close(); err != nil {
+			return 0, err
+		}
+	}
+	return max, nil
+}
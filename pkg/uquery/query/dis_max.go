@@ -0,0 +1,83 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package query
+
+import (
+	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/search"
+	"github.com/blugelabs/bluge/search/searcher"
+)
+
+// disMaxQuery matches the documents matching any of the queries, the score is the
+// max score of the matching queries plus tie_breaker * the scores of the others.
+type disMaxQuery struct {
+	queries    []bluge.Query
+	tieBreaker float64
+	boost      float64
+}
+
+func newDisMaxQuery(queries []bluge.Query, tieBreaker float64) *disMaxQuery {
+	return &disMaxQuery{queries: queries, tieBreaker: tieBreaker, boost: 1.0}
+}
+
+func (q *disMaxQuery) SetBoost(boost float64) *disMaxQuery {
+	q.boost = boost
+	return q
+}
+
+func (q *disMaxQuery) Searcher(i search.Reader, options search.SearcherOptions) (search.Searcher, error) {
+	searchers := make([]search.Searcher, 0, len(q.queries))
+	for _, query := range q.queries {
+		s, err := query.Searcher(i, options)
+		if err != nil {
+			for _, s := range searchers {
+				_ = s.Close()
+			}
+			return nil, err
+		}
+		searchers = append(searchers, s)
+	}
+	if len(searchers) == 0 {
+		return searcher.NewMatchNoneSearcher(i, options)
+	}
+	return searcher.NewDisjunctionSearcher(i, searchers, 1, &disMaxScorer{tieBreaker: q.tieBreaker, boost: q.boost}, options)
+}
+
+type disMaxScorer struct {
+	tieBreaker float64
+	boost      float64
+}
+
+func (s *disMaxScorer) ScoreComposite(constituents []*search.DocumentMatch) float64 {
+	var max, sum float64
+	for _, constituent := range constituents {
+		sum += constituent.Score
+		if constituent.Score > max {
+			max = constituent.Score
+		}
+	}
+	return (max + s.tieBreaker*(sum-max)) * s.boost
+}
+
+func (s *disMaxScorer) ExplainComposite(constituents []*search.DocumentMatch) *search.Explanation {
+	var children []*search.Explanation
+	for _, constituent := range constituents {
+		children = append(children, constituent.Explanation)
+	}
+	return search.NewExplanation(s.ScoreComposite(constituents),
+		"max plus tie_breaker times others of:",
+		children...)
+}
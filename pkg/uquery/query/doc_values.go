@@ -0,0 +1,177 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package query
+
+import (
+	"fmt"
+
+	"github.com/blugelabs/bluge/numeric"
+	"github.com/blugelabs/bluge/search"
+
+	"github.com/zincsearch/zincsearch/pkg/meta"
+)
+
+const (
+	docValueDouble = iota
+	docValueLong
+	docValueDate
+	docValueBool
+	docValueKeyword
+)
+
+// docValueField describes how to decode the doc values of a field
+type docValueField struct {
+	name          string
+	kind          int
+	scalingFactor float64
+}
+
+// newDocValueField returns the doc value field of the mapping,
+// numeric fields are decoded as float64 and date fields as epoch milliseconds.
+func newDocValueField(field string, mappings *meta.Mappings) (docValueField, error) {
+	if mappings == nil {
+		return docValueField{}, fmt.Errorf("no field found for [%s] in mapping", field)
+	}
+	prop, ok := mappings.GetProperty(field)
+	if !ok {
+		return docValueField{}, fmt.Errorf("no field found for [%s] in mapping", field)
+	}
+	switch prop.Type {
+	case "numeric":
+		if prop.IsLong() {
+			return docValueField{name: field, kind: docValueLong, scalingFactor: prop.ScalingFactor}, nil
+		}
+		return docValueField{name: field, kind: docValueDouble}, nil
+	case "date", "time":
+		return docValueField{name: field, kind: docValueDate}, nil
+	case "bool":
+		return docValueField{name: field, kind: docValueBool}, nil
+	case "keyword":
+		return docValueField{name: field, kind: docValueKeyword}, nil
+	default:
+		return docValueField{}, fmt.Errorf("field [%s] of type [%s] doesn't support doc values", field, prop.Type)
+	}
+}
+
+// mergeDocValueFields appends the fields which are not in the list
+func mergeDocValueFields(fields []docValueField, others ...docValueField) []docValueField {
+	for _, other := range others {
+		exists := false
+		for _, field := range fields {
+			if field.name == other.name {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			fields = append(fields, other)
+		}
+	}
+	return fields
+}
+
+// docValues reads the doc values of the fields of a document by the doc value visitor
+type docValues struct {
+	fields  map[string]docValueField
+	visit   func(number uint64, visitor func(field string, term []byte)) error
+	numbers map[string][]float64
+	terms   map[string][]byte // the first term of the field
+}
+
+func newDocValues(i search.Reader, fields []docValueField) (*docValues, error) {
+	names := make([]string, 0, len(fields))
+	dv := &docValues{
+		fields:  make(map[string]docValueField, len(fields)),
+		numbers: make(map[string][]float64, len(fields)),
+		terms:   make(map[string][]byte, len(fields)),
+	}
+	for _, field := range fields {
+		names = append(names, field.name)
+		dv.fields[field.name] = field
+	}
+	reader, err := i.DocumentValueReader(names)
+	if err != nil {
+		return nil, err
+	}
+	dv.visit = func(number uint64, visitor func(field string, term []byte)) error {
+		return reader.VisitDocumentValues(number, visitor)
+	}
+	return dv, nil
+}
+
+// load reads the doc values of the document
+func (dv *docValues) load(number uint64) error {
+	for name := range dv.numbers {
+		dv.numbers[name] = dv.numbers[name][:0]
+	}
+	for name := range dv.terms {
+		delete(dv.terms, name)
+	}
+	return dv.visit(number, dv.visitor)
+}
+
+func (dv *docValues) visitor(field string, term []byte) {
+	f, ok := dv.fields[field]
+	if !ok {
+		return
+	}
+	if _, ok := dv.terms[field]; !ok {
+		dv.terms[field] = append([]byte(nil), term...)
+	}
+
+	var value float64
+	switch f.kind {
+	case docValueBool:
+		if string(term) == "true" {
+			value = 1
+		}
+	case docValueKeyword:
+		return
+	default:
+		// numeric terms are indexed with multiple precisions, only the full precision term is the value
+		prefixCoded := numeric.PrefixCoded(term)
+		if shift, err := prefixCoded.Shift(); err != nil || shift != 0 {
+			return
+		}
+		i64, err := prefixCoded.Int64()
+		if err != nil {
+			return
+		}
+		switch f.kind {
+		case docValueLong:
+			value = float64(i64)
+			if f.scalingFactor > 0 {
+				value /= f.scalingFactor
+			}
+		case docValueDate:
+			value = float64(i64 / 1e6)
+		default:
+			value = numeric.Int64ToFloat64(i64)
+		}
+	}
+	dv.numbers[field] = append(dv.numbers[field], value)
+}
+
+// Numbers returns the numeric values of the field
+func (dv *docValues) Numbers(field string) []float64 {
+	return dv.numbers[field]
+}
+
+// Term returns the first term of the field
+func (dv *docValues) Term(field string) ([]byte, bool) {
+	term, ok := dv.terms[field]
+	return term, ok
+}
@@ -0,0 +1,379 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package query
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/analysis"
+	"github.com/blugelabs/bluge/search"
+
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+func FunctionScoreQuery(query map[string]interface{}, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (bluge.Query, error) {
+	value := new(meta.FunctionScoreQuery)
+	value.Boost = -1.0
+	value.MaxBoost = math.MaxFloat64
+	shorthand := make(map[string]interface{})
+	var functions []*weightedScoreFunction
+	for k, v := range query {
+		k := strings.ToLower(k)
+		switch k {
+		case "query":
+			value.Query = v
+		case "boost":
+			value.Boost, _ = zutils.ToFloat64(v)
+		case "score_mode":
+			value.ScoreMode, _ = zutils.ToString(v)
+		case "boost_mode":
+			value.BoostMode, _ = zutils.ToString(v)
+		case "max_boost":
+			value.MaxBoost, _ = zutils.ToFloat64(v)
+		case "min_score":
+			minScore, err := zutils.ToFloat64(v)
+			if err != nil {
+				return nil, errors.New(errors.ErrorTypeParsingException, "[function_score] min_score should be a number")
+			}
+			value.MinScore = &minScore
+		case "functions":
+			items, ok := v.([]interface{})
+			if !ok {
+				return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[function_score] functions doesn't support values of type: %T", v))
+			}
+			for _, item := range items {
+				item, ok := item.(map[string]interface{})
+				if !ok {
+					return nil, errors.New(errors.ErrorTypeParsingException, "[function_score] functions should be an array of objects")
+				}
+				f, err := parseScoreFunction(item, mappings, analyzers)
+				if err != nil {
+					return nil, err
+				}
+				functions = append(functions, f)
+			}
+		case "weight", "field_value_factor", "random_score", "script_score", "gauss", "exp", "linear":
+			shorthand[k] = v
+		default:
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[function_score] unknown field [%s]", k))
+		}
+	}
+	if len(shorthand) > 0 {
+		if functions != nil {
+			return nil, errors.New(errors.ErrorTypeParsingException, "failed to parse [function_score] query. already found [functions] array, now encountering a function outside of it.")
+		}
+		f, err := parseScoreFunction(shorthand, mappings, analyzers)
+		if err != nil {
+			return nil, err
+		}
+		functions = append(functions, f)
+	}
+
+	q := &functionScoreQuery{
+		functions: functions,
+		scoreMode: strings.ToLower(value.ScoreMode),
+		boostMode: strings.ToLower(value.BoostMode),
+		maxBoost:  value.MaxBoost,
+		boost:     1.0,
+	}
+	switch q.scoreMode {
+	case "":
+		q.scoreMode = "multiply"
+	case "multiply", "sum", "avg", "first", "max", "min":
+	default:
+		return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[function_score] illegal score_mode [%s]", value.ScoreMode))
+	}
+	switch q.boostMode {
+	case "":
+		q.boostMode = "multiply"
+	case "multiply", "replace", "sum", "avg", "max", "min":
+	default:
+		return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[function_score] illegal boost_mode [%s]", value.BoostMode))
+	}
+	if value.MinScore != nil {
+		q.minScore = *value.MinScore
+		q.hasMinScore = true
+	}
+	if value.Boost >= 0 {
+		q.boost = value.Boost
+	}
+
+	var err error
+	if value.Query == nil {
+		q.query = bluge.NewMatchAllQuery()
+	} else if q.query, err = Query(value.Query, mappings, analyzers); err != nil {
+		return nil, errors.New(errors.ErrorTypeXContentParseException, "[function_score] failed to parse field [query]").Cause(err)
+	}
+	for _, f := range functions {
+		if f.function != nil {
+			q.fields = mergeDocValueFields(q.fields, f.function.Fields()...)
+		}
+	}
+
+	return q, nil
+}
+
+// functionScoreQuery modifies the scores of the documents matching the query by the functions
+type functionScoreQuery struct {
+	query       bluge.Query
+	functions   []*weightedScoreFunction
+	scoreMode   string
+	boostMode   string
+	maxBoost    float64
+	minScore    float64
+	hasMinScore bool
+	boost       float64
+	fields      []docValueField
+}
+
+func (q *functionScoreQuery) Searcher(i search.Reader, options search.SearcherOptions) (search.Searcher, error) {
+	s, err := q.query.Searcher(i, options)
+	if err != nil {
+		return nil, err
+	}
+	rv := &functionScoreSearcher{
+		query:    q,
+		searcher: s,
+		filters:  make([]*filterMatcher, len(q.functions)),
+		explain:  options.Explain,
+	}
+
+	filterOptions := options
+	filterOptions.Explain = false
+	filterOptions.Score = "none"
+	for j, f := range q.functions {
+		if f.filter == nil {
+			continue
+		}
+		fs, err := f.filter.Searcher(i, filterOptions)
+		if err != nil {
+			_ = rv.Close()
+			return nil, err
+		}
+		rv.filters[j] = &filterMatcher{searcher: fs}
+	}
+	if len(q.fields) > 0 {
+		if rv.values, err = newDocValues(i, q.fields); err != nil {
+			_ = rv.Close()
+			return nil, err
+		}
+	}
+	return rv, nil
+}
+
+type functionScoreSearcher struct {
+	query    *functionScoreQuery
+	searcher search.Searcher
+	filters  []*filterMatcher
+	values   *docValues
+	explain  bool
+}
+
+func (s *functionScoreSearcher) Next(ctx *search.Context) (*search.DocumentMatch, error) {
+	for {
+		dm, err := s.searcher.Next(ctx)
+		if err != nil || dm == nil {
+			return nil, err
+		}
+		ok, err := s.score(ctx, dm)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return dm, nil
+		}
+		ctx.DocumentMatchPool.Put(dm)
+	}
+}
+
+func (s *functionScoreSearcher) Advance(ctx *search.Context, number uint64) (*search.DocumentMatch, error) {
+	dm, err := s.searcher.Advance(ctx, number)
+	if err != nil || dm == nil {
+		return nil, err
+	}
+	ok, err := s.score(ctx, dm)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return dm, nil
+	}
+	ctx.DocumentMatchPool.Put(dm)
+	return s.Next(ctx)
+}
+
+// score computes the score of the document, returns false if the score is less than min_score
+func (s *functionScoreSearcher) score(ctx *search.Context, dm *search.DocumentMatch) (bool, error) {
+	q := s.query
+	doc := &functionDoc{number: dm.Number, score: dm.Score}
+	if s.values != nil {
+		if err := s.values.load(dm.Number); err != nil {
+			return false, err
+		}
+		doc.values = s.values
+	}
+
+	var factor, totalWeight float64
+	matched := false
+	for j, f := range q.functions {
+		if filter := s.filters[j]; filter != nil {
+			ok, err := filter.matches(ctx, dm.Number)
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				continue
+			}
+		}
+		score, err := f.Score(doc)
+		if err != nil {
+			return false, err
+		}
+		if !matched {
+			matched = true
+			factor = score
+			totalWeight = f.weight
+			if q.scoreMode == "first" {
+				break
+			}
+			continue
+		}
+		totalWeight += f.weight
+		switch q.scoreMode {
+		case "sum", "avg":
+			factor += score
+		case "max":
+			factor = math.Max(factor, score)
+		case "min":
+			factor = math.Min(factor, score)
+		default:
+			factor *= score
+		}
+	}
+	if !matched {
+		factor = 1
+	} else if q.scoreMode == "avg" && totalWeight != 0 {
+		factor /= totalWeight
+	}
+	factor = math.Min(factor, q.maxBoost)
+
+	var score float64
+	switch q.boostMode {
+	case "replace":
+		score = factor
+	case "sum":
+		score = dm.Score + factor
+	case "avg":
+		score = (dm.Score + factor) / 2
+	case "max":
+		score = math.Max(dm.Score, factor)
+	case "min":
+		score = math.Min(dm.Score, factor)
+	default:
+		score = dm.Score * factor
+	}
+	score *= q.boost
+
+	if s.explain {
+		dm.Explanation = search.NewExplanation(score,
+			fmt.Sprintf("function score, score mode [%s], boost mode [%s]", q.scoreMode, q.boostMode),
+			dm.Explanation,
+			search.NewExplanation(factor, "min of functions score and max boost"),
+		)
+	}
+	dm.Score = score
+
+	return !q.hasMinScore || score >= q.minScore, nil
+}
+
+func (s *functionScoreSearcher) Close() error {
+	var err error
+	if s.searcher != nil {
+		err = s.searcher.Close()
+	}
+	for _, filter := range s.filters {
+		if filter == nil {
+			continue
+		}
+		if e := filter.searcher.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (s *functionScoreSearcher) Count() uint64 {
+	return s.searcher.Count()
+}
+
+func (s *functionScoreSearcher) Min() int {
+	return s.searcher.Min()
+}
+
+func (s *functionScoreSearcher) Size() int {
+	size := s.searcher.Size()
+	for _, filter := range s.filters {
+		if filter != nil {
+			size += filter.searcher.Size()
+		}
+	}
+	return size
+}
+
+func (s *functionScoreSearcher) DocumentMatchPoolSize() int {
+	size := s.searcher.DocumentMatchPoolSize()
+	for _, filter := range s.filters {
+		if filter != nil {
+			size += filter.searcher.DocumentMatchPoolSize() + 1
+		}
+	}
+	return size
+}
+
+// filterMatcher checks if the documents match the filter, the documents must be checked in order
+type filterMatcher struct {
+	searcher search.Searcher
+	current  *search.DocumentMatch
+	done     bool
+}
+
+func (f *filterMatcher) matches(ctx *search.Context, number uint64) (bool, error) {
+	if f.current != nil && f.current.Number >= number {
+		return f.current.Number == number, nil
+	}
+	if f.done {
+		return false, nil
+	}
+	if f.current != nil {
+		ctx.DocumentMatchPool.Put(f.current)
+		f.current = nil
+	}
+	dm, err := f.searcher.Advance(ctx, number)
+	if err != nil {
+		return false, err
+	}
+	if dm == nil {
+		f.done = true
+		return false, nil
+	}
+	f.current = dm
+	return dm.Number == number, nil
+}
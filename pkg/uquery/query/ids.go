@@ -65,5 +65,5 @@ func IdsQuery(query map[string]interface{}, mappings *meta.Mappings) (bluge.Quer
 
 	return TermsQuery(map[string]interface{}{
 		"_id": value.Values,
-	}, mappings)
+	}, mappings, nil)
 }
@@ -21,6 +21,7 @@ import (
 
 	"github.com/blugelabs/bluge"
 	"github.com/blugelabs/bluge/analysis"
+	"github.com/blugelabs/bluge/analysis/analyzer"
 
 	"github.com/zincsearch/zincsearch/pkg/errors"
 	"github.com/zincsearch/zincsearch/pkg/meta"
@@ -55,6 +56,17 @@ func MatchQuery(query map[string]interface{}, mappings *meta.Mappings, analyzers
 					value.Fuzziness = v
 				case "prefix_length":
 					value.PrefixLength, _ = zutils.ToFloat64(v)
+				case "minimum_should_match":
+					value.MinimumShouldMatch = v
+				case "zero_terms_query":
+					value.ZeroTermsQuery, _ = zutils.ToString(v)
+				case "cutoff_frequency":
+					value.CutoffFrequency, _ = zutils.ToFloat64(v)
+				case "lenient":
+					value.Lenient, _ = zutils.ToBool(v)
+				case "auto_generate_synonyms_phrase_query":
+					b, _ := zutils.ToBool(v)
+					value.AutoGenerateSynonymsPhraseQuery = &b
 				case "boost":
 					value.Boost, _ = zutils.ToFloat64(v)
 				default:
@@ -83,35 +95,167 @@ func MatchQuery(query map[string]interface{}, mappings *meta.Mappings, analyzers
 		}
 	}
 
-	subq := bluge.NewMatchQuery(value.Query).SetField(field)
-	if zer != nil {
-		subq.SetAnalyzer(zer)
+	return buildMatchQuery("match", field, value, zer, mappings)
+}
+
+// buildMatchQuery analyzes the query text and combines the terms with the operator of the match query.
+// Tokens at the same position (synonyms) are grouped as one clause.
+func buildMatchQuery(name, field string, value *meta.MatchQuery, zer *analysis.Analyzer, mappings *meta.Mappings) (bluge.Query, error) {
+	operator, err := parseMatchOperator(name, value.Operator)
+	if err != nil {
+		return nil, err
 	}
-	if value.Operator != "" {
-		op := strings.ToUpper(value.Operator)
-		switch op {
-		case "OR":
-			subq.SetOperator(bluge.MatchQueryOperatorOr)
-		case "AND":
-			subq.SetOperator(bluge.MatchQueryOperatorAnd)
-		default:
-			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[match] unknown operator %s", op))
+	minimumShouldMatch := ""
+	if value.MinimumShouldMatch != nil {
+		if minimumShouldMatch, err = ParseMinimumShouldMatch(value.MinimumShouldMatch); err != nil {
+			return nil, minimumShouldMatchError(name, err)
 		}
 	}
-	if value.Fuzziness != nil {
-		if value.Fuzziness != nil {
-			v := ParseFuzziness(value.Fuzziness, value.Query, zer)
-			if v > 0 {
-				subq.SetFuzziness(v)
-			}
+	zeroTerms, err := parseZeroTermsQuery(name, value.ZeroTermsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	if mappings != nil {
+		if prop, ok := mappings.GetProperty(field); ok && (prop.Type == "numeric" || prop.Type == "bool") {
+			return matchQueryNonText(name, field, prop, value)
 		}
 	}
-	if value.PrefixLength > 0 {
-		subq.SetPrefix(int(value.PrefixLength))
+
+	if zer == nil {
+		zer = analyzer.NewStandardAnalyzer()
+	}
+	groups := analyzeTermGroups(zer, value.Query)
+	if len(groups) == 0 {
+		return zeroTermsQuery(zeroTerms), nil
+	}
+
+	fuzziness := 0
+	if value.Fuzziness != nil {
+		fuzziness = ParseFuzziness(value.Fuzziness, value.Query, zer)
+	}
+	clauses := make([]bluge.Query, len(groups))
+	for i, terms := range groups {
+		clauses[i] = matchTermGroupQuery(field, terms, fuzziness, int(value.PrefixLength))
+	}
+
+	if value.CutoffFrequency > 0 {
+		subq := &commonTermsQuery{
+			field:              field,
+			terms:              groups,
+			clauses:            clauses,
+			cutoff:             value.CutoffFrequency,
+			operator:           operator,
+			minimumShouldMatch: minimumShouldMatch,
+			boost:              value.Boost,
+		}
+		return subq, nil
 	}
+
+	subq := combineMatchClauses(clauses, operator, minimumShouldMatch)
 	if value.Boost >= 0 {
 		subq.SetBoost(value.Boost)
 	}
+	return subq, nil
+}
+
+// matchQueryNonText matches the query text as an exact value of numeric or bool field,
+// the format error is ignored when lenient is set.
+func matchQueryNonText(name, field string, prop meta.Property, value *meta.MatchQuery) (bluge.Query, error) {
+	term := &meta.TermQuery{Value: value.Query, Boost: value.Boost}
 
+	var subq bluge.Query
+	var err error
+	switch {
+	case prop.Type == "bool":
+		subq, err = TermQueryBool(field, term)
+	case prop.IsLong():
+		subq, err = TermQueryLong(field, prop, term)
+	default:
+		subq, err = TermQueryNumeric(field, term)
+	}
+	if err != nil {
+		if value.Lenient {
+			return bluge.NewMatchNoneQuery(), nil
+		}
+		return nil, errors.New(errors.ErrorTypeXContentParseException, fmt.Sprintf("[%s] failed to create query for field [%s]", name, field)).Cause(err)
+	}
 	return subq, nil
 }
+
+// analyzeTermGroups returns the terms grouped by position
+func analyzeTermGroups(zer *analysis.Analyzer, text string) [][]string {
+	tokens := zer.Analyze([]byte(text))
+	groups := make([][]string, 0, len(tokens))
+	for _, token := range tokens {
+		if token.PositionIncr == 0 && len(groups) > 0 {
+			groups[len(groups)-1] = append(groups[len(groups)-1], string(token.Term))
+			continue
+		}
+		groups = append(groups, []string{string(token.Term)})
+	}
+	return groups
+}
+
+func matchTermGroupQuery(field string, terms []string, fuzziness, prefix int) bluge.Query {
+	queries := make([]bluge.Query, len(terms))
+	for i, term := range terms {
+		if fuzziness > 0 {
+			queries[i] = bluge.NewFuzzyQuery(term).SetFuzziness(fuzziness).SetPrefix(prefix).SetField(field)
+		} else {
+			queries[i] = bluge.NewTermQuery(term).SetField(field)
+		}
+	}
+	if len(queries) == 1 {
+		return queries[0]
+	}
+	return bluge.NewBooleanQuery().AddShould(queries...)
+}
+
+// combineMatchClauses requires all the clauses for operator AND,
+// and at least one or minimum_should_match of the clauses for operator OR.
+func combineMatchClauses(clauses []bluge.Query, operator bluge.MatchQueryOperator, minimumShouldMatch string) *bluge.BooleanQuery {
+	subq := bluge.NewBooleanQuery()
+	if operator == bluge.MatchQueryOperatorAnd {
+		subq.AddMust(clauses...)
+		return subq
+	}
+
+	subq.AddShould(clauses...)
+	minShould, _ := CalculateMinimumShouldMatch(len(clauses), minimumShouldMatch)
+	if minShould < 1 {
+		minShould = 1
+	}
+	subq.SetMinShould(minShould) // lgtm[go/hardcoded-credentials]
+	return subq
+}
+
+func parseMatchOperator(name, operator string) (bluge.MatchQueryOperator, error) {
+	switch op := strings.ToUpper(operator); op {
+	case "", "OR":
+		return bluge.MatchQueryOperatorOr, nil
+	case "AND":
+		return bluge.MatchQueryOperatorAnd, nil
+	default:
+		return bluge.MatchQueryOperatorOr, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[%s] unknown operator %s", name, op))
+	}
+}
+
+func parseZeroTermsQuery(name, zeroTerms string) (string, error) {
+	switch v := strings.ToLower(zeroTerms); v {
+	case "", "none":
+		return "none", nil
+	case "all":
+		return v, nil
+	default:
+		return "", errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[%s] unsupported zero_terms_query value [%s]", name, zeroTerms))
+	}
+}
+
+// zeroTermsQuery returns the query used when the analyzer removes all the tokens, e.g. only stop words
+func zeroTermsQuery(zeroTerms string) bluge.Query {
+	if zeroTerms == "all" {
+		return bluge.NewMatchAllQuery()
+	}
+	return bluge.NewMatchNoneQuery()
+}
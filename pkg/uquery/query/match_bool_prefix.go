@@ -26,6 +26,7 @@ import (
 	"github.com/zincsearch/zincsearch/pkg/errors"
 	"github.com/zincsearch/zincsearch/pkg/meta"
 	zincanalysis "github.com/zincsearch/zincsearch/pkg/uquery/analysis"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
 )
 
 func MatchBoolPrefixQuery(query map[string]interface{}, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (bluge.Query, error) {
@@ -49,6 +50,10 @@ func MatchBoolPrefixQuery(query map[string]interface{}, mappings *meta.Mappings,
 					value.Query = v.(string)
 				case "analyzer":
 					value.Analyzer = v.(string)
+				case "operator":
+					value.Operator, _ = zutils.ToString(v)
+				case "minimum_should_match":
+					value.MinimumShouldMatch = v
 				case "boost":
 					value.Boost = v.(float64)
 				default:
@@ -76,22 +81,39 @@ func MatchBoolPrefixQuery(query map[string]interface{}, mappings *meta.Mappings,
 			zer = indexZer
 		}
 	}
+
+	return buildMatchBoolPrefixQuery("match_bool_prefix", field, value, zer)
+}
+
+func buildMatchBoolPrefixQuery(name, field string, value *meta.MatchBoolPrefixQuery, zer *analysis.Analyzer) (bluge.Query, error) {
+	operator, err := parseMatchOperator(name, value.Operator)
+	if err != nil {
+		return nil, err
+	}
+	minimumShouldMatch := ""
+	if value.MinimumShouldMatch != nil {
+		if minimumShouldMatch, err = ParseMinimumShouldMatch(value.MinimumShouldMatch); err != nil {
+			return nil, minimumShouldMatchError(name, err)
+		}
+	}
+
 	if zer == nil {
 		zer = analyzer.NewStandardAnalyzer()
 	}
 
 	tokens := zer.Analyze([]byte(value.Query))
-	subq := bluge.NewBooleanQuery()
-	if value.Boost >= 0 {
-		subq.SetBoost(value.Boost)
-	}
+	clauses := make([]bluge.Query, len(tokens))
 	for i := 0; i < len(tokens); i++ {
 		if i == len(tokens)-1 {
-			subq.AddShould(bluge.NewPrefixQuery(string(tokens[i].Term)).SetField(field))
+			clauses[i] = bluge.NewPrefixQuery(string(tokens[i].Term)).SetField(field)
 		} else {
-			subq.AddShould(bluge.NewTermQuery(string(tokens[i].Term)).SetField(field))
+			clauses[i] = bluge.NewTermQuery(string(tokens[i].Term)).SetField(field)
 		}
 	}
+	subq := combineMatchClauses(clauses, operator, minimumShouldMatch)
+	if value.Boost >= 0 {
+		subq.SetBoost(value.Boost)
+	}
 
 	return subq, nil
 }
@@ -21,10 +21,12 @@ import (
 
 	"github.com/blugelabs/bluge"
 	"github.com/blugelabs/bluge/analysis"
+	"github.com/blugelabs/bluge/analysis/analyzer"
 
 	"github.com/zincsearch/zincsearch/pkg/errors"
 	"github.com/zincsearch/zincsearch/pkg/meta"
 	zincanalysis "github.com/zincsearch/zincsearch/pkg/uquery/analysis"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
 )
 
 func MatchPhraseQuery(query map[string]interface{}, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (bluge.Query, error) {
@@ -48,6 +50,10 @@ func MatchPhraseQuery(query map[string]interface{}, mappings *meta.Mappings, ana
 					value.Query = v.(string)
 				case "analyzer":
 					value.Analyzer = v.(string)
+				case "slop":
+					value.Slop, _ = zutils.ToInt(v)
+				case "zero_terms_query":
+					value.ZeroTermsQuery, _ = zutils.ToString(v)
 				case "boost":
 					value.Boost = v.(float64)
 				default:
@@ -76,9 +82,25 @@ func MatchPhraseQuery(query map[string]interface{}, mappings *meta.Mappings, ana
 		}
 	}
 
-	subq := bluge.NewMatchPhraseQuery(value.Query).SetField(field)
-	if zer != nil {
-		subq.SetAnalyzer(zer)
+	return buildMatchPhraseQuery("match_phrase", field, value, zer)
+}
+
+func buildMatchPhraseQuery(name, field string, value *meta.MatchPhraseQuery, zer *analysis.Analyzer) (bluge.Query, error) {
+	zeroTerms, err := parseZeroTermsQuery(name, value.ZeroTermsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	if zer == nil {
+		zer = analyzer.NewStandardAnalyzer()
+	}
+	if len(zer.Analyze([]byte(value.Query))) == 0 {
+		return zeroTermsQuery(zeroTerms), nil
+	}
+
+	subq := bluge.NewMatchPhraseQuery(value.Query).SetField(field).SetAnalyzer(zer)
+	if value.Slop > 0 {
+		subq.SetSlop(value.Slop)
 	}
 	if value.Boost >= 0 {
 		subq.SetBoost(value.Boost)
@@ -22,10 +22,13 @@ import (
 	"github.com/blugelabs/bluge"
 	"github.com/blugelabs/bluge/analysis"
 	"github.com/blugelabs/bluge/analysis/analyzer"
+	"github.com/blugelabs/bluge/search"
+	"github.com/blugelabs/bluge/search/searcher"
 
 	"github.com/zincsearch/zincsearch/pkg/errors"
 	"github.com/zincsearch/zincsearch/pkg/meta"
 	zincanalysis "github.com/zincsearch/zincsearch/pkg/uquery/analysis"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
 )
 
 func MatchPhrasePrefixQuery(query map[string]interface{}, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (bluge.Query, error) {
@@ -49,6 +52,12 @@ func MatchPhrasePrefixQuery(query map[string]interface{}, mappings *meta.Mapping
 					value.Query = v.(string)
 				case "analyzer":
 					value.Analyzer = v.(string)
+				case "slop":
+					value.Slop, _ = zutils.ToInt(v)
+				case "max_expansions":
+					value.MaxExpansions, _ = zutils.ToInt(v)
+				case "zero_terms_query":
+					value.ZeroTermsQuery, _ = zutils.ToString(v)
 				case "boost":
 					value.Boost = v.(float64)
 				default:
@@ -76,26 +85,90 @@ func MatchPhrasePrefixQuery(query map[string]interface{}, mappings *meta.Mapping
 			zer = indexZer
 		}
 	}
+
+	return buildMatchPhrasePrefixQuery("match_phrase_prefix", field, value, zer)
+}
+
+func buildMatchPhrasePrefixQuery(name, field string, value *meta.MatchPhrasePrefixQuery, zer *analysis.Analyzer) (bluge.Query, error) {
+	zeroTerms, err := parseZeroTermsQuery(name, value.ZeroTermsQuery)
+	if err != nil {
+		return nil, err
+	}
+
 	if zer == nil {
 		zer = analyzer.NewStandardAnalyzer()
 	}
 
-	tokens := zer.Analyze([]byte(value.Query))
-	subq := bluge.NewBooleanQuery()
-	if len(tokens) > 0 {
-		subq.AddMust(bluge.NewPrefixQuery(string(tokens[len(tokens)-1].Term)).SetField(field))
+	groups := analyzeTermGroups(zer, value.Query)
+	if len(groups) == 0 {
+		return zeroTermsQuery(zeroTerms), nil
 	}
-	if len(tokens) > 1 {
-		phrase := strings.Builder{}
-		for i := 0; i < len(tokens)-1; i++ {
-			phrase.WriteString(string(tokens[i].Term))
-			phrase.WriteString(" ")
-		}
-		subq.AddMust(bluge.NewMatchPhraseQuery(strings.TrimSpace(phrase.String())).SetField(field).SetAnalyzer(zer))
+
+	subq := &phrasePrefixQuery{
+		field:         field,
+		terms:         groups[:len(groups)-1],
+		prefix:        groups[len(groups)-1][0],
+		slop:          value.Slop,
+		maxExpansions: value.MaxExpansions,
+		boost:         value.Boost,
 	}
-	if value.Boost >= 0 {
-		subq.SetBoost(value.Boost)
+	if subq.maxExpansions <= 0 {
+		subq.maxExpansions = defaultMaxExpansions
 	}
 
 	return subq, nil
 }
+
+const defaultMaxExpansions = 50
+
+// phrasePrefixQuery matches the terms as a phrase with the last term as a prefix,
+// the prefix is expanded to max_expansions terms of the field when searching.
+type phrasePrefixQuery struct {
+	field         string
+	terms         [][]string
+	prefix        string
+	slop          int
+	maxExpansions int
+	boost         float64
+}
+
+func (q *phrasePrefixQuery) Searcher(i search.Reader, options search.SearcherOptions) (search.Searcher, error) {
+	start := []byte(q.prefix)
+	end := make([]byte, len(start))
+	copy(end, start)
+	for j := len(end) - 1; j >= 0; j-- {
+		end[j]++
+		if end[j] != 0 {
+			break
+		}
+	}
+
+	dict, err := i.DictionaryIterator(q.field, nil, start, end)
+	if err != nil {
+		return nil, err
+	}
+	var expansions []string
+	entry, err := dict.Next()
+	for err == nil && entry != nil && len(expansions) < q.maxExpansions {
+		expansions = append(expansions, entry.Term())
+		entry, err = dict.Next()
+	}
+	if cerr := dict.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(expansions) == 0 {
+		return searcher.NewMatchNoneSearcher(i, options)
+	}
+
+	terms := make([][]string, 0, len(q.terms)+1)
+	terms = append(terms, q.terms...)
+	terms = append(terms, expansions)
+	subq := bluge.NewMultiPhraseQuery(terms).SetField(q.field).SetSlop(q.slop)
+	if q.boost >= 0 {
+		subq.SetBoost(q.boost)
+	}
+	return subq.Searcher(i, options)
+}
@@ -0,0 +1,133 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package query
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+)
+
+// ParseMinimumShouldMatch validates a minimum_should_match value and returns it as a string spec.
+// The supported formats are the same as elasticsearch:
+//
+//	integer:     3
+//	negative:    -2
+//	percentage:  75%
+//	negative %:  -25%
+//	combination: 3<90%
+//	multiple:    2<-25% 9<-3
+func ParseMinimumShouldMatch(v interface{}) (string, error) {
+	var spec string
+	switch v := v.(type) {
+	case float64:
+		spec = strconv.Itoa(int(v))
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return "", fmt.Errorf("invalid minimum_should_match value [%s]", v)
+		}
+		spec = strconv.Itoa(int(f))
+	default:
+		s, err := zutils.ToString(v)
+		if err != nil {
+			return "", fmt.Errorf("doesn't support values of type: %T", v)
+		}
+		spec = s
+	}
+	spec = strings.TrimSpace(spec)
+	if _, err := CalculateMinimumShouldMatch(0, spec); err != nil {
+		return "", err
+	}
+	return spec, nil
+}
+
+// CalculateMinimumShouldMatch returns the number of optional clauses that must match for the given spec.
+func CalculateMinimumShouldMatch(optionalClauseCount int, spec string) (int, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return 0, nil
+	}
+
+	result := optionalClauseCount
+	if strings.Contains(spec, "<") {
+		for _, s := range strings.Fields(normalizeConditionalSpec(spec)) {
+			parts := strings.Split(s, "<")
+			if len(parts) != 2 || parts[1] == "" {
+				return 0, fmt.Errorf("invalid minimum_should_match value [%s]", spec)
+			}
+			upperBound, err := strconv.Atoi(parts[0])
+			if err != nil {
+				return 0, fmt.Errorf("invalid minimum_should_match value [%s]", spec)
+			}
+			calc, err := CalculateMinimumShouldMatch(optionalClauseCount, parts[1])
+			if err != nil {
+				return 0, err
+			}
+			if optionalClauseCount > upperBound {
+				result = calc
+			}
+		}
+		return result, nil
+	}
+
+	if strings.HasSuffix(spec, "%") {
+		percent, err := strconv.Atoi(strings.TrimSuffix(spec, "%"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid minimum_should_match value [%s]", spec)
+		}
+		calc := float64(result*percent) / 100
+		if calc < 0 {
+			result += int(math.Trunc(calc))
+		} else {
+			result = int(math.Trunc(calc))
+		}
+	} else {
+		calc, err := strconv.Atoi(spec)
+		if err != nil {
+			return 0, fmt.Errorf("invalid minimum_should_match value [%s]", spec)
+		}
+		if calc < 0 {
+			result += calc
+		} else {
+			result = calc
+		}
+	}
+
+	if result < 0 {
+		result = 0
+	}
+	return result, nil
+}
+
+// normalizeConditionalSpec removes the spaces around '<', "3 < 90%" -> "3<90%"
+func normalizeConditionalSpec(spec string) string {
+	for strings.Contains(spec, " <") || strings.Contains(spec, "< ") {
+		spec = strings.ReplaceAll(spec, " <", "<")
+		spec = strings.ReplaceAll(spec, "< ", "<")
+	}
+	return spec
+}
+
+// minimumShouldMatchError wraps the parse error of minimum_should_match for a query
+func minimumShouldMatchError(query string, err error) error {
+	return errors.New(errors.ErrorTypeXContentParseException, fmt.Sprintf("[%s] minimum_should_match %s", query, err))
+}
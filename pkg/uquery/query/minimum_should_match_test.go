@@ -0,0 +1,94 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+)
+
+func TestCalculateMinimumShouldMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   int
+		spec    string
+		want    int
+		wantErr bool
+	}{
+		{name: "empty", count: 5, spec: "", want: 0},
+		{name: "integer", count: 5, spec: "3", want: 3},
+		{name: "integer more than clauses", count: 2, spec: "3", want: 3},
+		{name: "negative integer", count: 5, spec: "-2", want: 3},
+		{name: "negative integer more than clauses", count: 1, spec: "-2", want: 0},
+		{name: "percentage", count: 4, spec: "75%", want: 3},
+		{name: "percentage round down", count: 3, spec: "75%", want: 2},
+		{name: "negative percentage", count: 4, spec: "-25%", want: 3},
+		{name: "negative percentage round down", count: 3, spec: "-25%", want: 3},
+		{name: "combination below bound", count: 3, spec: "3<90%", want: 3},
+		{name: "combination above bound", count: 5, spec: "3<90%", want: 4},
+		{name: "combination with spaces", count: 5, spec: " 3 < 90% ", want: 4},
+		{name: "multiple combinations low", count: 2, spec: "2<-25% 9<-3", want: 2},
+		{name: "multiple combinations middle", count: 8, spec: "2<-25% 9<-3", want: 6},
+		{name: "multiple combinations high", count: 12, spec: "2<-25% 9<-3", want: 9},
+		{name: "invalid", count: 5, spec: "abc", wantErr: true},
+		{name: "invalid percentage", count: 5, spec: "a%", wantErr: true},
+		{name: "invalid combination", count: 5, spec: "3<", wantErr: true},
+		{name: "invalid nested combination", count: 5, spec: "3<4<50%", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CalculateMinimumShouldMatch(tt.count, tt.spec)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseMinimumShouldMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "float64", value: float64(2), want: "2"},
+		{name: "int", value: -1, want: "-1"},
+		{name: "json.Number", value: json.Number("3"), want: "3"},
+		{name: "string", value: " 75% ", want: "75%"},
+		{name: "combination", value: "3<90%", want: "3<90%"},
+		{name: "integer string", value: "75", want: "75"},
+		{name: "invalid", value: "many", wantErr: true},
+		{name: "invalid type", value: []interface{}{1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMinimumShouldMatch(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,436 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package query
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/analysis"
+	"github.com/blugelabs/bluge/analysis/analyzer"
+	"github.com/blugelabs/bluge/search"
+	"github.com/blugelabs/bluge/search/searcher"
+
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	zincanalysis "github.com/zincsearch/zincsearch/pkg/uquery/analysis"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+	"github.com/zincsearch/zincsearch/pkg/zutils/flatten"
+)
+
+// DocumentGetter returns the source of document by index name and id,
+// it is set by core for more_like_this to fetch the referenced documents.
+var DocumentGetter func(index, id string) (map[string]interface{}, error)
+
+func MoreLikeThisQuery(query map[string]interface{}, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (bluge.Query, error) {
+	value := &meta.MoreLikeThisQuery{
+		MaxQueryTerms:      25,
+		MinTermFreq:        2,
+		MinDocFreq:         5,
+		MinimumShouldMatch: "30%",
+		Boost:              -1.0,
+	}
+	failOnUnsupportedField := true
+	var err error
+	for k, v := range query {
+		k := strings.ToLower(k)
+		switch k {
+		case "fields":
+			items, ok := v.([]interface{})
+			if !ok {
+				return nil, errors.New(errors.ErrorTypeParsingException, "[more_like_this] fields should be an array of string")
+			}
+			for _, item := range items {
+				field, err := zutils.ToString(item)
+				if err != nil {
+					return nil, errors.New(errors.ErrorTypeParsingException, "[more_like_this] fields should be an array of string")
+				}
+				value.Fields = append(value.Fields, field)
+			}
+		case "like":
+			value.Like = v
+		case "unlike":
+			value.Unlike = v
+		case "max_query_terms":
+			value.MaxQueryTerms, err = zutils.ToInt(v)
+		case "min_term_freq":
+			value.MinTermFreq, err = zutils.ToInt(v)
+		case "min_doc_freq":
+			value.MinDocFreq, err = zutils.ToInt(v)
+		case "max_doc_freq":
+			value.MaxDocFreq, err = zutils.ToInt(v)
+		case "min_word_length":
+			value.MinWordLength, err = zutils.ToInt(v)
+		case "max_word_length":
+			value.MaxWordLength, err = zutils.ToInt(v)
+		case "stop_words":
+			items, ok := v.([]interface{})
+			if !ok {
+				return nil, errors.New(errors.ErrorTypeParsingException, "[more_like_this] stop_words should be an array of string")
+			}
+			for _, item := range items {
+				word, _ := zutils.ToString(item)
+				value.StopWords = append(value.StopWords, word)
+			}
+		case "analyzer":
+			value.Analyzer, _ = zutils.ToString(v)
+		case "minimum_should_match":
+			value.MinimumShouldMatch = v
+		case "boost_terms":
+			value.BoostTerms, err = zutils.ToFloat64(v)
+		case "include":
+			value.Include, err = zutils.ToBool(v)
+		case "boost":
+			value.Boost, err = zutils.ToFloat64(v)
+		case "fail_on_unsupported_field":
+			failOnUnsupportedField, err = zutils.ToBool(v)
+		default:
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[more_like_this] unknown field [%s]", k))
+		}
+		if err != nil {
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[more_like_this] %s doesn't support value [%v]", k, v))
+		}
+	}
+	if value.Like == nil {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[more_like_this] requires 'like' to be specified")
+	}
+	if value.MaxQueryTerms <= 0 {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[more_like_this] max_query_terms must be > 0")
+	}
+
+	minimumShouldMatch, err := ParseMinimumShouldMatch(value.MinimumShouldMatch)
+	if err != nil {
+		return nil, minimumShouldMatchError("more_like_this", err)
+	}
+
+	fields, err := moreLikeThisFields(value.Fields, mappings, failOnUnsupportedField)
+	if err != nil {
+		return nil, err
+	}
+	fieldAnalyzers := make(map[string]*analysis.Analyzer, len(fields))
+	for _, field := range fields {
+		if fieldAnalyzers[field], err = moreLikeThisAnalyzer(field, value.Analyzer, mappings, analyzers); err != nil {
+			return nil, err
+		}
+	}
+
+	q := &moreLikeThisQuery{
+		maxQueryTerms:      value.MaxQueryTerms,
+		minDocFreq:         value.MinDocFreq,
+		maxDocFreq:         value.MaxDocFreq,
+		minimumShouldMatch: minimumShouldMatch,
+		boostTerms:         value.BoostTerms,
+		boost:              value.Boost,
+	}
+	stopWords := make(map[string]struct{}, len(value.StopWords))
+	for _, word := range value.StopWords {
+		stopWords[word] = struct{}{}
+	}
+	accept := func(term string) bool {
+		n := utf8.RuneCountInString(term)
+		if value.MinWordLength > 0 && n < value.MinWordLength {
+			return false
+		}
+		if value.MaxWordLength > 0 && n > value.MaxWordLength {
+			return false
+		}
+		_, stop := stopWords[term]
+		return !stop
+	}
+
+	like, likeIDs, err := moreLikeThisItems("like", value.Like)
+	if err != nil {
+		return nil, err
+	}
+	unlike, _, err := moreLikeThisItems("unlike", value.Unlike)
+	if err != nil {
+		return nil, err
+	}
+	likeFreqs := moreLikeThisTermFreqs(like, fields, fieldAnalyzers, mappings, accept)
+	unlikeFreqs := moreLikeThisTermFreqs(unlike, fields, fieldAnalyzers, mappings, accept)
+	for key, freq := range likeFreqs {
+		if _, ok := unlikeFreqs[key]; ok || freq < value.MinTermFreq {
+			continue
+		}
+		q.terms = append(q.terms, moreLikeThisTerm{field: key.field, term: key.term, freq: freq})
+	}
+	// the order is deterministic for the same scores
+	sort.Slice(q.terms, func(i, j int) bool {
+		if q.terms[i].field != q.terms[j].field {
+			return q.terms[i].field < q.terms[j].field
+		}
+		return q.terms[i].term < q.terms[j].term
+	})
+	if !value.Include {
+		q.excludeIDs = likeIDs
+	}
+	return q, nil
+}
+
+// moreLikeThisFields returns the fields used to select terms, the default is all text fields
+func moreLikeThisFields(fields []string, mappings *meta.Mappings, failOnUnsupportedField bool) ([]string, error) {
+	if len(fields) == 0 {
+		if mappings != nil {
+			for field, prop := range mappings.ListProperty() {
+				if prop.Type == "text" && prop.Index {
+					fields = append(fields, field)
+				}
+			}
+		}
+		sort.Strings(fields)
+		return fields, nil
+	}
+	rv := make([]string, 0, len(fields))
+	for _, field := range fields {
+		var prop meta.Property
+		var ok bool
+		if mappings != nil {
+			prop, ok = mappings.GetProperty(field)
+		}
+		if !ok || (prop.Type != "text" && prop.Type != "keyword") {
+			if failOnUnsupportedField && ok {
+				return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[more_like_this] query cannot infer the field [%s] of type [%s], only text and keyword fields are supported", field, prop.Type))
+			}
+			continue
+		}
+		rv = append(rv, field)
+	}
+	return rv, nil
+}
+
+// moreLikeThisAnalyzer returns the analyzer of field, keyword field without normalizer uses the whole value
+func moreLikeThisAnalyzer(field, name string, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (*analysis.Analyzer, error) {
+	prop, _ := mappings.GetProperty(field)
+	if name != "" && prop.Type == "text" {
+		return zincanalysis.QueryAnalyzer(analyzers, name)
+	}
+	indexZer, _ := zincanalysis.QueryAnalyzerForField(analyzers, mappings, field)
+	if indexZer == nil && prop.Type == "text" {
+		indexZer = analyzer.NewStandardAnalyzer()
+	}
+	return indexZer, nil
+}
+
+// moreLikeThisItem is a liked text or document, the text is analyzed by all fields
+type moreLikeThisItem struct {
+	text string
+	doc  map[string]interface{}
+}
+
+// moreLikeThisItems parses the like or unlike items, the referenced documents are fetched by DocumentGetter
+func moreLikeThisItems(name string, v interface{}) ([]moreLikeThisItem, []string, error) {
+	if v == nil {
+		return nil, nil, nil
+	}
+	values, ok := v.([]interface{})
+	if !ok {
+		values = []interface{}{v}
+	}
+	var items []moreLikeThisItem
+	var ids []string
+	for _, v := range values {
+		switch v := v.(type) {
+		case string:
+			items = append(items, moreLikeThisItem{text: v})
+		case map[string]interface{}:
+			if doc, ok := v["doc"]; ok {
+				m, ok := doc.(map[string]interface{})
+				if !ok {
+					return nil, nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[more_like_this] %s doc should be an object", name))
+				}
+				items = append(items, moreLikeThisItem{doc: m})
+				continue
+			}
+			index, _ := zutils.ToString(v["_index"])
+			id, _ := zutils.ToString(v["_id"])
+			if index == "" || id == "" {
+				return nil, nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[more_like_this] %s document requires _index and _id, or doc", name))
+			}
+			if DocumentGetter == nil {
+				return nil, nil, errors.New(errors.ErrorTypeNotImplemented, fmt.Sprintf("[more_like_this] %s document can't be fetched", name))
+			}
+			doc, err := DocumentGetter(index, id)
+			if err != nil {
+				return nil, nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[more_like_this] %s document [%s/%s] can't be fetched: %s", name, index, id, err.Error()))
+			}
+			items = append(items, moreLikeThisItem{doc: doc})
+			ids = append(ids, id)
+		default:
+			return nil, nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[more_like_this] %s doesn't support values of type: %T", name, v))
+		}
+	}
+	return items, ids, nil
+}
+
+type moreLikeThisKey struct {
+	field string
+	term  string
+}
+
+// moreLikeThisTermFreqs returns the frequency of terms in the items for each field
+func moreLikeThisTermFreqs(items []moreLikeThisItem, fields []string, fieldAnalyzers map[string]*analysis.Analyzer, mappings *meta.Mappings, accept func(string) bool) map[moreLikeThisKey]int {
+	freqs := make(map[moreLikeThisKey]int)
+	add := func(field, text string) {
+		zer := fieldAnalyzers[field]
+		if zer == nil {
+			if text != "" && accept(text) {
+				freqs[moreLikeThisKey{field: field, term: text}]++
+			}
+			return
+		}
+		for _, token := range zer.Analyze([]byte(text)) {
+			if term := string(token.Term); accept(term) {
+				freqs[moreLikeThisKey{field: field, term: term}]++
+			}
+		}
+	}
+	for _, item := range items {
+		if item.doc == nil {
+			for _, field := range fields {
+				add(field, item.text)
+			}
+			continue
+		}
+		flatDoc, _ := flatten.Flatten(item.doc, "")
+		for _, field := range fields {
+			v, ok := flatDoc[field]
+			if !ok {
+				// the sub field is indexed from the value of parent field
+				if i := strings.LastIndex(field, "."); i > 0 {
+					if prop, exists := mappings.GetProperty(field[:i]); exists {
+						if _, sub := prop.Fields[field[i+1:]]; sub {
+							v, ok = flatDoc[field[:i]]
+						}
+					}
+				}
+			}
+			if !ok {
+				continue
+			}
+			values, isArray := v.([]interface{})
+			if !isArray {
+				values = []interface{}{v}
+			}
+			for _, v := range values {
+				if s, err := zutils.ToString(v); err == nil {
+					add(field, s)
+				}
+			}
+		}
+	}
+	return freqs
+}
+
+type moreLikeThisTerm struct {
+	field string
+	term  string
+	freq  int
+	score float64
+}
+
+// moreLikeThisQuery selects the terms by tf-idf with the term statistics of reader,
+// the selected terms are combined as a boolean query.
+type moreLikeThisQuery struct {
+	terms              []moreLikeThisTerm
+	excludeIDs         []string
+	maxQueryTerms      int
+	minDocFreq         int
+	maxDocFreq         int
+	minimumShouldMatch string
+	boostTerms         float64
+	boost              float64
+}
+
+func (q *moreLikeThisQuery) Searcher(i search.Reader, options search.SearcherOptions) (search.Searcher, error) {
+	selected := make([]moreLikeThisTerm, 0, len(q.terms))
+	numDocs := make(map[string]float64)
+	for _, t := range q.terms {
+		n, ok := numDocs[t.field]
+		if !ok {
+			stats, err := i.CollectionStats(t.field)
+			if err != nil {
+				return nil, err
+			}
+			n = float64(stats.TotalDocumentCount())
+			numDocs[t.field] = n
+		}
+		df, err := readerDocFreq(i, t.field, t.term)
+		if err != nil {
+			return nil, err
+		}
+		if df == 0 || df < uint64(q.minDocFreq) || (q.maxDocFreq > 0 && df > uint64(q.maxDocFreq)) {
+			continue
+		}
+		idf := math.Log((n+1)/(float64(df)+1)) + 1
+		t.score = float64(t.freq) * idf
+		selected = append(selected, t)
+	}
+	if len(selected) == 0 {
+		return searcher.NewMatchNoneSearcher(i, options)
+	}
+	sort.SliceStable(selected, func(i, j int) bool { return selected[i].score > selected[j].score })
+	if len(selected) > q.maxQueryTerms {
+		selected = selected[:q.maxQueryTerms]
+	}
+
+	bq := bluge.NewBooleanQuery()
+	for _, t := range selected {
+		tq := bluge.NewTermQuery(t.term).SetField(t.field)
+		if q.boostTerms > 0 {
+			tq.SetBoost(q.boostTerms * t.score / selected[0].score)
+		}
+		bq.AddShould(tq)
+	}
+	minShould, err := CalculateMinimumShouldMatch(len(selected), q.minimumShouldMatch)
+	if err != nil {
+		return nil, err
+	}
+	if minShould < 1 {
+		minShould = 1
+	}
+	bq.SetMinShould(minShould)
+	for _, id := range q.excludeIDs {
+		bq.AddMustNot(bluge.NewTermQuery(id).SetField("_id"))
+	}
+	if q.boost >= 0 {
+		bq.SetBoost(q.boost)
+	}
+	return bq.Searcher(i, options)
+}
+
+// readerDocFreq returns the number of documents containing the term in the reader
+func readerDocFreq(i search.Reader, field, term string) (uint64, error) {
+	dict, err := i.DictionaryIterator(field, nil, []byte(term), append([]byte(term), 0))
+	if err != nil {
+		return 0, err
+	}
+	var freq uint64
+	entry, err := dict.Next()
+	for err == nil && entry != nil {
+		if entry.Term() == term {
+			freq += entry.Count()
+		}
+		entry, err = dict.Next()
+	}
+	if cerr := dict.Close(); err == nil {
+		err = cerr
+	}
+	return freq, err
+}
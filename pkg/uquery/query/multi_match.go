@@ -17,15 +17,19 @@ package query
 
 import (
 	"fmt"
+	"path"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/blugelabs/bluge"
 	"github.com/blugelabs/bluge/analysis"
+	"github.com/blugelabs/bluge/analysis/analyzer"
 
 	"github.com/zincsearch/zincsearch/pkg/errors"
 	"github.com/zincsearch/zincsearch/pkg/meta"
 	zincanalysis "github.com/zincsearch/zincsearch/pkg/uquery/analysis"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
 )
 
 func MultiMatchQuery(query map[string]interface{}, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (bluge.Query, error) {
@@ -35,85 +39,275 @@ func MultiMatchQuery(query map[string]interface{}, mappings *meta.Mappings, anal
 		k := strings.ToLower(k)
 		switch k {
 		case "query":
-			value.Query = v.(string)
+			value.Query, _ = zutils.ToString(v)
 		case "analyzer":
-			value.Analyzer = v.(string)
+			value.Analyzer, _ = zutils.ToString(v)
 		case "fields":
-			if vv, ok := v.([]interface{}); ok {
-				for _, vvv := range vv {
-					value.Fields = append(value.Fields, vvv.(string))
-				}
-			}
-		case "boost":
-			value.Boost = v.(float64)
-		case "type":
-			value.Type = v.(string)
-		case "operator":
-			value.Operator = v.(string)
-		case "minimum_should_match":
 			switch v := v.(type) {
 			case string:
-				if strings.Contains(v, "%") || strings.Contains(v, "<") {
-					return nil, errors.New(errors.ErrorTypeXContentParseException, fmt.Sprintf("[multi_match] %s value only support integer", k))
-				}
-				vi, err := strconv.ParseInt(v, 10, 64)
-				if err != nil {
-					return nil, errors.New(errors.ErrorTypeXContentParseException, fmt.Sprintf("[multi_match] %s type string convert to int error: %s", k, err))
+				value.Fields = append(value.Fields, v)
+			case []interface{}:
+				for _, vv := range v {
+					field, _ := zutils.ToString(vv)
+					value.Fields = append(value.Fields, field)
 				}
-				value.MinimumShouldMatch = float64(vi)
-			case int:
-				value.MinimumShouldMatch = float64(v)
-			case float64:
-				value.MinimumShouldMatch = v
 			default:
 				return nil, errors.New(errors.ErrorTypeXContentParseException, fmt.Sprintf("[multi_match] %s doesn't support values of type: %T", k, v))
 			}
+		case "boost":
+			value.Boost, _ = zutils.ToFloat64(v)
+		case "type":
+			value.Type, _ = zutils.ToString(v)
+		case "operator":
+			value.Operator, _ = zutils.ToString(v)
+		case "minimum_should_match":
+			value.MinimumShouldMatch = v
+		case "tie_breaker":
+			value.TieBreaker, _ = zutils.ToFloat64(v)
+		case "slop":
+			value.Slop, _ = zutils.ToInt(v)
+		case "max_expansions":
+			value.MaxExpansions, _ = zutils.ToInt(v)
+		case "fuzziness":
+			value.Fuzziness = v
+		case "prefix_length":
+			value.PrefixLength, _ = zutils.ToFloat64(v)
+		case "zero_terms_query":
+			value.ZeroTermsQuery, _ = zutils.ToString(v)
+		case "lenient":
+			value.Lenient, _ = zutils.ToBool(v)
 		default:
 			// return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[multi_match] unknown field [%s]", k))
 		}
 	}
 
+	fields, err := resolveMultiMatchFields(value.Fields, mappings)
+	if err != nil {
+		return nil, err
+	}
+
 	var zer *analysis.Analyzer
 	if value.Analyzer != "" {
-		zer, _ = zincanalysis.QueryAnalyzer(analyzers, value.Analyzer)
+		if zer, err = zincanalysis.QueryAnalyzer(analyzers, value.Analyzer); err != nil {
+			return nil, err
+		}
+	}
+	fieldAnalyzer := func(field string) *analysis.Analyzer {
+		if zer != nil {
+			return zer
+		}
+		indexZer, searchZer := zincanalysis.QueryAnalyzerForField(analyzers, mappings, field)
+		if searchZer != nil {
+			return searchZer
+		}
+		return indexZer
 	}
 
-	var operator bluge.MatchQueryOperator = bluge.MatchQueryOperatorOr
-	if value.Operator != "" {
-		op := strings.ToUpper(value.Operator)
-		switch op {
-		case "OR":
-			operator = bluge.MatchQueryOperatorOr
-		case "AND":
-			operator = bluge.MatchQueryOperatorAnd
-		default:
-			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[multi_match] unknown operator %s", op))
+	typ := strings.ToLower(value.Type)
+	switch typ {
+	case "", "best_fields", "most_fields":
+		queries := make([]bluge.Query, 0, len(fields))
+		for _, field := range fields {
+			match := &meta.MatchQuery{
+				Query:              value.Query,
+				Operator:           value.Operator,
+				Fuzziness:          value.Fuzziness,
+				PrefixLength:       value.PrefixLength,
+				MinimumShouldMatch: value.MinimumShouldMatch,
+				ZeroTermsQuery:     value.ZeroTermsQuery,
+				Lenient:            value.Lenient,
+				Boost:              field.boost,
+			}
+			subq, err := buildMatchQuery("multi_match", field.name, match, fieldAnalyzer(field.name), mappings)
+			if err != nil {
+				return nil, err
+			}
+			queries = append(queries, subq)
+		}
+		if typ == "most_fields" {
+			return mostFieldsQuery(queries, value.Boost), nil
 		}
+		return bestFieldsQuery(queries, value.TieBreaker, value.Boost), nil
+	case "phrase", "phrase_prefix":
+		queries := make([]bluge.Query, 0, len(fields))
+		for _, field := range fields {
+			var subq bluge.Query
+			if typ == "phrase" {
+				match := &meta.MatchPhraseQuery{Query: value.Query, Slop: value.Slop, ZeroTermsQuery: value.ZeroTermsQuery, Boost: field.boost}
+				subq, err = buildMatchPhraseQuery("multi_match", field.name, match, fieldAnalyzer(field.name))
+			} else {
+				match := &meta.MatchPhrasePrefixQuery{Query: value.Query, Slop: value.Slop, MaxExpansions: value.MaxExpansions, ZeroTermsQuery: value.ZeroTermsQuery, Boost: field.boost}
+				subq, err = buildMatchPhrasePrefixQuery("multi_match", field.name, match, fieldAnalyzer(field.name))
+			}
+			if err != nil {
+				return nil, err
+			}
+			queries = append(queries, subq)
+		}
+		return bestFieldsQuery(queries, value.TieBreaker, value.Boost), nil
+	case "bool_prefix":
+		queries := make([]bluge.Query, 0, len(fields))
+		for _, field := range fields {
+			match := &meta.MatchBoolPrefixQuery{
+				Query:              value.Query,
+				Operator:           value.Operator,
+				MinimumShouldMatch: value.MinimumShouldMatch,
+				Boost:              field.boost,
+			}
+			subq, err := buildMatchBoolPrefixQuery("multi_match", field.name, match, fieldAnalyzer(field.name))
+			if err != nil {
+				return nil, err
+			}
+			queries = append(queries, subq)
+		}
+		return mostFieldsQuery(queries, value.Boost), nil
+	case "cross_fields":
+		return crossFieldsQuery(value, fields, fieldAnalyzer, mappings)
+	default:
+		return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[multi_match] query does not support type %s", value.Type))
 	}
+}
+
+type multiMatchField struct {
+	name  string
+	boost float64
+}
 
-	subq := bluge.NewBooleanQuery()
-	if value.MinimumShouldMatch > 0 {
-		subq.SetMinShould(int(value.MinimumShouldMatch)) // lgtm[go/hardcoded-credentials]
+// resolveMultiMatchFields parses the field boost like title^3 and expands
+// the field patterns like *_name to the text and keyword fields of mappings.
+func resolveMultiMatchFields(fields []string, mappings *meta.Mappings) ([]multiMatchField, error) {
+	if len(fields) == 0 {
+		fields = []string{"*"}
 	}
-	if value.Boost >= 0 {
-		subq.SetBoost(value.Boost)
+
+	var properties []string
+	resolved := make([]multiMatchField, 0, len(fields))
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		name, boost := strings.TrimSpace(field), -1.0
+		if i := strings.LastIndex(name, "^"); i > 0 {
+			v, err := strconv.ParseFloat(name[i+1:], 64)
+			if err != nil {
+				return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[multi_match] invalid boost of field [%s]", field))
+			}
+			name, boost = name[:i], v
+		}
+
+		if !strings.ContainsAny(name, "*?") {
+			if !seen[name] {
+				seen[name] = true
+				resolved = append(resolved, multiMatchField{name: name, boost: boost})
+			}
+			continue
+		}
+
+		if properties == nil && mappings != nil {
+			for k, prop := range mappings.ListProperty() {
+				if prop.Type == "text" || prop.Type == "keyword" {
+					properties = append(properties, k)
+				}
+			}
+			sort.Strings(properties)
+		}
+		for _, k := range properties {
+			if ok, _ := path.Match(name, k); ok && !seen[k] {
+				seen[k] = true
+				resolved = append(resolved, multiMatchField{name: k, boost: boost})
+			}
+		}
 	}
-	for _, field := range value.Fields {
-		subqq := bluge.NewMatchQuery(value.Query).SetField(field).SetOperator(operator)
-		if zer != nil {
-			subqq.SetAnalyzer(zer)
-		} else {
-			indexZer, searchZer := zincanalysis.QueryAnalyzerForField(analyzers, mappings, field)
-			if zer == nil && searchZer != nil {
-				zer = searchZer
+
+	return resolved, nil
+}
+
+// bestFieldsQuery scores the document by the best matching field
+func bestFieldsQuery(queries []bluge.Query, tieBreaker, boost float64) bluge.Query {
+	subq := newDisMaxQuery(queries, tieBreaker)
+	if boost >= 0 {
+		subq.SetBoost(boost)
+	}
+	return subq
+}
+
+// mostFieldsQuery scores the document by the sum of all the matching fields
+func mostFieldsQuery(queries []bluge.Query, boost float64) bluge.Query {
+	subq := bluge.NewBooleanQuery().AddShould(queries...).SetMinShould(1)
+	if boost >= 0 {
+		subq.SetBoost(boost)
+	}
+	return subq
+}
+
+// crossFieldsQuery is term-centric, it treats the fields with the same analyzer as one big field.
+// Each term is looked up across the fields with the blended document frequency and scored by the best field,
+// then the terms are combined by operator and minimum_should_match. The groups of fields with different analyzers are combined as best_fields.
+func crossFieldsQuery(value *meta.MultiMatchQuery, fields []multiMatchField, fieldAnalyzer func(string) *analysis.Analyzer, mappings *meta.Mappings) (bluge.Query, error) {
+	operator, err := parseMatchOperator("multi_match", value.Operator)
+	if err != nil {
+		return nil, err
+	}
+	minimumShouldMatch := ""
+	if value.MinimumShouldMatch != nil {
+		if minimumShouldMatch, err = ParseMinimumShouldMatch(value.MinimumShouldMatch); err != nil {
+			return nil, minimumShouldMatchError("multi_match", err)
+		}
+	}
+	zeroTerms, err := parseZeroTermsQuery("multi_match", value.ZeroTermsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := make([]bluge.Query, 0, len(fields))
+	keys := make([]string, 0, len(fields))
+	zers := make(map[string]*analysis.Analyzer)
+	groups := make(map[string][]multiMatchField)
+	for _, field := range fields {
+		key := value.Analyzer
+		if mappings != nil {
+			if prop, ok := mappings.GetProperty(field.name); ok {
+				if prop.Type == "numeric" || prop.Type == "bool" {
+					match := &meta.MatchQuery{Query: value.Query, Lenient: value.Lenient, Boost: field.boost}
+					subq, err := matchQueryNonText("multi_match", field.name, prop, match)
+					if err != nil {
+						return nil, err
+					}
+					queries = append(queries, subq)
+					continue
+				}
+				if key == "" {
+					key = strings.Join([]string{prop.Type, prop.Analyzer, prop.SearchAnalyzer, prop.Normalizer}, "/")
+				}
 			}
-			if zer == nil && indexZer != nil {
-				zer = indexZer
+		}
+		if _, ok := groups[key]; !ok {
+			zer := fieldAnalyzer(field.name)
+			if zer == nil {
+				zer = analyzer.NewStandardAnalyzer()
 			}
-			subqq.SetAnalyzer(zer)
+			keys = append(keys, key)
+			zers[key] = zer
+		}
+		groups[key] = append(groups[key], field)
+	}
+
+	for _, key := range keys {
+		termGroups := analyzeTermGroups(zers[key], value.Query)
+		if len(termGroups) == 0 {
+			queries = append(queries, zeroTermsQuery(zeroTerms))
+			continue
+		}
+		clauses := make([]bluge.Query, len(termGroups))
+		for i, terms := range termGroups {
+			clauses[i] = &blendedTermQuery{fields: groups[key], terms: terms, tieBreaker: value.TieBreaker}
 		}
-		subq.AddShould(subqq)
+		queries = append(queries, combineMatchClauses(clauses, operator, minimumShouldMatch))
 	}
 
-	return subq, nil
+	if len(queries) == 1 {
+		if value.Boost >= 0 {
+			return bluge.NewBooleanQuery().AddMust(queries[0]).SetBoost(value.Boost), nil
+		}
+		return queries[0], nil
+	}
+	return bestFieldsQuery(queries, value.TieBreaker, value.Boost), nil
 }
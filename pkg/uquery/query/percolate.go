@@ -0,0 +1,373 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/analysis"
+	"github.com/blugelabs/bluge/search"
+	"github.com/blugelabs/bluge/search/searcher"
+	"github.com/blugelabs/bluge/search/similarity"
+
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+)
+
+// PercolatorAnyTerm is indexed for the stored query which terms can't be extracted,
+// the query is always a candidate of percolate query.
+const PercolatorAnyTerm = "\x00"
+
+// PercolateDocumentBuilder returns the bluge document of the percolated document, the document is checked and
+// indexed like a new document of the index. It is set by core to build the in-memory index of percolate query.
+var PercolateDocumentBuilder func(mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer, docID string, doc map[string]interface{}) (*bluge.Document, error)
+
+func PercolateQuery(query map[string]interface{}, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (bluge.Query, error) {
+	value := &meta.PercolateQuery{Boost: -1.0}
+	var err error
+	for k, v := range query {
+		k := strings.ToLower(k)
+		switch k {
+		case "field":
+			value.Field, err = zutils.ToString(v)
+		case "document":
+			doc, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, errors.New(errors.ErrorTypeParsingException, "[percolate] document should be an object")
+			}
+			value.Documents = append(value.Documents, doc)
+		case "documents":
+			items, ok := v.([]interface{})
+			if !ok {
+				return nil, errors.New(errors.ErrorTypeParsingException, "[percolate] documents should be an array of object")
+			}
+			for _, item := range items {
+				doc, ok := item.(map[string]interface{})
+				if !ok {
+					return nil, errors.New(errors.ErrorTypeParsingException, "[percolate] documents should be an array of object")
+				}
+				value.Documents = append(value.Documents, doc)
+			}
+		case "index":
+			value.Index, err = zutils.ToString(v)
+		case "id":
+			value.ID, err = zutils.ToString(v)
+		case "boost":
+			value.Boost, err = zutils.ToFloat64(v)
+		case "name", "routing", "preference":
+			// ignore
+		default:
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[percolate] unknown field [%s]", k))
+		}
+		if err != nil {
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[percolate] %s doesn't support value [%v]", k, v))
+		}
+	}
+
+	if value.Field == "" {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[percolate] query is missing required [field] parameter")
+	}
+	if prop, ok := mappings.GetProperty(value.Field); !ok || prop.Type != "percolator" {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[percolate] field [%s] does not exist or is not of type [percolator]", value.Field))
+	}
+	if value.ID != "" {
+		if len(value.Documents) > 0 {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[percolate] query can't specify both [document(s)] and [id]")
+		}
+		if value.Index == "" {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[percolate] query requires [index] if [id] is specified")
+		}
+		if DocumentGetter == nil {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[percolate] fetching document is not supported")
+		}
+		doc, err := DocumentGetter(value.Index, value.ID)
+		if err != nil {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[percolate] failed to fetch document [%s] of index [%s]", value.ID, value.Index)).Cause(err)
+		}
+		value.Documents = append(value.Documents, doc)
+	}
+	if len(value.Documents) == 0 {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[percolate] query requires [document], [documents] or [id]")
+	}
+	if PercolateDocumentBuilder == nil {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[percolate] indexing document is not supported")
+	}
+
+	boost := value.Boost
+	if boost < 0 {
+		boost = 1
+	}
+	return &percolateQuery{
+		field:     value.Field,
+		documents: value.Documents,
+		boost:     boost,
+		mappings:  mappings,
+		analyzers: analyzers,
+	}, nil
+}
+
+// percolateQuery matches the stored queries of percolator field which match any of the documents.
+// The documents are indexed into an in-memory index, the stored queries which share a term with
+// the documents are selected as candidates, then each candidate is verified against the in-memory index.
+type percolateQuery struct {
+	field     string
+	documents []map[string]interface{}
+	boost     float64
+	mappings  *meta.Mappings
+	analyzers map[string]*analysis.Analyzer
+}
+
+func (q *percolateQuery) Searcher(i search.Reader, options search.SearcherOptions) (search.Searcher, error) {
+	w, r, err := q.indexDocuments()
+	if w != nil {
+		defer w.Close()
+	}
+	if r != nil {
+		defer r.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	terms, err := percolateDocumentTerms(r)
+	if err != nil {
+		return nil, err
+	}
+	terms = append(terms, PercolatorAnyTerm)
+	candidates, err := q.candidateSearcher(i, terms, options)
+	if err != nil {
+		return nil, err
+	}
+	defer candidates.Close()
+
+	type percolateHit struct {
+		id    string
+		score float64
+	}
+	var hits []percolateHit
+	ctx := search.NewSearchContext(candidates.DocumentMatchPoolSize(), 0)
+	match, err := candidates.Next(ctx)
+	for err == nil && match != nil {
+		var id string
+		var data []byte
+		err = i.VisitStoredFields(match.Number, func(field string, value []byte) bool {
+			switch field {
+			case "_id":
+				id = string(value)
+			case q.field:
+				data = append([]byte(nil), value...)
+			}
+			return true
+		})
+		ctx.DocumentMatchPool.Put(match)
+		if err != nil {
+			return nil, err
+		}
+		if data != nil {
+			score, ok, err := q.verify(r, data)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				hits = append(hits, percolateHit{id: id, score: score * q.boost})
+			}
+		}
+		match, err = candidates.Next(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(hits) == 0 {
+		return searcher.NewMatchNoneSearcher(i, options)
+	}
+	searchers := make([]search.Searcher, 0, len(hits))
+	for _, hit := range hits {
+		s, err := searcher.NewTermSearcher(i, hit.id, "_id", 1, similarity.ConstantScorer(hit.score), options)
+		if err != nil {
+			for _, s := range searchers {
+				_ = s.Close()
+			}
+			return nil, err
+		}
+		searchers = append(searchers, s)
+	}
+	return searcher.NewDisjunctionSearcher(i, searchers, 1, similarity.NewCompositeSumScorer(), options)
+}
+
+// candidateSearcher matches the stored queries which contain any of the terms,
+// only the terms in the dictionary of percolator field are searched.
+func (q *percolateQuery) candidateSearcher(i search.Reader, terms []string, options search.SearcherOptions) (search.Searcher, error) {
+	dict, err := i.DictionaryLookup(q.field)
+	if err != nil {
+		return nil, err
+	}
+	defer dict.Close()
+
+	searchers := make([]search.Searcher, 0)
+	for _, term := range terms {
+		if ok, err := dict.Contains([]byte(term)); err != nil || !ok {
+			continue
+		}
+		s, err := searcher.NewTermSearcher(i, term, q.field, 1, similarity.ConstantScorer(1), options)
+		if err != nil {
+			for _, s := range searchers {
+				_ = s.Close()
+			}
+			return nil, err
+		}
+		searchers = append(searchers, s)
+	}
+	if len(searchers) == 0 {
+		return searcher.NewMatchNoneSearcher(i, options)
+	}
+	return searcher.NewDisjunctionSearcher(i, searchers, 1, similarity.NewCompositeSumScorer(), options)
+}
+
+// indexDocuments indexes the percolated documents into an in-memory index, the document id is the slot of document
+func (q *percolateQuery) indexDocuments() (*bluge.Writer, *bluge.Reader, error) {
+	w, err := bluge.OpenWriter(bluge.InMemoryOnlyConfig())
+	if err != nil {
+		return nil, nil, err
+	}
+	batch := bluge.NewBatch()
+	for slot, doc := range q.documents {
+		bdoc, err := PercolateDocumentBuilder(q.mappings, q.analyzers, strconv.Itoa(slot), doc)
+		if err != nil {
+			return w, nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[percolate] failed to parse document of slot [%d]", slot)).Cause(err)
+		}
+		batch.Insert(bdoc)
+	}
+	if err = w.Batch(batch); err != nil {
+		return w, nil, err
+	}
+	r, err := w.Reader()
+	return w, r, err
+}
+
+// verify runs the stored query against the in-memory index, it returns the best score of the documents
+func (q *percolateQuery) verify(r *bluge.Reader, data []byte) (float64, bool, error) {
+	var source map[string]interface{}
+	if err := json.UnmarshalNumber(data, &source); err != nil {
+		return 0, false, err
+	}
+	subq, err := Query(source, q.mappings, q.analyzers)
+	if err != nil {
+		return 0, false, err
+	}
+	dmi, err := r.Search(context.Background(), bluge.NewTopNSearch(1, subq))
+	if err != nil {
+		return 0, false, err
+	}
+	next, err := dmi.Next()
+	if err != nil || next == nil {
+		return 0, false, err
+	}
+	return next.Score, true, nil
+}
+
+// percolateDocumentTerms returns the encoded terms of all fields in the in-memory index
+func percolateDocumentTerms(r *bluge.Reader) ([]string, error) {
+	fields, err := r.Fields()
+	if err != nil {
+		return nil, err
+	}
+	var terms []string
+	for _, field := range fields {
+		it, err := r.DictionaryIterator(field, nil, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		entry, err := it.Next()
+		for err == nil && entry != nil {
+			terms = append(terms, percolatorTerm(field, entry.Term()))
+			entry, err = it.Next()
+		}
+		_ = it.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return terms, nil
+}
+
+// PercolatorTerms returns the terms of stored query which are indexed in percolator field, a document can match the
+// query only if it contains one of the terms. It returns PercolatorAnyTerm if the terms can't be extracted.
+func PercolatorTerms(query bluge.Query) []string {
+	terms, ok := extractQueryTerms(query)
+	if !ok {
+		return []string{PercolatorAnyTerm}
+	}
+	return terms
+}
+
+// extractQueryTerms returns the terms of which at least one is required to match the query,
+// it returns false for the queries which can match a document without any term, like range or match_all.
+func extractQueryTerms(query bluge.Query) ([]string, bool) {
+	switch q := query.(type) {
+	case *bluge.TermQuery:
+		if q.Field() == "" {
+			return nil, false
+		}
+		return []string{percolatorTerm(q.Field(), q.Term())}, true
+	case *bluge.MultiPhraseQuery:
+		var terms []string
+		for _, group := range q.Terms() {
+			for _, term := range group {
+				terms = append(terms, percolatorTerm(q.Field(), term))
+			}
+		}
+		return terms, q.Field() != "" && len(terms) > 0
+	case *bluge.BooleanQuery:
+		// any required clause is enough, the one with fewest terms selects fewest candidates
+		var best []string
+		found := false
+		for _, subq := range q.Musts() {
+			if terms, ok := extractQueryTerms(subq); ok && (!found || len(terms) < len(best)) {
+				best = terms
+				found = true
+			}
+		}
+		if found {
+			return best, true
+		}
+		// the should clauses are required if there is no must clause or minimum should match is set
+		shoulds := q.Shoulds()
+		if len(shoulds) == 0 || (len(q.Musts()) > 0 && q.MinShould() == 0) {
+			return nil, false
+		}
+		var terms []string
+		for _, subq := range shoulds {
+			subTerms, ok := extractQueryTerms(subq)
+			if !ok {
+				return nil, false
+			}
+			terms = append(terms, subTerms...)
+		}
+		return terms, true
+	}
+	return nil, false
+}
+
+func percolatorTerm(field, term string) string {
+	return field + "\x00" + term
+}
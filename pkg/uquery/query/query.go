@@ -38,7 +38,7 @@ func Query(query interface{}, mappings *meta.Mappings, analyzers map[string]*ana
 			return nil, errors.New(errors.ErrorTypeInvalidArgument, "query must be a map[string]interface{}")
 		}
 		var newQuery map[string]interface{}
-		if err = json.Unmarshal(data, &newQuery); err != nil {
+		if err = json.UnmarshalNumber(data, &newQuery); err != nil {
 			return nil, errors.New(errors.ErrorTypeInvalidArgument, "query must be a map[string]interface{}")
 		}
 		query = newQuery
@@ -98,6 +98,22 @@ func Query(query interface{}, mappings *meta.Mappings, analyzers map[string]*ana
 			if subq, err = MatchNoneQuery(); err != nil {
 				return nil, errors.New(errors.ErrorTypeXContentParseException, "[match_none] failed to parse field").Cause(err)
 			}
+		case "function_score":
+			if subq, err = FunctionScoreQuery(v, mappings, analyzers); err != nil {
+				return nil, errors.New(errors.ErrorTypeXContentParseException, "[function_score] failed to parse field").Cause(err)
+			}
+		case "script_score":
+			if subq, err = ScriptScoreQuery(v, mappings, analyzers); err != nil {
+				return nil, errors.New(errors.ErrorTypeXContentParseException, "[script_score] failed to parse field").Cause(err)
+			}
+		case "more_like_this":
+			if subq, err = MoreLikeThisQuery(v, mappings, analyzers); err != nil {
+				return nil, errors.New(errors.ErrorTypeXContentParseException, "[more_like_this] failed to parse field").Cause(err)
+			}
+		case "percolate":
+			if subq, err = PercolateQuery(v, mappings, analyzers); err != nil {
+				return nil, errors.New(errors.ErrorTypeXContentParseException, "[percolate] failed to parse field").Cause(err)
+			}
 		case "combined_fields":
 			if subq, err = CombinedFieldsQuery(v); err != nil {
 				return nil, errors.New(errors.ErrorTypeXContentParseException, "[combined_fields] failed to parse field").Cause(err)
@@ -139,11 +155,11 @@ func Query(query interface{}, mappings *meta.Mappings, analyzers map[string]*ana
 				return nil, errors.New(errors.ErrorTypeXContentParseException, "[wildcard] failed to parse field").Cause(err)
 			}
 		case "term":
-			if subq, err = TermQuery(v, mappings); err != nil {
+			if subq, err = TermQuery(v, mappings, analyzers); err != nil {
 				return nil, errors.New(errors.ErrorTypeXContentParseException, "[term] failed to parse field").Cause(err)
 			}
 		case "terms":
-			if subq, err = TermsQuery(v, mappings); err != nil {
+			if subq, err = TermsQuery(v, mappings, analyzers); err != nil {
 				return nil, errors.New(errors.ErrorTypeXContentParseException, "[terms] failed to parse field").Cause(err)
 			}
 		case "terms_set":
@@ -50,6 +50,10 @@ func QueryStringQuery(query map[string]interface{}, mappings *meta.Mappings, ana
 			value.DefaultOperator = v.(string)
 		case "boost":
 			value.Boost = v.(float64)
+		case "minimum_should_match":
+			value.MinimumShouldMatch = v
+		case "lenient":
+			// noop, the parsed queries don't convert values by field type
 		case "analyze_wildcard":
 			// noop
 		default:
@@ -57,6 +61,14 @@ func QueryStringQuery(query map[string]interface{}, mappings *meta.Mappings, ana
 		}
 	}
 
+	minimumShouldMatch := ""
+	if value.MinimumShouldMatch != nil {
+		var err error
+		if minimumShouldMatch, err = ParseMinimumShouldMatch(value.MinimumShouldMatch); err != nil {
+			return nil, minimumShouldMatchError("query_string", err)
+		}
+	}
+
 	options := querystr.DefaultOptions()
 
 	// TODO fields
@@ -96,5 +108,16 @@ func QueryStringQuery(query map[string]interface{}, mappings *meta.Mappings, ana
 		}
 	}
 
-	return querystr.ParseQueryString(value.Query, options)
+	subq, err := querystr.ParseQueryString(value.Query, options)
+	if err != nil {
+		return nil, err
+	}
+	if minimumShouldMatch != "" {
+		if boolQuery, ok := subq.(*bluge.BooleanQuery); ok {
+			minShould, _ := CalculateMinimumShouldMatch(len(boolQuery.Shoulds()), minimumShouldMatch)
+			boolQuery.SetMinShould(minShould) // lgtm[go/hardcoded-credentials]
+		}
+	}
+
+	return subq, nil
 }
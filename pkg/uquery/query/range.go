@@ -41,6 +41,9 @@ func RangeQuery(query map[string]interface{}, mappings *meta.Mappings) (bluge.Qu
 		prop, _ := mappings.GetProperty(field)
 		switch prop.Type {
 		case "numeric":
+			if prop.IsLong() {
+				return RangeQueryLong(field, prop, vv)
+			}
 			return RangeQueryNumeric(field, vv, mappings)
 		case "date", "time":
 			return RangeQueryTime(field, vv, mappings)
@@ -104,6 +107,75 @@ func RangeQueryNumeric(field string, query map[string]interface{}, mappings *met
 	return subq, nil
 }
 
+// RangeQueryLong matches the exact int64 range of long field, the value is indexed as date time term of nanoseconds
+func RangeQueryLong(field string, prop meta.Property, query map[string]interface{}) (bluge.Query, error) {
+	value := new(meta.RangeQuery)
+	value.Boost = -1.0
+	for k, v := range query {
+		k := strings.ToLower(k)
+		switch k {
+		case "gt":
+			value.GT = v
+		case "gte":
+			value.GTE = v
+		case "lt":
+			value.LT = v
+		case "lte":
+			value.LTE = v
+		case "boost":
+			value.Boost, _ = zutils.ToFloat64(v)
+		default:
+			// return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[range] unknown field [%s]", k))
+		}
+	}
+
+	// the zero time means unbounded
+	bound := func(name string, v interface{}) (time.Time, error) {
+		if v == nil {
+			return time.Time{}, nil
+		}
+		i, err := prop.ToLong(v)
+		if err != nil {
+			return time.Time{}, errors.New(errors.ErrorTypeXContentParseException, fmt.Sprintf("[range] %s range.%s convert to %s err %s", field, name, prop.NumericType, err.Error()))
+		}
+		return time.Unix(0, i), nil
+	}
+
+	var err error
+	min := time.Time{}
+	max := time.Time{}
+	minInclusive := false
+	maxInclusive := false
+	if value.GT != nil {
+		if min, err = bound("gt", value.GT); err != nil {
+			return nil, err
+		}
+	}
+	if value.GTE != nil {
+		minInclusive = true
+		if min, err = bound("gte", value.GTE); err != nil {
+			return nil, err
+		}
+	}
+	if value.LT != nil {
+		if max, err = bound("lt", value.LT); err != nil {
+			return nil, err
+		}
+	}
+	if value.LTE != nil {
+		maxInclusive = true
+		if max, err = bound("lte", value.LTE); err != nil {
+			return nil, err
+		}
+	}
+	subq := bluge.NewDateRangeInclusiveQuery(min, max, minInclusive, maxInclusive).SetField(field)
+	if value.Boost >= 0 {
+		subq.SetBoost(value.Boost)
+	}
+
+	return subq, nil
+}
+
 func RangeQueryTime(field string, query map[string]interface{}, mappings *meta.Mappings) (bluge.Query, error) {
 	value := new(meta.RangeQuery)
 	value.Boost = -1.0
@@ -0,0 +1,513 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package query
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/analysis"
+
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/uquery/script"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+// scoreFunction computes the score of a document from the doc values and the query score
+type scoreFunction interface {
+	Fields() []docValueField
+	Score(doc *functionDoc) (float64, error)
+}
+
+// functionDoc is the document evaluated by the score functions, it implements script.Context
+type functionDoc struct {
+	number uint64
+	score  float64
+	values *docValues
+}
+
+func (d *functionDoc) Score() float64 {
+	return d.score
+}
+
+func (d *functionDoc) DocValues(field string) []float64 {
+	if d.values == nil {
+		return nil
+	}
+	return d.values.Numbers(field)
+}
+
+// weightedScoreFunction is an entry of the functions of function_score,
+// function is nil if the entry only has a weight.
+type weightedScoreFunction struct {
+	filter   bluge.Query
+	function scoreFunction
+	weight   float64
+}
+
+func (f *weightedScoreFunction) Score(doc *functionDoc) (float64, error) {
+	if f.function == nil {
+		return f.weight, nil
+	}
+	score, err := f.function.Score(doc)
+	if err != nil {
+		return 0, err
+	}
+	return score * f.weight, nil
+}
+
+// parseScoreFunction parses an entry of the functions of function_score
+func parseScoreFunction(query map[string]interface{}, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (*weightedScoreFunction, error) {
+	f := &weightedScoreFunction{weight: 1.0}
+	hasWeight := false
+	functionName := ""
+	for k, v := range query {
+		k := strings.ToLower(k)
+		var function scoreFunction
+		var err error
+		switch k {
+		case "filter":
+			if f.filter, err = Query(v, mappings, analyzers); err != nil {
+				return nil, errors.New(errors.ErrorTypeXContentParseException, "[function_score] failed to parse field [filter]").Cause(err)
+			}
+			continue
+		case "weight":
+			if f.weight, err = zutils.ToFloat64(v); err != nil {
+				return nil, errors.New(errors.ErrorTypeParsingException, "[function_score] weight should be a number")
+			}
+			hasWeight = true
+			continue
+		case "field_value_factor":
+			function, err = parseFieldValueFactorFunction(v, mappings)
+		case "random_score":
+			function, err = parseRandomScoreFunction(v)
+		case "script_score":
+			function, err = parseScriptScoreFunction(v, mappings)
+		case "gauss", "exp", "linear":
+			function, err = parseDecayFunction(k, v, mappings)
+		default:
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[function_score] unknown field [%s]", k))
+		}
+		if err != nil {
+			return nil, err
+		}
+		if functionName != "" {
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("failed to parse [function_score] query. already found function [%s], now encountering [%s]. use [functions] array if you want to define several functions.", functionName, k))
+		}
+		functionName = k
+		f.function = function
+	}
+	if f.function == nil && !hasWeight {
+		return nil, errors.New(errors.ErrorTypeParsingException, "failed to parse [function_score] query. an entry in functions list is missing a function.")
+	}
+	return f, nil
+}
+
+// fieldValueFactorFunction scores by modifier(factor * doc['field'].value)
+type fieldValueFactorFunction struct {
+	field      docValueField
+	factor     float64
+	modifier   string
+	missing    float64
+	hasMissing bool
+}
+
+func parseFieldValueFactorFunction(query interface{}, mappings *meta.Mappings) (scoreFunction, error) {
+	params, ok := query.(map[string]interface{})
+	if !ok {
+		return nil, errors.New(errors.ErrorTypeParsingException, "[field_value_factor] value should be an object")
+	}
+	value := new(meta.FieldValueFactorFunction)
+	value.Factor = 1.0
+	for k, v := range params {
+		k := strings.ToLower(k)
+		switch k {
+		case "field":
+			value.Field, _ = zutils.ToString(v)
+		case "factor":
+			value.Factor, _ = zutils.ToFloat64(v)
+		case "modifier":
+			value.Modifier, _ = zutils.ToString(v)
+		case "missing":
+			missing, err := zutils.ToFloat64(v)
+			if err != nil {
+				return nil, errors.New(errors.ErrorTypeParsingException, "[field_value_factor] missing should be a number")
+			}
+			value.Missing = &missing
+		default:
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[field_value_factor] unknown field [%s]", k))
+		}
+	}
+	if value.Field == "" {
+		return nil, errors.New(errors.ErrorTypeParsingException, "[field_value_factor] field is required")
+	}
+	modifier := strings.ToLower(value.Modifier)
+	switch modifier {
+	case "":
+		modifier = "none"
+	case "none", "log", "log1p", "log2p", "ln", "ln1p", "ln2p", "square", "sqrt", "reciprocal":
+	default:
+		return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[field_value_factor] illegal modifier [%s]", value.Modifier))
+	}
+	field, err := newDocValueField(value.Field, mappings)
+	if err != nil {
+		return nil, errors.New(errors.ErrorTypeParsingException, "[field_value_factor] "+err.Error())
+	}
+
+	f := &fieldValueFactorFunction{field: field, factor: value.Factor, modifier: modifier}
+	if value.Missing != nil {
+		f.missing = *value.Missing
+		f.hasMissing = true
+	}
+	return f, nil
+}
+
+func (f *fieldValueFactorFunction) Fields() []docValueField {
+	return []docValueField{f.field}
+}
+
+func (f *fieldValueFactorFunction) Score(doc *functionDoc) (float64, error) {
+	var value float64
+	if values := doc.DocValues(f.field.name); len(values) > 0 {
+		value = values[0]
+	} else if f.hasMissing {
+		value = f.missing
+	} else {
+		return 0, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("missing value for field [%s]", f.field.name))
+	}
+
+	value *= f.factor
+	var score float64
+	switch f.modifier {
+	case "log":
+		score = math.Log10(value)
+	case "log1p":
+		score = math.Log10(value + 1)
+	case "log2p":
+		score = math.Log10(value + 2)
+	case "ln":
+		score = math.Log(value)
+	case "ln1p":
+		score = math.Log(value + 1)
+	case "ln2p":
+		score = math.Log(value + 2)
+	case "square":
+		score = value * value
+	case "sqrt":
+		score = math.Sqrt(value)
+	case "reciprocal":
+		score = 1 / value
+	default:
+		score = value
+	}
+	if math.IsNaN(score) || math.IsInf(score, 0) {
+		return 0, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("result of field modification [%s(%v)] must be a number", f.modifier, value))
+	}
+	return score, nil
+}
+
+// randomScoreFunction scores by a random number in [0, 1), the number is stable for the seed
+// and the value of the field, or the internal doc number if the field is not set.
+type randomScoreFunction struct {
+	seed  uint64
+	field string
+}
+
+func parseRandomScoreFunction(query interface{}) (scoreFunction, error) {
+	params, ok := query.(map[string]interface{})
+	if !ok {
+		return nil, errors.New(errors.ErrorTypeParsingException, "[random_score] value should be an object")
+	}
+	f := &randomScoreFunction{seed: uint64(time.Now().UnixNano())}
+	for k, v := range params {
+		k := strings.ToLower(k)
+		switch k {
+		case "seed":
+			switch v := v.(type) {
+			case string:
+				h := fnv.New64a()
+				_, _ = h.Write([]byte(v))
+				f.seed = h.Sum64()
+			default:
+				seed, err := zutils.ToInt64(v)
+				if err != nil {
+					return nil, errors.New(errors.ErrorTypeParsingException, "[random_score] seed should be a number or string")
+				}
+				f.seed = uint64(seed)
+			}
+		case "field":
+			f.field, _ = zutils.ToString(v)
+		default:
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[random_score] unknown field [%s]", k))
+		}
+	}
+	return f, nil
+}
+
+func (f *randomScoreFunction) Fields() []docValueField {
+	if f.field == "" {
+		return nil
+	}
+	return []docValueField{{name: f.field, kind: docValueKeyword}}
+}
+
+func (f *randomScoreFunction) Score(doc *functionDoc) (float64, error) {
+	buf := make([]byte, 8)
+	h := fnv.New64a()
+	binary.BigEndian.PutUint64(buf, f.seed)
+	_, _ = h.Write(buf)
+	term, ok := []byte(nil), false
+	if f.field != "" && doc.values != nil {
+		term, ok = doc.values.Term(f.field)
+	}
+	if ok {
+		_, _ = h.Write(term)
+	} else {
+		binary.BigEndian.PutUint64(buf, doc.number)
+		_, _ = h.Write(buf)
+	}
+	return float64(h.Sum64()>>11) / (1 << 53), nil
+}
+
+// scriptScoreFunction scores by the script
+type scriptScoreFunction struct {
+	script *script.Script
+	fields []docValueField
+}
+
+func parseScriptScoreFunction(query interface{}, mappings *meta.Mappings) (scoreFunction, error) {
+	params, ok := query.(map[string]interface{})
+	if !ok {
+		return nil, errors.New(errors.ErrorTypeParsingException, "[script_score] value should be an object")
+	}
+	var s *script.Script
+	for k, v := range params {
+		k := strings.ToLower(k)
+		switch k {
+		case "script":
+			var err error
+			if s, err = parseScript(v); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[script_score] unknown field [%s]", k))
+		}
+	}
+	if s == nil {
+		return nil, errors.New(errors.ErrorTypeParsingException, "[script_score] script is required")
+	}
+	return newScriptScoreFunction(s, mappings)
+}
+
+func newScriptScoreFunction(s *script.Script, mappings *meta.Mappings) (*scriptScoreFunction, error) {
+	f := &scriptScoreFunction{script: s}
+	for _, name := range s.Fields() {
+		field, err := newDocValueField(name, mappings)
+		if err != nil {
+			return nil, errors.New(errors.ErrorTypeParsingException, "[script] "+err.Error())
+		}
+		f.fields = append(f.fields, field)
+	}
+	return f, nil
+}
+
+func (f *scriptScoreFunction) Fields() []docValueField {
+	return f.fields
+}
+
+func (f *scriptScoreFunction) Score(doc *functionDoc) (float64, error) {
+	score, err := f.script.Eval(doc)
+	if err != nil {
+		return 0, errors.New(errors.ErrorTypeScriptException, "runtime error").Cause(err)
+	}
+	return score, nil
+}
+
+// decayFunction scores by the distance of the value from the origin,
+// the score is 1 within the offset and decay at offset + scale.
+type decayFunction struct {
+	field          docValueField
+	decayFn        func(distance, scale, decay float64) float64
+	origin         float64
+	scale          float64
+	offset         float64
+	decay          float64
+	multiValueMode string
+}
+
+func parseDecayFunction(name string, query interface{}, mappings *meta.Mappings) (scoreFunction, error) {
+	params, ok := query.(map[string]interface{})
+	if !ok {
+		return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] value should be an object", name))
+	}
+	f := &decayFunction{decay: 0.5, multiValueMode: "min"}
+	switch name {
+	case "gauss":
+		f.decayFn = script.DecayGauss
+	case "exp":
+		f.decayFn = script.DecayExp
+	default:
+		f.decayFn = script.DecayLinear
+	}
+
+	var fieldName string
+	var fieldParams map[string]interface{}
+	for k, v := range params {
+		if strings.ToLower(k) == "multi_value_mode" {
+			mode, _ := zutils.ToString(v)
+			f.multiValueMode = strings.ToLower(mode)
+			switch f.multiValueMode {
+			case "min", "max", "avg", "sum":
+			default:
+				return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] illegal multi_value_mode [%s]", name, mode))
+			}
+			continue
+		}
+		if fieldName != "" {
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] query doesn't support multiple fields, found [%s] and [%s]", name, fieldName, k))
+		}
+		if fieldParams, ok = v.(map[string]interface{}); !ok {
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] field [%s] value should be an object", name, k))
+		}
+		fieldName = k
+	}
+	if fieldName == "" {
+		return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] field is required", name))
+	}
+
+	field, err := newDocValueField(fieldName, mappings)
+	if err != nil {
+		return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] %s", name, err.Error()))
+	}
+	f.field = field
+
+	var origin, scale, offset interface{}
+	for k, v := range fieldParams {
+		k := strings.ToLower(k)
+		switch k {
+		case "origin":
+			origin = v
+		case "scale":
+			scale = v
+		case "offset":
+			offset = v
+		case "decay":
+			if f.decay, err = zutils.ToFloat64(v); err != nil {
+				return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] decay should be a number", name))
+			}
+		default:
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] unknown field [%s]", name, k))
+		}
+	}
+	if scale == nil {
+		return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] scale is required", name))
+	}
+	if f.decay <= 0 || f.decay >= 1 {
+		return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] decay must be in the range (0..1), got [%v]", name, f.decay))
+	}
+
+	switch field.kind {
+	case docValueDate:
+		prop, _ := mappings.GetProperty(fieldName)
+		f.origin = float64(time.Now().UnixMilli())
+		if s, _ := origin.(string); origin != nil && s != "now" {
+			t, err := zutils.ParseTime(origin, prop.Format, prop.TimeZone)
+			if err != nil {
+				return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] origin parse error: %s", name, err.Error()))
+			}
+			f.origin = float64(t.UnixMilli())
+		}
+		if f.scale, err = parseDecayDuration(scale); err != nil {
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] scale parse error: %s", name, err.Error()))
+		}
+		if offset != nil {
+			if f.offset, err = parseDecayDuration(offset); err != nil {
+				return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] offset parse error: %s", name, err.Error()))
+			}
+		}
+	case docValueDouble, docValueLong:
+		if origin == nil {
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] origin is required", name))
+		}
+		if f.origin, err = zutils.ToFloat64(origin); err != nil {
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] origin should be a number", name))
+		}
+		if f.scale, err = zutils.ToFloat64(scale); err != nil {
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] scale should be a number", name))
+		}
+		if offset != nil {
+			if f.offset, err = zutils.ToFloat64(offset); err != nil {
+				return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] offset should be a number", name))
+			}
+		}
+	default:
+		return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] field [%s] should be a numeric or date field", name, fieldName))
+	}
+	if f.scale <= 0 {
+		return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] scale must be > 0", name))
+	}
+	if f.offset < 0 {
+		return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] offset must be >= 0", name))
+	}
+	return f, nil
+}
+
+// parseDecayDuration parses the duration of date decay as milliseconds, a number is milliseconds
+func parseDecayDuration(v interface{}) (float64, error) {
+	if s, ok := v.(string); ok {
+		d, err := zutils.ParseDuration(s)
+		if err != nil {
+			return 0, err
+		}
+		return float64(d.Milliseconds()), nil
+	}
+	return zutils.ToFloat64(v)
+}
+
+func (f *decayFunction) Fields() []docValueField {
+	return []docValueField{f.field}
+}
+
+func (f *decayFunction) Score(doc *functionDoc) (float64, error) {
+	values := doc.DocValues(f.field.name)
+	if len(values) == 0 {
+		return 1, nil
+	}
+	var distance float64
+	for i, value := range values {
+		d := math.Max(0, math.Abs(value-f.origin)-f.offset)
+		switch {
+		case i == 0:
+			distance = d
+		case f.multiValueMode == "max":
+			distance = math.Max(distance, d)
+		case f.multiValueMode == "avg", f.multiValueMode == "sum":
+			distance += d
+		default:
+			distance = math.Min(distance, d)
+		}
+	}
+	if f.multiValueMode == "avg" {
+		distance /= float64(len(values))
+	}
+	return f.decayFn(distance, f.scale, f.decay), nil
+}
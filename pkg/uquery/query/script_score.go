@@ -0,0 +1,145 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package query
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/analysis"
+
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/uquery/script"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+func ScriptScoreQuery(query map[string]interface{}, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (bluge.Query, error) {
+	value := new(meta.ScriptScoreQuery)
+	value.Boost = -1.0
+	var s *script.Script
+	var err error
+	for k, v := range query {
+		k := strings.ToLower(k)
+		switch k {
+		case "query":
+			value.Query = v
+		case "script":
+			if s, err = parseScript(v); err != nil {
+				return nil, err
+			}
+		case "min_score":
+			minScore, err := zutils.ToFloat64(v)
+			if err != nil {
+				return nil, errors.New(errors.ErrorTypeParsingException, "[script_score] min_score should be a number")
+			}
+			value.MinScore = &minScore
+		case "boost":
+			value.Boost, _ = zutils.ToFloat64(v)
+		default:
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[script_score] unknown field [%s]", k))
+		}
+	}
+	if value.Query == nil {
+		return nil, errors.New(errors.ErrorTypeParsingException, "[script_score] query is required")
+	}
+	if s == nil {
+		return nil, errors.New(errors.ErrorTypeParsingException, "[script_score] script is required")
+	}
+
+	function, err := newScriptScoreFunction(s, mappings)
+	if err != nil {
+		return nil, err
+	}
+	q := &functionScoreQuery{
+		functions: []*weightedScoreFunction{{function: &nonNegativeScoreFunction{function}, weight: 1.0}},
+		scoreMode: "first",
+		boostMode: "replace",
+		maxBoost:  math.MaxFloat64,
+		boost:     1.0,
+		fields:    function.Fields(),
+	}
+	if value.MinScore != nil {
+		q.minScore = *value.MinScore
+		q.hasMinScore = true
+	}
+	if value.Boost >= 0 {
+		q.boost = value.Boost
+	}
+	if q.query, err = Query(value.Query, mappings, analyzers); err != nil {
+		return nil, errors.New(errors.ErrorTypeXContentParseException, "[script_score] failed to parse field [query]").Cause(err)
+	}
+
+	return q, nil
+}
+
+// parseScript parses the script, it can be an object with source, lang and params or just the source
+func parseScript(v interface{}) (*script.Script, error) {
+	value := new(meta.Script)
+	switch v := v.(type) {
+	case string:
+		value.Source = v
+	case map[string]interface{}:
+		for k, v := range v {
+			k := strings.ToLower(k)
+			switch k {
+			case "source":
+				value.Source, _ = zutils.ToString(v)
+			case "lang":
+				value.Lang, _ = zutils.ToString(v)
+			case "params":
+				params, ok := v.(map[string]interface{})
+				if !ok {
+					return nil, errors.New(errors.ErrorTypeParsingException, "[script] params should be an object")
+				}
+				value.Params = params
+			default:
+				return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[script] unknown field [%s]", k))
+			}
+		}
+	default:
+		return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[script] doesn't support values of type: %T", v))
+	}
+
+	switch strings.ToLower(value.Lang) {
+	case "", "painless", "expression":
+	default:
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[script] script_lang not supported [%s]", value.Lang))
+	}
+	s, err := script.Compile(value.Source, value.Params)
+	if err != nil {
+		return nil, errors.New(errors.ErrorTypeScriptException, "compile error").Cause(err)
+	}
+	return s, nil
+}
+
+// nonNegativeScoreFunction rejects the negative scores of script_score
+type nonNegativeScoreFunction struct {
+	scoreFunction
+}
+
+func (f *nonNegativeScoreFunction) Score(doc *functionDoc) (float64, error) {
+	score, err := f.scoreFunction.Score(doc)
+	if err != nil {
+		return 0, err
+	}
+	if score < 0 || math.IsNaN(score) {
+		return 0, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("script_score script returned an invalid score [%v] for doc [%d]. Must be a non-negative score!", score, doc.number))
+	}
+	return score, nil
+}
@@ -19,15 +19,19 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/analysis"
 
 	"github.com/zincsearch/zincsearch/pkg/errors"
 	"github.com/zincsearch/zincsearch/pkg/meta"
+	zincanalysis "github.com/zincsearch/zincsearch/pkg/uquery/analysis"
 	"github.com/zincsearch/zincsearch/pkg/zutils"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
 )
 
-func TermQuery(query map[string]interface{}, mappings *meta.Mappings) (bluge.Query, error) {
+func TermQuery(query map[string]interface{}, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (bluge.Query, error) {
 	if len(query) > 1 {
 		return nil, errors.New(errors.ErrorTypeParsingException, "[term] query doesn't support multiple fields")
 	}
@@ -42,6 +46,8 @@ func TermQuery(query map[string]interface{}, mappings *meta.Mappings) (bluge.Que
 			value.Value = v
 		case float64:
 			value.Value = v
+		case json.Number:
+			value.Value = v
 		case bool:
 			value.Value = v
 		case map[string]interface{}:
@@ -68,10 +74,18 @@ func TermQuery(query map[string]interface{}, mappings *meta.Mappings) (bluge.Que
 	prop, _ := mappings.GetProperty(field)
 	switch prop.Type {
 	case "numeric":
+		if prop.IsLong() {
+			return TermQueryLong(field, prop, value)
+		}
 		return TermQueryNumeric(field, value)
 	case "bool":
 		return TermQueryBool(field, value)
 	default:
+		if normalizer := zincanalysis.QueryNormalizerForField(analyzers, mappings, field); normalizer != nil {
+			if v, ok := value.Value.(string); ok {
+				value.Value = zincanalysis.Normalize(normalizer, v)
+			}
+		}
 		return TermQueryText(field, value)
 	}
 }
@@ -88,6 +102,20 @@ func TermQueryNumeric(field string, value *meta.TermQuery) (bluge.Query, error)
 	return subq, nil
 }
 
+// TermQueryLong matches the exact int64 of long field, the value is indexed as date time term of nanoseconds
+func TermQueryLong(field string, prop meta.Property, value *meta.TermQuery) (bluge.Query, error) {
+	val, err := prop.ToLong(value.Value)
+	if err != nil {
+		return nil, errors.New(errors.ErrorTypeXContentParseException, fmt.Sprintf("[term] convert value to %s error: %s", prop.NumericType, err))
+	}
+	t := time.Unix(0, val)
+	subq := bluge.NewDateRangeInclusiveQuery(t, t, true, true).SetField(field)
+	if value.Boost >= 0 {
+		subq.SetBoost(value.Boost)
+	}
+	return subq, nil
+}
+
 func TermQueryBool(field string, value *meta.TermQuery) (bluge.Query, error) {
 	val, err := zutils.ToBool(value.Value)
 	if err != nil {
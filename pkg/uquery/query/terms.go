@@ -20,12 +20,15 @@ import (
 	"strings"
 
 	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/analysis"
 
 	"github.com/zincsearch/zincsearch/pkg/errors"
 	"github.com/zincsearch/zincsearch/pkg/meta"
+	zincanalysis "github.com/zincsearch/zincsearch/pkg/uquery/analysis"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
 )
 
-func TermsQuery(query map[string]interface{}, mappings *meta.Mappings) (bluge.Query, error) {
+func TermsQuery(query map[string]interface{}, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (bluge.Query, error) {
 	if len(query) > 2 {
 		return nil, errors.New(errors.ErrorTypeParsingException, "[terms] query doesn't support multiple fields")
 	}
@@ -34,6 +37,7 @@ func TermsQuery(query map[string]interface{}, mappings *meta.Mappings) (bluge.Qu
 	values := []string{}
 	valueFloat := []float64{}
 	valueInts := []int{}
+	valueNumbers := []json.Number{}
 	valueBools := []bool{}
 	boost := -1.0
 	for k, v := range query {
@@ -61,6 +65,8 @@ func TermsQuery(query map[string]interface{}, mappings *meta.Mappings) (bluge.Qu
 					valueFloat = append(valueFloat, vvv)
 				case int:
 					valueInts = append(valueInts, vvv)
+				case json.Number:
+					valueNumbers = append(valueNumbers, vvv)
 				case bool:
 					valueBools = append(valueBools, vvv)
 				default:
@@ -72,8 +78,21 @@ func TermsQuery(query map[string]interface{}, mappings *meta.Mappings) (bluge.Qu
 		}
 	}
 
+	var prop meta.Property
+	if mappings != nil {
+		prop, _ = mappings.GetProperty(field)
+	}
+	numericQuery := func(term interface{}) (bluge.Query, error) {
+		if prop.IsLong() {
+			return TermQueryLong(field, prop, &meta.TermQuery{Value: term})
+		}
+		return TermQueryNumeric(field, &meta.TermQuery{Value: term})
+	}
+
+	normalizer := zincanalysis.QueryNormalizerForField(analyzers, mappings, field)
 	subq := bluge.NewBooleanQuery()
 	for _, term := range values {
+		term = zincanalysis.Normalize(normalizer, term)
 		subqq, err := TermQueryText(field, &meta.TermQuery{Value: term})
 		if err != nil {
 			return nil, err
@@ -81,14 +100,21 @@ func TermsQuery(query map[string]interface{}, mappings *meta.Mappings) (bluge.Qu
 		subq.AddShould(subqq)
 	}
 	for _, term := range valueFloat {
-		subqq, err := TermQueryNumeric(field, &meta.TermQuery{Value: term})
+		subqq, err := numericQuery(term)
 		if err != nil {
 			return nil, err
 		}
 		subq.AddShould(subqq)
 	}
 	for _, term := range valueInts {
-		subqq, err := TermQueryNumeric(field, &meta.TermQuery{Value: term})
+		subqq, err := numericQuery(term)
+		if err != nil {
+			return nil, err
+		}
+		subq.AddShould(subqq)
+	}
+	for _, term := range valueNumbers {
+		subqq, err := numericQuery(term)
 		if err != nil {
 			return nil, err
 		}
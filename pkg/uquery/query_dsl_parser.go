@@ -20,15 +20,18 @@ import (
 
 	"github.com/blugelabs/bluge"
 	"github.com/blugelabs/bluge/analysis"
-	"github.com/blugelabs/bluge/search"
 
+	"github.com/zincsearch/zincsearch/pkg/bluge/collector"
 	"github.com/zincsearch/zincsearch/pkg/config"
 	"github.com/zincsearch/zincsearch/pkg/errors"
 	"github.com/zincsearch/zincsearch/pkg/meta"
 	"github.com/zincsearch/zincsearch/pkg/uquery/aggregation"
+	"github.com/zincsearch/zincsearch/pkg/uquery/collapse"
 	"github.com/zincsearch/zincsearch/pkg/uquery/fields"
 	"github.com/zincsearch/zincsearch/pkg/uquery/highlight"
+	"github.com/zincsearch/zincsearch/pkg/uquery/knn"
 	"github.com/zincsearch/zincsearch/pkg/uquery/query"
+	"github.com/zincsearch/zincsearch/pkg/uquery/rescore"
 	"github.com/zincsearch/zincsearch/pkg/uquery/sort"
 	"github.com/zincsearch/zincsearch/pkg/uquery/source"
 )
@@ -49,6 +52,19 @@ func ParseQueryDSL(q *meta.ZincQuery, mappings *meta.Mappings, analyzers map[str
 		return nil, errors.New(errors.ErrorTypeNotImplemented, fmt.Sprintf("[%s] query doesn't support", q.Query))
 	}
 
+	// parse knn, the query is combined with the hits after the knn searches executed
+	knnRequest, err := knn.Parse(q, mappings, analyzers)
+	if err != nil {
+		return nil, err
+	}
+	if knnRequest != nil {
+		q.KNN = knnRequest
+		if q.Query == nil {
+			query = nil
+		}
+		query = knnRequest.Query(query)
+	}
+
 	// create search request
 	request := bluge.NewTopNSearch(q.Size, query).WithStandardAggregations()
 
@@ -91,14 +107,38 @@ func ParseQueryDSL(q *meta.ZincQuery, mappings *meta.Mappings, analyzers map[str
 
 	// parse sort
 	if q.Sort != nil {
-		if q.Sort, err = sort.Request(q.Sort); err != nil {
+		order, err := sort.Request(q.Sort, mappings)
+		if err != nil {
 			return nil, err
 		}
-		if q.Sort != nil {
-			request.SortByCustom(q.Sort.(search.SortOrder))
+		if order != nil {
+			q.Sort = order
+			request.SortByCustom(order.SortOrder())
 		}
 	}
 
+	// parse rescore
+	rescorers, err := rescore.Request(q.Rescore, mappings, analyzers)
+	if err != nil {
+		return nil, err
+	}
+
+	// parse collapse
+	fieldCollapse, err := collapse.Request(q.Collapse, mappings)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rescorers) > 0 && q.Sort != nil {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "Cannot use [sort] option in conjunction with [rescore].")
+	}
+	if len(rescorers) > 0 && fieldCollapse != nil {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "cannot use `collapse` in conjunction with `rescore`")
+	}
+	if len(rescorers) > 0 || fieldCollapse != nil {
+		return collector.NewTopNSearch(request).Rescore(rescorers...).Collapse(fieldCollapse), nil
+	}
+
 	// pagenation
 	// TODO: search after PIT support
 
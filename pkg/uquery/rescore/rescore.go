@@ -0,0 +1,131 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rescore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blugelabs/bluge/analysis"
+
+	"github.com/zincsearch/zincsearch/pkg/bluge/collector"
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/uquery/query"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+const defaultWindowSize = 10
+
+// Request parses the rescore of the request, it can be a single rescorer or an array of them
+//
+//	{"window_size": 50, "query": {"rescore_query": {}, "query_weight": 0.7, "rescore_query_weight": 1.2, "score_mode": "total"}}
+func Request(v interface{}, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) ([]*collector.Rescorer, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	var rescorers []*collector.Rescorer
+	switch v := v.(type) {
+	case map[string]interface{}:
+		r, err := parseRescorer(v, mappings, analyzers)
+		if err != nil {
+			return nil, err
+		}
+		rescorers = append(rescorers, r)
+	case []interface{}:
+		for _, v := range v {
+			vv, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[rescore] doesn't support values of type: %T", v))
+			}
+			r, err := parseRescorer(vv, mappings, analyzers)
+			if err != nil {
+				return nil, err
+			}
+			rescorers = append(rescorers, r)
+		}
+	default:
+		return nil, errors.New(errors.ErrorTypeXContentParseException, "[rescore] value should be object or array")
+	}
+
+	return rescorers, nil
+}
+
+func parseRescorer(v map[string]interface{}, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (*collector.Rescorer, error) {
+	r := &collector.Rescorer{
+		WindowSize:         defaultWindowSize,
+		QueryWeight:        1.0,
+		RescoreQueryWeight: 1.0,
+		ScoreMode:          "total",
+	}
+	var err error
+	for k, v := range v {
+		k = strings.ToLower(k)
+		switch k {
+		case "window_size":
+			if r.WindowSize, err = zutils.ToInt(v); err != nil || r.WindowSize < 0 {
+				return nil, errors.New(errors.ErrorTypeParsingException, "[rescore] window_size should be a non-negative integer")
+			}
+		case "query":
+			params, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, errors.New(errors.ErrorTypeParsingException, "[rescore] query should be an object")
+			}
+			if err = parseRescoreQuery(r, params, mappings, analyzers); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[rescore] unknown field [%s]", k))
+		}
+	}
+	if r.Query == nil {
+		return nil, errors.New(errors.ErrorTypeParsingException, "[rescore] query.rescore_query is required")
+	}
+	return r, nil
+}
+
+func parseRescoreQuery(r *collector.Rescorer, params map[string]interface{}, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) error {
+	var err error
+	for k, v := range params {
+		k = strings.ToLower(k)
+		switch k {
+		case "rescore_query":
+			if r.Query, err = query.Query(v, mappings, analyzers); err != nil {
+				return errors.New(errors.ErrorTypeXContentParseException, "[rescore] failed to parse field [rescore_query]").Cause(err)
+			}
+		case "query_weight":
+			if r.QueryWeight, err = zutils.ToFloat64(v); err != nil {
+				return errors.New(errors.ErrorTypeParsingException, "[rescore] query_weight should be a number")
+			}
+		case "rescore_query_weight":
+			if r.RescoreQueryWeight, err = zutils.ToFloat64(v); err != nil {
+				return errors.New(errors.ErrorTypeParsingException, "[rescore] rescore_query_weight should be a number")
+			}
+		case "score_mode":
+			mode, _ := zutils.ToString(v)
+			r.ScoreMode = strings.ToLower(mode)
+			switch r.ScoreMode {
+			case "total", "multiply", "avg", "max", "min":
+			default:
+				return errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[rescore] illegal score_mode [%s]", mode))
+			}
+		default:
+			return errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[rescore] unknown field [%s]", k))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package script
+
+import (
+	"math"
+)
+
+type function struct {
+	args int
+	call func(args []float64) float64
+}
+
+// functions are the Math functions of painless and the score functions of elasticsearch script_score
+var functions = map[string]function{
+	"Math.abs":   {args: 1, call: func(a []float64) float64 { return math.Abs(a[0]) }},
+	"Math.ceil":  {args: 1, call: func(a []float64) float64 { return math.Ceil(a[0]) }},
+	"Math.exp":   {args: 1, call: func(a []float64) float64 { return math.Exp(a[0]) }},
+	"Math.floor": {args: 1, call: func(a []float64) float64 { return math.Floor(a[0]) }},
+	"Math.log":   {args: 1, call: func(a []float64) float64 { return math.Log(a[0]) }},
+	"Math.log10": {args: 1, call: func(a []float64) float64 { return math.Log10(a[0]) }},
+	"Math.log1p": {args: 1, call: func(a []float64) float64 { return math.Log1p(a[0]) }},
+	"Math.max":   {args: 2, call: func(a []float64) float64 { return math.Max(a[0], a[1]) }},
+	"Math.min":   {args: 2, call: func(a []float64) float64 { return math.Min(a[0], a[1]) }},
+	"Math.pow":   {args: 2, call: func(a []float64) float64 { return math.Pow(a[0], a[1]) }},
+	"Math.round": {args: 1, call: func(a []float64) float64 { return math.Floor(a[0] + 0.5) }},
+	"Math.sqrt":  {args: 1, call: func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	// saturation(value, k) = value/(k + value)
+	"saturation": {args: 2, call: func(a []float64) float64 { return a[0] / (a[1] + a[0]) }},
+	// sigmoid(value, k, a) = value^a/(k^a + value^a)
+	"sigmoid": {args: 3, call: func(a []float64) float64 {
+		return math.Pow(a[0], a[2]) / (math.Pow(a[1], a[2]) + math.Pow(a[0], a[2]))
+	}},
+	// decayNumericLinear(origin, scale, offset, decay, value)
+	"decayNumericLinear": {args: 5, call: func(a []float64) float64 {
+		return DecayLinear(decayDistance(a[0], a[2], a[4]), a[1], a[3])
+	}},
+	"decayNumericExp": {args: 5, call: func(a []float64) float64 {
+		return DecayExp(decayDistance(a[0], a[2], a[4]), a[1], a[3])
+	}},
+	"decayNumericGauss": {args: 5, call: func(a []float64) float64 {
+		return DecayGauss(decayDistance(a[0], a[2], a[4]), a[1], a[3])
+	}},
+}
+
+func decayDistance(origin, offset, value float64) float64 {
+	return math.Max(0, math.Abs(value-origin)-offset)
+}
+
+// DecayLinear returns the linear decay score of the distance, the score is decay at scale
+func DecayLinear(distance, scale, decay float64) float64 {
+	s := scale / (1.0 - decay)
+	return math.Max(0, (s-distance)/s)
+}
+
+// DecayExp returns the exponential decay score of the distance, the score is decay at scale
+func DecayExp(distance, scale, decay float64) float64 {
+	return math.Exp(math.Log(decay) / scale * distance)
+}
+
+// DecayGauss returns the gaussian decay score of the distance, the score is decay at scale
+func DecayGauss(distance, scale, decay float64) float64 {
+	sigmaSquared := -scale * scale / (2.0 * math.Log(decay))
+	return math.Exp(-distance * distance / (2.0 * sigmaSquared))
+}
@@ -0,0 +1,114 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package script
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenPunct
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	number float64
+	pos    int
+}
+
+// punctuations are ordered by length, the longer ones are matched first
+var punctuations = []string{
+	"&&", "||", "==", "!=", "<=", ">=",
+	"(", ")", "[", "]", ".", ",", "?", ":", "+", "-", "*", "/", "%", "!", "<", ">",
+}
+
+func lex(src string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(src) {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c) || (c == '.' && i+1 < len(src) && unicode.IsDigit(rune(src[i+1]))):
+			start := i
+			for i < len(src) && (unicode.IsDigit(rune(src[i])) || src[i] == '.') {
+				i++
+			}
+			if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+				i++
+				if i < len(src) && (src[i] == '+' || src[i] == '-') {
+					i++
+				}
+				for i < len(src) && unicode.IsDigit(rune(src[i])) {
+					i++
+				}
+			}
+			text := src[start:i]
+			// number suffix of painless, 1.0d, 2L
+			if i < len(src) && strings.ContainsRune("dDfFlL", rune(src[i])) {
+				i++
+			}
+			v, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number [%s] at position %d", text, start)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: text, number: v, pos: start})
+		case c == '\'' || c == '"':
+			start := i
+			i++
+			for i < len(src) && rune(src[i]) != c {
+				i++
+			}
+			if i >= len(src) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: src[start+1 : i], pos: start})
+			i++
+		case c == '_' || c == '$' || unicode.IsLetter(c):
+			start := i
+			for i < len(src) && (src[i] == '_' || src[i] == '$' || unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i]))) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: src[start:i], pos: start})
+		default:
+			matched := false
+			for _, p := range punctuations {
+				if strings.HasPrefix(src[i:], p) {
+					tokens = append(tokens, token{kind: tokenPunct, text: p, pos: i})
+					i += len(p)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character [%c] at position %d", c, i)
+			}
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEOF, text: "EOF", pos: len(src)})
+	return tokens, nil
+}
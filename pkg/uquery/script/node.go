@@ -0,0 +1,176 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package script
+
+import (
+	"fmt"
+	"math"
+)
+
+type node interface {
+	eval(ctx Context) (float64, error)
+}
+
+type numberNode struct {
+	value float64
+}
+
+func (n *numberNode) eval(ctx Context) (float64, error) {
+	return n.value, nil
+}
+
+type scoreNode struct{}
+
+func (n *scoreNode) eval(ctx Context) (float64, error) {
+	return ctx.Score(), nil
+}
+
+type docNode struct {
+	field string
+	attr  string // value, size, empty
+}
+
+func (n *docNode) eval(ctx Context) (float64, error) {
+	values := ctx.DocValues(n.field)
+	switch n.attr {
+	case "size":
+		return float64(len(values)), nil
+	case "empty":
+		return boolValue(len(values) == 0), nil
+	default:
+		if len(values) == 0 {
+			return 0, fmt.Errorf("a document doesn't have a value for field [%s], use doc['%s'].size()==0 to check if a document is missing a field", n.field, n.field)
+		}
+		return values[0], nil
+	}
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+func (n *unaryNode) eval(ctx Context) (float64, error) {
+	v, err := n.operand.eval(ctx)
+	if err != nil {
+		return 0, err
+	}
+	switch n.op {
+	case "-":
+		return -v, nil
+	case "!":
+		return boolValue(v == 0), nil
+	case "trunc":
+		return math.Trunc(v), nil
+	default:
+		return v, nil
+	}
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) eval(ctx Context) (float64, error) {
+	l, err := n.left.eval(ctx)
+	if err != nil {
+		return 0, err
+	}
+	// short circuit of logical operators
+	switch n.op {
+	case "&&":
+		if l == 0 {
+			return 0, nil
+		}
+	case "||":
+		if l != 0 {
+			return 1, nil
+		}
+	}
+	r, err := n.right.eval(ctx)
+	if err != nil {
+		return 0, err
+	}
+	switch n.op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		return l / r, nil
+	case "%":
+		return math.Mod(l, r), nil
+	case "<":
+		return boolValue(l < r), nil
+	case "<=":
+		return boolValue(l <= r), nil
+	case ">":
+		return boolValue(l > r), nil
+	case ">=":
+		return boolValue(l >= r), nil
+	case "==":
+		return boolValue(l == r), nil
+	case "!=":
+		return boolValue(l != r), nil
+	case "&&", "||":
+		return boolValue(r != 0), nil
+	default:
+		return 0, fmt.Errorf("unsupported operator [%s]", n.op)
+	}
+}
+
+type conditionalNode struct {
+	cond, then, otherwise node
+}
+
+func (n *conditionalNode) eval(ctx Context) (float64, error) {
+	v, err := n.cond.eval(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if v != 0 {
+		return n.then.eval(ctx)
+	}
+	return n.otherwise.eval(ctx)
+}
+
+type callNode struct {
+	name string
+	fn   func(args []float64) float64
+	args []node
+}
+
+func (n *callNode) eval(ctx Context) (float64, error) {
+	args := make([]float64, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval(ctx)
+		if err != nil {
+			return 0, err
+		}
+		args[i] = v
+	}
+	return n.fn(args), nil
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,310 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package script
+
+import (
+	"fmt"
+
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+// binary operators by precedence, the higher binds tighter
+var binaryPrecedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+	params map[string]interface{}
+	fields map[string]struct{}
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) accept(text string) bool {
+	if tok := p.peek(); tok.kind == tokenPunct && tok.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		tok := p.peek()
+		return fmt.Errorf("expected [%s] but found [%s] at position %d", text, tok.text, tok.pos)
+	}
+	return nil
+}
+
+// parseExpression parses the ternary and binary operators by precedence climbing
+func (p *parser) parseExpression(minPrecedence int) (node, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxDepth {
+		return nil, fmt.Errorf("script is nested too deeply, the max depth is %d", maxDepth)
+	}
+
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokenPunct {
+			break
+		}
+		if tok.text == "?" && minPrecedence == 0 {
+			p.next()
+			then, err := p.parseExpression(0)
+			if err != nil {
+				return nil, err
+			}
+			if err = p.expect(":"); err != nil {
+				return nil, err
+			}
+			otherwise, err := p.parseExpression(0)
+			if err != nil {
+				return nil, err
+			}
+			return &conditionalNode{cond: left, then: then, otherwise: otherwise}, nil
+		}
+		precedence, ok := binaryPrecedence[tok.text]
+		if !ok || precedence <= minPrecedence {
+			break
+		}
+		p.next()
+		right, err := p.parseExpression(precedence)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: tok.text, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if tok := p.peek(); tok.kind == tokenPunct && (tok.text == "-" || tok.text == "+" || tok.text == "!") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: tok.text, operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		return &numberNode{value: tok.number}, nil
+	case tokenPunct:
+		if tok.text == "(" {
+			// casts of painless, (double) doc['price'].value
+			if ident := p.peek(); ident.kind == tokenIdent && isCastType(ident.text) && p.tokens[p.pos+1].text == ")" {
+				p.pos += 2
+				operand, err := p.parseUnary()
+				if err != nil {
+					return nil, err
+				}
+				if ident.text == "long" || ident.text == "int" {
+					return &unaryNode{op: "trunc", operand: operand}, nil
+				}
+				return operand, nil
+			}
+			expr, err := p.parseExpression(0)
+			if err != nil {
+				return nil, err
+			}
+			if err = p.expect(")"); err != nil {
+				return nil, err
+			}
+			return expr, nil
+		}
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return &numberNode{value: 1}, nil
+		case "false":
+			return &numberNode{value: 0}, nil
+		case "_score":
+			return &scoreNode{}, nil
+		case "doc":
+			return p.parseDoc()
+		case "params":
+			return p.parseParam()
+		case "Math":
+			if err := p.expect("."); err != nil {
+				return nil, err
+			}
+			name := p.next()
+			if name.kind != tokenIdent {
+				return nil, fmt.Errorf("expected Math function but found [%s] at position %d", name.text, name.pos)
+			}
+			return p.parseCall("Math."+name.text, name.pos)
+		default:
+			return p.parseCall(tok.text, tok.pos)
+		}
+	}
+	return nil, fmt.Errorf("unexpected token [%s] at position %d", tok.text, tok.pos)
+}
+
+// parseDoc parses doc['field'].value, doc['field'].size(), doc['field'].empty and doc['field'].isEmpty()
+func (p *parser) parseDoc() (node, error) {
+	var field string
+	if p.accept("[") {
+		tok := p.next()
+		if tok.kind != tokenString {
+			return nil, fmt.Errorf("expected field name but found [%s] at position %d", tok.text, tok.pos)
+		}
+		field = tok.text
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+	} else if p.accept(".") {
+		tok := p.next()
+		if tok.kind != tokenIdent {
+			return nil, fmt.Errorf("expected field name but found [%s] at position %d", tok.text, tok.pos)
+		}
+		field = tok.text
+	} else {
+		tok := p.peek()
+		return nil, fmt.Errorf("expected [[] but found [%s] at position %d", tok.text, tok.pos)
+	}
+	p.fields[field] = struct{}{}
+
+	if err := p.expect("."); err != nil {
+		return nil, err
+	}
+	tok := p.next()
+	switch tok.text {
+	case "value":
+		return &docNode{field: field, attr: "value"}, nil
+	case "empty":
+		return &docNode{field: field, attr: "empty"}, nil
+	case "size", "isEmpty":
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		if tok.text == "isEmpty" {
+			return &docNode{field: field, attr: "empty"}, nil
+		}
+		return &docNode{field: field, attr: "size"}, nil
+	default:
+		return nil, fmt.Errorf("unsupported doc attribute [%s] at position %d", tok.text, tok.pos)
+	}
+}
+
+// parseParam parses params.name and params['name'], the value must be a number or boolean
+func (p *parser) parseParam() (node, error) {
+	var name string
+	if p.accept("[") {
+		tok := p.next()
+		if tok.kind != tokenString {
+			return nil, fmt.Errorf("expected param name but found [%s] at position %d", tok.text, tok.pos)
+		}
+		name = tok.text
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := p.expect("."); err != nil {
+			return nil, err
+		}
+		tok := p.next()
+		if tok.kind != tokenIdent {
+			return nil, fmt.Errorf("expected param name but found [%s] at position %d", tok.text, tok.pos)
+		}
+		name = tok.text
+	}
+
+	v, ok := p.params[name]
+	if !ok {
+		return nil, fmt.Errorf("param [%s] is not defined", name)
+	}
+	if b, ok := v.(bool); ok {
+		if b {
+			return &numberNode{value: 1}, nil
+		}
+		return &numberNode{value: 0}, nil
+	}
+	f, err := zutils.ToFloat64(v)
+	if err != nil {
+		return nil, fmt.Errorf("param [%s] should be a number", name)
+	}
+	return &numberNode{value: f}, nil
+}
+
+func (p *parser) parseCall(name string, pos int) (node, error) {
+	fn, ok := functions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown identifier [%s] at position %d", name, pos)
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var args []node
+	if !p.accept(")") {
+		for {
+			arg, err := p.parseExpression(0)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.accept(")") {
+				break
+			}
+			if err = p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(args) != fn.args {
+		return nil, fmt.Errorf("function [%s] expects %d arguments but got %d", name, fn.args, len(args))
+	}
+	return &callNode{name: name, fn: fn.call, args: args}, nil
+}
+
+func isCastType(name string) bool {
+	switch name {
+	case "double", "float", "long", "int":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,101 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+// Package script implements a small expression language used by script_score.
+//
+// The language is sandboxed: a script can only read the score, the doc values of
+// the document and the params, there is no assignment, loop or function definition.
+//
+//	_score * Math.log(2 + doc['likes'].value)
+//	doc['price'].empty ? 0 : params.factor / doc['price'].value
+//	saturation(doc['clicks'].value, 10)
+package script
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	maxScriptLength = 16 * 1024
+	maxDepth        = 64
+)
+
+// Context provides the values of the document evaluated by the script
+type Context interface {
+	// Score returns the score of the document
+	Score() float64
+	// DocValues returns the numeric doc values of the field, date fields are epoch milliseconds
+	DocValues(field string) []float64
+}
+
+// Script is a compiled script, it is safe to evaluate by multiple goroutines
+type Script struct {
+	source string
+	root   node
+	fields []string
+}
+
+// Compile parses the source, the params are resolved at compile time
+func Compile(source string, params map[string]interface{}) (*Script, error) {
+	if len(source) > maxScriptLength {
+		return nil, fmt.Errorf("script is too long, the max length is %d", maxScriptLength)
+	}
+	src := strings.TrimSpace(source)
+	src = strings.TrimSpace(strings.TrimSuffix(src, ";"))
+	if strings.HasPrefix(src, "return ") {
+		src = strings.TrimSpace(strings.TrimPrefix(src, "return "))
+	}
+	if src == "" {
+		return nil, fmt.Errorf("script is empty")
+	}
+
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, params: params, fields: make(map[string]struct{})}
+	root, err := p.parseExpression(0)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected token [%s] at position %d", tok.text, tok.pos)
+	}
+
+	fields := make([]string, 0, len(p.fields))
+	for field := range p.fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	return &Script{source: source, root: root, fields: fields}, nil
+}
+
+// Source returns the source of script
+func (s *Script) Source() string {
+	return s.source
+}
+
+// Fields returns the doc value fields used by the script
+func (s *Script) Fields() []string {
+	return s.fields
+}
+
+// Eval evaluates the script for the document
+func (s *Script) Eval(ctx Context) (float64, error) {
+	return s.root.eval(ctx)
+}
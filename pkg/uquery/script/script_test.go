@@ -0,0 +1,130 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package script
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testContext struct {
+	score  float64
+	values map[string][]float64
+}
+
+func (c *testContext) Score() float64 {
+	return c.score
+}
+
+func (c *testContext) DocValues(field string) []float64 {
+	return c.values[field]
+}
+
+func TestScript_Eval(t *testing.T) {
+	ctx := &testContext{
+		score: 2,
+		values: map[string][]float64{
+			"likes": {8},
+			"price": {10, 20},
+		},
+	}
+	params := map[string]interface{}{"factor": 1.5, "enabled": true}
+
+	tests := []struct {
+		name   string
+		source string
+		want   float64
+	}{
+		{name: "number", source: "42", want: 42},
+		{name: "precedence", source: "1 + 2 * 3 - 4 / 2", want: 5},
+		{name: "parentheses", source: "(1 + 2) * 3", want: 9},
+		{name: "modulo", source: "7 % 3", want: 1},
+		{name: "unary", source: "-2 + +3 - -1", want: 2},
+		{name: "score", source: "_score * 10", want: 20},
+		{name: "return and semicolon", source: "return _score + 1;", want: 3},
+		{name: "doc value", source: "doc['likes'].value", want: 8},
+		{name: "doc value dot notation", source: "doc.likes.value", want: 8},
+		{name: "doc size", source: "doc['price'].size()", want: 2},
+		{name: "doc empty", source: "doc['missing'].empty ? 1 : 0", want: 1},
+		{name: "doc isEmpty", source: "doc['likes'].isEmpty() ? 1 : 0", want: 0},
+		{name: "params", source: "params.factor * 2", want: 3},
+		{name: "params brackets", source: "params['factor'] * 2", want: 3},
+		{name: "params bool", source: "params.enabled ? 1 : 2", want: 1},
+		{name: "comparison", source: "doc['likes'].value >= 8 && _score < 3", want: 1},
+		{name: "or short circuit", source: "true || doc['missing'].value > 0", want: 1},
+		{name: "not", source: "!(1 > 2)", want: 1},
+		{name: "nested ternary", source: "_score > 5 ? 1 : _score > 1 ? 2 : 3", want: 2},
+		{name: "cast", source: "(long) 2.7 + (double) 1", want: 3},
+		{name: "number suffix", source: "1.5d + 2L", want: 3.5},
+		{name: "math", source: "Math.max(Math.sqrt(16), Math.pow(2, 3))", want: 8},
+		{name: "math log", source: "Math.log10(100)", want: 2},
+		{name: "saturation", source: "saturation(doc['likes'].value, 8)", want: 0.5},
+		{name: "sigmoid", source: "sigmoid(2, 2, 1)", want: 0.5},
+		{name: "decay linear", source: "decayNumericLinear(0, 10, 0, 0.5, 10)", want: 0.5},
+		{name: "decay exp", source: "decayNumericExp(0, 10, 0, 0.5, 10)", want: 0.5},
+		{name: "decay gauss", source: "decayNumericGauss(0, 10, 0, 0.5, 10)", want: 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Compile(tt.source, params)
+			assert.NoError(t, err)
+			got, err := s.Eval(ctx)
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
+
+func TestScript_Fields(t *testing.T) {
+	s, err := Compile("doc['price'].value + doc['likes'].value * doc['price'].size()", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"likes", "price"}, s.Fields())
+}
+
+func TestScript_CompileError(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{name: "empty", source: " ; "},
+		{name: "unknown identifier", source: "foo + 1"},
+		{name: "unknown function", source: "Math.random()"},
+		{name: "wrong arguments", source: "Math.abs(1, 2)"},
+		{name: "unclosed parentheses", source: "(1 + 2"},
+		{name: "trailing token", source: "1 2"},
+		{name: "undefined param", source: "params.missing"},
+		{name: "string param", source: "params.name"},
+		{name: "doc attribute", source: "doc['likes'].values"},
+		{name: "assignment", source: "x = 1"},
+		{name: "too deep", source: strings.Repeat("(", 100) + "1" + strings.Repeat(")", 100)},
+		{name: "too long", source: strings.Repeat("1+", maxScriptLength) + "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.source, map[string]interface{}{"name": "zinc"})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestScript_EvalError(t *testing.T) {
+	s, err := Compile("doc['missing'].value * 2", nil)
+	assert.NoError(t, err)
+	_, err = s.Eval(&testContext{})
+	assert.Error(t, err)
+}
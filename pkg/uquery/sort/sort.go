@@ -16,63 +16,589 @@
 package sort
 
 import (
+	"bytes"
+	"fmt"
+	"math"
+	gosort "sort"
 	"strings"
+	"time"
 
+	"github.com/blugelabs/bluge/numeric"
 	"github.com/blugelabs/bluge/search"
 
 	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
 )
 
-func Request(v interface{}) (search.SortOrder, error) {
+// Order is the parsed sort of the request, it keeps the field types to decode the sort values of the hits
+type Order struct {
+	sorts    search.SortOrder
+	decoders []func(value []byte) interface{}
+}
+
+// SortOrder returns the sort order of bluge
+func (o *Order) SortOrder() search.SortOrder {
+	return o.sorts
+}
+
+// Values returns the sort values of the document, missing values are nil
+func (o *Order) Values(match *search.DocumentMatch) []interface{} {
+	if len(match.SortValue) < len(o.decoders) {
+		return nil
+	}
+	values := make([]interface{}, len(o.decoders))
+	for i, decode := range o.decoders {
+		value := match.SortValue[i]
+		if isMissingTerm(value) {
+			continue
+		}
+		values[i] = decode(value)
+	}
+	return values
+}
+
+func (o *Order) add(sort *search.Sort, decoder func(value []byte) interface{}) {
+	o.sorts = append(o.sorts, sort)
+	o.decoders = append(o.decoders, decoder)
+}
+
+// Request parses the sort of the request, it can be:
+//
+//	"field", "-field", "_score", "_doc"
+//	["field", {"field": "desc"}, {"field": {"order": "desc", "missing": "_first", "mode": "max", "unmapped_type": "long"}}]
+//
+// mappings are the mappings of the searched indexes, the field only needs to be mapped in one of them.
+func Request(v interface{}, mappings ...*meta.Mappings) (*Order, error) {
 	if v == nil {
 		return nil, nil
 	}
-	if v, ok := v.(search.SortOrder); ok {
+	if v, ok := v.(*Order); ok {
 		return v, nil
 	}
 
-	sorts := make(search.SortOrder, 0, 1)
+	order := new(Order)
 	switch v := v.(type) {
 	case string:
-		sorts = append(sorts, search.ParseSearchSortString(v))
-		return sorts, nil
+		if err := order.parseString(v, mappings); err != nil {
+			return nil, err
+		}
 	case []interface{}:
 		for _, v := range v {
 			switch v := v.(type) {
 			case string:
-				sorts = append(sorts, search.ParseSearchSortString(v))
+				if err := order.parseString(v, mappings); err != nil {
+					return nil, err
+				}
 			case map[string]interface{}:
 				if len(v) > 1 {
 					return nil, errors.New(errors.ErrorTypeParsingException, "[sort] field doesn't support multiple values")
 				}
 				for field, v := range v {
-					sort := search.SortBy(search.Field(field))
-					switch v := v.(type) {
-					case string:
-						if strings.ToLower(v) == "desc" {
-							sort.Desc()
-						}
-					case map[string]interface{}:
-						for kk, vv := range v {
-							kk = strings.ToLower(kk)
-							switch kk {
-							case "order":
-								if strings.ToLower(vv.(string)) == "desc" {
-									sort.Desc()
-								}
-							case "format":
-							default:
-							}
-						}
-					default:
+					if err := order.parseField(field, v, mappings); err != nil {
+						return nil, err
 					}
-					sorts = append(sorts, sort)
 				}
+			default:
+				return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[sort] doesn't support values of type: %T", v))
 			}
 		}
 	default:
 		return nil, errors.New(errors.ErrorTypeXContentParseException, "[sort] value should be string or array")
 	}
 
-	return sorts, nil
+	return order, nil
+}
+
+func (o *Order) parseString(v string, mappings []*meta.Mappings) error {
+	desc := false
+	if strings.HasPrefix(v, "-") {
+		desc = true
+		v = v[1:]
+	}
+	v = strings.TrimPrefix(v, "+")
+	opts := &options{order: "asc"}
+	if desc || v == "_score" {
+		opts.order = "desc"
+	}
+	return o.addField(v, opts, mappings)
+}
+
+// options are the sort options of a field
+type options struct {
+	order        string
+	missing      interface{}
+	mode         string
+	unmappedType string
+	numericType  string
+	format       string
+}
+
+func (o *Order) parseField(field string, v interface{}, mappings []*meta.Mappings) error {
+	opts := &options{order: "asc"}
+	if field == "_score" {
+		opts.order = "desc"
+	}
+	switch v := v.(type) {
+	case string:
+		opts.order = strings.ToLower(v)
+	case map[string]interface{}:
+		for k, v := range v {
+			k = strings.ToLower(k)
+			switch k {
+			case "order":
+				order, _ := zutils.ToString(v)
+				opts.order = strings.ToLower(order)
+			case "missing":
+				opts.missing = v
+			case "mode":
+				mode, _ := zutils.ToString(v)
+				opts.mode = strings.ToLower(mode)
+			case "unmapped_type":
+				opts.unmappedType, _ = zutils.ToString(v)
+			case "numeric_type":
+				numericType, _ := zutils.ToString(v)
+				opts.numericType = strings.ToLower(numericType)
+			case "format":
+				opts.format, _ = zutils.ToString(v)
+			default:
+				return errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[sort] unknown field [%s]", k))
+			}
+		}
+	default:
+		return errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[sort] field [%s] doesn't support values of type: %T", field, v))
+	}
+	return o.addField(field, opts, mappings)
+}
+
+func (o *Order) addField(field string, opts *options, mappings []*meta.Mappings) error {
+	switch opts.order {
+	case "asc", "desc":
+	default:
+		return errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[sort] unknown order [%s], should be asc or desc", opts.order))
+	}
+	switch opts.mode {
+	case "", "min", "max", "avg", "sum", "median":
+	default:
+		return errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[sort] unknown mode [%s]", opts.mode))
+	}
+	desc := opts.order == "desc"
+
+	switch field {
+	case "_score":
+		sort := search.SortBy(search.DocumentScore())
+		if desc {
+			sort.Desc()
+		}
+		o.add(sort, decodeFloat)
+		return nil
+	case "_doc":
+		sort := search.SortBy(docNumberSource{})
+		if desc {
+			sort.Desc()
+		}
+		o.add(sort, decodeInt)
+		return nil
+	}
+
+	source, err := newFieldSource(field, opts, mappings)
+	if err != nil {
+		return err
+	}
+	sort := search.SortBy(source)
+	if desc {
+		sort.Desc()
+	}
+	if s, ok := opts.missing.(string); ok && s == "_first" {
+		sort.MissingFirst()
+	}
+	o.add(sort, source.decode)
+	return nil
+}
+
+const (
+	valueKeyword = iota
+	valueDouble
+	valueLong
+	valueDate
+)
+
+// fieldSource returns the sort value of a field, the numeric values are reduced by mode and
+// converted to numeric_type, the result is encoded as the sortable prefix coded term.
+type fieldSource struct {
+	field         string
+	kind          int
+	scalingFactor float64
+	numericType   string
+	isBool        bool
+	mode          string
+	format        string
+	missing       []byte
+}
+
+func newFieldSource(field string, opts *options, mappings []*meta.Mappings) (*fieldSource, error) {
+	s := &fieldSource{field: field, mode: opts.mode, format: opts.format}
+
+	prop, ok, checked := fieldProperty(field, mappings)
+	switch {
+	case ok:
+	case opts.unmappedType != "":
+		if prop, ok = unmappedProperty(opts.unmappedType); !ok {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[sort] unknown unmapped_type [%s]", opts.unmappedType))
+		}
+	case checked && field != "_id":
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("No mapping found for [%s] in order to sort on", field))
+	}
+
+	switch prop.Type {
+	case "numeric":
+		if prop.IsLong() {
+			s.kind = valueLong
+			s.scalingFactor = prop.ScalingFactor
+			s.numericType = "long"
+			if prop.ScalingFactor > 0 {
+				s.numericType = "double"
+			}
+		} else {
+			s.kind = valueDouble
+			s.numericType = "double"
+		}
+	case "date", "time":
+		s.kind = valueDate
+		s.numericType = "date"
+	case "bool":
+		s.kind = valueKeyword
+		s.isBool = true
+	default:
+		s.kind = valueKeyword
+	}
+
+	if opts.numericType != "" {
+		if s.kind == valueKeyword {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[sort] numeric_type can only be used with numeric or date fields, field [%s] is [%s]", field, prop.Type))
+		}
+		switch opts.numericType {
+		case "double", "long", "date", "date_nanos":
+			s.numericType = opts.numericType
+		default:
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[sort] unknown numeric_type [%s]", opts.numericType))
+		}
+	}
+	switch s.mode {
+	case "avg", "sum", "median":
+		if s.kind == valueKeyword {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[sort] mode [%s] can only be used with numeric or date fields, field [%s] is [%s]", s.mode, field, prop.Type))
+		}
+	case "":
+		// the smallest value is used in ascending order and the largest in descending order
+		s.mode = "min"
+		if opts.order == "desc" {
+			s.mode = "max"
+		}
+	}
+
+	if opts.missing != nil {
+		if v, ok := opts.missing.(string); !ok || (v != "_first" && v != "_last") {
+			missing, err := s.encodeMissing(opts.missing, prop)
+			if err != nil {
+				return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[sort] field [%s] missing value [%v] is invalid: %s", field, opts.missing, err.Error()))
+			}
+			s.missing = missing
+		}
+	}
+
+	return s, nil
+}
+
+// fieldProperty returns the property of the field in the mappings of the searched indexes, the documents of
+// the indexes don't map the field are missing values. The field mapped to different types is sorted as keyword.
+// checked is false if there is no mappings to check the field.
+func fieldProperty(field string, mappings []*meta.Mappings) (prop meta.Property, ok, checked bool) {
+	for _, m := range mappings {
+		if m == nil {
+			continue
+		}
+		checked = true
+		p, found := m.GetProperty(field)
+		if !found {
+			continue
+		}
+		if !ok {
+			prop, ok = p, true
+			continue
+		}
+		if p.Type != prop.Type || p.IsLong() != prop.IsLong() || p.ScalingFactor != prop.ScalingFactor {
+			return meta.NewProperty("keyword"), true, true
+		}
+	}
+	return prop, ok, checked
+}
+
+// unmappedProperty returns the property of unmapped_type
+func unmappedProperty(typ string) (meta.Property, bool) {
+	switch strings.ToLower(typ) {
+	case "long", "integer", "short", "byte":
+		prop := meta.NewProperty("numeric")
+		prop.NumericType = meta.NumericTypeLong
+		return prop, true
+	case "numeric", "double", "float", "half_float", "scaled_float":
+		return meta.NewProperty("numeric"), true
+	case "date", "date_nanos", "time":
+		return meta.NewProperty("date"), true
+	case "keyword", "text", "match_only_text", "bool", "boolean":
+		return meta.NewProperty("keyword"), true
+	default:
+		return meta.Property{}, false
+	}
+}
+
+func (s *fieldSource) Fields() []string {
+	return []string{s.field}
+}
+
+func (s *fieldSource) Value(match *search.DocumentMatch) []byte {
+	terms := match.DocValues(s.field)
+	if s.kind == valueKeyword {
+		return s.keywordValue(terms)
+	}
+
+	var values []int64
+	for _, term := range terms {
+		prefixCoded := numeric.PrefixCoded(term)
+		if shift, err := prefixCoded.Shift(); err != nil || shift != 0 {
+			continue
+		}
+		if i64, err := prefixCoded.Int64(); err == nil {
+			values = append(values, i64)
+		}
+	}
+	if len(values) == 0 {
+		return s.missing
+	}
+	if s.numericType == "double" {
+		floats := make([]float64, len(values))
+		for i, v := range values {
+			floats[i] = s.toFloat(v)
+		}
+		return encodeFloat(reduceFloats(floats, s.mode))
+	}
+	for i, v := range values {
+		values[i] = s.toInt(v)
+	}
+	return encodeInt(reduceInts(values, s.mode))
+}
+
+func (s *fieldSource) keywordValue(terms [][]byte) []byte {
+	var rv []byte
+	for _, term := range terms {
+		if rv == nil ||
+			(s.mode == "max" && bytes.Compare(term, rv) > 0) ||
+			(s.mode != "max" && bytes.Compare(term, rv) < 0) {
+			rv = term
+		}
+	}
+	if rv == nil {
+		return s.missing
+	}
+	return rv
+}
+
+// toFloat converts the indexed int64 value to the float64 value of the field
+func (s *fieldSource) toFloat(v int64) float64 {
+	switch s.kind {
+	case valueLong:
+		if s.scalingFactor > 0 {
+			return float64(v) / s.scalingFactor
+		}
+		return float64(v)
+	case valueDate:
+		return float64(v / 1e6)
+	default:
+		return numeric.Int64ToFloat64(v)
+	}
+}
+
+// toInt converts the indexed int64 value to the int64 value of numeric_type
+func (s *fieldSource) toInt(v int64) int64 {
+	switch s.kind {
+	case valueDouble:
+		return int64(numeric.Int64ToFloat64(v))
+	case valueLong:
+		if s.scalingFactor > 0 {
+			return int64(float64(v) / s.scalingFactor)
+		}
+		return v
+	default:
+		if s.numericType == "date_nanos" {
+			return v
+		}
+		return v / 1e6
+	}
+}
+
+func (s *fieldSource) encodeMissing(v interface{}, prop meta.Property) ([]byte, error) {
+	if s.kind == valueKeyword {
+		str, err := zutils.ToString(v)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(str), nil
+	}
+	if s.kind == valueDate {
+		if str, ok := v.(string); ok {
+			t, err := zutils.ParseTime(str, prop.Format, prop.TimeZone)
+			if err != nil {
+				return nil, err
+			}
+			if s.numericType == "double" {
+				return encodeFloat(float64(t.UnixMilli())), nil
+			}
+			if s.numericType == "date_nanos" {
+				return encodeInt(t.UnixNano()), nil
+			}
+			return encodeInt(t.UnixMilli()), nil
+		}
+	}
+	f, err := zutils.ToFloat64(v)
+	if err != nil {
+		return nil, err
+	}
+	if s.numericType == "double" {
+		return encodeFloat(f), nil
+	}
+	return encodeInt(int64(f)), nil
+}
+
+func (s *fieldSource) decode(value []byte) interface{} {
+	if s.isBool {
+		return string(value) == "true"
+	}
+	if s.kind == valueKeyword {
+		return string(value)
+	}
+	i64, err := numeric.PrefixCoded(value).Int64()
+	if err != nil {
+		return nil
+	}
+	switch s.numericType {
+	case "double":
+		return numeric.Int64ToFloat64(i64)
+	case "date", "date_nanos":
+		if s.format == "" || s.format == "epoch_millis" && s.numericType == "date" {
+			return i64
+		}
+		t := time.UnixMilli(i64)
+		if s.numericType == "date_nanos" {
+			t = time.Unix(0, i64)
+		}
+		switch s.format {
+		case "epoch_millis":
+			return t.UnixMilli()
+		case "epoch_second":
+			return t.Unix()
+		default:
+			return t.UTC().Format(s.format)
+		}
+	default:
+		return i64
+	}
+}
+
+func reduceFloats(values []float64, mode string) float64 {
+	rv := values[0]
+	switch mode {
+	case "max":
+		for _, v := range values[1:] {
+			rv = math.Max(rv, v)
+		}
+	case "sum", "avg":
+		for _, v := range values[1:] {
+			rv += v
+		}
+		if mode == "avg" {
+			rv /= float64(len(values))
+		}
+	case "median":
+		gosort.Float64s(values)
+		n := len(values)
+		rv = values[n/2]
+		if n%2 == 0 {
+			rv = (values[n/2-1] + values[n/2]) / 2
+		}
+	default:
+		for _, v := range values[1:] {
+			rv = math.Min(rv, v)
+		}
+	}
+	return rv
+}
+
+func reduceInts(values []int64, mode string) int64 {
+	switch mode {
+	case "avg", "median":
+		floats := make([]float64, len(values))
+		for i, v := range values {
+			floats[i] = float64(v)
+		}
+		return int64(math.Round(reduceFloats(floats, mode)))
+	}
+	rv := values[0]
+	for _, v := range values[1:] {
+		switch mode {
+		case "max":
+			if v > rv {
+				rv = v
+			}
+		case "sum":
+			rv += v
+		default:
+			if v < rv {
+				rv = v
+			}
+		}
+	}
+	return rv
+}
+
+func encodeFloat(v float64) []byte {
+	return numeric.MustNewPrefixCodedInt64(numeric.Float64ToInt64(v), 0)
+}
+
+func encodeInt(v int64) []byte {
+	return numeric.MustNewPrefixCodedInt64(v, 0)
+}
+
+func decodeFloat(value []byte) interface{} {
+	i64, err := numeric.PrefixCoded(value).Int64()
+	if err != nil {
+		return nil
+	}
+	return numeric.Int64ToFloat64(i64)
+}
+
+func decodeInt(value []byte) interface{} {
+	i64, err := numeric.PrefixCoded(value).Int64()
+	if err != nil {
+		return nil
+	}
+	return i64
+}
+
+// isMissingTerm returns true if the value is the term of bluge for the missing values
+func isMissingTerm(value []byte) bool {
+	if len(value) == 1 && value[0] == 0x00 {
+		return true
+	}
+	return len(value) == 10 && bytes.Equal(value, bytes.Repeat([]byte{0xff}, 10))
+}
+
+// docNumberSource sorts by the index order of the documents
+type docNumberSource struct{}
+
+func (docNumberSource) Fields() []string {
+	return nil
+}
+
+func (docNumberSource) Value(match *search.DocumentMatch) []byte {
+	return encodeInt(int64(match.Number))
 }
@@ -60,7 +60,7 @@ func Response(source *meta.Source, data []byte) map[string]interface{} {
 		return ret
 	}
 
-	err := json.Unmarshal(data, &ret)
+	err := json.UnmarshalNumber(data, &ret)
 	if err != nil {
 		return nil
 	}
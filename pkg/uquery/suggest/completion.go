@@ -0,0 +1,620 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package suggest
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/analysis"
+
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	zincanalysis "github.com/zincsearch/zincsearch/pkg/uquery/analysis"
+	"github.com/zincsearch/zincsearch/pkg/uquery/source"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+)
+
+// The completion inputs are indexed as the terms of field, the term dictionary of each segment is an FST,
+// so the prefix lookup walks the FST. The term is encoded as
+//
+//	context \x1e analyzed input \x1f original input \x1f weight
+//
+// the context is empty for the input without context, or name \x1d value of a category context.
+const (
+	completionContextSep = "\x1d"
+	completionInputSep   = "\x1e"
+	completionValueSep   = "\x1f"
+
+	defaultMaxInputLength = 50
+)
+
+// CompletionInputs normalizes the value of completion field to a list of {"input": [], "weight": 1, "contexts": {}},
+// the value can be a string, an object or an array of them. The contexts with path are read from the flattened document.
+func CompletionInputs(field string, prop meta.Property, value interface{}, doc map[string]interface{}) ([]interface{}, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		items = []interface{}{value}
+	}
+
+	var inputs []interface{}
+	var entries []interface{}
+	for _, item := range items {
+		switch v := item.(type) {
+		case nil:
+		case string:
+			inputs = append(inputs, v)
+		case map[string]interface{}:
+			entry, err := completionEntry(field, v)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		default:
+			return nil, fmt.Errorf("field [%s] of type [completion] doesn't support value [%v]", field, v)
+		}
+	}
+	if len(inputs) > 0 {
+		entries = append(entries, map[string]interface{}{"input": inputs, "weight": 1, "contexts": map[string]interface{}{}})
+	}
+
+	// read the contexts from path
+	for _, c := range prop.Contexts {
+		if c.Path == "" {
+			continue
+		}
+		v, ok := doc[c.Path]
+		if !ok || v == nil {
+			continue
+		}
+		values, ok := v.([]interface{})
+		if !ok {
+			values = []interface{}{v}
+		}
+		for _, entry := range entries {
+			contexts := entry.(map[string]interface{})["contexts"].(map[string]interface{})
+			if _, ok := contexts[c.Name]; !ok {
+				contexts[c.Name] = values
+			}
+		}
+	}
+	return entries, nil
+}
+
+func completionEntry(field string, v map[string]interface{}) (map[string]interface{}, error) {
+	entry := map[string]interface{}{"weight": 1, "contexts": map[string]interface{}{}}
+	for k, v := range v {
+		switch k {
+		case "input":
+			switch input := v.(type) {
+			case string:
+				entry["input"] = []interface{}{input}
+			case []interface{}:
+				for _, s := range input {
+					if _, ok := s.(string); !ok {
+						return nil, fmt.Errorf("field [%s] completion input should be a string or an array of string", field)
+					}
+				}
+				entry["input"] = input
+			default:
+				return nil, fmt.Errorf("field [%s] completion input should be a string or an array of string", field)
+			}
+		case "weight":
+			weight, err := zutils.ToInt(v)
+			if err != nil || weight < 0 {
+				return nil, fmt.Errorf("field [%s] completion weight should be a non-negative integer", field)
+			}
+			entry["weight"] = weight
+		case "contexts":
+			contexts, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("field [%s] completion contexts should be an object", field)
+			}
+			entry["contexts"] = contexts
+		default:
+			return nil, fmt.Errorf("field [%s] completion doesn't support option [%s]", field, k)
+		}
+	}
+	if _, ok := entry["input"]; !ok {
+		return nil, fmt.Errorf("field [%s] completion input is required", field)
+	}
+	return entry, nil
+}
+
+// CompletionTerms returns the terms of a completion entry normalized by CompletionInputs
+func CompletionTerms(prop meta.Property, analyzers map[string]*analysis.Analyzer, entry interface{}) ([]string, error) {
+	m, ok := entry.(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	analyzer, err := completionAnalyzer(analyzers, prop.Analyzer)
+	if err != nil {
+		return nil, err
+	}
+	weight, _ := zutils.ToInt(m["weight"])
+	prefixes := []string{""}
+	if contexts, ok := m["contexts"].(map[string]interface{}); ok {
+		for _, c := range prop.Contexts {
+			values, ok := contexts[c.Name].([]interface{})
+			if !ok {
+				if v, ok := contexts[c.Name]; ok && v != nil {
+					values = []interface{}{v}
+				}
+			}
+			for _, v := range values {
+				s, err := zutils.ToString(v)
+				if err != nil || s == "" {
+					continue
+				}
+				prefixes = append(prefixes, c.Name+completionContextSep+s)
+			}
+		}
+	}
+
+	inputs, _ := m["input"].([]interface{})
+	terms := make([]string, 0, len(inputs)*len(prefixes))
+	for _, v := range inputs {
+		input, _ := v.(string)
+		analyzed := analyzeCompletion(analyzer, input, prop.MaxInputLength)
+		if analyzed == "" {
+			continue
+		}
+		for _, prefix := range prefixes {
+			terms = append(terms, prefix+completionInputSep+analyzed+completionValueSep+input+completionValueSep+strconv.Itoa(weight))
+		}
+	}
+	return terms, nil
+}
+
+// completionAnalyzer returns the analyzer of completion field, the default is simple analyzer
+func completionAnalyzer(analyzers map[string]*analysis.Analyzer, name string) (*analysis.Analyzer, error) {
+	if name == "" {
+		name = "simple"
+	}
+	return zincanalysis.QueryAnalyzer(analyzers, name)
+}
+
+// analyzeCompletion returns the tokens of input joined by space, it is truncated to maxLength runes
+func analyzeCompletion(analyzer *analysis.Analyzer, input string, maxLength int) string {
+	if maxLength <= 0 {
+		maxLength = defaultMaxInputLength
+	}
+	tokens := analyze(analyzer, input)
+	terms := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		terms = append(terms, strings.Map(func(r rune) rune {
+			if r < 0x20 {
+				return -1
+			}
+			return r
+		}, t.term))
+	}
+	analyzed := strings.Join(terms, " ")
+	if utf8.RuneCountInString(analyzed) > maxLength {
+		analyzed = string([]rune(analyzed)[:maxLength])
+	}
+	return analyzed
+}
+
+// decodeCompletion returns the analyzed input, original input and weight of the term after context
+func decodeCompletion(term string) (string, string, int, bool) {
+	i := strings.Index(term, completionInputSep)
+	if i < 0 {
+		return "", "", 0, false
+	}
+	term = term[i+1:]
+	i = strings.Index(term, completionValueSep)
+	j := strings.LastIndex(term, completionValueSep)
+	if i < 0 || i == j {
+		return "", "", 0, false
+	}
+	weight, err := strconv.Atoi(term[j+1:])
+	if err != nil {
+		return "", "", 0, false
+	}
+	return term[:i], term[i+1 : j], weight, true
+}
+
+// completionContext filters the suggestions by a category context
+type completionContext struct {
+	name   string
+	value  string
+	boost  float64
+	prefix bool
+}
+
+// completionSuggester suggests the documents whose completion input starts with the prefix
+type completionSuggester struct {
+	text           string
+	field          string
+	analyzer       *analysis.Analyzer
+	maxInputLength int
+	regex          *regexp.Regexp
+	size           int
+	skipDuplicates bool
+	fuzziness      int
+	fuzzyAuto      bool
+	fuzzyPrefix    int
+	fuzzyMinLength int
+	contexts       []completionContext
+	source         *meta.Source
+}
+
+// CompletionSuggester parses the completion suggester
+//
+//	{"field": "suggest", "size": 5, "skip_duplicates": true, "fuzzy": {"fuzziness": "AUTO"}, "contexts": {"genre": ["rock"]}}
+func CompletionSuggester(text string, regex bool, options map[string]interface{}, q *meta.ZincQuery, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (Suggester, error) {
+	field, prop, err := parseField("completion", options, mappings)
+	if err != nil {
+		return nil, err
+	}
+	if prop.Type != "completion" {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("Field [%s] is not a completion suggest field", field))
+	}
+	s := &completionSuggester{
+		text:           text,
+		field:          field,
+		maxInputLength: prop.MaxInputLength,
+		size:           5,
+	}
+	if src, ok := q.Source.(*meta.Source); ok {
+		s.source = src
+	}
+	analyzerName := prop.SearchAnalyzer
+	if analyzerName == "" {
+		analyzerName = prop.Analyzer
+	}
+	if s.analyzer, err = completionAnalyzer(analyzers, analyzerName); err != nil {
+		return nil, err
+	}
+	if regex {
+		if s.regex, err = regexp.Compile("^(?:" + text + ")"); err != nil {
+			return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[completion] invalid regex [%s]: %s", text, err.Error()))
+		}
+	}
+	for k, v := range options {
+		k = strings.ToLower(k)
+		switch k {
+		case "field":
+			// handled
+		case "size":
+			s.size, err = parsePositiveInt("completion", k, v)
+		case "skip_duplicates":
+			s.skipDuplicates, err = zutils.ToBool(v)
+		case "fuzzy":
+			err = s.parseFuzzy(v)
+		case "contexts":
+			err = s.parseContexts(v, prop)
+		default:
+			err = errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[completion] unknown field [%s]", k))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if s.regex != nil && (s.fuzziness > 0 || s.fuzzyAuto) {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[completion] regex doesn't support fuzzy")
+	}
+	return s, nil
+}
+
+func (s *completionSuggester) parseFuzzy(v interface{}) error {
+	s.fuzziness = 0
+	s.fuzzyAuto = true
+	s.fuzzyPrefix = 1
+	s.fuzzyMinLength = 3
+	if b, ok := v.(bool); ok {
+		if !b {
+			s.fuzzyAuto = false
+		}
+		return nil
+	}
+	params, ok := v.(map[string]interface{})
+	if !ok {
+		return errors.New(errors.ErrorTypeParsingException, "[completion] fuzzy should be a boolean or an object")
+	}
+	var err error
+	for k, v := range params {
+		switch strings.ToLower(k) {
+		case "fuzziness":
+			if str, ok := v.(string); ok && strings.EqualFold(str, "auto") {
+				s.fuzzyAuto = true
+				continue
+			}
+			s.fuzzyAuto = false
+			if s.fuzziness, err = parsePositiveInt("completion", "fuzziness", v); err != nil {
+				return err
+			}
+			if s.fuzziness > 2 {
+				return errors.New(errors.ErrorTypeIllegalArgumentException, "[completion] fuzziness must be between 0 and 2")
+			}
+		case "prefix_length":
+			if s.fuzzyPrefix, err = parsePositiveInt("completion", "prefix_length", v); err != nil {
+				return err
+			}
+		case "min_length":
+			if s.fuzzyMinLength, err = parsePositiveInt("completion", "min_length", v); err != nil {
+				return err
+			}
+		case "transpositions", "unicode_aware":
+			// transpositions are always allowed and the distance is measured by runes
+		default:
+			return errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[completion] fuzzy doesn't support option [%s]", k))
+		}
+	}
+	return nil
+}
+
+// parseContexts parses the category contexts, {"genre": ["rock", {"context": "pop", "boost": 2, "prefix": true}]}
+func (s *completionSuggester) parseContexts(v interface{}, prop meta.Property) error {
+	params, ok := v.(map[string]interface{})
+	if !ok {
+		return errors.New(errors.ErrorTypeParsingException, "[completion] contexts should be an object")
+	}
+	for name, v := range params {
+		found := false
+		for _, c := range prop.Contexts {
+			if c.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[completion] unknown context name [%s], must be one of the contexts of field [%s]", name, s.field))
+		}
+		items, ok := v.([]interface{})
+		if !ok {
+			items = []interface{}{v}
+		}
+		for _, item := range items {
+			c := completionContext{name: name, boost: 1}
+			switch item := item.(type) {
+			case map[string]interface{}:
+				var err error
+				for k, v := range item {
+					switch strings.ToLower(k) {
+					case "context":
+						c.value, err = zutils.ToString(v)
+					case "boost":
+						c.boost, err = parseFloat("completion", "boost", v)
+					case "prefix":
+						c.prefix, err = zutils.ToBool(v)
+					default:
+						err = errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[completion] context doesn't support option [%s]", k))
+					}
+					if err != nil {
+						return err
+					}
+				}
+			default:
+				value, err := zutils.ToString(item)
+				if err != nil {
+					return errors.New(errors.ErrorTypeParsingException, "[completion] context should be a string or an object")
+				}
+				c.value = value
+			}
+			s.contexts = append(s.contexts, c)
+		}
+	}
+	return nil
+}
+
+// completionCandidate is a term matched the prefix
+type completionCandidate struct {
+	term    string
+	input   string
+	score   float64
+	context *completionContext
+}
+
+func (s *completionSuggester) Suggest(readers []*bluge.Reader) ([]meta.SuggestEntry, error) {
+	entry := meta.SuggestEntry{Text: s.text, Offset: 0, Length: len(s.text), Options: []meta.SuggestOption{}}
+	if s.size == 0 {
+		return []meta.SuggestEntry{entry}, nil
+	}
+
+	var options []meta.SuggestOption
+	for _, r := range readers {
+		candidates, err := s.candidates(r)
+		if err != nil {
+			return nil, err
+		}
+		opts, err := s.documents(r, candidates)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, opts...)
+	}
+
+	sort.SliceStable(options, func(i, j int) bool {
+		if options[i].DocScore != options[j].DocScore {
+			return options[i].DocScore > options[j].DocScore
+		}
+		return options[i].Text < options[j].Text
+	})
+	docs := make(map[string]struct{}, len(options))
+	texts := make(map[string]struct{}, len(options))
+	for _, o := range options {
+		if len(entry.Options) >= s.size {
+			break
+		}
+		key := o.Index + "/" + o.ID
+		if _, ok := docs[key]; ok {
+			continue
+		}
+		if _, ok := texts[o.Text]; ok && s.skipDuplicates {
+			continue
+		}
+		docs[key] = struct{}{}
+		texts[o.Text] = struct{}{}
+		entry.Options = append(entry.Options, o)
+	}
+	return []meta.SuggestEntry{entry}, nil
+}
+
+// candidates returns the terms matched the prefix in the reader, sorted by score
+func (s *completionSuggester) candidates(r *bluge.Reader) ([]completionCandidate, error) {
+	prefix := analyzeCompletion(s.analyzer, s.text, s.maxInputLength)
+	if s.regex != nil {
+		prefix = ""
+	}
+	prefixRunes := []rune(prefix)
+	fuzziness := s.fuzziness
+	if s.fuzzyAuto {
+		switch n := len(prefixRunes); {
+		case n < 3:
+			fuzziness = 0
+		case n < 6:
+			fuzziness = 1
+		default:
+			fuzziness = 2
+		}
+	}
+	if len(prefixRunes) < s.fuzzyMinLength {
+		fuzziness = 0
+	}
+	// the range of dictionary is narrowed by the exact prefix
+	rangePrefix := prefix
+	if fuzziness > 0 {
+		n := s.fuzzyPrefix
+		if n > len(prefixRunes) {
+			n = len(prefixRunes)
+		}
+		rangePrefix = string(prefixRunes[:n])
+	}
+
+	contexts := s.contexts
+	if len(contexts) == 0 {
+		contexts = []completionContext{{boost: 1}}
+	}
+	var candidates []completionCandidate
+	for i := range contexts {
+		c := &contexts[i]
+		var start string
+		switch {
+		case c.name == "":
+			start = completionInputSep + rangePrefix
+		case c.prefix:
+			start = c.name + completionContextSep + c.value
+		default:
+			start = c.name + completionContextSep + c.value + completionInputSep + rangePrefix
+		}
+		err := visitPrefix([]*bluge.Reader{r}, s.field, start, func(term string, _ uint64) {
+			analyzed, input, weight, ok := decodeCompletion(term)
+			if !ok {
+				return
+			}
+			switch {
+			case s.regex != nil:
+				if !s.regex.MatchString(analyzed) {
+					return
+				}
+			case fuzziness > 0:
+				if !strings.HasPrefix(analyzed, rangePrefix) || prefixEditDistance(prefixRunes, []rune(analyzed), fuzziness) > fuzziness {
+					return
+				}
+			default:
+				if !strings.HasPrefix(analyzed, prefix) {
+					return
+				}
+			}
+			candidate := completionCandidate{term: term, input: input, score: float64(weight) * c.boost}
+			if c.name != "" {
+				candidate.context = c
+			}
+			candidates = append(candidates, candidate)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].input < candidates[j].input
+	})
+	return candidates, nil
+}
+
+// documents returns the top documents of candidates in the reader, a document is suggested once
+func (s *completionSuggester) documents(r *bluge.Reader, candidates []completionCandidate) ([]meta.SuggestOption, error) {
+	var options []meta.SuggestOption
+	docs := make(map[string]struct{})
+	texts := make(map[string]struct{})
+	for _, c := range candidates {
+		if len(options) >= s.size {
+			break
+		}
+		if _, ok := texts[c.input]; ok && s.skipDuplicates {
+			continue
+		}
+		query := bluge.NewTermQuery(c.term).SetField(s.field)
+		dmi, err := r.Search(context.Background(), bluge.NewTopNSearch(s.size, query))
+		if err != nil {
+			return nil, err
+		}
+		next, err := dmi.Next()
+		for err == nil && next != nil && len(options) < s.size {
+			option := meta.SuggestOption{Text: c.input, DocScore: c.score}
+			err = next.VisitStoredFields(func(field string, value []byte) bool {
+				switch field {
+				case "_id":
+					option.ID = string(value)
+				case "_index":
+					option.Index = string(value)
+				case "_source":
+					if s.source != nil {
+						option.Source = source.Response(s.source, value)
+					} else {
+						var data map[string]interface{}
+						_ = json.Unmarshal(value, &data)
+						option.Source = data
+					}
+				}
+				return true
+			})
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := docs[option.ID]; !ok {
+				docs[option.ID] = struct{}{}
+				texts[c.input] = struct{}{}
+				if c.context != nil {
+					// the value of prefix context is the indexed one
+					value := c.term[len(c.context.name)+1 : strings.Index(c.term, completionInputSep)]
+					option.Contexts = map[string][]string{c.context.name: {value}}
+				}
+				options = append(options, option)
+				if s.skipDuplicates {
+					break
+				}
+			}
+			next, err = dmi.Next()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return options, nil
+}
@@ -0,0 +1,146 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package suggest
+
+import (
+	"github.com/blugelabs/bluge"
+)
+
+// visitTerms visits the terms of field in the range [start, end) of each reader, the same term is visited once per reader
+func visitTerms(readers []*bluge.Reader, field string, start, end []byte, visitor func(term string, freq uint64)) error {
+	for _, r := range readers {
+		dict, err := r.DictionaryIterator(field, nil, start, end)
+		if err != nil {
+			return err
+		}
+		entry, err := dict.Next()
+		for err == nil && entry != nil {
+			visitor(entry.Term(), entry.Count())
+			entry, err = dict.Next()
+		}
+		if cerr := dict.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// visitPrefix visits the terms of field starting with prefix
+func visitPrefix(readers []*bluge.Reader, field, prefix string, visitor func(term string, freq uint64)) error {
+	if prefix == "" {
+		return visitTerms(readers, field, nil, nil, visitor)
+	}
+	return visitTerms(readers, field, []byte(prefix), prefixEnd([]byte(prefix)), visitor)
+}
+
+// docFreq returns the number of documents containing the term in all readers
+func docFreq(readers []*bluge.Reader, field, term string) (uint64, error) {
+	var freq uint64
+	start := []byte(term)
+	end := append([]byte(term), 0)
+	err := visitTerms(readers, field, start, end, func(t string, n uint64) {
+		if t == term {
+			freq += n
+		}
+	})
+	return freq, err
+}
+
+// prefixEnd returns the first term greater than all terms starting with prefix, nil means no upper bound
+func prefixEnd(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+	for j := len(end) - 1; j >= 0; j-- {
+		end[j]++
+		if end[j] != 0 {
+			return end[:j+1]
+		}
+	}
+	return nil
+}
+
+// editDistance returns the optimal string alignment distance of runes,
+// it returns max+1 once the distance is known to exceed max
+func editDistance(a, b []rune, max int) int {
+	if abs(len(a)-len(b)) > max {
+		return max + 1
+	}
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d := minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d = minInt(d, prev2[j-2]+1)
+			}
+			cur[j] = d
+			if d < rowMin {
+				rowMin = d
+			}
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	if prev[len(b)] > max {
+		return max + 1
+	}
+	return prev[len(b)]
+}
+
+// prefixEditDistance returns the minimal edit distance between prefix and any prefix of s
+func prefixEditDistance(prefix, s []rune, max int) int {
+	best := max + 1
+	for n := len(prefix) - max; n <= len(prefix)+max && n <= len(s); n++ {
+		if n < 0 {
+			continue
+		}
+		if d := editDistance(prefix, s[:n], max); d < best {
+			best = d
+		}
+	}
+	return best
+}
+
+func minInt(v int, vs ...int) int {
+	for _, n := range vs {
+		if n < v {
+			v = n
+		}
+	}
+	return v
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
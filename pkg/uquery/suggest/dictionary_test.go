@@ -0,0 +1,64 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package suggest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		max  int
+		want int
+	}{
+		{a: "string", b: "string", max: 2, want: 0},
+		{a: "tring", b: "string", max: 2, want: 1},
+		{a: "sting", b: "string", max: 2, want: 1},
+		{a: "strnig", b: "string", max: 2, want: 1},
+		{a: "stirng", b: "string", max: 1, want: 1},
+		{a: "abc", b: "string", max: 2, want: 3},
+		{a: "café", b: "cafe", max: 2, want: 1},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, editDistance([]rune(tt.a), []rune(tt.b), tt.max), "%s %s", tt.a, tt.b)
+	}
+}
+
+func TestPrefixEditDistance(t *testing.T) {
+	assert.Equal(t, 0, prefixEditDistance([]rune("nir"), []rune("nirvana"), 1))
+	assert.Equal(t, 1, prefixEditDistance([]rune("nurv"), []rune("nirvana"), 1))
+	assert.Equal(t, 2, prefixEditDistance([]rune("nurv"), []rune("nine inch nails"), 1))
+}
+
+func TestPrefixEnd(t *testing.T) {
+	assert.Equal(t, []byte("ab"), prefixEnd([]byte("aa")))
+	assert.Equal(t, []byte("b"), prefixEnd([]byte{'a', 0xff}))
+	assert.Nil(t, prefixEnd([]byte{0xff}))
+}
+
+func TestDecodeCompletion(t *testing.T) {
+	analyzed, input, weight, ok := decodeCompletion("genre" + completionContextSep + "rock" + completionInputSep + "nirvana" + completionValueSep + "Nirvana" + completionValueSep + "34")
+	assert.True(t, ok)
+	assert.Equal(t, "nirvana", analyzed)
+	assert.Equal(t, "Nirvana", input)
+	assert.Equal(t, 34, weight)
+
+	_, _, _, ok = decodeCompletion("nirvana")
+	assert.False(t, ok)
+}
@@ -0,0 +1,514 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package suggest
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/analysis"
+
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+// maxPhraseCorrections limits the corrections enumerated by phrase suggester
+const maxPhraseCorrections = 10000
+
+// phraseSuggester corrects the whole text by the n-gram language model of the field,
+// the n-grams are the shingles indexed in the field joined by separator.
+type phraseSuggester struct {
+	text                    string
+	field                   string
+	analyzer                *analysis.Analyzer
+	size                    int
+	gramSize                int
+	realWordErrorLikelihood float64
+	confidence              float64
+	maxErrors               float64
+	separator               string
+	generators              []*generator
+	smoothing               smoothing
+	preTag, postTag         string
+}
+
+// PhraseSuggester parses the phrase suggester
+//
+//	{"field": "title.trigram", "size": 1, "gram_size": 3, "direct_generator": [{"field": "title.trigram", "suggest_mode": "always"}],
+//	 "highlight": {"pre_tag": "<em>", "post_tag": "</em>"}, "smoothing": {"stupid_backoff": {"discount": 0.4}}}
+func PhraseSuggester(text string, options map[string]interface{}, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (Suggester, error) {
+	field, _, err := parseField("phrase", options, mappings)
+	if err != nil {
+		return nil, err
+	}
+	s := &phraseSuggester{
+		text:                    text,
+		field:                   field,
+		size:                    5,
+		gramSize:                2,
+		realWordErrorLikelihood: 0.95,
+		confidence:              1.0,
+		maxErrors:               1.0,
+		separator:               " ",
+		smoothing:               &stupidBackoff{discount: 0.4},
+	}
+	if s.analyzer, err = parseAnalyzer(options["analyzer"], field, mappings, analyzers); err != nil {
+		return nil, err
+	}
+	for k, v := range options {
+		k = strings.ToLower(k)
+		switch k {
+		case "field", "analyzer":
+			// handled
+		case "size":
+			s.size, err = parsePositiveInt("phrase", k, v)
+		case "gram_size":
+			s.gramSize, err = parsePositiveInt("phrase", k, v)
+			if err == nil && s.gramSize < 1 {
+				err = errors.New(errors.ErrorTypeIllegalArgumentException, "[phrase] gram_size must be >= 1")
+			}
+		case "real_word_error_likelihood":
+			s.realWordErrorLikelihood, err = parseFloat("phrase", k, v)
+			if err == nil && (s.realWordErrorLikelihood <= 0 || s.realWordErrorLikelihood > 1) {
+				err = errors.New(errors.ErrorTypeIllegalArgumentException, "[phrase] real_word_error_likelihood must be in (0, 1]")
+			}
+		case "confidence":
+			s.confidence, err = parseFloat("phrase", k, v)
+			if err == nil && s.confidence < 0 {
+				err = errors.New(errors.ErrorTypeIllegalArgumentException, "[phrase] confidence must be >= 0")
+			}
+		case "max_errors":
+			s.maxErrors, err = parseFloat("phrase", k, v)
+			if err == nil && s.maxErrors <= 0 {
+				err = errors.New(errors.ErrorTypeIllegalArgumentException, "[phrase] max_errors must be > 0")
+			}
+		case "separator":
+			s.separator, _ = zutils.ToString(v)
+		case "highlight":
+			err = s.parseHighlight(v)
+		case "smoothing":
+			s.smoothing, err = parseSmoothing(v)
+		case "direct_generator":
+			err = s.parseGenerators(v, mappings)
+		case "shard_size", "token_limit", "force_unigrams":
+			// accepted for compatibility
+		default:
+			err = errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[phrase] unknown field [%s]", k))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(s.generators) == 0 {
+		s.generators = []*generator{newGenerator(field, suggestModeAlways)}
+	}
+	return s, nil
+}
+
+func (s *phraseSuggester) parseHighlight(v interface{}) error {
+	params, ok := v.(map[string]interface{})
+	if !ok {
+		return errors.New(errors.ErrorTypeParsingException, "[phrase] highlight should be an object")
+	}
+	s.preTag, _ = zutils.ToString(params["pre_tag"])
+	s.postTag, _ = zutils.ToString(params["post_tag"])
+	if s.preTag == "" || s.postTag == "" {
+		return errors.New(errors.ErrorTypeIllegalArgumentException, "[phrase] highlight requires both pre_tag and post_tag")
+	}
+	return nil
+}
+
+func (s *phraseSuggester) parseGenerators(v interface{}, mappings *meta.Mappings) error {
+	items, ok := v.([]interface{})
+	if !ok {
+		items = []interface{}{v}
+	}
+	for _, item := range items {
+		params, ok := item.(map[string]interface{})
+		if !ok {
+			return errors.New(errors.ErrorTypeParsingException, "[direct_generator] should be an object")
+		}
+		field, _, err := parseField("direct_generator", params, mappings)
+		if err != nil {
+			return err
+		}
+		g := newGenerator(field, suggestModeMissing)
+		for k, v := range params {
+			k = strings.ToLower(k)
+			if k == "field" {
+				continue
+			}
+			ok, err := g.parse("direct_generator", k, v)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[direct_generator] unknown field [%s]", k))
+			}
+		}
+		s.generators = append(s.generators, g)
+	}
+	return nil
+}
+
+// correction is a candidate term of a token with the log probability of channel model,
+// it is the real word error likelihood for the token itself and the similarity for the corrections
+type correction struct {
+	term    string
+	channel float64
+	changed bool
+}
+
+func (s *phraseSuggester) Suggest(readers []*bluge.Reader) ([]meta.SuggestEntry, error) {
+	entry := meta.SuggestEntry{Text: s.text, Offset: 0, Length: len(s.text), Options: []meta.SuggestOption{}}
+	tokens := analyze(s.analyzer, s.text)
+	if len(tokens) == 0 || s.size == 0 {
+		return []meta.SuggestEntry{entry}, nil
+	}
+
+	lm, err := newLanguageModel(readers, s.field, s.separator, s.smoothing)
+	if err != nil {
+		return nil, err
+	}
+
+	// candidates of each token, the first one is the token itself
+	corrections := make([][]correction, len(tokens))
+	for i, t := range tokens {
+		// the token absent in the index is likely an error
+		freq, err := lm.frequency([]string{t.term})
+		if err != nil {
+			return nil, err
+		}
+		likelihood := s.realWordErrorLikelihood
+		if freq == 0 {
+			likelihood = 1 - likelihood
+		}
+		corrections[i] = []correction{{term: t.term, channel: math.Log(likelihood)}}
+		seen := map[string]struct{}{t.term: {}}
+		for _, g := range s.generators {
+			_, candidates, err := g.candidates(readers, t.term)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range candidates {
+				if _, ok := seen[c.term]; ok {
+					continue
+				}
+				seen[c.term] = struct{}{}
+				corrections[i] = append(corrections[i], correction{
+					term:    c.term,
+					channel: math.Log(c.score),
+					changed: true,
+				})
+			}
+		}
+	}
+
+	maxErrors := int(s.maxErrors)
+	if s.maxErrors < 1 {
+		maxErrors = int(math.Ceil(s.maxErrors * float64(len(tokens))))
+	}
+	if maxErrors < 1 {
+		maxErrors = 1
+	}
+
+	type phrase struct {
+		terms []correction
+		score float64
+	}
+	var phrases []phrase
+	var original float64
+	path := make([]correction, len(tokens))
+	var walk func(i, errs int) error
+	walk = func(i, errs int) error {
+		if len(phrases) >= maxPhraseCorrections {
+			return nil
+		}
+		if i == len(tokens) {
+			score, err := s.score(lm, path)
+			if err != nil {
+				return err
+			}
+			if errs == 0 {
+				original = score
+				return nil
+			}
+			phrases = append(phrases, phrase{terms: append([]correction{}, path...), score: score})
+			return nil
+		}
+		for _, c := range corrections[i] {
+			next := errs
+			if c.changed {
+				if next++; next > maxErrors {
+					continue
+				}
+			}
+			path[i] = c
+			if err := walk(i+1, next); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(0, 0); err != nil {
+		return nil, err
+	}
+
+	threshold := math.Inf(-1)
+	if s.confidence > 0 {
+		threshold = original + math.Log(s.confidence)
+	}
+	sort.SliceStable(phrases, func(i, j int) bool { return phrases[i].score > phrases[j].score })
+	for _, p := range phrases {
+		if len(entry.Options) >= s.size {
+			break
+		}
+		if p.score <= threshold {
+			continue
+		}
+		terms := make([]string, len(p.terms))
+		highlighted := make([]string, len(p.terms))
+		for i, c := range p.terms {
+			terms[i] = c.term
+			highlighted[i] = c.term
+			if c.changed && s.preTag != "" {
+				highlighted[i] = s.preTag + c.term + s.postTag
+			}
+		}
+		option := meta.SuggestOption{Text: strings.Join(terms, " "), Score: math.Exp(p.score)}
+		if s.preTag != "" {
+			option.Highlighted = strings.Join(highlighted, " ")
+		}
+		entry.Options = append(entry.Options, option)
+	}
+	return []meta.SuggestEntry{entry}, nil
+}
+
+// score returns the log probability of the phrase by the channel model and language model
+func (s *phraseSuggester) score(lm *languageModel, terms []correction) (float64, error) {
+	var score float64
+	words := make([]string, len(terms))
+	for i, c := range terms {
+		words[i] = c.term
+		start := i - s.gramSize + 1
+		if start < 0 {
+			start = 0
+		}
+		p, err := lm.probability(words[start : i+1])
+		if err != nil {
+			return 0, err
+		}
+		score += c.channel + math.Log(p)
+	}
+	return score, nil
+}
+
+// languageModel counts the n-grams of field from the term dictionary
+type languageModel struct {
+	readers   []*bluge.Reader
+	field     string
+	separator string
+	smoothing smoothing
+	total     float64 // the sum of unigram frequencies
+	vocabSize float64
+	freqs     map[string]float64
+}
+
+func newLanguageModel(readers []*bluge.Reader, field, separator string, smoothing smoothing) (*languageModel, error) {
+	lm := &languageModel{
+		readers:   readers,
+		field:     field,
+		separator: separator,
+		smoothing: smoothing,
+		freqs:     make(map[string]float64),
+	}
+	vocab := make(map[string]struct{})
+	err := visitTerms(readers, field, nil, nil, func(term string, freq uint64) {
+		if separator != "" && strings.Contains(term, separator) {
+			return
+		}
+		vocab[term] = struct{}{}
+		lm.total += float64(freq)
+	})
+	lm.vocabSize = float64(len(vocab))
+	return lm, err
+}
+
+func (lm *languageModel) frequency(words []string) (float64, error) {
+	term := strings.Join(words, lm.separator)
+	if f, ok := lm.freqs[term]; ok {
+		return f, nil
+	}
+	n, err := docFreq(lm.readers, lm.field, term)
+	if err != nil {
+		return 0, err
+	}
+	lm.freqs[term] = float64(n)
+	return float64(n), nil
+}
+
+// probability returns the probability of the last word given the previous words
+func (lm *languageModel) probability(words []string) (float64, error) {
+	return lm.smoothing.probability(lm, words)
+}
+
+// unigram returns the probability of the word with add-one smoothing
+func (lm *languageModel) unigram(word string) (float64, error) {
+	f, err := lm.frequency([]string{word})
+	if err != nil {
+		return 0, err
+	}
+	return (f + 1) / (lm.total + lm.vocabSize), nil
+}
+
+// conditional returns the frequency of words and the frequency of the context words
+func (lm *languageModel) conditional(words []string) (float64, float64, error) {
+	f, err := lm.frequency(words)
+	if err != nil {
+		return 0, 0, err
+	}
+	ctx, err := lm.frequency(words[:len(words)-1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return f, ctx, nil
+}
+
+// smoothing estimates the probability of the n-gram which is rare or absent in the index
+type smoothing interface {
+	probability(lm *languageModel, words []string) (float64, error)
+}
+
+func parseSmoothing(v interface{}) (smoothing, error) {
+	params, ok := v.(map[string]interface{})
+	if !ok || len(params) != 1 {
+		return nil, errors.New(errors.ErrorTypeParsingException, "[phrase] smoothing should be an object with one model")
+	}
+	for name, v := range params {
+		options, _ := v.(map[string]interface{})
+		switch strings.ToLower(name) {
+		case "stupid_backoff":
+			m := &stupidBackoff{discount: 0.4}
+			if d, ok := options["discount"]; ok {
+				f, err := parseFloat("stupid_backoff", "discount", d)
+				if err != nil {
+					return nil, err
+				}
+				m.discount = f
+			}
+			return m, nil
+		case "laplace":
+			m := &laplace{alpha: 0.5}
+			if a, ok := options["alpha"]; ok {
+				f, err := parseFloat("laplace", "alpha", a)
+				if err != nil {
+					return nil, err
+				}
+				m.alpha = f
+			}
+			return m, nil
+		case "linear_interpolation":
+			m := &linearInterpolation{}
+			var err error
+			if m.trigram, err = parseFloat("linear_interpolation", "trigram_lambda", options["trigram_lambda"]); err != nil {
+				return nil, err
+			}
+			if m.bigram, err = parseFloat("linear_interpolation", "bigram_lambda", options["bigram_lambda"]); err != nil {
+				return nil, err
+			}
+			if m.unigram, err = parseFloat("linear_interpolation", "unigram_lambda", options["unigram_lambda"]); err != nil {
+				return nil, err
+			}
+			if math.Abs(m.trigram+m.bigram+m.unigram-1) > 0.001 {
+				return nil, errors.New(errors.ErrorTypeIllegalArgumentException, "[linear_interpolation] lambdas must sum to 1")
+			}
+			return m, nil
+		default:
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[phrase] doesn't support smoothing model [%s]", name))
+		}
+	}
+	return nil, nil
+}
+
+// stupidBackoff backs off to the lower order n-gram with a discount if the n-gram is absent
+type stupidBackoff struct {
+	discount float64
+}
+
+func (m *stupidBackoff) probability(lm *languageModel, words []string) (float64, error) {
+	if len(words) == 1 {
+		return lm.unigram(words[0])
+	}
+	f, ctx, err := lm.conditional(words)
+	if err != nil {
+		return 0, err
+	}
+	if f > 0 && ctx > 0 {
+		return f / ctx, nil
+	}
+	p, err := m.probability(lm, words[1:])
+	return m.discount * p, err
+}
+
+// laplace adds alpha to all the counts
+type laplace struct {
+	alpha float64
+}
+
+func (m *laplace) probability(lm *languageModel, words []string) (float64, error) {
+	if len(words) == 1 {
+		f, err := lm.frequency(words)
+		return (f + m.alpha) / (lm.total + m.alpha*lm.vocabSize), err
+	}
+	f, ctx, err := lm.conditional(words)
+	return (f + m.alpha) / (ctx + m.alpha*lm.vocabSize), err
+}
+
+// linearInterpolation sums the weighted probabilities of trigram, bigram and unigram
+type linearInterpolation struct {
+	trigram, bigram, unigram float64
+}
+
+func (m *linearInterpolation) probability(lm *languageModel, words []string) (float64, error) {
+	p, err := lm.unigram(words[len(words)-1])
+	if err != nil || len(words) == 1 {
+		return p, err
+	}
+	score := m.unigram * p
+	if len(words) >= 2 {
+		f, ctx, err := lm.conditional(words[len(words)-2:])
+		if err != nil {
+			return 0, err
+		}
+		if ctx > 0 {
+			score += m.bigram * f / ctx
+		}
+	}
+	if len(words) >= 3 {
+		f, ctx, err := lm.conditional(words[len(words)-3:])
+		if err != nil {
+			return 0, err
+		}
+		if ctx > 0 {
+			score += m.trigram * f / ctx
+		}
+	}
+	return score, nil
+}
@@ -0,0 +1,207 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+// Package suggest implements the term, phrase and completion suggesters of search request.
+package suggest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/analysis"
+	"github.com/blugelabs/bluge/analysis/analyzer"
+
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	zincanalysis "github.com/zincsearch/zincsearch/pkg/uquery/analysis"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+// Suggester suggests the terms, phrases or documents by the readers of index
+type Suggester interface {
+	Suggest(readers []*bluge.Reader) ([]meta.SuggestEntry, error)
+}
+
+// Request parses the suggest section of the request, it returns the suggesters by name
+//
+//	{"text": "global text", "my-suggest": {"text": "tring out", "term": {"field": "message"}}}
+func Request(q *meta.ZincQuery, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (map[string]Suggester, error) {
+	if len(q.Suggest) == 0 {
+		return nil, nil
+	}
+
+	var globalText string
+	if v, ok := q.Suggest["text"]; ok {
+		var err error
+		if globalText, err = zutils.ToString(v); err != nil {
+			return nil, errors.New(errors.ErrorTypeParsingException, "[suggest] text should be a string")
+		}
+	}
+
+	suggesters := make(map[string]Suggester, len(q.Suggest))
+	for name, v := range q.Suggest {
+		if name == "text" {
+			continue
+		}
+		params, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[suggest] suggestion [%s] should be an object", name))
+		}
+		s, err := parseSuggester(name, globalText, params, q, mappings, analyzers)
+		if err != nil {
+			return nil, err
+		}
+		suggesters[name] = s
+	}
+	return suggesters, nil
+}
+
+func parseSuggester(name, text string, params map[string]interface{}, q *meta.ZincQuery, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (Suggester, error) {
+	var kind string
+	var options map[string]interface{}
+	var regex bool
+	for k, v := range params {
+		k = strings.ToLower(k)
+		switch k {
+		case "text", "prefix", "regex":
+			s, err := zutils.ToString(v)
+			if err != nil {
+				return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[suggest] suggestion [%s] %s should be a string", name, k))
+			}
+			text = s
+			regex = k == "regex"
+		case "term", "phrase", "completion":
+			if kind != "" {
+				return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[suggest] suggestion [%s] only supports one suggester, got [%s] and [%s]", name, kind, k))
+			}
+			if options, _ = v.(map[string]interface{}); options == nil {
+				return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[suggest] suggestion [%s] %s should be an object", name, k))
+			}
+			kind = k
+		default:
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[suggest] suggestion [%s] doesn't support suggester [%s]", name, k))
+		}
+	}
+	if kind == "" {
+		return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[suggest] suggestion [%s] should have one of term, phrase or completion", name))
+	}
+	if regex && kind != "completion" {
+		return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[suggest] suggestion [%s] regex only supports completion suggester", name))
+	}
+
+	switch kind {
+	case "term":
+		return TermSuggester(text, options, mappings, analyzers)
+	case "phrase":
+		return PhraseSuggester(text, options, mappings, analyzers)
+	default:
+		return CompletionSuggester(text, regex, options, q, mappings, analyzers)
+	}
+}
+
+// Response executes the suggesters on the readers
+func Response(suggesters map[string]Suggester, readers []*bluge.Reader) (map[string][]meta.SuggestEntry, error) {
+	if len(suggesters) == 0 {
+		return nil, nil
+	}
+	resp := make(map[string][]meta.SuggestEntry, len(suggesters))
+	for name, s := range suggesters {
+		entries, err := s.Suggest(readers)
+		if err != nil {
+			return nil, err
+		}
+		resp[name] = entries
+	}
+	return resp, nil
+}
+
+// parseField returns the field of suggester, it must be mapped
+func parseField(kind string, options map[string]interface{}, mappings *meta.Mappings) (string, meta.Property, error) {
+	v, ok := options["field"]
+	if !ok {
+		return "", meta.Property{}, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[%s] field is required", kind))
+	}
+	field, err := zutils.ToString(v)
+	if err != nil || field == "" {
+		return "", meta.Property{}, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] field should be a string", kind))
+	}
+	var prop meta.Property
+	if mappings != nil {
+		prop, ok = mappings.GetProperty(field)
+	}
+	if !ok {
+		return "", meta.Property{}, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("no mapping found for field [%s]", field))
+	}
+	return field, prop, nil
+}
+
+// parseAnalyzer returns the named analyzer, or the analyzer of field, the text field uses standard analyzer by default
+func parseAnalyzer(v interface{}, field string, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (*analysis.Analyzer, error) {
+	if v != nil {
+		name, _ := zutils.ToString(v)
+		return zincanalysis.QueryAnalyzer(analyzers, name)
+	}
+	indexZer, searchZer := zincanalysis.QueryAnalyzerForField(analyzers, mappings, field)
+	if searchZer != nil {
+		return searchZer, nil
+	}
+	if indexZer != nil {
+		return indexZer, nil
+	}
+	if prop, ok := mappings.GetProperty(field); ok && prop.Type == "text" {
+		return analyzer.NewStandardAnalyzer(), nil
+	}
+	return nil, nil
+}
+
+// parsePositiveInt parses the option which should be a non-negative integer
+func parsePositiveInt(kind, name string, v interface{}) (int, error) {
+	n, err := zutils.ToInt(v)
+	if err != nil || n < 0 {
+		return 0, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] %s should be a non-negative integer", kind, name))
+	}
+	return n, nil
+}
+
+// parseFloat parses the option which should be a number
+func parseFloat(kind, name string, v interface{}) (float64, error) {
+	f, err := zutils.ToFloat64(v)
+	if err != nil {
+		return 0, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[%s] %s should be a number", kind, name))
+	}
+	return f, nil
+}
+
+type token struct {
+	term       string
+	start, end int
+}
+
+// analyze returns the tokens of text, the whole text is a token if the analyzer is nil
+func analyze(analyzer *analysis.Analyzer, text string) []token {
+	if analyzer == nil {
+		if text == "" {
+			return nil
+		}
+		return []token{{term: text, start: 0, end: len(text)}}
+	}
+	tokens := analyzer.Analyze([]byte(text))
+	rv := make([]token, 0, len(tokens))
+	for _, t := range tokens {
+		rv = append(rv, token{term: string(t.Term), start: t.Start, end: t.End})
+	}
+	return rv
+}
@@ -0,0 +1,230 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package suggest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/analysis"
+
+	"github.com/zincsearch/zincsearch/pkg/errors"
+	"github.com/zincsearch/zincsearch/pkg/meta"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
+)
+
+const (
+	suggestModeMissing = "missing"
+	suggestModePopular = "popular"
+	suggestModeAlways  = "always"
+)
+
+// generator generates the candidates of a term by edit distance from the term dictionary
+type generator struct {
+	field         string
+	size          int
+	suggestMode   string
+	maxEdits      int
+	prefixLength  int
+	minWordLength int
+	minDocFreq    uint64
+}
+
+type candidate struct {
+	term  string
+	freq  uint64
+	score float64
+}
+
+func newGenerator(field, suggestMode string) *generator {
+	return &generator{
+		field:         field,
+		size:          5,
+		suggestMode:   suggestMode,
+		maxEdits:      2,
+		prefixLength:  1,
+		minWordLength: 4,
+	}
+}
+
+// parse parses the options of generator, it returns false if the option is unknown
+func (g *generator) parse(kind, k string, v interface{}) (bool, error) {
+	var err error
+	switch k {
+	case "size":
+		g.size, err = parsePositiveInt(kind, k, v)
+	case "suggest_mode":
+		mode, _ := zutils.ToString(v)
+		g.suggestMode = strings.ToLower(mode)
+		switch g.suggestMode {
+		case suggestModeMissing, suggestModePopular, suggestModeAlways:
+		default:
+			err = errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[%s] illegal suggest_mode [%s]", kind, mode))
+		}
+	case "max_edits":
+		g.maxEdits, err = parsePositiveInt(kind, k, v)
+		if err == nil && (g.maxEdits < 1 || g.maxEdits > 2) {
+			err = errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[%s] max_edits must be between 1 and 2", kind))
+		}
+	case "prefix_length", "prefix_len":
+		g.prefixLength, err = parsePositiveInt(kind, k, v)
+	case "min_word_length", "min_word_len":
+		g.minWordLength, err = parsePositiveInt(kind, k, v)
+	case "min_doc_freq":
+		var n int
+		n, err = parsePositiveInt(kind, k, v)
+		g.minDocFreq = uint64(n)
+	case "shard_size", "max_inspections", "max_term_freq", "string_distance", "accuracy":
+		// accepted for compatibility
+	default:
+		return false, nil
+	}
+	return true, err
+}
+
+// candidates returns the doc freq of the term and the corrections of it
+func (g *generator) candidates(readers []*bluge.Reader, term string) (uint64, []candidate, error) {
+	freq, err := docFreq(readers, g.field, term)
+	if err != nil {
+		return 0, nil, err
+	}
+	runes := []rune(term)
+	if len(runes) < g.minWordLength || g.size == 0 {
+		return freq, nil, nil
+	}
+	if g.suggestMode == suggestModeMissing && freq > 0 {
+		return freq, nil, nil
+	}
+
+	prefix := runes
+	if len(prefix) > g.prefixLength {
+		prefix = prefix[:g.prefixLength]
+	}
+	freqs := make(map[string]uint64)
+	err = visitPrefix(readers, g.field, string(prefix), func(t string, n uint64) {
+		if t == term || abs(utf8.RuneCountInString(t)-len(runes)) > g.maxEdits {
+			return
+		}
+		freqs[t] += n
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var rv []candidate
+	for t, n := range freqs {
+		if n < g.minDocFreq || (g.suggestMode == suggestModePopular && n <= freq) {
+			continue
+		}
+		tr := []rune(t)
+		d := editDistance(runes, tr, g.maxEdits)
+		if d > g.maxEdits {
+			continue
+		}
+		rv = append(rv, candidate{term: t, freq: n, score: 1 - float64(d)/float64(minInt(len(runes), len(tr)))})
+	}
+	sort.Slice(rv, func(i, j int) bool {
+		if rv[i].score != rv[j].score {
+			return rv[i].score > rv[j].score
+		}
+		if rv[i].freq != rv[j].freq {
+			return rv[i].freq > rv[j].freq
+		}
+		return rv[i].term < rv[j].term
+	})
+	if len(rv) > g.size {
+		rv = rv[:g.size]
+	}
+	return freq, rv, nil
+}
+
+// termSuggester suggests the corrections of each token of the text
+type termSuggester struct {
+	*generator
+	text     string
+	analyzer *analysis.Analyzer
+	sort     string
+}
+
+// TermSuggester parses the term suggester
+//
+//	{"field": "message", "size": 5, "sort": "score", "suggest_mode": "missing", "max_edits": 2}
+func TermSuggester(text string, options map[string]interface{}, mappings *meta.Mappings, analyzers map[string]*analysis.Analyzer) (Suggester, error) {
+	field, _, err := parseField("term", options, mappings)
+	if err != nil {
+		return nil, err
+	}
+	s := &termSuggester{
+		generator: newGenerator(field, suggestModeMissing),
+		text:      text,
+		sort:      "score",
+	}
+	if s.analyzer, err = parseAnalyzer(options["analyzer"], field, mappings, analyzers); err != nil {
+		return nil, err
+	}
+	for k, v := range options {
+		k = strings.ToLower(k)
+		switch k {
+		case "field", "analyzer":
+			// handled
+		case "sort":
+			s.sort, _ = zutils.ToString(v)
+			s.sort = strings.ToLower(s.sort)
+			if s.sort != "score" && s.sort != "frequency" {
+				return nil, errors.New(errors.ErrorTypeIllegalArgumentException, fmt.Sprintf("[term] illegal sort [%v]", v))
+			}
+		default:
+			ok, err := s.generator.parse("term", k, v)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[term] unknown field [%s]", k))
+			}
+		}
+	}
+	return s, nil
+}
+
+func (s *termSuggester) Suggest(readers []*bluge.Reader) ([]meta.SuggestEntry, error) {
+	tokens := analyze(s.analyzer, s.text)
+	entries := make([]meta.SuggestEntry, 0, len(tokens))
+	for _, t := range tokens {
+		_, candidates, err := s.candidates(readers, t.term)
+		if err != nil {
+			return nil, err
+		}
+		if s.sort == "frequency" {
+			sort.SliceStable(candidates, func(i, j int) bool {
+				return candidates[i].freq > candidates[j].freq
+			})
+		}
+		options := make([]meta.SuggestOption, 0, len(candidates))
+		for _, c := range candidates {
+			options = append(options, meta.SuggestOption{Text: c.term, Score: c.score, Freq: int(c.freq)})
+		}
+		entries = append(entries, meta.SuggestEntry{
+			Text:    s.text[t.start:t.end],
+			Offset:  t.start,
+			Length:  t.end - t.start,
+			Options: options,
+		})
+	}
+	return entries, nil
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/zincsearch/zincsearch/pkg/errors"
 	"github.com/zincsearch/zincsearch/pkg/meta"
 	"github.com/zincsearch/zincsearch/pkg/uquery/index"
+	"github.com/zincsearch/zincsearch/pkg/zutils"
 )
 
 func Request(data map[string]interface{}) (*meta.IndexTemplate, error) {
@@ -30,7 +31,7 @@ func Request(data map[string]interface{}) (*meta.IndexTemplate, error) {
 		return nil, nil
 	}
 
-	if data["template"] == nil {
+	if data["template"] == nil && data["composed_of"] == nil {
 		return nil, errors.New(errors.ErrorTypeXContentParseException, "[template] template should be defined")
 	}
 
@@ -38,7 +39,7 @@ func Request(data map[string]interface{}) (*meta.IndexTemplate, error) {
 	for k, v := range data {
 		k = strings.ToLower(k)
 		switch k {
-		case "name", "data_stream":
+		case "name", "data_stream", "version", "_meta", "allow_auto_create":
 			// ignore
 		case "index_patterns":
 			patterns, ok := v.([]interface{})
@@ -65,15 +66,26 @@ func Request(data map[string]interface{}) (*meta.IndexTemplate, error) {
 				// compatible {"priority":150,"template":"filebeat-7.16.3-*"}
 				template.IndexPatterns = append(template.IndexPatterns, v)
 			case map[string]interface{}:
-				tmpIndex, err := index.Request(v)
+				tmpTemplate, err := requestTemplate(v)
 				if err != nil {
 					return nil, err
 				}
-				template.Template.Settings = tmpIndex.Settings
-				template.Template.Mappings = tmpIndex.Mappings
+				template.Template = *tmpTemplate
 			default:
 				return nil, errors.New(errors.ErrorTypeXContentParseException, "[template] template value should be an object")
 			}
+		case "composed_of":
+			names, ok := v.([]interface{})
+			if !ok {
+				return nil, errors.New(errors.ErrorTypeXContentParseException, "[template] composed_of value should be an array of string")
+			}
+			for _, name := range names {
+				name, ok := name.(string)
+				if !ok || name == "" {
+					return nil, errors.New(errors.ErrorTypeXContentParseException, "[template] composed_of value should be an array of string")
+				}
+				template.ComposedOf = append(template.ComposedOf, name)
+			}
 		default:
 			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[template] unknown option [%s]", k))
 		}
@@ -85,3 +97,69 @@ func Request(data map[string]interface{}) (*meta.IndexTemplate, error) {
 
 	return template, nil
 }
+
+// RequestComponent parses the component template, it only has the template and the optional version and _meta
+func RequestComponent(data map[string]interface{}) (*meta.ComponentTemplateData, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	v, ok := data["template"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New(errors.ErrorTypeXContentParseException, "[component_template] template should be an object")
+	}
+	tmpTemplate, err := requestTemplate(v)
+	if err != nil {
+		return nil, err
+	}
+
+	component := &meta.ComponentTemplateData{Template: *tmpTemplate}
+	for k, v := range data {
+		switch strings.ToLower(k) {
+		case "template", "name":
+			// parsed or ignore
+		case "version":
+			version, err := zutils.ToInt64(v)
+			if err != nil {
+				return nil, errors.New(errors.ErrorTypeXContentParseException, "[component_template] version value should be a numberic")
+			}
+			component.Version = version
+		case "_meta":
+			component.Meta, ok = v.(map[string]interface{})
+			if !ok {
+				return nil, errors.New(errors.ErrorTypeXContentParseException, "[component_template] _meta value should be an object")
+			}
+		default:
+			return nil, errors.New(errors.ErrorTypeParsingException, fmt.Sprintf("[component_template] unknown option [%s]", k))
+		}
+	}
+	return component, nil
+}
+
+// requestTemplate parses the settings, mappings and aliases of template
+func requestTemplate(data map[string]interface{}) (*meta.TemplateTemplate, error) {
+	tmpIndex, err := index.Request(data)
+	if err != nil {
+		return nil, err
+	}
+	template := new(meta.TemplateTemplate)
+	if tmpIndex != nil {
+		template.Settings = tmpIndex.Settings
+		template.Mappings = tmpIndex.Mappings
+	}
+	if v, ok := data["aliases"]; ok && v != nil {
+		aliases, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, errors.New(errors.ErrorTypeXContentParseException, "[template] aliases value should be an object")
+		}
+		for alias := range aliases {
+			if alias == "" {
+				return nil, errors.New(errors.ErrorTypeXContentParseException, "[template] alias name should be not empty")
+			}
+		}
+		if len(aliases) > 0 {
+			template.Aliases = aliases
+		}
+	}
+	return template, nil
+}
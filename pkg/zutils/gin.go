@@ -16,6 +16,7 @@
 package zutils
 
 import (
+	"bytes"
 	"io"
 	"strings"
 
@@ -23,13 +24,30 @@ import (
 	"github.com/zincsearch/zincsearch/pkg/zutils/json"
 )
 
+// GinBindJSON binds the request body, the big integers in interface values are kept as json.Number
 func GinBindJSON(c *gin.Context, obj interface{}) error {
 	body, err := io.ReadAll(c.Request.Body)
 	if err != nil {
 		return err
 	}
 	defer c.Request.Body.Close()
-	return json.Unmarshal(body, obj)
+	return json.UnmarshalNumber(body, obj)
+}
+
+// GinBindOptionalJSON binds the request body if it is not empty
+func GinBindOptionalJSON(c *gin.Context, obj interface{}) error {
+	if c.Request.Body == nil {
+		return nil
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return err
+	}
+	defer c.Request.Body.Close()
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	return json.UnmarshalNumber(body, obj)
 }
 
 func GinRenderJSON(c *gin.Context, code int, obj interface{}) {
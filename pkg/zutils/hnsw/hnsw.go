@@ -0,0 +1,371 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+// Package hnsw implements the Hierarchical Navigable Small World graph for approximate nearest neighbor search.
+package hnsw
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sync"
+)
+
+const (
+	DefaultM              = 16
+	DefaultEfConstruction = 100
+)
+
+// Similarity returns the similarity of two vectors, the greater is the more similar
+type Similarity func(a, b []float32) float64
+
+// Result is a neighbor found by search
+type Result struct {
+	ID    string
+	Score float64
+}
+
+type node struct {
+	id      string
+	vector  []float32
+	friends [][]uint32 // the neighbors of each level
+	deleted bool
+}
+
+// Graph is a HNSW graph of the vectors identified by string id,
+// the deleted vectors are kept for the traversal until the graph is compacted.
+type Graph struct {
+	similarity     Similarity
+	m              int
+	m0             int
+	efConstruction int
+	levelMult      float64
+	rand           *rand.Rand
+	nodes          []*node
+	ids            map[string]uint32
+	entry          uint32
+	maxLevel       int
+	deleted        int
+	lock           sync.RWMutex
+}
+
+// New returns a graph, m is the number of neighbors of each node and
+// efConstruction is the size of dynamic candidate list when inserting
+func New(similarity Similarity, m, efConstruction int) *Graph {
+	if m < 2 {
+		m = DefaultM
+	}
+	if efConstruction < m {
+		efConstruction = DefaultEfConstruction
+	}
+	return &Graph{
+		similarity:     similarity,
+		m:              m,
+		m0:             m * 2,
+		efConstruction: efConstruction,
+		levelMult:      1 / math.Log(float64(m)),
+		rand:           rand.New(rand.NewSource(1)),
+		ids:            make(map[string]uint32),
+	}
+}
+
+// Len returns the number of vectors in the graph, the deleted vectors are not counted
+func (g *Graph) Len() int {
+	g.lock.RLock()
+	n := len(g.ids)
+	g.lock.RUnlock()
+	return n
+}
+
+// Get returns the vector of id
+func (g *Graph) Get(id string) ([]float32, bool) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	i, ok := g.ids[id]
+	if !ok {
+		return nil, false
+	}
+	return g.nodes[i].vector, true
+}
+
+// Add inserts the vector of id, the old vector of the same id is replaced
+func (g *Graph) Add(id string, vector []float32) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if i, ok := g.ids[id]; ok {
+		g.delete(i)
+	}
+	g.insert(id, vector)
+	g.compact()
+}
+
+// Delete removes the vector of id
+func (g *Graph) Delete(id string) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if i, ok := g.ids[id]; ok {
+		g.delete(i)
+		g.compact()
+	}
+}
+
+func (g *Graph) delete(i uint32) {
+	g.nodes[i].deleted = true
+	delete(g.ids, g.nodes[i].id)
+	g.deleted++
+}
+
+// compact rebuilds the graph if the deleted nodes are more than the alive nodes
+func (g *Graph) compact() {
+	if g.deleted < 64 || g.deleted < len(g.ids) {
+		return
+	}
+	nodes := g.nodes
+	g.nodes = make([]*node, 0, len(g.ids))
+	g.ids = make(map[string]uint32, len(g.ids))
+	g.maxLevel = 0
+	g.deleted = 0
+	for _, n := range nodes {
+		if !n.deleted {
+			g.insert(n.id, n.vector)
+		}
+	}
+}
+
+func (g *Graph) randomLevel() int {
+	return int(math.Floor(-math.Log(1-g.rand.Float64()) * g.levelMult))
+}
+
+func (g *Graph) insert(id string, vector []float32) {
+	level := g.randomLevel()
+	n := &node{id: id, vector: vector, friends: make([][]uint32, level+1)}
+	i := uint32(len(g.nodes))
+	g.nodes = append(g.nodes, n)
+	g.ids[id] = i
+	if i == 0 {
+		g.entry = i
+		g.maxLevel = level
+		return
+	}
+
+	entry := g.entry
+	for l := g.maxLevel; l > level; l-- {
+		entry = g.greedy(vector, entry, l)
+	}
+	for l := minInt(level, g.maxLevel); l >= 0; l-- {
+		candidates := g.searchLayer(vector, entry, g.efConstruction, l, nil)
+		neighbors := g.selectNeighbors(candidates, g.m)
+		n.friends[l] = neighbors
+		for _, friend := range neighbors {
+			g.link(friend, i, l)
+		}
+		entry = candidates[0].id
+	}
+	if level > g.maxLevel {
+		g.maxLevel = level
+		g.entry = i
+	}
+}
+
+// link adds the reverse link to the node, the farthest neighbors are pruned if it has too many neighbors
+func (g *Graph) link(i, friend uint32, level int) {
+	n := g.nodes[i]
+	n.friends[level] = append(n.friends[level], friend)
+	max := g.m
+	if level == 0 {
+		max = g.m0
+	}
+	if len(n.friends[level]) <= max {
+		return
+	}
+	candidates := make([]candidate, 0, len(n.friends[level]))
+	for _, j := range n.friends[level] {
+		candidates = append(candidates, candidate{id: j, score: g.similarity(n.vector, g.nodes[j].vector)})
+	}
+	sortCandidates(candidates)
+	n.friends[level] = g.selectNeighbors(candidates, max)
+}
+
+// selectNeighbors returns the most similar candidates, the candidates are sorted by score
+func (g *Graph) selectNeighbors(candidates []candidate, m int) []uint32 {
+	if len(candidates) > m {
+		candidates = candidates[:m]
+	}
+	rv := make([]uint32, 0, len(candidates))
+	for _, c := range candidates {
+		rv = append(rv, c.id)
+	}
+	return rv
+}
+
+// greedy returns the most similar node of the level starts from entry
+func (g *Graph) greedy(vector []float32, entry uint32, level int) uint32 {
+	best := g.similarity(vector, g.nodes[entry].vector)
+	for changed := true; changed; {
+		changed = false
+		for _, friend := range g.nodes[entry].friends[level] {
+			if score := g.similarity(vector, g.nodes[friend].vector); score > best {
+				best = score
+				entry = friend
+				changed = true
+			}
+		}
+	}
+	return entry
+}
+
+// searchLayer returns the ef most similar nodes of the level sorted by score, the deleted nodes
+// and the nodes not accepted are used for the traversal but not returned
+func (g *Graph) searchLayer(vector []float32, entry uint32, ef, level int, accept func(*node) bool) []candidate {
+	visited := map[uint32]struct{}{entry: {}}
+	score := g.similarity(vector, g.nodes[entry].vector)
+	candidates := &maxHeap{{id: entry, score: score}}
+	results := &minHeap{}
+	if accept == nil || accept(g.nodes[entry]) {
+		heap.Push(results, candidate{id: entry, score: score})
+	}
+	for candidates.Len() > 0 {
+		c := heap.Pop(candidates).(candidate)
+		if results.Len() >= ef && c.score < (*results)[0].score {
+			break
+		}
+		for _, friend := range g.nodes[c.id].friends[level] {
+			if _, ok := visited[friend]; ok {
+				continue
+			}
+			visited[friend] = struct{}{}
+			score := g.similarity(vector, g.nodes[friend].vector)
+			if results.Len() >= ef && score < (*results)[0].score {
+				continue
+			}
+			heap.Push(candidates, candidate{id: friend, score: score})
+			if accept == nil || accept(g.nodes[friend]) {
+				heap.Push(results, candidate{id: friend, score: score})
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+	rv := make([]candidate, results.Len())
+	for i := len(rv) - 1; i >= 0; i-- {
+		rv[i] = heap.Pop(results).(candidate)
+	}
+	return rv
+}
+
+// Search returns the k most similar vectors, ef is the size of dynamic candidate list,
+// the vectors are returned only if the filter accepts the id, nil filter accepts all.
+func (g *Graph) Search(vector []float32, k, ef int, filter func(id string) bool) []Result {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	if len(g.ids) == 0 || k <= 0 {
+		return nil
+	}
+	if ef < k {
+		ef = k
+	}
+	entry := g.entry
+	for l := g.maxLevel; l > 0; l-- {
+		entry = g.greedy(vector, entry, l)
+	}
+	accept := func(n *node) bool {
+		return !n.deleted && (filter == nil || filter(n.id))
+	}
+	candidates := g.searchLayer(vector, entry, ef, 0, accept)
+	if len(candidates) > k {
+		candidates = candidates[:k]
+	}
+	rv := make([]Result, 0, len(candidates))
+	for _, c := range candidates {
+		rv = append(rv, Result{ID: g.nodes[c.id].id, Score: c.score})
+	}
+	return rv
+}
+
+// Exact returns the k most similar vectors of the ids by brute force, the ids not in the graph are ignored
+func (g *Graph) Exact(vector []float32, k int, ids []string) []Result {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	results := &minHeap{}
+	for _, id := range ids {
+		i, ok := g.ids[id]
+		if !ok {
+			continue
+		}
+		heap.Push(results, candidate{id: i, score: g.similarity(vector, g.nodes[i].vector)})
+		if results.Len() > k {
+			heap.Pop(results)
+		}
+	}
+	rv := make([]Result, results.Len())
+	for i := len(rv) - 1; i >= 0; i-- {
+		c := heap.Pop(results).(candidate)
+		rv[i] = Result{ID: g.nodes[c.id].id, Score: c.score}
+	}
+	return rv
+}
+
+type candidate struct {
+	id    uint32
+	score float64
+}
+
+func sortCandidates(candidates []candidate) {
+	h := maxHeap(candidates)
+	heap.Init(&h)
+	sorted := make([]candidate, 0, len(candidates))
+	for h.Len() > 0 {
+		sorted = append(sorted, heap.Pop(&h).(candidate))
+	}
+	copy(candidates, sorted)
+}
+
+// minHeap keeps the least similar candidate on the top
+type minHeap []candidate
+
+func (h minHeap) Len() int            { return len(h) }
+func (h minHeap) Less(i, j int) bool  { return h[i].score < h[j].score }
+func (h minHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *minHeap) Push(x interface{}) { *h = append(*h, x.(candidate)) }
+func (h *minHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// maxHeap keeps the most similar candidate on the top
+type maxHeap []candidate
+
+func (h maxHeap) Len() int            { return len(h) }
+func (h maxHeap) Less(i, j int) bool  { return h[i].score > h[j].score }
+func (h maxHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *maxHeap) Push(x interface{}) { *h = append(*h, x.(candidate)) }
+func (h *maxHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,164 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package hnsw
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func negativeL2(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		d := float64(a[i] - b[i])
+		sum += d * d
+	}
+	return -sum
+}
+
+func randomVectors(n, dims int) [][]float32 {
+	r := rand.New(rand.NewSource(42))
+	vectors := make([][]float32, n)
+	for i := range vectors {
+		vectors[i] = make([]float32, dims)
+		for j := range vectors[i] {
+			vectors[i][j] = r.Float32()
+		}
+	}
+	return vectors
+}
+
+func bruteForce(vectors [][]float32, query []float32, k int, filter func(id string) bool) []string {
+	results := make([]Result, 0, len(vectors))
+	for i, v := range vectors {
+		id := strconv.Itoa(i)
+		if filter == nil || filter(id) {
+			results = append(results, Result{ID: id, Score: negativeL2(query, v)})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	ids := make([]string, 0, k)
+	for _, r := range results[:k] {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func recall(want []string, got []Result) float64 {
+	ids := make(map[string]struct{}, len(got))
+	for _, r := range got {
+		ids[r.ID] = struct{}{}
+	}
+	n := 0
+	for _, id := range want {
+		if _, ok := ids[id]; ok {
+			n++
+		}
+	}
+	return float64(n) / float64(len(want))
+}
+
+func TestGraph_Search(t *testing.T) {
+	vectors := randomVectors(1000, 8)
+	g := New(negativeL2, DefaultM, DefaultEfConstruction)
+	for i, v := range vectors {
+		g.Add(strconv.Itoa(i), v)
+	}
+	assert.Equal(t, 1000, g.Len())
+
+	even := func(id string) bool {
+		i, _ := strconv.Atoi(id)
+		return i%2 == 0
+	}
+	tests := []struct {
+		name   string
+		filter func(id string) bool
+	}{
+		{name: "all"},
+		{name: "filter", filter: even},
+	}
+	queries := randomVectors(20, 8)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var total float64
+			for _, q := range queries {
+				got := g.Search(q, 10, 100, tt.filter)
+				assert.Len(t, got, 10)
+				for i := 1; i < len(got); i++ {
+					assert.GreaterOrEqual(t, got[i-1].Score, got[i].Score)
+				}
+				if tt.filter != nil {
+					for _, r := range got {
+						assert.True(t, tt.filter(r.ID))
+					}
+				}
+				total += recall(bruteForce(vectors, q, 10, tt.filter), got)
+			}
+			assert.Greater(t, total/float64(len(queries)), 0.9)
+		})
+	}
+
+	t.Run("exact", func(t *testing.T) {
+		got := g.Exact(queries[0], 3, []string{"1", "2", "3", "4", "not_exists"})
+		assert.Len(t, got, 3)
+		assert.Equal(t, bruteForce(vectors[:5], queries[0], 3, func(id string) bool { return id != "0" }), []string{got[0].ID, got[1].ID, got[2].ID})
+	})
+}
+
+func TestGraph_Update(t *testing.T) {
+	g := New(negativeL2, 4, 8)
+	assert.Nil(t, g.Search([]float32{0, 0}, 1, 1, nil))
+
+	g.Add("a", []float32{0, 0})
+	g.Add("b", []float32{1, 1})
+	g.Add("c", []float32{2, 2})
+	got := g.Search([]float32{0.1, 0.1}, 1, 10, nil)
+	assert.Equal(t, []Result{{ID: "a", Score: negativeL2([]float32{0.1, 0.1}, []float32{0, 0})}}, got)
+
+	// replace
+	g.Add("a", []float32{5, 5})
+	assert.Equal(t, 3, g.Len())
+	v, ok := g.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []float32{5, 5}, v)
+	got = g.Search([]float32{0.1, 0.1}, 1, 10, nil)
+	assert.Equal(t, "b", got[0].ID)
+
+	// delete
+	g.Delete("b")
+	g.Delete("not_exists")
+	assert.Equal(t, 2, g.Len())
+	got = g.Search([]float32{0.1, 0.1}, 3, 10, nil)
+	assert.Len(t, got, 2)
+	assert.Equal(t, "c", got[0].ID)
+	assert.Equal(t, "a", got[1].ID)
+
+	// compact
+	for i := 0; i < 200; i++ {
+		g.Add(strconv.Itoa(i), []float32{float32(i), 0})
+	}
+	for i := 0; i < 190; i++ {
+		g.Delete(strconv.Itoa(i))
+	}
+	assert.Equal(t, 12, g.Len())
+	assert.Less(t, len(g.nodes), 200)
+	got = g.Search([]float32{195, 0}, 1, 10, nil)
+	assert.Equal(t, "195", got[0].ID)
+}
@@ -15,7 +15,109 @@
 
 package json
 
-import "github.com/goccy/go-json"
+import (
+	"bytes"
+	"reflect"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
 
 var Marshal = json.Marshal
 var Unmarshal = json.Unmarshal
+
+// Number represents a JSON number literal
+type Number = json.Number
+
+// maxExactInt is the largest integer which float64 can represent exactly
+const maxExactInt = 1 << 53
+
+// UnmarshalNumber unmarshals the data like Unmarshal, but the integers which float64 can't represent exactly
+// are kept as Number in the interface values, so the big integers such as 64-bit ids aren't corrupted.
+// The other numbers are float64 as Unmarshal does.
+func UnmarshalNumber(data []byte, v interface{}) error {
+	if err := UnmarshalUseNumber(data, v); err != nil {
+		return err
+	}
+	normalizeNumber(reflect.ValueOf(v))
+	return nil
+}
+
+// UnmarshalUseNumber unmarshals the data in one pass and keeps all the numbers in interface values as Number,
+// the caller converts them by NumberValue or by the type it expects.
+func UnmarshalUseNumber(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	err := dec.Decode(v)
+	if err == nil && len(bytes.TrimSpace(data[dec.InputOffset():])) == 0 {
+		return nil
+	}
+	// returns the same syntax error as Unmarshal
+	if uerr := json.Unmarshal(data, v); uerr != nil {
+		return uerr
+	}
+	return err
+}
+
+// NormalizeNumber converts the Number in the interface values of v by NumberValue, v is a map, slice or pointer
+func NormalizeNumber(v interface{}) {
+	normalizeNumber(reflect.ValueOf(v))
+}
+
+// normalizeNumber walks the value and converts the Number in interface values by NumberValue
+func normalizeNumber(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			normalizeNumber(v.Elem())
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		if n, ok := v.Elem().Interface().(Number); ok {
+			if v.CanSet() {
+				v.Set(reflect.ValueOf(NumberValue(n)))
+			}
+			return
+		}
+		normalizeNumber(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				normalizeNumber(v.Field(i))
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			val := iter.Value()
+			if val.Kind() == reflect.Interface && !val.IsNil() {
+				if n, ok := val.Elem().Interface().(Number); ok {
+					v.SetMapIndex(iter.Key(), reflect.ValueOf(NumberValue(n)))
+					continue
+				}
+				val = val.Elem()
+			}
+			normalizeNumber(val)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			normalizeNumber(v.Index(i))
+		}
+	}
+}
+
+// NumberValue returns the Number itself if it is an integer out of the float64 precision, otherwise returns float64
+func NumberValue(n Number) interface{} {
+	if !strings.ContainsAny(string(n), ".eE") {
+		if i, err := n.Int64(); err != nil || i > maxExactInt || i < -maxExactInt {
+			return n
+		}
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return n
+	}
+	return f
+}
@@ -0,0 +1,68 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalNumber(t *testing.T) {
+	t.Run("map", func(t *testing.T) {
+		var doc map[string]interface{}
+		err := UnmarshalNumber([]byte(`{"id":1234567890123456789,"n":42,"f":1.5,"list":[9007199254740993,1],"obj":{"id":-9007199254740993}}`), &doc)
+		assert.NoError(t, err)
+		assert.Equal(t, Number("1234567890123456789"), doc["id"])
+		assert.Equal(t, float64(42), doc["n"])
+		assert.Equal(t, 1.5, doc["f"])
+		assert.Equal(t, []interface{}{Number("9007199254740993"), float64(1)}, doc["list"])
+		assert.Equal(t, Number("-9007199254740993"), doc["obj"].(map[string]interface{})["id"])
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		var v struct {
+			Value interface{} `json:"value"`
+			Size  int         `json:"size"`
+			Terms []interface{}
+		}
+		err := UnmarshalNumber([]byte(`{"value":1234567890123456789,"size":10,"terms":[1,2]}`), &v)
+		assert.NoError(t, err)
+		assert.Equal(t, Number("1234567890123456789"), v.Value)
+		assert.Equal(t, 10, v.Size)
+		assert.Equal(t, []interface{}{float64(1), float64(2)}, v.Terms)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var doc map[string]interface{}
+		assert.Error(t, UnmarshalNumber([]byte(`{"id":1`), &doc))
+		assert.Error(t, UnmarshalNumber([]byte(`{"id":1} {"id":2}`), &doc))
+	})
+}
+
+func TestUnmarshalUseNumber(t *testing.T) {
+	var doc map[string]interface{}
+	err := UnmarshalUseNumber([]byte(`{"id":1234567890123456789,"n":42,"list":[1.5]} `), &doc)
+	assert.NoError(t, err)
+	assert.Equal(t, Number("1234567890123456789"), doc["id"])
+	assert.Equal(t, Number("42"), doc["n"])
+	assert.Equal(t, []interface{}{Number("1.5")}, doc["list"])
+
+	NormalizeNumber(doc)
+	assert.Equal(t, Number("1234567890123456789"), doc["id"])
+	assert.Equal(t, float64(42), doc["n"])
+	assert.Equal(t, []interface{}{1.5}, doc["list"])
+}
@@ -0,0 +1,363 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+// Package mustache renders the mustache templates of search templates, the variables are escaped as json string
+// like elasticsearch, and it supports the elasticsearch extensions {{#toJson}}, {{#join}} and {{#url}}.
+// Partials and set delimiter tags are not supported.
+package mustache
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
+)
+
+type nodeType int
+
+const (
+	nodeText nodeType = iota
+	nodeVariable
+	nodeSection
+	nodeInverted
+)
+
+type node struct {
+	typ      nodeType
+	text     string // text of text node, or name of tag
+	args     string // arguments of section, like delimiter of join
+	escape   bool
+	inner    string // raw template of section
+	children []*node
+}
+
+// Template is a parsed mustache template
+type Template struct {
+	nodes []*node
+}
+
+// Parse parses the mustache template
+func Parse(source string) (*Template, error) {
+	p := &parser{source: source}
+	nodes, err := p.parse("")
+	if err != nil {
+		return nil, err
+	}
+	return &Template{nodes: nodes}, nil
+}
+
+// Render renders the template with the params
+func Render(source string, params map[string]interface{}) (string, error) {
+	t, err := Parse(source)
+	if err != nil {
+		return "", err
+	}
+	return t.Render(params)
+}
+
+// Render renders the template with the params
+func (t *Template) Render(params map[string]interface{}) (string, error) {
+	var b strings.Builder
+	if err := renderNodes(&b, t.nodes, []interface{}{params}); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+type parser struct {
+	source        string
+	pos           int
+	closeTagStart int // start of the last close tag, it ends the raw template of section
+}
+
+// parse parses the nodes until the close tag of section, the section is empty for the top level
+func (p *parser) parse(section string) ([]*node, error) {
+	var nodes []*node
+	for p.pos < len(p.source) {
+		start := strings.Index(p.source[p.pos:], "{{")
+		if start < 0 {
+			nodes = append(nodes, &node{typ: nodeText, text: p.source[p.pos:]})
+			p.pos = len(p.source)
+			break
+		}
+		if start > 0 {
+			nodes = append(nodes, &node{typ: nodeText, text: p.source[p.pos : p.pos+start]})
+		}
+		tagStart := p.pos + start
+		p.pos = tagStart + 2
+
+		closeTag := "}}"
+		if strings.HasPrefix(p.source[p.pos:], "{") {
+			closeTag = "}}}"
+		}
+		end := strings.Index(p.source[p.pos:], closeTag)
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed tag at position %d", tagStart)
+		}
+		tag := p.source[p.pos : p.pos+end]
+		p.pos += end + len(closeTag)
+
+		if tag == "" {
+			return nil, fmt.Errorf("empty tag at position %d", tagStart)
+		}
+		content := strings.TrimSpace(tag[1:])
+		switch tag[0] {
+		case '!':
+			// comment
+		case '{', '&':
+			nodes = append(nodes, &node{typ: nodeVariable, text: content})
+		case '#', '^':
+			if content == "" {
+				return nil, fmt.Errorf("empty section name at position %d", tagStart)
+			}
+			innerStart := p.pos
+			children, err := p.parse(content)
+			if err != nil {
+				return nil, err
+			}
+			n := &node{typ: nodeSection, children: children, inner: p.source[innerStart:p.closeTagStart]}
+			if tag[0] == '^' {
+				n.typ = nodeInverted
+			}
+			n.text, n.args = content, ""
+			if i := strings.IndexAny(content, " \t"); i > 0 {
+				n.text, n.args = content[:i], strings.TrimSpace(content[i+1:])
+			}
+			nodes = append(nodes, n)
+		case '/':
+			if content != section {
+				return nil, fmt.Errorf("unexpected close tag [%s] at position %d, expected [%s]", content, tagStart, section)
+			}
+			p.closeTagStart = tagStart
+			return nodes, nil
+		case '>':
+			return nil, fmt.Errorf("partial [%s] is not supported", content)
+		case '=':
+			return nil, fmt.Errorf("set delimiter is not supported")
+		default:
+			nodes = append(nodes, &node{typ: nodeVariable, text: strings.TrimSpace(tag), escape: true})
+		}
+	}
+	if section != "" {
+		return nil, fmt.Errorf("unclosed section [%s]", section)
+	}
+	return nodes, nil
+}
+
+func renderNodes(b *strings.Builder, nodes []*node, stack []interface{}) error {
+	for _, n := range nodes {
+		switch n.typ {
+		case nodeText:
+			b.WriteString(n.text)
+		case nodeVariable:
+			value, _ := lookup(stack, n.text)
+			s, err := toString(value)
+			if err != nil {
+				return err
+			}
+			if n.escape {
+				s = escapeJSON(s)
+			}
+			b.WriteString(s)
+		case nodeSection:
+			if err := renderSection(b, n, stack); err != nil {
+				return err
+			}
+		case nodeInverted:
+			value, _ := lookup(stack, n.text)
+			if !truthy(value) {
+				if err := renderNodes(b, n.children, stack); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func renderSection(b *strings.Builder, n *node, stack []interface{}) error {
+	switch n.text {
+	case "toJson":
+		value, _ := lookup(stack, strings.TrimSpace(n.inner))
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		b.Write(data)
+		return nil
+	case "join":
+		delimiter, err := joinDelimiter(n.args)
+		if err != nil {
+			return err
+		}
+		value, _ := lookup(stack, strings.TrimSpace(n.inner))
+		items, ok := value.([]interface{})
+		if !ok {
+			items = []interface{}{value}
+		}
+		for i, item := range items {
+			s, err := toString(item)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				b.WriteString(delimiter)
+			}
+			b.WriteString(escapeJSON(s))
+		}
+		return nil
+	case "url":
+		var sub strings.Builder
+		if err := renderNodes(&sub, n.children, stack); err != nil {
+			return err
+		}
+		b.WriteString(url.QueryEscape(sub.String()))
+		return nil
+	}
+
+	value, _ := lookup(stack, n.text)
+	if !truthy(value) {
+		return nil
+	}
+	if items, ok := value.([]interface{}); ok {
+		for _, item := range items {
+			if err := renderNodes(b, n.children, append(stack, item)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return renderNodes(b, n.children, append(stack, value))
+}
+
+// joinDelimiter parses the delimiter argument of join section, the default delimiter is comma
+func joinDelimiter(args string) (string, error) {
+	if args == "" {
+		return ",", nil
+	}
+	if !strings.HasPrefix(args, "delimiter=") {
+		return "", fmt.Errorf("join doesn't support argument [%s]", args)
+	}
+	delimiter := strings.TrimPrefix(args, "delimiter=")
+	if len(delimiter) >= 2 && (delimiter[0] == '\'' || delimiter[0] == '"') && delimiter[len(delimiter)-1] == delimiter[0] {
+		delimiter = delimiter[1 : len(delimiter)-1]
+	}
+	return delimiter, nil
+}
+
+// lookup returns the value of dotted name from the top of context stack,
+// the first part of name is looked up through the stack, the other parts are looked up in the found value.
+func lookup(stack []interface{}, name string) (interface{}, bool) {
+	if name == "." {
+		return stack[len(stack)-1], true
+	}
+	parts := strings.Split(name, ".")
+	for i := len(stack) - 1; i >= 0; i-- {
+		value, ok := child(stack[i], parts[0])
+		if !ok {
+			continue
+		}
+		for _, part := range parts[1:] {
+			if value, ok = child(value, part); !ok {
+				return nil, false
+			}
+		}
+		return value, true
+	}
+	return nil, false
+}
+
+func child(value interface{}, name string) (interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		value, ok := v[name]
+		return value, ok
+	case []interface{}:
+		i, err := strconv.Atoi(name)
+		if err != nil || i < 0 || i >= len(v) {
+			return nil, false
+		}
+		return v[i], true
+	}
+	return nil, false
+}
+
+// truthy returns false for the missing value, false, empty string and empty list
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	}
+	return true
+}
+
+func toString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case json.Number:
+		return v.String(), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	}
+	data, err := json.Marshal(value)
+	return string(data), err
+}
+
+// escapeJSON escapes the value as the content of json string
+func escapeJSON(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04x`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,77 @@
+/* Copyright 2022 Zinc Labs Inc. and Contributors
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*     http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package mustache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	params := map[string]interface{}{
+		"text":   `say "hi"\now`,
+		"size":   float64(10),
+		"from":   float64(0),
+		"tags":   []interface{}{"a", "b"},
+		"user":   map[string]interface{}{"name": "joe", "roles": []interface{}{"admin", "dev"}},
+		"filter": []interface{}{map[string]interface{}{"field": "f1"}, map[string]interface{}{"field": "f2"}},
+		"empty":  "",
+		"enable": true,
+	}
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{name: "variable", template: `{"size":{{size}},"from":{{from}}}`, want: `{"size":10,"from":0}`},
+		{name: "escaped", template: `"{{text}}"`, want: `"say \"hi\"\\now"`},
+		{name: "unescaped", template: `{{{text}}}|{{& text}}`, want: `say "hi"\now|say "hi"\now`},
+		{name: "dotted name", template: `{{user.name}} {{user.roles.1}}`, want: `joe dev`},
+		{name: "missing", template: `[{{missing}}{{user.missing}}]`, want: `[]`},
+		{name: "comment", template: `a{{! comment }}b`, want: `ab`},
+		{name: "section list", template: `{{#filter}}{{field}},{{/filter}}`, want: `f1,f2,`},
+		{name: "section scalar list", template: `{{#tags}}[{{.}}]{{/tags}}`, want: `[a][b]`},
+		{name: "section object", template: `{{#user}}{{name}}-{{size}}{{/user}}`, want: `joe-10`},
+		{name: "section bool", template: `{{#enable}}on{{/enable}}{{#missing}}off{{/missing}}`, want: `on`},
+		{name: "default value", template: `{{empty}}{{^empty}}now{{/empty}} {{size}}{{^size}}20{{/size}}`, want: `now 10`},
+		{name: "toJson", template: `{{#toJson}}tags{{/toJson}} {{#toJson}}user{{/toJson}}`, want: `["a","b"] {"name":"joe","roles":["admin","dev"]}`},
+		{name: "join", template: `{{#join}}tags{{/join}}`, want: `a,b`},
+		{name: "join delimiter", template: `{{#join delimiter='||'}}user.roles{{/join delimiter='||'}}`, want: `admin||dev`},
+		{name: "url", template: `{{#url}}{{user.name}} & co{{/url}}`, want: `joe+%26+co`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.template, params)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	errTests := []string{
+		`{{#tags}}unclosed`,
+		`{{#tags}}{{/user}}`,
+		`{{unclosed`,
+		`{{> partial}}`,
+		`{{=<% %>=}}`,
+	}
+	for _, tpl := range errTests {
+		t.Run(tpl, func(t *testing.T) {
+			_, err := Render(tpl, params)
+			assert.Error(t, err)
+		})
+	}
+}
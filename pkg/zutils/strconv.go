@@ -17,7 +17,10 @@ package zutils
 
 import (
 	"fmt"
+	"math"
 	"strconv"
+
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
 )
 
 func ToString(v interface{}) (string, error) {
@@ -34,6 +37,8 @@ func ToString(v interface{}) (string, error) {
 		return strconv.Itoa(v), nil
 	case bool:
 		return strconv.FormatBool(v), nil
+	case json.Number:
+		return v.String(), nil
 	default:
 		return fmt.Sprintf("%v", v), nil
 	}
@@ -51,6 +56,8 @@ func ToFloat64(v interface{}) (float64, error) {
 		return float64(v), nil
 	case string:
 		return strconv.ParseFloat(v, 64)
+	case json.Number:
+		return v.Float64()
 	case bool:
 		if v {
 			return 1, nil
@@ -74,6 +81,8 @@ func ToUint64(v interface{}) (uint64, error) {
 		return uint64(v), nil
 	case string:
 		return strconv.ParseUint(v, 10, 64)
+	case json.Number:
+		return strconv.ParseUint(v.String(), 10, 64)
 	case bool:
 		if v {
 			return 1, nil
@@ -97,6 +106,9 @@ func ToInt(v interface{}) (int, error) {
 		return int(v), nil
 	case string:
 		return strconv.Atoi(v)
+	case json.Number:
+		i, err := v.Int64()
+		return int(i), err
 	case bool:
 		if v {
 			return 1, nil
@@ -122,7 +134,57 @@ func ToBool(v interface{}) (bool, error) {
 		return v != 0, nil
 	case int:
 		return v != 0, nil
+	case json.Number:
+		f, err := v.Float64()
+		return f != 0, err
 	default:
 		return false, fmt.Errorf("ToInt: unknown supported type %T", v)
 	}
 }
+
+// ToInt64 converts v to int64 without the precision loss of float64,
+// the fractional part of a float number is truncated.
+func ToInt64(v interface{}) (int64, error) {
+	switch v := v.(type) {
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, fmt.Errorf("ToInt64: value [%d] is out of range", v)
+		}
+		return int64(v), nil
+	case float64:
+		return floatToInt64(v)
+	case string:
+		return parseInt64(v)
+	case json.Number:
+		return parseInt64(v.String())
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("ToInt64: unknown supported type %T", v)
+	}
+}
+
+func parseInt64(s string) (int64, error) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	return floatToInt64(f)
+}
+
+func floatToInt64(f float64) (int64, error) {
+	if math.IsNaN(f) || f >= math.MaxInt64 || f < math.MinInt64 {
+		return 0, fmt.Errorf("ToInt64: value [%v] is out of range", f)
+	}
+	return int64(f), nil
+}
@@ -17,6 +17,8 @@ package zutils
 
 import (
 	"testing"
+
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
 )
 
 func TestToString(t *testing.T) {
@@ -353,3 +355,70 @@ func TestToBool(t *testing.T) {
 		})
 	}
 }
+
+func TestToInt64(t *testing.T) {
+	type args struct {
+		v interface{}
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "number",
+			args: args{
+				v: json.Number("9007199254740993"),
+			},
+			want: 9007199254740993,
+		},
+		{
+			name: "string",
+			args: args{
+				v: "-9223372036854775808",
+			},
+			want: -9223372036854775808,
+		},
+		{
+			name: "float64",
+			args: args{
+				v: 3.99,
+			},
+			want: 3,
+		},
+		{
+			name: "uint64",
+			args: args{
+				v: uint64(3),
+			},
+			want: 3,
+		},
+		{
+			name: "out of range",
+			args: args{
+				v: json.Number("1e20"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "error",
+			args: args{
+				v: "abc",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToInt64(tt.args.v)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ToInt64() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ToInt64() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/zincsearch/zincsearch/pkg/zutils/json"
 )
 
 func ParseDuration(s string) (time.Duration, error) {
@@ -93,6 +95,11 @@ func ParseTime(value interface{}, format, timeZone string) (time.Time, error) {
 		vInt = int64(v)
 	case int64:
 		vInt = v
+	case json.Number:
+		var err error
+		if vInt, err = ToInt64(v); err != nil {
+			return time.Time{}, fmt.Errorf("time value [%s] is not a valid timestamp", v)
+		}
 	case string:
 		vStr = v
 	default:
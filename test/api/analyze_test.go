@@ -314,6 +314,125 @@ func TestAnalyze(t *testing.T) {
 			assert.NoError(t, err)
 			assert.Equal(t, output, tokens)
 		})
+
+		t.Run("bengali analyzer", func(t *testing.T) {
+			input := `{
+				"analyzer": "bengali",
+				"text": "ছেলেরা বইগুলো পড়েছিলেন ১২৩"
+			  }`
+			output := `[ছেল বই পর 123]`
+
+			body := bytes.NewBuffer(nil)
+			body.WriteString(input)
+			resp := request("POST", "/api/_analyze", body)
+			assert.Equal(t, http.StatusOK, resp.Code)
+
+			tokens, err := getTokenStrings(resp.Body.Bytes())
+			assert.NoError(t, err)
+			assert.Equal(t, output, tokens)
+		})
+
+		t.Run("brazilian analyzer", func(t *testing.T) {
+			input := `{
+				"analyzer": "brazilian",
+				"text": "As crianças brincavam alegremente"
+			  }`
+			output := `[crianc brinc alegr]`
+
+			body := bytes.NewBuffer(nil)
+			body.WriteString(input)
+			resp := request("POST", "/api/_analyze", body)
+			assert.Equal(t, http.StatusOK, resp.Code)
+
+			tokens, err := getTokenStrings(resp.Body.Bytes())
+			assert.NoError(t, err)
+			assert.Equal(t, output, tokens)
+		})
+
+		t.Run("estonian analyzer", func(t *testing.T) {
+			input := `{
+				"analyzer": "estonian",
+				"text": "Tallinn ja majadega"
+			  }`
+			output := `[tallinn maj]`
+
+			body := bytes.NewBuffer(nil)
+			body.WriteString(input)
+			resp := request("POST", "/api/_analyze", body)
+			assert.Equal(t, http.StatusOK, resp.Code)
+
+			tokens, err := getTokenStrings(resp.Body.Bytes())
+			assert.NoError(t, err)
+			assert.Equal(t, output, tokens)
+		})
+
+		t.Run("latvian analyzer", func(t *testing.T) {
+			input := `{
+				"analyzer": "latvian",
+				"text": "Rīga ir Latvijas galvaspilsēta"
+			  }`
+			output := `[rīg latvij galvaspilsēt]`
+
+			body := bytes.NewBuffer(nil)
+			body.WriteString(input)
+			resp := request("POST", "/api/_analyze", body)
+			assert.Equal(t, http.StatusOK, resp.Code)
+
+			tokens, err := getTokenStrings(resp.Body.Bytes())
+			assert.NoError(t, err)
+			assert.Equal(t, output, tokens)
+		})
+
+		t.Run("thai analyzer", func(t *testing.T) {
+			input := `{
+				"analyzer": "thai",
+				"text": "ฉันชอบกินข้าวผัดที่ร้านอาหาร ๒๕๖๖"
+			  }`
+			output := `[ฉัน ชอบ กิน ข้าวผัด ร้านอาหาร 2566]`
+
+			body := bytes.NewBuffer(nil)
+			body.WriteString(input)
+			resp := request("POST", "/api/_analyze", body)
+			assert.Equal(t, http.StatusOK, resp.Code)
+
+			tokens, err := getTokenStrings(resp.Body.Bytes())
+			assert.NoError(t, err)
+			assert.Equal(t, output, tokens)
+		})
+
+		t.Run("kuromoji analyzer", func(t *testing.T) {
+			input := `{
+				"analyzer": "kuromoji",
+				"text": "関西国際空港で飲みました"
+			  }`
+			output := `[関西 国際 空港 飲む]`
+
+			body := bytes.NewBuffer(nil)
+			body.WriteString(input)
+			resp := request("POST", "/api/_analyze", body)
+			assert.Equal(t, http.StatusOK, resp.Code)
+
+			tokens, err := getTokenStrings(resp.Body.Bytes())
+			assert.NoError(t, err)
+			assert.Equal(t, output, tokens)
+		})
+
+		t.Run("nori analyzer", func(t *testing.T) {
+			input := `{
+				"analyzer": "nori",
+				"text": "가거도항 공원에 갔다."
+			  }`
+			output := `[가거도 항 공원 가]`
+
+			body := bytes.NewBuffer(nil)
+			body.WriteString(input)
+			resp := request("POST", "/api/_analyze", body)
+			assert.Equal(t, http.StatusOK, resp.Code)
+
+			tokens, err := getTokenStrings(resp.Body.Bytes())
+			assert.NoError(t, err)
+			assert.Equal(t, output, tokens)
+		})
 	})
 
 	t.Run("test tokenizer", func(t *testing.T) {
@@ -743,6 +862,62 @@ func TestAnalyze(t *testing.T) {
 			// delete index
 			request("DELETE", "/api/index/"+indexName, nil)
 		})
+
+		t.Run("gse tokenizer with user dictionary rules", func(t *testing.T) {
+			indexName := "my-index-gse"
+			index := `{
+				"settings": {
+				  "analysis": {
+					"analyzer": {
+					  "my_analyzer": {
+						"tokenizer": "my_tokenizer",
+						"filter": ["my_stop"]
+					  }
+					},
+					"tokenizer": {
+					  "my_tokenizer": {
+						"type": "gse_standard",
+						"user_dictionary_rules": ["复仇者联盟 100 n", "灭霸 100 n"]
+					  }
+					},
+					"filter": {
+					  "my_stop": {
+						"type": "gse_stop",
+						"stopwords": ["的"]
+					  }
+					}
+				  }
+				}
+			  }`
+			input := `{
+				"analyzer": "my_analyzer",
+				"text": "复仇者联盟的灭霸"
+			  }`
+			output := `[复仇者联盟 灭霸]`
+
+			// create index with custom analyzer
+			body := bytes.NewBuffer(nil)
+			body.WriteString(index)
+			resp := request("PUT", "/api/index/"+indexName, body)
+			assert.Equal(t, http.StatusOK, resp.Code)
+
+			// analyze
+			body.Reset()
+			body.WriteString(input)
+			resp = request("POST", "/api/"+indexName+"/_analyze", body)
+			assert.Equal(t, http.StatusOK, resp.Code)
+			tokens, err := getTokenStrings(resp.Body.Bytes())
+			assert.NoError(t, err)
+			assert.Equal(t, output, tokens)
+
+			// reload dictionaries
+			resp = request("POST", "/es/"+indexName+"/_reload_search_analyzers", nil)
+			assert.Equal(t, http.StatusOK, resp.Code)
+			assert.Contains(t, resp.Body.String(), `"reloaded_analyzers":["my_analyzer"]`)
+
+			// delete index
+			request("DELETE", "/api/index/"+indexName, nil)
+		})
 	})
 
 	t.Run("test token filter", func(t *testing.T) {
@@ -1090,6 +1265,88 @@ func TestAnalyze(t *testing.T) {
 			assert.Equal(t, output, tokens)
 		})
 
+		t.Run("Kuromoji readingform token filter", func(t *testing.T) {
+			input := `{
+				"tokenizer": "kuromoji_tokenizer",
+				"filter": [
+					{
+						"type": "kuromoji_readingform",
+						"use_romaji": true
+					}
+				],
+				"text": "東京の喫茶店"
+			}`
+			output := `[toukyou no kissaten]`
+
+			body := bytes.NewBuffer(nil)
+			body.WriteString(input)
+			resp := request("POST", "/api/_analyze", body)
+			assert.Equal(t, http.StatusOK, resp.Code)
+
+			tokens, err := getTokenStrings(resp.Body.Bytes())
+			assert.NoError(t, err)
+			assert.Equal(t, output, tokens)
+		})
+
+		t.Run("Nori part of speech token filter", func(t *testing.T) {
+			input := `{
+				"tokenizer": "nori_tokenizer",
+				"filter": [
+					{
+						"type": "nori_part_of_speech",
+						"stoptags": ["E", "J"]
+					}
+				],
+				"text": "가거도항 공원에 갔다."
+			}`
+			output := `[가거도 항 공원 가]`
+
+			body := bytes.NewBuffer(nil)
+			body.WriteString(input)
+			resp := request("POST", "/api/_analyze", body)
+			assert.Equal(t, http.StatusOK, resp.Code)
+
+			tokens, err := getTokenStrings(resp.Body.Bytes())
+			assert.NoError(t, err)
+			assert.Equal(t, output, tokens)
+		})
+
+		t.Run("Thai tokenizer with thai_stop and decimal_digit token filters", func(t *testing.T) {
+			input := `{
+				"tokenizer": "thai",
+				"filter": [ "thai_stop", "decimal_digit" ],
+				"text": "ประเทศไทยมีประชากรประมาณ ๖๐ ล้านคน"
+			}`
+			output := `[ประเทศไทย ประชากร ประมาณ 60 ล้าน คน]`
+
+			body := bytes.NewBuffer(nil)
+			body.WriteString(input)
+			resp := request("POST", "/api/_analyze", body)
+			assert.Equal(t, http.StatusOK, resp.Code)
+
+			tokens, err := getTokenStrings(resp.Body.Bytes())
+			assert.NoError(t, err)
+			assert.Equal(t, output, tokens)
+		})
+
+		t.Run("Latvian stemmer and stop token filters", func(t *testing.T) {
+			input := `{
+				"tokenizer": "standard",
+				"filter": [ "lowercase", "latvian_stop", "latvian_stemmer" ],
+				"text": "Rīga ir Latvijas galvaspilsēta"
+			}`
+			output := `[rīg latvij galvaspilsēt]`
+
+			body := bytes.NewBuffer(nil)
+			body.WriteString(input)
+			resp := request("POST", "/api/_analyze", body)
+			assert.Equal(t, http.StatusOK, resp.Code)
+
+			tokens, err := getTokenStrings(resp.Body.Bytes())
+			assert.NoError(t, err)
+			assert.Equal(t, output, tokens)
+		})
+
 		t.Run("Trim token filter", func(t *testing.T) {
 			input := `{
 				"tokenizer" : "keyword",